@@ -70,16 +70,46 @@ func main() {
 	// Chat
 	router.AddRoute("send", handlers.HandleSendMessage)
 	router.AddRoute("fetch", handlers.HandleFetchMessage)
+	router.AddRoute("sc", handlers.HandleSpectatorMessage)
+	router.AddRoute("msg", handlers.HandleMsg)
+	router.AddRoute("channel", handlers.HandleChannel)
+	router.AddRoute("c", handlers.HandleChannelSay)
 
 	// Sala
 	router.AddRoute("create", handlers.HandleCreateRoom)
 	router.AddRoute("join", handlers.HandleJoinRoom)
 	router.AddRoute("leave", handlers.HandleLeaveRoom)
+	router.AddRoute("rooms", handlers.HandleListRooms)
+	router.AddRoute("invite", handlers.HandleInvite)
+	router.AddRoute("spectate", handlers.HandleSpectate)
+	router.AddRoute("kick", handlers.HandleKick)
+	router.AddRoute("lock", handlers.HandleLock)
+	router.AddRoute("unlock", handlers.HandleUnlock)
+	router.AddRoute("host", handlers.HandleTransferHost)
+	router.AddRoute("challenge", handlers.HandleChallenge)
 
 	// Jogo
+	router.AddRoute("ready", handlers.HandleReady)
+	router.AddRoute("unready", handlers.HandleUnready)
+	router.AddRoute("rematch", handlers.HandleRematch)
+	router.AddRoute("decline", handlers.HandleDecline)
 	router.AddRoute("play", handlers.HandlePlay)
+	router.AddRoute("rules", handlers.HandleRules)
 	router.AddRoute("cards", handlers.HandleCards)
+	router.AddRoute("hand", handlers.HandleHand)
+	router.AddRoute("games", handlers.HandleGames)
+	router.AddRoute("deck", handlers.HandleDeck)
 	router.AddRoute("buy", handlers.HandleBuy)
+	router.AddRoute("store", handlers.HandleStore)
+	router.AddRoute("fair", handlers.HandleFair)
+	router.AddRoute("balance", handlers.HandleBalance)
+	router.AddRoute("craft", handlers.HandleCraft)
+	router.AddRoute("disenchant", handlers.HandleDisenchant)
+	router.AddRoute("trade", handlers.HandleTrade)
+	router.AddRoute("market", handlers.HandleMarket)
+	router.AddRoute("replays", handlers.HandleReplays)
+	router.AddRoute("replay", handlers.HandleReplay)
+	router.AddRoute("tournament", handlers.HandleTournament)
 
 	// Diversos
 	router.AddRoute("whoami", handlers.HandleWhoami)
@@ -98,13 +128,47 @@ func main() {
 			"/register <usuario> <senha> - Registra um novo usuário\n" +
 			"/login <usuario> <senha> - Faz login\n" +
 			"/logout - Faz logout da sessão atual\n" +
-			"/create <nome_da_sala> - Cria uma nova sala de chat/jogo\n" +
-			"/join <nome_da_sala> - Entra em uma sala existente\n" +
+			"/create [-private] [-password <senha>] [-rules <regras>] [-commit] [-async [horas]] [-wager <moedas>] [-stake <cartas>] [-bot <dificuldade>] <nome_da_sala> - Cria uma nova sala de chat/jogo (-commit usa jogadas com compromisso e revelação, -async cria partidas por correspondência com prazo por rodada, -wager e -stake apostam moedas e cartas em cada partida, -bot easy|medium|hard coloca um bot do servidor como oponente)\n" +
+			"/join <id_da_sala|#n> [senha] - Entra em uma sala existente (#n usa a listagem de /rooms)\n" +
+			"/join <código> - Entra em uma sala privada usando um convite\n" +
+			"/invite [-multi] [minutos] - Gera um convite para a sala privada atual\n" +
+			"/spectate <id_da_sala|#n|código> [senha] - Assiste a uma sala como espectador\n" +
+			"/sc <mensagem> - Envia mensagem apenas para os espectadores da sala\n" +
+			"/msg <usuario> [mensagem] - Envia uma mensagem direta para um usuário (sem mensagem, mostra a conversa com ele)\n" +
+			"/channel list | join <nome> | leave [nome] | who [nome] - Lista os canais de chat, entra (criando, se preciso) ou sai de um deles e mostra os seus membros\n" +
+			"/c <mensagem> - Envia mensagem para o canal de chat atual (fora de uma sala, basta digitar a mensagem)\n" +
+			"/rooms [-all] [-playing] [-public] [nome] - Lista as salas disponíveis\n" +
 			"/leave - Sai da sala atual\n" +
+			"/kick <usuario> - Expulsa um usuário da sua sala (apenas anfitrião)\n" +
+			"/lock e /unlock - Tranca ou destranca a sua sala para novos usuários (apenas anfitrião)\n" +
+			"/host <usuario> - Passa o papel de anfitrião para outro membro (apenas anfitrião)\n" +
+			"/challenge <usuario> [-rules <regras>] [-commit] - Desafia um usuário conectado para um duelo em uma sala privada\n" +
+			"/challenge list | accept [id] | decline [id] | cancel <id> - Lista, aceita, recusa ou cancela desafios (sem ID, responde o último recebido)\n" +
 			"/send <mensagem> - Envia mensagem para a sala atual (ou apenas digite a mensagem sem /)" +
-			"\n/play <carta> - Joga uma carta (rock, paper ou scissors)" +
-			"\n/cards - Mostra suas cartas atuais" +
-			"\n/buy - Compra um novo pacote de cartas" +
+			"\n/ready [ids] e /unready - Confirma (ou cancela) que você está pronto; a partida começa quando os dois jogadores estiverem prontos (nas salas com aposta de cartas, os IDs escolhem as cartas apostadas)" +
+			"\n/rematch e /decline - Aceita ou recusa uma revanche depois do fim da partida" +
+			"\n/play <carta|id> - Joga uma carta da sua mão, pelo tipo ou pelo ID" +
+			"\n/hand - Mostra as cartas da sua mão na partida" +
+			"\n/games [-all] | <id_da_sala> - Lista as partidas por correspondência que aguardam a sua jogada ou volta a uma delas" +
+			"\n/rules [-all] - Mostra as regras da sala atual (ou todas as regras do servidor)" +
+			"\n/cards [usuario] - Mostra a sua coleção de cartas (ou a de outro usuário), com IDs e habilidades" +
+			"\n/deck save <nome> <ids...> | list | use [nome] - Salva, lista ou escolhe o baralho das partidas" +
+			"\n/buy [pacote] - Compra um pacote de cartas para a sua coleção, pago com moedas (padrão: basic)" +
+			"\n/store - Mostra os pacotes da loja, com preços, chances de raridade e garantias" +
+			"\n/fair [seed <semente> | rotate [semente]] - Mostra ou troca as sementes do sorteio dos pacotes, revelando a semente em uso" +
+			"\n/fair history | verify <id> - Lista os pacotes abertos ou confere um pacote com a semente revelada" +
+			"\n/balance - Mostra o seu saldo de moedas e de pó de criação e os lançamentos recentes" +
+			"\n/craft [recipes | <ids>] - Lista as receitas ou funde cartas repetidas em uma carta com mais estrelas" +
+			"\n/disenchant <ids> - Desfaz cartas da sua coleção em pó de criação" +
+			"\n/trade offer <usuario> <seus ids|-> [for <ids dele>] [-minutes <n>] - Propõe uma troca de cartas (IDs separados por vírgula)" +
+			"\n/trade list | accept <id> | decline <id> | cancel <id> - Lista, aceita, recusa ou cancela propostas de troca" +
+			"\n/market [list] [tipo] [estrelas|min-max] - Busca anúncios do mercado por tipo de carta e faixa de estrelas" +
+			"\n/market sell <ids> <preco> [-auction] [-minutes <n>] - Anuncia cartas por um preço fixo ou em leilão" +
+			"\n/market buy <id> | bid <id> <valor> | cancel <id> | mine - Compra, dá um lance, retira um anúncio ou lista os seus" +
+			"\n/replays - Lista as partidas que você jogou ou assistiu" +
+			"\n/replay <id> | next | prev | stop - Assiste ao registro de uma partida, rodada a rodada" +
+			"\n/tournament create [-roundrobin] [-rules <regras>] <tamanho> [nome] - Cria um torneio (eliminação simples ou todos contra todos)" +
+			"\n/tournament list | show <id> | join <id> | leave <id> | start <id> - Lista, mostra, entra, sai ou inicia um torneio" +
 			"\n/whoami - Exibe informações do usuário logado" +
 			"\n/whereami - Exibe a sala em que você está" +
 			"\n/ping - Verifica a conexão com o servidor" +
@@ -117,6 +181,26 @@ func main() {
 
 	serverRouter := application.NewServerRouter(client, chat)
 	serverRouter.AddRoute("opponent_played", handlers.HandleOpponentPlayed)
+	serverRouter.AddRoute("round_result", handlers.HandleRoundResult)
+	serverRouter.AddRoute("opponent_revealed", handlers.HandleOpponentRevealed)
+	serverRouter.AddRoute("room_event", handlers.HandleRoomEvent)
+	serverRouter.AddRoute("match_started", handlers.HandleMatchStarted)
+	serverRouter.AddRoute("match_finished", handlers.HandleMatchFinished)
+	serverRouter.AddRoute("wager_settled", handlers.HandleWagerSettled)
+	serverRouter.AddRoute("hand", handlers.HandleHandUpdate)
+	serverRouter.AddRoute("my_games", handlers.HandleMyGames)
+	serverRouter.AddRoute("dm", handlers.HandleDirectMessage)
+	serverRouter.AddRoute("dm_inbox", handlers.HandleDirectInbox)
+	serverRouter.AddRoute("channel_message", handlers.HandleChannelMessage)
+	serverRouter.AddRoute("channel_event", handlers.HandleChannelEvent)
+	serverRouter.AddRoute("committed", handlers.HandleCommitted)
+	serverRouter.AddRoute("tournament_update", handlers.HandleTournamentUpdate)
+	serverRouter.AddRoute("tournament_match", handlers.HandleTournamentMatch)
+	serverRouter.AddRoute("coins", handlers.HandleCoins)
+	serverRouter.AddRoute("trade_update", handlers.HandleTradeUpdate)
+	serverRouter.AddRoute("challenge_received", handlers.HandleChallengeReceived)
+	serverRouter.AddRoute("challenge_update", handlers.HandleChallengeUpdate)
+	serverRouter.AddRoute("market_update", handlers.HandleMarketUpdate)
 	serverRouter.Start()
 
 	// Mantém a goroutine principal viva aguardando o sinal de conclusão do chat.
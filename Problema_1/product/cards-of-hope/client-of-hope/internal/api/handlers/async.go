@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"fmt"
+	"time"
+)
+
+// asyncNotice explica como funcionam as partidas por correspondência; recebe o prazo de cada rodada em horas.
+const asyncNotice = "This is a correspondence room: each round has a %d hour deadline and the match goes on while you are offline. Whoever misses the deadline loses the match."
+
+// HandleGames lista as partidas por correspondência em andamento e volta a uma delas.
+//
+// Uso: /games [-all] ou /games <id_da_sala>
+//
+// Sem argumentos, lista só as partidas que aguardam a sua jogada; -all inclui as que aguardam o
+// oponente. Com o ID de uma sala, deixa a partida por correspondência atual e abre a escolhida.
+func HandleGames(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to see your correspondence games."
+		return
+	}
+	if len(args) > 1 {
+		chat.Outputs <- "Usage: /games [-all] or /games <room_id>"
+		return
+	}
+	if len(args) == 1 && args[0] != "-all" {
+		openGame(client, chat, args[0])
+		return
+	}
+
+	all := len(args) == 1
+	response, err := client.DoRequest(protocol.Request{
+		Method: "my_games",
+		Data:   utils.Dict{"user_id": state.UserID, "all": all},
+	})
+	if err != nil {
+		state.Log("My games request failed: %v", err)
+		chat.Outputs <- "Failed to list your games."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	games, _ := response.Data["games"].([]any)
+	total, _ := response.Data["total"].(float64)
+	if len(games) == 0 {
+		if total > 0 {
+			chat.Outputs <- fmt.Sprintf("No game is waiting on you. %d correspondence game(s) are waiting on your opponents; use /games -all to see them.", int(total))
+		} else {
+			chat.Outputs <- "You have no correspondence games in progress. Create one with /create -async."
+		}
+		return
+	}
+	showGames(chat, games)
+}
+
+// HandleMyGames avisa, logo após o login, das partidas por correspondência que aguardam a sua jogada.
+func HandleMyGames(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	games, _ := response.Data["games"].([]any)
+	if len(games) == 0 {
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("%d correspondence game(s) are waiting on you:", len(games))
+	showGames(chat, games)
+}
+
+// openGame volta a uma partida por correspondência, deixando de lado a partida por correspondência atual.
+func openGame(client *api.Client, chat *ui.Chat, roomID string) {
+	if state.RoomID == roomID {
+		chat.Outputs <- "You are already in that room."
+		return
+	}
+	if state.RoomID != "" {
+		if !state.RoomAsync || !state.InMatch || state.Spectating {
+			chat.Outputs <- "You must leave your current room before opening another game."
+			return
+		}
+		clearRoom()
+	}
+	joinRoom(client, chat, []string{roomID}, false)
+}
+
+// resumeMatch restaura a mão e a jogada da rodada ao voltar a uma partida em andamento.
+func resumeMatch(chat *ui.Chat, hand any, data map[string]any) {
+	drawPile, _ := data["draw_pile"].(float64)
+	resetRound()
+	state.InMatch = true
+	state.Hand = parseCards(hand)
+	state.DrawPile = int(drawPile)
+	chat.Outputs <- formatHand()
+
+	if played, ok := data["played_card"].(map[string]any); ok {
+		cardType, _ := played["type"].(string)
+		stars, _ := played["stars"].(float64)
+		ability, _ := played["ability"].(string)
+		card := state.HandCard{Type: cardType, Stars: int(stars), Ability: ability}
+		state.PlayedCard = card.Type
+		state.PlayedCardStar = card.Stars
+		chat.Outputs <- describePlay("You already played", card) + " Waiting for your opponent."
+	} else {
+		chat.Outputs <- "It is your turn. Use /play <card|id> to play."
+	}
+	if deadline, _ := data["turn_deadline"].(string); deadline != "" {
+		chat.Outputs <- "Round deadline: " + formatDeadline(deadline)
+	}
+}
+
+// showGames exibe uma linha por partida por correspondência, com o placar, a vez e o prazo da rodada.
+func showGames(chat *ui.Chat, games []any) {
+	for _, item := range games {
+		game, _ := item.(map[string]any)
+		roomID, _ := game["room_id"].(string)
+		name, _ := game["name"].(string)
+		opponent, _ := game["opponent"].(string)
+		round, _ := game["round"].(float64)
+		scores, _ := game["scores"].(map[string]any)
+		deadline, _ := game["turn_deadline"].(string)
+
+		turn := "waiting on " + opponent
+		if yourTurn, _ := game["your_turn"].(bool); yourTurn {
+			turn = "your turn"
+		}
+		line := fmt.Sprintf("  [%s] %s vs %s - round %d, %s, %s", roomID, name, opponent, int(round), formatScores(scores), turn)
+		if deadline != "" {
+			line += ", deadline " + formatDeadline(deadline)
+		}
+		chat.Outputs <- line
+
+		if last, ok := game["last_round"].(map[string]any); ok {
+			winnerID, _ := last["winner_id"].(string)
+			lastRound, _ := last["round"].(float64)
+			switch winnerID {
+			case "":
+				chat.Outputs <- fmt.Sprintf("      round %d was a tie", int(lastRound))
+			case state.UserID:
+				chat.Outputs <- fmt.Sprintf("      you won round %d", int(lastRound))
+			default:
+				chat.Outputs <- fmt.Sprintf("      %s won round %d", winnerID, int(lastRound))
+			}
+		}
+	}
+	chat.Outputs <- "Use /games <room_id> to open a game."
+}
+
+// formatDeadline exibe o prazo de uma rodada no horário local, com o tempo que falta.
+func formatDeadline(deadline string) string {
+	at, err := time.Parse(time.RFC3339, deadline)
+	if err != nil {
+		return deadline
+	}
+	left := time.Until(at).Round(time.Minute)
+	if left < 0 {
+		left = 0
+	}
+	return fmt.Sprintf("%s (%s left)", at.Local().Format("2006-01-02 15:04"), left)
+}
@@ -7,6 +7,7 @@ import (
 	"client-of-hope/internal/state"
 	"client-of-hope/internal/ui"
 	"client-of-hope/internal/utils"
+	"fmt"
 )
 
 // HandleRegister processa o comando de registro de novo usuário.
@@ -100,6 +101,10 @@ func HandleLogin(client *api.Client, chat *ui.Chat, args []string) {
 	state.Username = username
 	state.UserID = userID
 	chat.Outputs <- "Login realizado com sucesso como " + username
+	if channel, _ := response.Data["channel"].(string); channel != "" {
+		enterChannel(channel)
+		chat.Outputs <- fmt.Sprintf("You are in the #%s channel. %s", channel, channelHint())
+	}
 }
 
 // HandleLogout processa o comando de logout do usuário.
@@ -123,5 +128,6 @@ func HandleLogout(client *api.Client, chat *ui.Chat, args []string) {
 	}
 	state.Username = ""
 	state.UserID = ""
+	clearChannels()
 	chat.Outputs <- "Logout realizado com sucesso."
 }
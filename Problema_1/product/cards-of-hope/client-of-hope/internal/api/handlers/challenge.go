@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// challengeUsage resume os subcomandos de /challenge.
+const challengeUsage = "Usage: /challenge <user> [-rules <ruleset>] [-commit] | list | accept [id] | decline [id] | cancel <id>"
+
+// HandleChallenge desafia outro usuário conectado para um duelo e responde os desafios recebidos.
+//
+// Uso: /challenge <usuário> [-rules <regras>] [-commit] | list | accept [id] | decline [id] | cancel <id>
+//
+// Sem ID, accept e decline respondem o último desafio recebido. No aceite, o servidor cria uma
+// sala privada para os dois e o usuário sai da sala em que estava.
+func HandleChallenge(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to challenge other users."
+		return
+	}
+	if len(args) == 0 {
+		chat.Outputs <- challengeUsage
+		return
+	}
+
+	command := strings.ToLower(args[0])
+	switch command {
+	case "list":
+		listChallenges(client, chat)
+	case "accept", "decline":
+		challengeID := state.LastChallengeID
+		if len(args) > 1 {
+			challengeID = args[1]
+		}
+		if challengeID == "" || len(args) > 2 {
+			chat.Outputs <- fmt.Sprintf("Usage: /challenge %s [id]", command)
+			return
+		}
+		answerChallenge(client, chat, command, challengeID)
+	case "cancel":
+		if len(args) != 2 {
+			chat.Outputs <- "Usage: /challenge cancel <id>"
+			return
+		}
+		answerChallenge(client, chat, command, args[1])
+	default:
+		sendChallenge(client, chat, args)
+	}
+}
+
+// sendChallenge desafia outro usuário, com as regras e o modo de jogada do duelo.
+func sendChallenge(client *api.Client, chat *ui.Chat, args []string) {
+	data := utils.Dict{"user_id": state.UserID, "target": args[0]}
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "-rules" && i+1 < len(args):
+			data["ruleset"] = strings.ToLower(args[i+1])
+			i++
+		case args[i] == "-commit":
+			data["commit_reveal"] = true
+		default:
+			chat.Outputs <- challengeUsage
+			return
+		}
+	}
+
+	response, ok := challengeRequest(client, chat, "challenge", data)
+	if !ok {
+		return
+	}
+	challenge := parseChallenge(response.Data["challenge"])
+	chat.Outputs <- fmt.Sprintf("You challenged %s to a duel [%s] with the %s rules. The challenge expires at %s; /challenge cancel %s withdraws it.",
+		challenge.ToID, challenge.ID, challenge.RulesetID, formatExpiry(challenge.ExpiresAt), challenge.ID)
+}
+
+// listChallenges mostra os desafios pendentes feitos e recebidos pelo usuário.
+func listChallenges(client *api.Client, chat *ui.Chat) {
+	response, ok := challengeRequest(client, chat, "challenges", utils.Dict{"user_id": state.UserID})
+	if !ok {
+		return
+	}
+	challenges, _ := response.Data["challenges"].([]any)
+	if len(challenges) == 0 {
+		chat.Outputs <- "No pending challenges."
+		return
+	}
+	lines := []string{"Pending challenges:"}
+	for _, item := range challenges {
+		challenge := parseChallenge(item)
+		direction := "from " + challenge.FromID
+		if challenge.FromID == state.UserID {
+			direction = "to " + challenge.ToID
+		}
+		lines = append(lines, fmt.Sprintf("  [%s] %s - %s - expires at %s", challenge.ID, direction, describeChallenge(challenge), formatExpiry(challenge.ExpiresAt)))
+	}
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// answerChallenge aceita, recusa ou cancela um desafio.
+func answerChallenge(client *api.Client, chat *ui.Chat, command, challengeID string) {
+	response, ok := challengeRequest(client, chat, "challenge_"+command, utils.Dict{"user_id": state.UserID, "challenge_id": challengeID})
+	if !ok {
+		return
+	}
+	if challengeID == state.LastChallengeID {
+		state.LastChallengeID = ""
+	}
+
+	challenge := parseChallenge(response.Data["challenge"])
+	switch command {
+	case "accept":
+		enterChallengeRoom(client, chat, challenge, response.Data)
+	case "decline":
+		chat.Outputs <- fmt.Sprintf("You declined the challenge from %s.", challenge.FromID)
+	case "cancel":
+		chat.Outputs <- fmt.Sprintf("You cancelled your challenge to %s.", challenge.ToID)
+	}
+}
+
+// challengeRequest envia uma requisição de desafio e trata as falhas.
+func challengeRequest(client *api.Client, chat *ui.Chat, method string, data utils.Dict) (protocol.Response, bool) {
+	response, err := client.DoRequest(protocol.Request{Method: method, Data: data})
+	if err != nil {
+		state.Log("Request %s failed: %v", method, err)
+		chat.Outputs <- "Failed to reach the server for the challenge."
+		return protocol.Response{}, false
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return protocol.Response{}, false
+	}
+	return response, true
+}
+
+// HandleChallengeReceived avisa um desafio recebido, com os atalhos para respondê-lo.
+func HandleChallengeReceived(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	challenge := parseChallenge(response.Data["challenge"])
+	state.LastChallengeID = challenge.ID
+	chat.Outputs <- fmt.Sprintf("%s challenges you to a duel [%s] with %s. It expires at %s. Type /challenge accept or /challenge decline.",
+		challenge.FromID, challenge.ID, describeChallenge(challenge), formatExpiry(challenge.ExpiresAt))
+}
+
+// HandleChallengeUpdate avisa as respostas aos desafios do usuário e leva quem desafiou à sala
+// do duelo aceito.
+func HandleChallengeUpdate(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	event, _ := response.Data["event"].(string)
+	challenge := parseChallenge(response.Data["challenge"])
+	if challenge.ID == state.LastChallengeID && event != "accepted" {
+		state.LastChallengeID = ""
+	}
+
+	switch event {
+	case "accepted":
+		chat.Outputs <- fmt.Sprintf("%s accepted your challenge!", challenge.ToID)
+		enterChallengeRoom(client, chat, challenge, response.Data)
+	case "declined":
+		chat.Outputs <- fmt.Sprintf("%s declined your challenge.", challenge.ToID)
+	case "cancelled":
+		chat.Outputs <- fmt.Sprintf("%s cancelled the challenge.", challenge.FromID)
+	case "expired":
+		chat.Outputs <- fmt.Sprintf("The challenge between %s and %s expired without an answer.", challenge.FromID, challenge.ToID)
+	case "disconnected":
+		chat.Outputs <- fmt.Sprintf("The challenge between %s and %s was cancelled because %s disconnected.",
+			challenge.FromID, challenge.ToID, opponentOf(challenge))
+	}
+}
+
+// enterChallengeRoom leva o usuário à sala do duelo, saindo antes da sala em que estava.
+func enterChallengeRoom(client *api.Client, chat *ui.Chat, challenge state.Challenge, data map[string]any) {
+	roomID, _ := data["room_id"].(string)
+	room, _ := data["room"].(map[string]any)
+	name, _ := room["name"].(string)
+
+	if state.RoomID != "" && state.RoomID != roomID {
+		HandleLeaveRoom(client, chat, nil)
+	}
+
+	state.RoomID = roomID
+	state.RoomName = name
+	state.Spectating = false
+	state.RoomHostID, _ = room["host_id"].(string)
+	state.InMatch = false
+	state.RoomRuleset = parseRuleset(data["ruleset"])
+	state.RoomCommitReveal, _ = room["commit_reveal"].(bool)
+
+	chat.Outputs <- fmt.Sprintf("Your duel against %s is ready in the private room '%s' (%s).", opponentOf(challenge), name, roomID)
+	chat.Outputs <- fmt.Sprintf("Ruleset: %s. Use /rules to see it.", state.RoomRuleset.Name)
+	if state.RoomCommitReveal {
+		chat.Outputs <- commitRevealNotice
+	}
+	chat.Outputs <- "Type /ready when you are ready to play."
+}
+
+// opponentOf retorna o outro usuário do desafio.
+func opponentOf(challenge state.Challenge) string {
+	if challenge.FromID == state.UserID {
+		return challenge.ToID
+	}
+	return challenge.FromID
+}
+
+// describeChallenge descreve as regras do duelo.
+func describeChallenge(challenge state.Challenge) string {
+	description := "the " + challenge.RulesetID + " rules"
+	if challenge.CommitReveal {
+		description += " and commit-reveal plays"
+	}
+	return description
+}
+
+// parseChallenge converte o desafio recebido do servidor.
+func parseChallenge(data any) state.Challenge {
+	var challenge state.Challenge
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return challenge
+	}
+	if err := json.Unmarshal(raw, &challenge); err != nil {
+		state.Log("Invalid challenge from server: %v", err)
+	}
+	return challenge
+}
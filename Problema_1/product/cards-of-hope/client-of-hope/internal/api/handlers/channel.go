@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// channelUsage resume os subcomandos de /channel.
+const channelUsage = "Usage: /channel list | join <name> | leave [name] | who [name]"
+
+// HandleChannel lista os canais de chat, entra e sai deles e mostra os seus membros.
+//
+// Uso: /channel list | join <nome> | leave [nome] | who [nome]
+//
+// join cria o canal se ele não existir e o torna o canal atual; leave e who usam o canal atual
+// quando o nome não é informado.
+func HandleChannel(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to use chat channels."
+		return
+	}
+	if len(args) == 0 {
+		chat.Outputs <- channelUsage
+		return
+	}
+
+	name := state.Channel
+	if len(args) > 1 {
+		name = strings.ToLower(strings.TrimPrefix(args[1], "#"))
+	}
+	switch args[0] {
+	case "list":
+		listChannels(client, chat)
+	case "join":
+		if len(args) != 2 {
+			chat.Outputs <- channelUsage
+			return
+		}
+		joinChannel(client, chat, name)
+	case "leave":
+		if name == "" {
+			chat.Outputs <- channelUsage
+			return
+		}
+		leaveChannel(client, chat, name)
+	case "who":
+		if name == "" {
+			chat.Outputs <- channelUsage
+			return
+		}
+		showChannelMembers(client, chat, name)
+	default:
+		chat.Outputs <- channelUsage
+	}
+}
+
+// HandleChannelSay envia uma mensagem para o canal de chat atual, mesmo de dentro de uma sala.
+//
+// Uso: /c <mensagem>
+func HandleChannelSay(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to use chat channels."
+		return
+	}
+	if state.Channel == "" {
+		chat.Outputs <- "You are not in any chat channel. Use /channel join <name> to join one."
+		return
+	}
+	if len(args) == 0 {
+		chat.Outputs <- "Usage: /c <message>"
+		return
+	}
+	sendChannelMessage(client, chat, strings.Join(args, " "))
+}
+
+// HandleChannelMessage exibe a mensagem de outro membro de um canal de chat.
+func HandleChannelMessage(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	if state.UserID == "" {
+		return
+	}
+	channel, _ := response.Data["channel"].(string)
+	from, _ := response.Data["from_id"].(string)
+	text, _ := response.Data["text"].(string)
+	chat.Outputs <- fmt.Sprintf("[#%s] %s: %s", channel, from, text)
+}
+
+// HandleChannelEvent exibe a entrada ou a saída de um usuário de um canal de chat.
+func HandleChannelEvent(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	if state.UserID == "" {
+		return
+	}
+	channel, _ := response.Data["channel"].(string)
+	event, _ := response.Data["event"].(string)
+	userID, _ := response.Data["user_id"].(string)
+	members, _ := response.Data["members"].(float64)
+
+	switch event {
+	case "joined":
+		chat.Outputs <- fmt.Sprintf("[#%s] %s joined the channel (%d members).", channel, userID, int(members))
+	case "left":
+		chat.Outputs <- fmt.Sprintf("[#%s] %s left the channel (%d members).", channel, userID, int(members))
+	case "disconnected":
+		chat.Outputs <- fmt.Sprintf("[#%s] %s disconnected (%d members).", channel, userID, int(members))
+	}
+}
+
+// enterChannel registra a entrada em um canal de chat e o torna o canal atual.
+func enterChannel(name string) {
+	if !slices.Contains(state.Channels, name) {
+		state.Channels = append(state.Channels, name)
+	}
+	state.Channel = name
+}
+
+// clearChannels esquece os canais de chat, como no logout.
+func clearChannels() {
+	state.Channel = ""
+	state.Channels = nil
+}
+
+// sendChannelMessage envia uma mensagem para o canal de chat atual.
+func sendChannelMessage(client *api.Client, chat *ui.Chat, text string) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: "channel_send",
+		Data:   utils.Dict{"user_id": state.UserID, "channel": state.Channel, "text": text},
+	})
+	if err != nil {
+		state.Log("Channel message request failed: %v", err)
+		chat.Outputs <- "Failed to send the channel message."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+	}
+}
+
+// listChannels mostra os canais de chat existentes, com a quantidade de membros.
+func listChannels(client *api.Client, chat *ui.Chat) {
+	response, err := client.DoRequest(protocol.Request{Method: "channel_list", Data: utils.Dict{"user_id": state.UserID}})
+	if err != nil {
+		state.Log("Channel list request failed: %v", err)
+		chat.Outputs <- "Failed to list chat channels."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	channels, _ := response.Data["channels"].([]any)
+	chat.Outputs <- "Chat channels:"
+	for _, item := range channels {
+		channel, _ := item.(map[string]any)
+		name, _ := channel["name"].(string)
+		members, _ := channel["members"].(float64)
+		line := fmt.Sprintf("  #%s - %d member(s)", name, int(members))
+		if joined, _ := channel["joined"].(bool); joined {
+			line += ", joined"
+		}
+		if name == state.Channel {
+			line += ", current"
+		}
+		chat.Outputs <- line
+	}
+	chat.Outputs <- "Use /channel join <name> to join or create a channel."
+}
+
+// joinChannel entra em um canal de chat, criando-o se não existir, e o torna o canal atual.
+func joinChannel(client *api.Client, chat *ui.Chat, name string) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: "channel_join",
+		Data:   utils.Dict{"user_id": state.UserID, "channel": name},
+	})
+	if err != nil {
+		state.Log("Channel join request failed: %v", err)
+		chat.Outputs <- "Failed to join the channel."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	channel, _ := response.Data["channel"].(map[string]any)
+	joined, _ := channel["name"].(string)
+	members, _ := channel["members"].([]any)
+	enterChannel(joined)
+	if created, _ := response.Data["created"].(bool); created {
+		chat.Outputs <- fmt.Sprintf("Channel #%s created. It lasts while it has members.", joined)
+	}
+	chat.Outputs <- fmt.Sprintf("#%s is now your current channel (%d members: %s). %s", joined, len(members), joinNames(members), channelHint())
+}
+
+// leaveChannel sai de um canal de chat; saindo do canal atual, o próximo canal em que o usuário está passa a ser o atual.
+func leaveChannel(client *api.Client, chat *ui.Chat, name string) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: "channel_leave",
+		Data:   utils.Dict{"user_id": state.UserID, "channel": name},
+	})
+	if err != nil {
+		state.Log("Channel leave request failed: %v", err)
+		chat.Outputs <- "Failed to leave the channel."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	state.Channels = slices.DeleteFunc(state.Channels, func(channel string) bool { return channel == name })
+	if state.Channel == name {
+		state.Channel = ""
+		if len(state.Channels) > 0 {
+			state.Channel = state.Channels[len(state.Channels)-1]
+		}
+	}
+	if state.Channel != "" {
+		chat.Outputs <- fmt.Sprintf("You left #%s. Your current channel is #%s.", name, state.Channel)
+	} else {
+		chat.Outputs <- fmt.Sprintf("You left #%s. You are not in any channel now; use /channel join <name> to join one.", name)
+	}
+}
+
+// showChannelMembers mostra os membros de um canal de chat.
+func showChannelMembers(client *api.Client, chat *ui.Chat, name string) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: "channel_members",
+		Data:   utils.Dict{"user_id": state.UserID, "channel": name},
+	})
+	if err != nil {
+		state.Log("Channel members request failed: %v", err)
+		chat.Outputs <- "Failed to list the channel members."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	channel, _ := response.Data["channel"].(map[string]any)
+	members, _ := channel["members"].([]any)
+	chat.Outputs <- fmt.Sprintf("#%s has %d member(s): %s", name, len(members), joinNames(members))
+}
+
+// channelHint explica como falar no canal atual.
+func channelHint() string {
+	if state.RoomID != "" {
+		return "Use /c <message> to talk there."
+	}
+	return "Type a message to talk there, or use /c <message> from inside a room."
+}
@@ -11,6 +11,10 @@ import (
 )
 
 func HandleSendMessage(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID != "" && state.RoomID == "" && state.Channel != "" && len(args) > 0 {
+		sendChannelMessage(client, chat, strings.Join(args, " ")) // Fora de uma sala, fala no canal atual
+		return
+	}
 	if state.UserID == "" || state.RoomID == "" {
 		chat.Outputs <- "You must be logged in and in a room to send messages."
 		return
@@ -37,6 +41,41 @@ func HandleSendMessage(client *api.Client, chat *ui.Chat, args []string) {
 	}
 }
 
+// HandleSpectatorMessage envia uma mensagem apenas para os espectadores da sala atual.
+//
+// Uso: /sc <mensagem>
+func HandleSpectatorMessage(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" || state.RoomID == "" || !state.Spectating {
+		chat.Outputs <- "You must be spectating a room to use the spectator chat."
+		return
+	}
+	if len(args) < 1 {
+		chat.Outputs <- "Usage: /sc <message>"
+		return
+	}
+
+	request := protocol.Request{
+		Method: "send",
+		Data: utils.Dict{
+			"user_id": state.UserID,
+			"room_id": state.RoomID,
+			"channel": "spectators",
+			"message": fmt.Sprintf("[spectators] %s: %s", state.Username, strings.Join(args, " ")),
+		},
+	}
+
+	response, err := client.DoRequest(request)
+	if err != nil {
+		state.Log("Send spectator message request failed: %v", err)
+		chat.Outputs <- "Failed to send message."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+	}
+}
+
 func HandleFetchMessage(client *api.Client, chat *ui.Chat, args []string) {
 	if state.UserID == "" || state.RoomID == "" {
 		return // Don't show any error, just fail silently
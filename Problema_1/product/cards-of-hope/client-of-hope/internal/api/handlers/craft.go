@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HandleCraft lista as receitas de criação ou funde cartas repetidas em uma carta com mais estrelas.
+//
+// Uso: /craft [recipes | <ids>]
+//
+// Os IDs das cartas são separados por vírgula; todas precisam ter o mesmo tipo e as estrelas de uma receita.
+func HandleCraft(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to craft cards."
+		return
+	}
+	if len(args) == 0 || strings.ToLower(args[0]) == "recipes" {
+		showRecipes(client, chat)
+		return
+	}
+	if len(args) != 1 {
+		chat.Outputs <- "Usage: /craft [recipes | <ids>]"
+		return
+	}
+
+	response, ok := craftRequest(client, chat, "craft", args[0])
+	if !ok {
+		return
+	}
+	crafted := parseCards([]any{response.Data["card"]})
+	dust, _ := response.Data["dust"].(float64)
+	chat.Outputs <- fmt.Sprintf("You fused %s into %s. Crafting dust left: %d.",
+		formatCards(parseCards(response.Data["consumed"])), formatCards(crafted), int(dust))
+}
+
+// HandleDisenchant desfaz cartas da coleção em pó de criação.
+//
+// Uso: /disenchant <ids>
+//
+// Os IDs das cartas são separados por vírgula.
+func HandleDisenchant(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to disenchant cards."
+		return
+	}
+	if len(args) != 1 {
+		chat.Outputs <- "Usage: /disenchant <ids>"
+		return
+	}
+
+	response, ok := craftRequest(client, chat, "disenchant", args[0])
+	if !ok {
+		return
+	}
+	gained, _ := response.Data["gained"].(float64)
+	dust, _ := response.Data["dust"].(float64)
+	chat.Outputs <- fmt.Sprintf("You disenchanted %s for %d crafting dust. Crafting dust: %d.",
+		formatCards(parseCards(response.Data["consumed"])), int(gained), int(dust))
+}
+
+// showRecipes mostra as receitas de criação, o valor em pó das cartas e o pó do usuário.
+func showRecipes(client *api.Client, chat *ui.Chat) {
+	response, err := client.DoRequest(protocol.Request{Method: "recipes", Data: utils.Dict{"user_id": state.UserID}})
+	if err != nil {
+		state.Log("Recipes request failed: %v", err)
+		chat.Outputs <- "Failed to get the crafting recipes."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	dust, _ := response.Data["dust"].(float64)
+	lines := []string{fmt.Sprintf("Crafting recipes (you have %d dust):", int(dust))}
+	recipes, _ := response.Data["recipes"].([]any)
+	for _, item := range recipes {
+		recipe, _ := item.(map[string]any)
+		stars, _ := recipe["stars"].(float64)
+		cards, _ := recipe["cards"].(float64)
+		result, _ := recipe["result_stars"].(float64)
+		cost, _ := recipe["dust"].(float64)
+		lines = append(lines, fmt.Sprintf("  %d cards of the same type with %d stars + %d dust -> 1 card with %d stars",
+			int(cards), int(stars), int(cost), int(result)))
+	}
+
+	perStar, _ := response.Data["dust_per_star"].(float64)
+	ability, _ := response.Data["ability_dust"].(float64)
+	rarityDust, _ := response.Data["rarity_dust"].(map[string]any)
+	rarities := make([]string, 0, len(rarityDust))
+	for rarity, value := range rarityDust {
+		if amount, _ := value.(float64); amount > 0 {
+			rarities = append(rarities, fmt.Sprintf("+%d if %s", int(amount), rarity))
+		}
+	}
+	sort.Strings(rarities)
+	lines = append(lines,
+		fmt.Sprintf("Disenchanting gives %d dust per star, +%d if the card has an ability, %s.", int(perStar), int(ability), strings.Join(rarities, ", ")),
+		"The crafted card keeps the rarest rarity and the first ability among the fused cards. Use /cards to see the IDs.")
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// craftRequest envia uma requisição de criação ou desmanche com os IDs das cartas.
+func craftRequest(client *api.Client, chat *ui.Chat, method, cardIDs string) (protocol.Response, bool) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: method,
+		Data:   utils.Dict{"user_id": state.UserID, "card_ids": splitCardIDs(cardIDs)},
+	})
+	if err != nil {
+		state.Log("Request %s failed: %v", method, err)
+		chat.Outputs <- "Failed to reach the server for crafting."
+		return protocol.Response{}, false
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return protocol.Response{}, false
+	}
+	return response, true
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// deckUsage resume os subcomandos de /deck.
+const deckUsage = "Usage: /deck save <name> <card ids...> | /deck list | /deck use [name]"
+
+// HandleDeck gerencia os baralhos salvos do usuário.
+//
+// Uso: /deck save <nome> <ids das cartas...> | /deck list | /deck use [nome]
+//
+// Os IDs são os mostrados por /cards. /deck use escolhe o baralho para as próximas partidas da
+// sala atual; sem nome, volta ao baralho básico das regras da sala.
+func HandleDeck(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to manage your decks."
+		return
+	}
+	if len(args) == 0 {
+		chat.Outputs <- deckUsage
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "save":
+		saveDeck(client, chat, args[1:])
+	case "list":
+		listDecks(client, chat)
+	case "use":
+		selectDeck(client, chat, args[1:])
+	default:
+		chat.Outputs <- deckUsage
+	}
+}
+
+// saveDeck salva um baralho com as cartas da coleção informadas.
+func saveDeck(client *api.Client, chat *ui.Chat, args []string) {
+	if len(args) < 2 {
+		chat.Outputs <- "Usage: /deck save <name> <card ids...>"
+		return
+	}
+	name, cardIDs := args[0], args[1:]
+
+	response, err := client.DoRequest(protocol.Request{
+		Method: "deck_save",
+		Data:   utils.Dict{"user_id": state.UserID, "name": name, "card_ids": cardIDs},
+	})
+	if err != nil {
+		state.Log("Deck save request failed: %v", err)
+		chat.Outputs <- "Failed to save the deck."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("Deck '%s' saved. Use /deck use %s in a room to play with it.", name, name)
+}
+
+// listDecks mostra os baralhos salvos do usuário com suas cartas.
+func listDecks(client *api.Client, chat *ui.Chat) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: "deck_list",
+		Data:   utils.Dict{"user_id": state.UserID},
+	})
+	if err != nil {
+		state.Log("Deck list request failed: %v", err)
+		chat.Outputs <- "Failed to list your decks."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	var decks []struct {
+		Name  string           `json:"name"`
+		Cards []state.HandCard `json:"cards"`
+	}
+	raw, _ := json.Marshal(response.Data["decks"])
+	if err := json.Unmarshal(raw, &decks); err != nil {
+		state.Log("Invalid decks from server: %v", err)
+	}
+	if len(decks) == 0 {
+		chat.Outputs <- "You have no saved decks. Use /deck save <name> <card ids...>."
+		return
+	}
+	for _, deck := range decks {
+		cards := make([]string, 0, len(deck.Cards))
+		for _, card := range deck.Cards {
+			cards = append(cards, formatCard(card))
+		}
+		chat.Outputs <- fmt.Sprintf("Deck '%s': %s", deck.Name, strings.Join(cards, ", "))
+	}
+}
+
+// selectDeck escolhe o baralho do jogador para as próximas partidas da sala atual.
+func selectDeck(client *api.Client, chat *ui.Chat, args []string) {
+	if state.RoomID == "" || state.Spectating {
+		chat.Outputs <- "You must be playing in a room to choose a deck."
+		return
+	}
+	if len(args) > 1 {
+		chat.Outputs <- "Usage: /deck use [name]"
+		return
+	}
+	name := ""
+	if len(args) == 1 {
+		name = args[0]
+	}
+
+	response, err := client.DoRequest(protocol.Request{
+		Method: "deck_select",
+		Data:   utils.Dict{"user_id": state.UserID, "room_id": state.RoomID, "name": name},
+	})
+	if err != nil {
+		state.Log("Deck select request failed: %v", err)
+		chat.Outputs <- "Failed to choose the deck."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+	if name == "" {
+		chat.Outputs <- "You will play the next match with the basic deck of this room's rules."
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("You will play the next match with the deck '%s'.", name)
+}
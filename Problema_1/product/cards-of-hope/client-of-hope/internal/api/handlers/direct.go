@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HandleMsg envia uma mensagem direta para outro usuário ou mostra a conversa com ele.
+//
+// Uso: /msg <usuario> <mensagem> ou /msg <usuario>
+//
+// As mensagens diretas aparecem no painel próprio, acima do chat da sala. Sem mensagem, o
+// comando mostra as mensagens mais recentes da conversa com o usuário.
+func HandleMsg(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to send direct messages."
+		return
+	}
+	if len(args) == 0 {
+		chat.Outputs <- "Usage: /msg <user> <message> or /msg <user> to see your conversation"
+		return
+	}
+	if len(args) == 1 {
+		showConversation(client, chat, args[0])
+		return
+	}
+
+	to := args[0]
+	response, err := client.DoRequest(protocol.Request{
+		Method: "dm",
+		Data:   utils.Dict{"user_id": state.UserID, "to": to, "text": strings.Join(args[1:], " ")},
+	})
+	if err != nil {
+		state.Log("Direct message request failed: %v", err)
+		chat.Outputs <- "Failed to send the direct message."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	message, _ := response.Data["direct_message"].(map[string]any)
+	chat.Direct <- formatDirectMessage(message)
+	if delivered, _ := response.Data["delivered"].(bool); !delivered {
+		chat.Direct <- fmt.Sprintf("  %s is offline; the message will be delivered when they log in.", to)
+	}
+}
+
+// HandleDirectMessage exibe no painel de mensagens diretas uma mensagem recebida.
+func HandleDirectMessage(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	chat.Direct <- formatDirectMessage(response.Data)
+}
+
+// HandleDirectInbox exibe, logo após o login, as mensagens diretas recebidas enquanto o usuário estava desconectado.
+func HandleDirectInbox(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	messages, _ := response.Data["messages"].([]any)
+	if len(messages) == 0 {
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("You have %d direct message(s) received while you were away.", len(messages))
+	chat.Direct <- fmt.Sprintf("%d message(s) received while you were away:", len(messages))
+	for _, item := range messages {
+		message, _ := item.(map[string]any)
+		chat.Direct <- formatDirectMessage(message)
+	}
+}
+
+// showConversation mostra no painel de mensagens diretas a conversa recente com um usuário.
+func showConversation(client *api.Client, chat *ui.Chat, with string) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: "dm_history",
+		Data:   utils.Dict{"user_id": state.UserID, "with": with},
+	})
+	if err != nil {
+		state.Log("Direct message history request failed: %v", err)
+		chat.Outputs <- "Failed to load the conversation."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	messages, _ := response.Data["messages"].([]any)
+	if len(messages) == 0 {
+		chat.Direct <- fmt.Sprintf("No messages with %s yet. Use /msg %s <message> to start the conversation.", with, with)
+		return
+	}
+	chat.Direct <- fmt.Sprintf("Conversation with %s:", with)
+	for _, item := range messages {
+		message, _ := item.(map[string]any)
+		chat.Direct <- formatDirectMessage(message)
+	}
+}
+
+// formatDirectMessage descreve uma mensagem direta com o horário local, o remetente e o destinatário.
+func formatDirectMessage(message map[string]any) string {
+	from, _ := message["from_id"].(string)
+	to, _ := message["to_id"].(string)
+	text, _ := message["text"].(string)
+	sentAt, _ := message["sent_at"].(string)
+
+	when := sentAt
+	if moment, err := time.Parse(time.RFC3339, sentAt); err == nil {
+		when = moment.Local().Format("01-02 15:04")
+	}
+	if from == state.UserID {
+		return fmt.Sprintf("[%s] you -> %s: %s", when, to, text)
+	}
+	return fmt.Sprintf("[%s] %s: %s", when, from, text)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// fairUsage resume os subcomandos de /fair.
+const fairUsage = "Usage: /fair [seed <client seed> | rotate [client seed] | history | verify <id>]"
+
+// HandleFair mostra e troca as sementes do sorteio verificável dos pacotes e confere pacotes já
+// abertos.
+//
+// Uso: /fair [seed <semente> | rotate [semente] | history | verify <id>]
+//
+// Sem subcomando, mostra o hash da semente do servidor em uso, a semente do usuário e o próximo nonce.
+func HandleFair(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to check your pack seeds."
+		return
+	}
+
+	command := ""
+	if len(args) > 0 {
+		command, args = strings.ToLower(args[0]), args[1:]
+	}
+	switch command {
+	case "":
+		showSeed(client, chat, utils.Dict{"user_id": state.UserID})
+	case "seed":
+		if len(args) == 0 {
+			chat.Outputs <- "Usage: /fair seed <client seed>"
+			return
+		}
+		showSeed(client, chat, utils.Dict{"user_id": state.UserID, "client_seed": strings.Join(args, " ")})
+	case "rotate":
+		rotateSeed(client, chat, strings.Join(args, " "))
+	case "history":
+		packHistory(client, chat)
+	case "verify":
+		if len(args) != 1 {
+			chat.Outputs <- "Usage: /fair verify <id>"
+			return
+		}
+		verifyPack(client, chat, args[0])
+	default:
+		chat.Outputs <- fairUsage
+	}
+}
+
+// showSeed mostra a semente em uso, trocando antes a semente do usuário se ela vier nos dados.
+func showSeed(client *api.Client, chat *ui.Chat, data utils.Dict) {
+	response, ok := fairRequest(client, chat, "pack_seed", data)
+	if !ok {
+		return
+	}
+	seed := parseSeed(response.Data["seed"])
+	if _, changed := data["client_seed"]; changed {
+		chat.Outputs <- fmt.Sprintf("Client seed set to %q for your next packages.", seed.ClientSeed)
+	}
+	chat.Outputs <- formatActiveSeed(seed)
+}
+
+// rotateSeed revela a semente em uso, confere o hash publicado e mostra a nova semente.
+func rotateSeed(client *api.Client, chat *ui.Chat, clientSeed string) {
+	data := utils.Dict{"user_id": state.UserID}
+	if clientSeed != "" {
+		data["client_seed"] = clientSeed
+	}
+	response, ok := fairRequest(client, chat, "pack_seed_rotate", data)
+	if !ok {
+		return
+	}
+	revealed := parseSeed(response.Data["revealed"])
+	chat.Outputs <- fmt.Sprintf("Seed %s revealed after %d packages: %s. %s",
+		revealed.ID, revealed.Nonce, revealed.ServerSeed, checkSeedHash(revealed))
+	chat.Outputs <- formatActiveSeed(parseSeed(response.Data["seed"]))
+}
+
+// packHistory lista os pacotes abertos mais recentes, com a semente e o nonce de cada um.
+func packHistory(client *api.Client, chat *ui.Chat) {
+	response, ok := fairRequest(client, chat, "pack_history", utils.Dict{"user_id": state.UserID})
+	if !ok {
+		return
+	}
+	openings, _ := response.Data["openings"].([]any)
+	if len(openings) == 0 {
+		chat.Outputs <- "You have not opened any packages yet."
+		return
+	}
+	lines := []string{"Opened packages:"}
+	for _, item := range openings {
+		opening := parseOpening(item)
+		lines = append(lines, fmt.Sprintf("  [%s] %s - seed %s, nonce %d - %s - %s",
+			opening.ID, opening.Pack, opening.SeedID, opening.Nonce, formatListingCards(opening.Cards), formatExpiry(opening.CreatedAt)))
+	}
+	lines = append(lines, "Use /fair rotate to reveal the seed in use and /fair verify <id> to check a package.")
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// verifyPack pede ao servidor que recalcule um pacote com a semente revelada e confere o hash.
+func verifyPack(client *api.Client, chat *ui.Chat, openingID string) {
+	response, ok := fairRequest(client, chat, "verify_pack", utils.Dict{"user_id": state.UserID, "opening_id": openingID})
+	if !ok {
+		return
+	}
+	opening := parseOpening(response.Data["opening"])
+	seed := parseSeed(response.Data["seed"])
+	verified, _ := response.Data["verified"].(bool)
+
+	lines := []string{
+		fmt.Sprintf("Package %s (%s): server seed %s, client seed %q, nonce %d.", opening.ID, opening.Pack, seed.ServerSeed, opening.ClientSeed, opening.Nonce),
+		"  " + checkSeedHash(seed),
+		"  Received:   " + formatListingCards(opening.Cards),
+		"  Recomputed: " + formatListingCards(parseCards(response.Data["cards"])),
+	}
+	if verified {
+		lines = append(lines, "The package matches the draw from the revealed seed.")
+	} else {
+		lines = append(lines, "WARNING: the package does not match the draw from the revealed seed.")
+	}
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// fairRequest envia uma requisição do sorteio verificável e trata as falhas.
+func fairRequest(client *api.Client, chat *ui.Chat, method string, data utils.Dict) (protocol.Response, bool) {
+	response, err := client.DoRequest(protocol.Request{Method: method, Data: data})
+	if err != nil {
+		state.Log("Request %s failed: %v", method, err)
+		chat.Outputs <- "Failed to reach the server for the pack seeds."
+		return protocol.Response{}, false
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return protocol.Response{}, false
+	}
+	return response, true
+}
+
+// formatActiveSeed descreve a semente em uso e guarda o hash publicado para conferi-lo depois.
+func formatActiveSeed(seed state.PackSeed) string {
+	state.SeedHashes[seed.ID] = seed.Hash
+	return fmt.Sprintf("Seed %s in use: server seed hash %s, client seed %q, next nonce %d.", seed.ID, seed.Hash, seed.ClientSeed, seed.Nonce)
+}
+
+// checkSeedHash confere, no próprio cliente, se a semente revelada tem o hash publicado.
+func checkSeedHash(seed state.PackSeed) string {
+	sum := sha256.Sum256([]byte(seed.ServerSeed))
+	hash := hex.EncodeToString(sum[:])
+	published, seen := state.SeedHashes[seed.ID]
+	switch {
+	case hash != seed.Hash || (seen && hash != published):
+		return "WARNING: the revealed seed does not match the published hash."
+	case seen:
+		return "Its SHA-256 matches the hash published before you opened the packages."
+	default:
+		return "Its SHA-256 matches the hash " + seed.Hash + "."
+	}
+}
+
+// parseSeed converte a semente recebida do servidor.
+func parseSeed(data any) state.PackSeed {
+	var seed state.PackSeed
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return seed
+	}
+	if err := json.Unmarshal(raw, &seed); err != nil {
+		state.Log("Invalid pack seed from server: %v", err)
+	}
+	return seed
+}
+
+// parseOpening converte o pacote aberto recebido do servidor.
+func parseOpening(data any) state.PackOpening {
+	var opening state.PackOpening
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return opening
+	}
+	if err := json.Unmarshal(raw, &opening); err != nil {
+		state.Log("Invalid opened package from server: %v", err)
+	}
+	return opening
+}
@@ -5,34 +5,184 @@ import (
 	"client-of-hope/internal/api/protocol"
 	"client-of-hope/internal/state"
 	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
 	"fmt"
 	"strings"
 )
 
 func HandleCards(client *api.Client, chat *ui.Chat, args []string) {
-	var cardList []string
-	state.Cards.ForEach(func(card string, stars int) {
-		cardList = append(cardList, fmt.Sprintf("%s (%d stars)", card, stars))
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to see your cards."
+		return
+	}
+	data := utils.Dict{"user_id": state.UserID}
+	if len(args) > 0 {
+		data["owner_id"] = args[0] // A coleção de outro usuário, para pedir cartas em uma troca
+	}
+
+	response, err := client.DoRequest(protocol.Request{
+		Method: "collection",
+		Data:   data,
 	})
+	if err != nil {
+		state.Log("Collection request failed: %v", err)
+		chat.Outputs <- "Failed to get your cards."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
 
-	chat.Outputs <- "Your cards: " + strings.Join(cardList, ", ")
+	cards := parseCards(response.Data["cards"])
+	cardList := make([]string, 0, len(cards))
+	for _, card := range cards {
+		cardList = append(cardList, formatCard(card))
+	}
+	if len(args) > 0 {
+		chat.Outputs <- fmt.Sprintf("%s's collection (%d cards): %s", args[0], len(cards), strings.Join(cardList, ", "))
+		chat.Outputs <- fmt.Sprintf("Use the IDs in brackets to ask for cards with /trade offer %s.", args[0])
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("Your collection (%d cards): %s", len(cards), strings.Join(cardList, ", "))
+	chat.Outputs <- "Use the IDs in brackets with /deck save or /trade offer."
+}
+
+// HandleHand mostra as cartas na mão do jogador durante a partida.
+func HandleHand(client *api.Client, chat *ui.Chat, args []string) {
+	if !state.InMatch || state.Spectating {
+		chat.Outputs <- "You only have a hand while playing a match."
+		return
+	}
+	chat.Outputs <- formatHand()
 }
 
 func HandlePlay(client *api.Client, chat *ui.Chat, args []string) {
-	cardToPlay, stars, ok := validatePlay(chat, args)
+	card, ok := validatePlay(chat, args)
 	if !ok {
 		return
 	}
 
-	if !playCard(client, chat, cardToPlay, stars) {
+	if !playCard(client, chat, card) {
 		return
 	}
 }
 
+// HandleReady informa ao servidor que o jogador está pronto para a partida. A partida
+// começa quando os dois jogadores da sala estiverem prontos.
+//
+// Uso: /ready [ids]
+//
+// Nas salas que apostam cartas, os IDs (separados por espaços ou vírgulas) escolhem as cartas
+// da coleção apostadas na partida.
+func HandleReady(client *api.Client, chat *ui.Chat, args []string) {
+	setReady(client, chat, "ready", splitCardIDs(strings.Join(args, ",")))
+}
+
+// HandleUnready desfaz a confirmação de pronto antes de a partida começar.
+func HandleUnready(client *api.Client, chat *ui.Chat, args []string) {
+	setReady(client, chat, "unready", nil)
+}
+
+func setReady(client *api.Client, chat *ui.Chat, method string, stake []string) {
+	if state.UserID == "" || state.RoomID == "" {
+		chat.Outputs <- "You must be logged in and in a room to get ready."
+		return
+	}
+	if state.Spectating {
+		chat.Outputs <- "Spectators cannot play. Leave the room and /join it as a player."
+		return
+	}
+	if method == "ready" && state.RoomWagerCards > 0 && len(stake) != state.RoomWagerCards {
+		chat.Outputs <- fmt.Sprintf("This room wagers %d card(s) per player. Use /ready <ids> with the IDs from /cards.", state.RoomWagerCards)
+		return
+	}
+
+	data := utils.Dict{"user_id": state.UserID, "room_id": state.RoomID}
+	if len(stake) > 0 {
+		data["stake"] = stake
+	}
+	response, err := client.DoRequest(protocol.Request{
+		Method: method,
+		Data:   data,
+	})
+	if err != nil {
+		state.Log("%s request failed: %v", method, err)
+		chat.Outputs <- "Failed to update your ready state."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	if started, _ := response.Data["started"].(bool); !started && method == "ready" {
+		chat.Outputs <- "You are ready. Waiting for your opponent..."
+	} else if method == "unready" {
+		chat.Outputs <- "You are no longer ready."
+	}
+}
+
+// HandleRematch pede (ou aceita) uma revanche depois de uma partida encerrada. A revanche
+// começa quando os dois jogadores a pedirem dentro do prazo informado pelo servidor.
+func HandleRematch(client *api.Client, chat *ui.Chat, args []string) {
+	answerRematch(client, chat, true)
+}
+
+// HandleDecline recusa a revanche pedida pelo oponente.
+func HandleDecline(client *api.Client, chat *ui.Chat, args []string) {
+	answerRematch(client, chat, false)
+}
+
+func answerRematch(client *api.Client, chat *ui.Chat, accept bool) {
+	if state.UserID == "" || state.RoomID == "" || state.Spectating {
+		chat.Outputs <- "You must be playing in a room to answer a rematch."
+		return
+	}
+
+	response, err := client.DoRequest(protocol.Request{
+		Method: "rematch",
+		Data:   utils.Dict{"user_id": state.UserID, "room_id": state.RoomID, "accept": accept},
+	})
+	if err != nil {
+		state.Log("Rematch request failed: %v", err)
+		chat.Outputs <- "Failed to answer the rematch."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	if started, _ := response.Data["started"].(bool); started {
+		return // O match_started enviado pelo servidor avisa o início da revanche
+	}
+	if accept {
+		chat.Outputs <- "Rematch requested. Waiting for your opponent..."
+	} else {
+		chat.Outputs <- "You declined the rematch."
+	}
+}
+
 func HandleBuy(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to buy cards."
+		return
+	}
+	if len(args) > 1 {
+		chat.Outputs <- "Usage: /buy [pack]"
+		return
+	}
+	data := utils.Dict{"user_id": state.UserID}
+	if len(args) == 1 {
+		data["pack"] = strings.ToLower(args[0])
+	}
 	request := protocol.Request{
 		Method: "buy",
-		Data:   nil,
+		Data:   data,
 	}
 	response, err := client.DoRequest(request)
 	if err != nil {
@@ -45,23 +195,27 @@ func HandleBuy(client *api.Client, chat *ui.Chat, args []string) {
 		chat.Outputs <- message
 		return
 	}
-	cardPackage, _ := response.Data["package"].(map[string]any)
-	rockStarsF, _ := cardPackage["rock"].(float64)
-	paperStarsF, _ := cardPackage["paper"].(float64)
-	scissorsStarsF, _ := cardPackage["scissors"].(float64)
-	rockStars := int(rockStarsF)
-	paperStars := int(paperStarsF)
-	scissorsStars := int(scissorsStarsF)
 
-	if rockStars > 0 {
-		state.Cards.Set("rock", rockStars)
+	cards := parseCards(response.Data["cards"])
+	cardList := make([]string, 0, len(cards))
+	for _, card := range cards {
+		cardList = append(cardList, formatCard(card))
 	}
-	if paperStars > 0 {
-		state.Cards.Set("paper", paperStars)
+	pack, _ := response.Data["pack"].(string)
+	chat.Outputs <- fmt.Sprintf("You bought a %s card package: %s.", pack, strings.Join(cardList, ", "))
+	price, _ := response.Data["price"].(float64)
+	balance, _ := response.Data["balance"].(float64)
+	chat.Outputs <- fmt.Sprintf("It cost %d coins; you have %d left.", int(price), int(balance))
+	if pity, ok := response.Data["pity"].(map[string]any); ok {
+		chat.Outputs <- formatPity(pity)
 	}
-	if scissorsStars > 0 {
-		state.Cards.Set("scissors", scissorsStars)
+	opening := parseOpening(response.Data["opening"])
+	chat.Outputs <- fmt.Sprintf("Package %s was drawn from seed %s with nonce %d; /fair verify %s checks it once /fair rotate reveals the seed.",
+		opening.ID, opening.SeedID, opening.Nonce, opening.ID)
+	chat.Outputs <- "The cards were added to your collection. Use /deck save to build a deck with them."
+	for _, card := range cards {
+		if card.Ability != "" {
+			chat.Outputs <- fmt.Sprintf("Your new %s card has the ability '%s'. See /rules for what it does.", card.Type, card.Ability)
+		}
 	}
-
-	chat.Outputs <- fmt.Sprintf("You bought a card package: rock (%d stars), paper (%d stars), scissors (%d stars).", rockStars, paperStars, scissorsStars)
 }
@@ -6,6 +6,7 @@ import (
 	"client-of-hope/internal/state"
 	"client-of-hope/internal/ui"
 	"fmt"
+	"strings"
 )
 
 func HandleOpponentPlayed(client *api.Client, chat *ui.Chat, response protocol.Response) {
@@ -26,9 +27,179 @@ func HandleOpponentPlayed(client *api.Client, chat *ui.Chat, response protocol.R
 
 	state.OpponentCard = opponentCard
 	state.OpponentCardStar = int(opponentCardStar)
-	chat.Outputs <- fmt.Sprintf("Opponent played a %s card with %d stars.", state.OpponentCard, state.OpponentCardStar)
+	if ability, _ := response.Data["opponent_ability"].(string); ability != "" {
+		chat.Outputs <- fmt.Sprintf("Opponent played a %s card with %d stars and the ability %s.", state.OpponentCard, state.OpponentCardStar, abilityName(ability))
+	} else {
+		chat.Outputs <- fmt.Sprintf("Opponent played a %s card with %d stars.", state.OpponentCard, state.OpponentCardStar)
+	}
 
-	// Agora que temos as duas cartas, podemos determinar o vencedor
-	determineWinner(chat)
+	// O servidor já resolveu a rodada ao enviar a carta do oponente
+	effects, _ := response.Data["effects"].([]any)
+	showEffects(chat, effects)
+	winnerID, _ := response.Data["winner_id"].(string)
+	scores, _ := response.Data["scores"].(map[string]any)
+	showRoundResult(chat, winnerID, scores)
 	resetRound()
 }
+
+// HandleMatchStarted avisa que os dois jogadores estão prontos e a partida começou.
+func HandleMatchStarted(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	roomID, _ := response.Data["room_id"].(string)
+	if roomID != state.RoomID {
+		return
+	}
+	players, _ := response.Data["players"].([]any)
+	winningRounds, _ := response.Data["winning_rounds"].(float64)
+	maxRounds, _ := response.Data["max_rounds"].(float64)
+
+	matchNumber, _ := response.Data["match_number"].(float64)
+	series, _ := response.Data["series"].(map[string]any)
+
+	resetRound()
+	state.InMatch = true
+	state.RoomCommitReveal, _ = response.Data["commit_reveal"].(bool)
+	if matchNumber > 1 {
+		chat.Outputs <- fmt.Sprintf("Rematch! Match %d of the series (seats swapped). Series: %s", int(matchNumber), formatScores(series))
+	}
+	goal := fmt.Sprintf("First to win %d rounds wins the match", int(winningRounds))
+	if maxRounds > 0 {
+		goal += fmt.Sprintf(", within %d rounds", int(maxRounds))
+	}
+	chat.Outputs <- fmt.Sprintf("Match started: %s. %s.", joinNames(players), goal)
+	if state.RoomCommitReveal {
+		chat.Outputs <- commitRevealNotice
+	}
+	if wager, ok := response.Data["wager"].(map[string]any); ok {
+		chat.Outputs <- "Stakes held in escrow: " + formatPot(wager) + ". The winner takes them all; a draw returns them."
+	}
+	if !state.Spectating {
+		chat.Outputs <- "Use /hand to see your cards and /play <card|id> to play."
+	}
+}
+
+// HandleMatchFinished exibe o vencedor da partida e o placar final.
+func HandleMatchFinished(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	roomID, _ := response.Data["room_id"].(string)
+	if roomID != state.RoomID {
+		return
+	}
+	winnerID, _ := response.Data["winner_id"].(string)
+	scores, _ := response.Data["scores"].(map[string]any)
+
+	state.InMatch = false
+	switch {
+	case winnerID == "":
+		chat.Outputs <- "The match ended in a draw. Final score: " + formatScores(scores)
+	case state.Spectating:
+		chat.Outputs <- fmt.Sprintf("%s won the match! Final score: %s", winnerID, formatScores(scores))
+	case winnerID == state.UserID:
+		chat.Outputs <- "You won the match! Final score: " + formatScores(scores)
+	default:
+		chat.Outputs <- "You lost the match. Final score: " + formatScores(scores)
+	}
+	if series, ok := response.Data["series"].(map[string]any); ok {
+		chat.Outputs <- "Series: " + formatScores(series)
+	}
+	forfeitedBy, _ := response.Data["forfeited_by"].(string)
+	timedOut, _ := response.Data["timed_out"].(bool)
+	switch {
+	case timedOut && forfeitedBy != "":
+		chat.Outputs <- fmt.Sprintf("%s forfeited: they missed the round deadline.", forfeitedBy)
+	case timedOut:
+		chat.Outputs <- "Neither player made their play before the round deadline."
+	case forfeitedBy != "":
+		chat.Outputs <- fmt.Sprintf("%s forfeited: their reveals did not match their commitments.", forfeitedBy)
+	}
+	if replayID, _ := response.Data["replay_id"].(string); replayID != "" {
+		chat.Outputs <- fmt.Sprintf("Match recorded. Use /replay %s to watch it again.", replayID)
+	}
+	if !state.Spectating {
+		seconds, _ := response.Data["rematch_seconds"].(float64)
+		chat.Outputs <- fmt.Sprintf("Rematch? Type /rematch within %d seconds to keep the series going, or /decline.", int(seconds))
+	}
+}
+
+// HandleRoundResult exibe aos espectadores as jogadas de uma rodada encerrada.
+func HandleRoundResult(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	plays, _ := response.Data["plays"].([]any)
+	if len(plays) != 2 {
+		chat.Outputs <- "Invalid round result from server."
+		return
+	}
+
+	var descriptions []string
+	for _, item := range plays {
+		play, _ := item.(map[string]any)
+		userID, _ := play["user_id"].(string)
+		card, _ := play["card"].(string)
+		stars, _ := play["stars"].(float64)
+		description := fmt.Sprintf("%s played %s (%d stars)", userID, card, int(stars))
+		if ability, _ := play["ability"].(string); ability != "" {
+			description += " with " + abilityName(ability)
+		}
+		descriptions = append(descriptions, description)
+	}
+	chat.Outputs <- "Round result: " + strings.Join(descriptions, " vs ")
+	effects, _ := response.Data["effects"].([]any)
+	showEffects(chat, effects)
+
+	winnerID, _ := response.Data["winner_id"].(string)
+	scores, _ := response.Data["scores"].(map[string]any)
+	if winnerID == "" {
+		chat.Outputs <- "The round is a tie. Score: " + formatScores(scores)
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("%s wins the round. Score: %s", winnerID, formatScores(scores))
+}
+
+// HandleOpponentRevealed mostra a carta que o oponente acabou de jogar, revelada por uma
+// habilidade usada na rodada anterior.
+func HandleOpponentRevealed(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	roomID, _ := response.Data["room_id"].(string)
+	if roomID != state.RoomID {
+		return
+	}
+	card, _ := response.Data["card"].(string)
+	stars, _ := response.Data["stars"].(float64)
+	description := fmt.Sprintf("Revealed: your opponent played %s (%d stars)", card, int(stars))
+	if ability, _ := response.Data["ability"].(string); ability != "" {
+		description += " with " + abilityName(ability)
+	}
+	chat.Outputs <- description + ". Choose your card!"
+}
+
+// HandleHandUpdate guarda as cartas da mão enviadas pelo servidor no início da partida e depois
+// de cada rodada, quando as cartas jogadas são repostas do baralho.
+func HandleHandUpdate(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	roomID, _ := response.Data["room_id"].(string)
+	if roomID != state.RoomID {
+		return
+	}
+	drawPile, _ := response.Data["draw_pile"].(float64)
+	state.Hand = parseCards(response.Data["hand"])
+	state.DrawPile = int(drawPile)
+	chat.Outputs <- formatHand()
+	if deadline, _ := response.Data["turn_deadline"].(string); deadline != "" {
+		chat.Outputs <- "Round deadline: " + formatDeadline(deadline)
+	}
+}
+
+// commitRevealNotice explica como as jogadas funcionam em salas com compromisso e revelação.
+const commitRevealNotice = "This room uses commit-reveal plays: /play sends a commitment of your card, which is revealed automatically once your opponent commits."
+
+// HandleCommitted acompanha os compromissos da rodada e, quando os dois jogadores se
+// comprometeram, revela a carta pendente.
+func HandleCommitted(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	roomID, _ := response.Data["room_id"].(string)
+	if roomID != state.RoomID {
+		return
+	}
+	userID, _ := response.Data["user_id"].(string)
+	if reveal, _ := response.Data["reveal"].(bool); reveal {
+		revealCard(client, chat)
+		return
+	}
+	if userID != state.UserID {
+		chat.Outputs <- "Your opponent has committed to a card."
+	}
+}
@@ -6,34 +6,74 @@ import (
 	"client-of-hope/internal/state"
 	"client-of-hope/internal/ui"
 	"client-of-hope/internal/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 )
 
-func validatePlay(chat *ui.Chat, args []string) (string, int, bool) {
+func validatePlay(chat *ui.Chat, args []string) (state.HandCard, bool) {
 	if state.UserID == "" || state.RoomID == "" {
 		chat.Outputs <- "You must be logged in and in a room to play."
-		return "", 0, false
+		return state.HandCard{}, false
+	}
+	if state.Spectating {
+		chat.Outputs <- "Spectators cannot play. Leave the room and /join it as a player."
+		return state.HandCard{}, false
+	}
+	if !state.InMatch {
+		chat.Outputs <- "The match has not started yet. Use /ready when both players are in the room."
+		return state.HandCard{}, false
 	}
 	if len(args) != 1 {
-		chat.Outputs <- "Usage: /play <card>"
-		return "", 0, false
+		chat.Outputs <- "Usage: /play <card|id>"
+		return state.HandCard{}, false
 	}
 
-	cardToPlay := strings.ToLower(args[0])
-	stars, exists := state.Cards.Get(cardToPlay)
-	if !exists {
-		chat.Outputs <- fmt.Sprintf("You don't have a '%s' card.", cardToPlay)
-		return "", 0, false
+	cardRef := strings.ToLower(args[0])
+	card, inHand := findHandCard(cardRef)
+	if !inHand {
+		chat.Outputs <- fmt.Sprintf("'%s' is not in your hand. %s", cardRef, formatHand())
+		return state.HandCard{}, false
 	}
 
-	return cardToPlay, stars, true
+	return card, true
+}
+
+// findHandCard procura uma carta da mão pelo ID ou, se nenhum ID corresponder, pelo tipo,
+// escolhendo a de mais estrelas desse tipo como o servidor faz.
+func findHandCard(cardRef string) (state.HandCard, bool) {
+	var best state.HandCard
+	found := false
+	for _, card := range state.Hand {
+		if card.ID == cardRef {
+			return card, true
+		}
+		if card.Type == cardRef && (!found || card.Stars > best.Stars) {
+			best = card
+			found = true
+		}
+	}
+	return best, found
 }
 
-func playCard(client *api.Client, chat *ui.Chat, cardToPlay string, stars int) bool {
+func playCard(client *api.Client, chat *ui.Chat, card state.HandCard) bool {
+	if card.Ability != "" {
+		if _, allowed := rulesetAbility(card.Ability); !allowed {
+			chat.Outputs <- fmt.Sprintf("The ability '%s' is not used in this room's rules; playing without it.", card.Ability)
+			card.Ability = ""
+		}
+	}
+	if state.RoomCommitReveal {
+		return commitCard(client, chat, card)
+	}
+
 	playRequest := protocol.Request{
 		Method: "play",
-		Data:   utils.Dict{"user_id": state.UserID, "room_id": state.RoomID, "card": cardToPlay, "stars": stars},
+		Data:   utils.Dict{"user_id": state.UserID, "room_id": state.RoomID, "card_id": card.ID},
 	}
 
 	playResponse, err := client.DoRequest(playRequest)
@@ -47,35 +87,205 @@ func playCard(client *api.Client, chat *ui.Chat, cardToPlay string, stars int) b
 		chat.Outputs <- message
 		return false
 	}
+	if hand, ok := playResponse.Data["hand"]; ok {
+		state.Hand = parseCards(hand)
+	}
+
+	chat.Outputs <- describePlay("You played", card)
+	state.PlayedCard = card.Type
+	state.PlayedCardStar = card.Stars
+	return true
+}
+
+// commitCard envia o compromisso de uma carta em salas com compromisso e revelação. A carta e o
+// nonce ficam guardados até que o servidor avise que os dois jogadores se comprometeram.
+func commitCard(client *api.Client, chat *ui.Chat, card state.HandCard) bool {
+	if state.PendingNonce != "" {
+		chat.Outputs <- "You already committed to a card this round. Waiting for your opponent..."
+		return false
+	}
+	nonce, err := newNonce()
+	if err != nil {
+		state.Log("Failed to generate nonce: %v", err)
+		chat.Outputs <- "Failed to commit to the card."
+		return false
+	}
+	state.PendingCard = card
+	state.PendingNonce = nonce
+
+	response, err := client.DoRequest(protocol.Request{
+		Method: "commit",
+		Data:   utils.Dict{"user_id": state.UserID, "room_id": state.RoomID, "commitment": commitment(card.ID, nonce)},
+	})
+	if err != nil || response.Status != "ok" {
+		state.PendingCard = state.HandCard{}
+		state.PendingNonce = ""
+		if err != nil {
+			state.Log("Commit request failed: %v", err)
+			chat.Outputs <- "Failed to commit to the card."
+			return false
+		}
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return false
+	}
 
-	chat.Outputs <- fmt.Sprintf("You played a %s card with %d stars.", cardToPlay, stars)
-	state.PlayedCard = cardToPlay
-	state.PlayedCardStar = stars
+	chat.Outputs <- fmt.Sprintf("You committed to a %s card. It will be revealed once your opponent commits too.", card.Type)
 	return true
 }
 
-func determineWinner(chat *ui.Chat) {
-	wins := 0
-	if state.CardWins[state.PlayedCard] == state.OpponentCard {
-		wins = 1
-	} else if state.CardWins[state.OpponentCard] == state.PlayedCard {
-		wins = -1
-	} else if state.PlayedCardStar > state.OpponentCardStar {
-		wins = 1
-	} else if state.PlayedCardStar < state.OpponentCardStar {
-		wins = -1
+// revealCard revela a carta e o nonce do compromisso pendente, depois que os dois jogadores se
+// comprometeram.
+func revealCard(client *api.Client, chat *ui.Chat) {
+	card, nonce := state.PendingCard, state.PendingNonce
+	if nonce == "" {
+		return
+	}
+	state.PendingCard = state.HandCard{}
+	state.PendingNonce = ""
+
+	response, err := client.DoRequest(protocol.Request{
+		Method: "reveal",
+		Data:   utils.Dict{"user_id": state.UserID, "room_id": state.RoomID, "card_id": card.ID, "nonce": nonce},
+	})
+	if err != nil {
+		state.Log("Reveal request failed: %v", err)
+		chat.Outputs <- "Failed to reveal your card."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+	if hand, ok := response.Data["hand"]; ok {
+		state.Hand = parseCards(hand)
 	}
 
-	if wins > 0 {
+	chat.Outputs <- describePlay("You revealed", card)
+	state.PlayedCard = card.Type
+	state.PlayedCardStar = card.Stars
+}
+
+// commitment calcula o compromisso de uma jogada como o servidor confere: o SHA-256, em
+// hexadecimal, do ID da carta e do nonce separados por dois-pontos.
+func commitment(cardID, nonce string) string {
+	sum := sha256.Sum256([]byte(cardID + ":" + nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// newNonce gera um nonce aleatório para o compromisso de uma jogada.
+func newNonce() (string, error) {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
+
+// describePlay descreve uma carta jogada, com a habilidade quando ela tiver uma.
+func describePlay(prefix string, card state.HandCard) string {
+	if card.Ability != "" {
+		return fmt.Sprintf("%s a %s card with %d stars and the ability %s.", prefix, card.Type, card.Stars, abilityName(card.Ability))
+	}
+	return fmt.Sprintf("%s a %s card with %d stars.", prefix, card.Type, card.Stars)
+}
+
+// parseCards converte a lista de cartas recebida do servidor.
+func parseCards(data any) []state.HandCard {
+	var cards []state.HandCard
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &cards); err != nil {
+		state.Log("Invalid cards from server: %v", err)
+	}
+	return cards
+}
+
+// formatCard descreve uma carta com seu ID, estrelas e habilidade.
+func formatCard(card state.HandCard) string {
+	description := fmt.Sprintf("[%s] %s (%d stars%s)", card.ID, card.Type, card.Stars, rarityNote(card.Rarity))
+	if card.Ability != "" {
+		description += " with " + abilityName(card.Ability)
+	}
+	return description
+}
+
+// rarityNote acrescenta a raridade à descrição das cartas que não são comuns.
+func rarityNote(rarity string) string {
+	if rarity == "" || rarity == "common" {
+		return ""
+	}
+	return ", " + rarity
+}
+
+// formatHand descreve as cartas da mão e quantas ainda restam no baralho.
+func formatHand() string {
+	if len(state.Hand) == 0 {
+		return "Your hand is empty."
+	}
+	cards := make([]string, 0, len(state.Hand))
+	for _, card := range state.Hand {
+		cards = append(cards, formatCard(card))
+	}
+	return fmt.Sprintf("Your hand: %s. Cards left to draw: %d.", strings.Join(cards, ", "), state.DrawPile)
+}
+
+// showEffects explica as habilidades aplicadas na rodada, na ordem em que o servidor as aplicou.
+func showEffects(chat *ui.Chat, effects []any) {
+	for _, item := range effects {
+		effect, _ := item.(map[string]any)
+		userID, _ := effect["user_id"].(string)
+		ability, _ := effect["ability"].(string)
+		owner := userID + "'s"
+		if userID == state.UserID {
+			owner = "Your"
+		}
+		description := fmt.Sprintf("%s ability %s took effect", owner, abilityName(ability))
+		if declared, exists := rulesetAbility(ability); exists {
+			description += ": " + declared.Description
+		}
+		if stars, ok := effect["stars"].(map[string]any); ok {
+			description += " Stars now: " + formatScores(stars) + "."
+		}
+		chat.Outputs <- description
+	}
+}
+
+// showRoundResult exibe o vencedor da rodada decidido pelo servidor e o placar da partida.
+func showRoundResult(chat *ui.Chat, winnerID string, scores map[string]any) {
+	switch winnerID {
+	case "":
+		chat.Outputs <- "This round is a tie!"
+	case state.UserID:
 		chat.Outputs <- "You win this round!"
-	} else if wins < 0 {
+	default:
 		chat.Outputs <- "You lose this round!"
-	} else {
-		chat.Outputs <- "This round is a tie!"
 	}
+	chat.Outputs <- "Score: " + formatScores(scores)
+}
+
+// formatScores descreve o placar da partida recebido do servidor.
+func formatScores(scores map[string]any) string {
+	players := make([]string, 0, len(scores))
+	for playerID := range scores {
+		players = append(players, playerID)
+	}
+	sort.Strings(players)
+
+	parts := make([]string, 0, len(players))
+	for _, playerID := range players {
+		wins, _ := scores[playerID].(float64)
+		parts = append(parts, fmt.Sprintf("%s %d", playerID, int(wins)))
+	}
+	return strings.Join(parts, " x ")
 }
 
 func resetRound() {
+	state.PendingCard = state.HandCard{}
+	state.PendingNonce = ""
 	state.PlayedCard = ""
 	state.PlayedCardStar = 0
 	state.OpponentCard = ""
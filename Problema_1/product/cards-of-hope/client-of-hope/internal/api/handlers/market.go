@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// marketUsage resume os subcomandos de /market.
+const marketUsage = "Usage: /market [list] [type] [stars|min-max] | mine | sell <ids> <price> [-auction] [-minutes <n>] | buy <id> | bid <id> <amount> | cancel <id>"
+
+// HandleMarket busca, anuncia, compra e dá lances em cartas do mercado.
+//
+// Uso: /market [list] [tipo] [estrelas|min-max] | mine | sell <ids> <preço> [-auction] [-minutes <n>] |
+// buy <id> | bid <id> <valor> | cancel <id>
+//
+// Os IDs das cartas são separados por vírgula. Sem subcomando, lista todos os anúncios abertos.
+func HandleMarket(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to use the market."
+		return
+	}
+
+	command := "list"
+	if len(args) > 0 {
+		command, args = strings.ToLower(args[0]), args[1:]
+	}
+	switch command {
+	case "list":
+		searchMarket(client, chat, args)
+	case "mine":
+		listMarket(client, chat, utils.Dict{"seller_id": state.UserID}, "You have no open listings.")
+	case "sell":
+		sellCards(client, chat, args)
+	case "buy", "cancel":
+		if len(args) != 1 {
+			chat.Outputs <- fmt.Sprintf("Usage: /market %s <id>", command)
+			return
+		}
+		answerListing(client, chat, command, utils.Dict{"user_id": state.UserID, "listing_id": args[0]})
+	case "bid":
+		amount, err := strconv.Atoi(argAt(args, 1))
+		if len(args) != 2 || err != nil || amount <= 0 {
+			chat.Outputs <- "Usage: /market bid <id> <amount>"
+			return
+		}
+		answerListing(client, chat, command, utils.Dict{"user_id": state.UserID, "listing_id": args[0], "amount": amount})
+	default:
+		// Sem subcomando conhecido, os argumentos são os filtros da busca
+		searchMarket(client, chat, append([]string{command}, args...))
+	}
+}
+
+// argAt retorna o argumento da posição informada, ou vazio se ele não existir.
+func argAt(args []string, index int) string {
+	if index < len(args) {
+		return args[index]
+	}
+	return ""
+}
+
+// searchMarket lista os anúncios abertos, filtrados por tipo de carta e faixa de estrelas.
+func searchMarket(client *api.Client, chat *ui.Chat, args []string) {
+	filter := utils.Dict{}
+	for _, arg := range args {
+		low, high, isRange := strings.Cut(arg, "-")
+		minStars, minErr := strconv.Atoi(low)
+		switch {
+		case minErr != nil:
+			filter["type"] = strings.ToLower(arg)
+		case !isRange:
+			filter["min_stars"], filter["max_stars"] = minStars, minStars
+		default:
+			maxStars, err := strconv.Atoi(high)
+			if err != nil {
+				chat.Outputs <- marketUsage
+				return
+			}
+			filter["min_stars"], filter["max_stars"] = minStars, maxStars
+		}
+	}
+	listMarket(client, chat, filter, "No listings found.")
+}
+
+// listMarket mostra os anúncios abertos que atendem ao filtro.
+func listMarket(client *api.Client, chat *ui.Chat, filter utils.Dict, empty string) {
+	response, err := client.DoRequest(protocol.Request{Method: "market_list", Data: filter})
+	if err != nil {
+		state.Log("Market list request failed: %v", err)
+		chat.Outputs <- "Failed to list the market."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	listings, _ := response.Data["listings"].([]any)
+	if len(listings) == 0 {
+		chat.Outputs <- empty
+		return
+	}
+	lines := []string{"Market listings:"}
+	for _, item := range listings {
+		lines = append(lines, "  "+formatListing(parseListing(item)))
+	}
+	lines = append(lines, "Use /market buy <id> for fixed prices or /market bid <id> <amount> for auctions.")
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// sellCards anuncia cartas da coleção por um preço fixo ou em leilão.
+func sellCards(client *api.Client, chat *ui.Chat, args []string) {
+	if len(args) < 2 {
+		chat.Outputs <- marketUsage
+		return
+	}
+	price, err := strconv.Atoi(args[1])
+	if err != nil || price <= 0 {
+		chat.Outputs <- "The price must be a positive number of coins."
+		return
+	}
+	data := utils.Dict{"user_id": state.UserID, "card_ids": splitCardIDs(args[0]), "price": price}
+	for i := 2; i < len(args); i++ {
+		switch {
+		case args[i] == "-auction":
+			data["auction"] = true
+		case args[i] == "-minutes" && i+1 < len(args):
+			minutes, err := strconv.Atoi(args[i+1])
+			if err != nil || minutes <= 0 {
+				chat.Outputs <- marketUsage
+				return
+			}
+			data["minutes"] = minutes
+			i++
+		default:
+			chat.Outputs <- marketUsage
+			return
+		}
+	}
+
+	response, err := client.DoRequest(protocol.Request{Method: "market_sell", Data: data})
+	if err != nil {
+		state.Log("Market sell request failed: %v", err)
+		chat.Outputs <- "Failed to reach the server for the market."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+	listing := parseListing(response.Data["listing"])
+	chat.Outputs <- fmt.Sprintf("Listed: %s. The cards are held by the market until the listing ends; /market cancel %s takes them back while there are no bids.",
+		formatListing(listing), listing.ID)
+}
+
+// answerListing compra, dá um lance ou retira um anúncio.
+func answerListing(client *api.Client, chat *ui.Chat, command string, data utils.Dict) {
+	response, err := client.DoRequest(protocol.Request{Method: "market_" + command, Data: data})
+	if err != nil {
+		state.Log("Market %s request failed: %v", command, err)
+		chat.Outputs <- "Failed to reach the server for the market."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	listing := parseListing(response.Data["listing"])
+	balance, _ := response.Data["balance"].(float64)
+	switch command {
+	case "buy":
+		chat.Outputs <- fmt.Sprintf("You bought listing %s for %d coins and received: %s. Balance: %d.",
+			listing.ID, listing.Price, formatCards(parseCards(response.Data["received"])), int(balance))
+	case "bid":
+		chat.Outputs <- fmt.Sprintf("Your bid of %d coins on listing %s is the highest; the coins are held until you are outbid or the auction ends. Balance: %d.",
+			listing.HighestBid, listing.ID, int(balance))
+	case "cancel":
+		chat.Outputs <- fmt.Sprintf("Listing %s cancelled; the cards are back in your collection.", listing.ID)
+	}
+}
+
+// HandleMarketUpdate avisa as vendas, os lances e o fim dos anúncios em que o usuário participa.
+func HandleMarketUpdate(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	event, _ := response.Data["event"].(string)
+	listing := parseListing(response.Data["listing"])
+
+	switch event {
+	case "bid":
+		chat.Outputs <- fmt.Sprintf("%s bid %d coins on your listing %s.", listing.HighestBidder, listing.HighestBid, listing.ID)
+	case "outbid":
+		chat.Outputs <- fmt.Sprintf("You were outbid on listing %s: the highest bid is now %d. Your coins were returned; /market bid %s %d to bid again.",
+			listing.ID, listing.HighestBid, listing.ID, listing.MinimumBid)
+	case "sold":
+		amount := listing.Price
+		if listing.Kind == "auction" {
+			amount = listing.HighestBid
+		}
+		chat.Outputs <- fmt.Sprintf("Your listing %s was sold to %s for %d coins.", listing.ID, listing.BuyerID, amount)
+	case "won":
+		chat.Outputs <- fmt.Sprintf("You won auction %s for %d coins: %s are now in your collection.", listing.ID, listing.HighestBid, formatListingCards(listing.Cards))
+	case "expired":
+		chat.Outputs <- fmt.Sprintf("Your listing %s ended unsold; the cards are back in your collection.", listing.ID)
+	}
+}
+
+// parseListing converte o anúncio recebido do servidor.
+func parseListing(data any) state.Listing {
+	var listing state.Listing
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return listing
+	}
+	if err := json.Unmarshal(raw, &listing); err != nil {
+		state.Log("Invalid listing from server: %v", err)
+	}
+	return listing
+}
+
+// formatListing descreve um anúncio em uma linha.
+func formatListing(listing state.Listing) string {
+	price := fmt.Sprintf("%d coins", listing.Price)
+	if listing.Kind == "auction" {
+		price = fmt.Sprintf("auction, %d bids, next bid %d", listing.Bids, listing.MinimumBid)
+	}
+	return fmt.Sprintf("[%s] %s by %s - %s - ends at %s",
+		listing.ID, formatListingCards(listing.Cards), listing.SellerID, price, formatExpiry(listing.EndsAt))
+}
+
+// formatListingCards descreve as cartas de um anúncio, que não têm ID de coleção.
+func formatListingCards(cards []state.HandCard) string {
+	descriptions := make([]string, 0, len(cards))
+	for _, card := range cards {
+		description := fmt.Sprintf("%s (%d stars%s)", card.Type, card.Stars, rarityNote(card.Rarity))
+		if card.Ability != "" {
+			description += " with " + abilityName(card.Ability)
+		}
+		descriptions = append(descriptions, description)
+	}
+	return strings.Join(descriptions, ", ")
+}
@@ -20,7 +20,11 @@ func HandleWhereami(client *api.Client, chat *ui.Chat, args []string) {
 		chat.Outputs <- "You are not currently in a room."
 		return
 	}
-	chat.Outputs <- fmt.Sprintf("You are in room: %s", state.RoomID)
+	if state.Spectating {
+		chat.Outputs <- fmt.Sprintf("You are spectating room: %s (%s), hosted by %s", state.RoomName, state.RoomID, state.RoomHostID)
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("You are in room: %s (%s), hosted by %s", state.RoomName, state.RoomID, state.RoomHostID)
 }
 
 func HandleQuit(client *api.Client, chat *ui.Chat, args []string) {
@@ -41,14 +45,69 @@ func HandleHelp(client *api.Client, chat *ui.Chat, args []string) {
 
   Chat & Rooms:
     /send <message>          - Send a message to the current room.
-    /create <room_name>      - Create a new chat room.
-    /join <room_name>        - Join an existing chat room.
+    /create [-private] [-password <pass>] [-rules <ruleset>] [-commit] [-async [hours]] [-wager <coins>] [-stake <cards>] [-bot <difficulty>] <room_name>
+                             - Create a new chat room (-commit uses commit-reveal plays,
+                               -async plays correspondence matches with a deadline per round,
+                               -wager and -stake bet coins and cards on every match,
+                               -bot easy|medium|hard seats a server bot as your opponent).
+    /join <room_id|#n> [pass] - Join an existing room (#n picks from /rooms).
+    /join <invite_code>      - Join a private room with an invite code.
+    /spectate <room_id|#n|code> [pass]
+                             - Watch a room as a spectator.
+    /sc <message>            - Send a message only to the other spectators.
+    /msg <user> [message]    - Send a direct message to a user (no message shows your conversation).
+    /channel list, /channel join <name>, /channel leave [name], /channel who [name]
+                             - List chat channels, join (creating it if needed) or leave one, or show its members.
+    /c <message>             - Send a message to your current chat channel (outside a room, just type it).
+    /invite [-multi] [min]   - Create an invite code for the current private room.
+    /rooms [-all] [name]     - List joinable rooms.
     /leave                   - Leave the current room.
+    /kick <user>             - Kick a user from your room (host only).
+    /lock, /unlock           - Stop or allow new users joining your room (host only).
+    /host <user>             - Hand the host role to another member (host only).
+    /challenge <user> [-rules <ruleset>] [-commit]
+                             - Challenge an online user to a duel in a private room.
+    /challenge list, /challenge accept|decline [id], /challenge cancel <id>
+                             - List or answer challenges (no ID answers the latest one received).
 
   Game:
-    /play <card>             - Play a card (rock, paper, scissors).
-    /cards                   - Show your current cards.
-    /buy                     - Buy a new package of cards.
+    /ready [ids], /unready    - Confirm (or cancel) you are ready; the match starts when both players are ready
+                               (in card wager rooms, the IDs choose the cards you stake).
+    /rematch, /decline       - Accept or decline a rematch after a match ends.
+    /play <card|id>          - Play a card from your hand, by type or by ID.
+    /hand                    - Show the cards in your hand during a match.
+    /games [-all], /games <room_id>
+                             - List correspondence games waiting on you, or open one of them.
+    /rules [-all]            - Show the room's ruleset (or every ruleset on the server).
+    /cards [user]            - Show your card collection (or another user's) with IDs and abilities.
+    /deck save <name> <ids...>, /deck list, /deck use [name]
+                             - Save, list or choose the deck for your matches.
+    /buy [pack]              - Buy a package of cards for your collection with coins (default: basic).
+    /store                   - Show the store packages, their prices, drop rates and guarantees.
+    /fair [seed <seed> | rotate [seed]]
+                             - Show or change your pack seeds; rotate reveals the seed in use.
+    /fair history, /fair verify <id>
+                             - List opened packages or check one against the revealed seed.
+    /balance                 - Show your coin and crafting dust balances and recent entries.
+    /craft [recipes | <ids>] - List the recipes or fuse duplicate cards into one with more stars.
+    /disenchant <ids>        - Turn cards from your collection into crafting dust.
+    /trade offer <user> <your ids|-> [for <their ids>] [-minutes <n>]
+                             - Offer a card trade (comma-separated IDs).
+    /trade list, /trade accept|decline|cancel <id>
+                             - List, accept, decline or cancel trade offers.
+    /market [list] [type] [stars|min-max]
+                             - Search market listings by card type and stars.
+    /market sell <ids> <price> [-auction] [-minutes <n>]
+                             - List cards for a fixed price or as an auction.
+    /market buy <id>, /market bid <id> <amount>, /market cancel <id>, /market mine
+                             - Buy, bid on, cancel or show your own listings.
+    /replays                 - List the matches you played or watched.
+    /replay <id>, /replay next, /replay prev, /replay stop
+                             - Watch a recorded match round by round.
+    /tournament create [-roundrobin] [-rules <ruleset>] <size> [name]
+                             - Create a tournament (single elimination or round robin).
+    /tournament list, /tournament show|join|leave|start <id>
+                             - List, show, join, leave or start a tournament.
 
   Misc:
     /whoami                  - Show your current user information.
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// replayUsage resume os subcomandos de /replay.
+const replayUsage = "Usage: /replay <id> | /replay next | /replay prev | /replay stop"
+
+// HandleReplays lista as partidas encerradas que o usuário jogou ou assistiu.
+func HandleReplays(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to list your replays."
+		return
+	}
+
+	response, err := client.DoRequest(protocol.Request{
+		Method: "replays",
+		Data:   utils.Dict{"user_id": state.UserID},
+	})
+	if err != nil {
+		state.Log("Replays request failed: %v", err)
+		chat.Outputs <- "Failed to list your replays."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	replays, _ := response.Data["replays"].([]any)
+	if len(replays) == 0 {
+		chat.Outputs <- "No recorded matches yet."
+		return
+	}
+	lines := []string{"Recorded matches (newest first):"}
+	for _, item := range replays {
+		replay, _ := item.(map[string]any)
+		id, _ := replay["id"].(string)
+		roomName, _ := replay["room_name"].(string)
+		number, _ := replay["match_number"].(float64)
+		players, _ := replay["players"].([]any)
+		rounds, _ := replay["rounds"].(float64)
+		winnerID, _ := replay["winner_id"].(string)
+		outcome := "winner: " + winnerID
+		if abandoned, _ := replay["abandoned"].(bool); abandoned {
+			outcome = "abandoned"
+		} else if winnerID == "" {
+			outcome = "draw"
+		}
+		lines = append(lines, fmt.Sprintf("  [%s] %s, match %d - %s - %d rounds - %s",
+			id, roomName, int(number), joinNames(players), int(rounds), outcome))
+	}
+	lines = append(lines, "Use /replay <id> to watch one.")
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// HandleReplay abre o registro de uma partida e o percorre rodada a rodada.
+//
+// Uso: /replay <id> | /replay next | /replay prev | /replay stop
+func HandleReplay(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to watch replays."
+		return
+	}
+	if len(args) != 1 {
+		chat.Outputs <- replayUsage
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "next", "prev":
+		if state.OpenReplay.ID == "" {
+			chat.Outputs <- "No replay open. Use /replay <id> first."
+			return
+		}
+		step := state.ReplayStep + 1
+		if strings.ToLower(args[0]) == "prev" {
+			step = state.ReplayStep - 1
+		}
+		if step < 1 || step > replaySteps(state.OpenReplay) {
+			chat.Outputs <- "No more rounds in that direction. Use /replay stop to close the replay."
+			return
+		}
+		state.ReplayStep = step
+		chat.Outputs <- formatReplayStep(state.OpenReplay, step)
+	case "stop":
+		state.OpenReplay = state.Replay{}
+		state.ReplayStep = 0
+		chat.Outputs <- "Replay closed."
+	default:
+		openReplay(client, chat, args[0])
+	}
+}
+
+// openReplay busca o registro de uma partida no servidor e exibe a primeira rodada.
+func openReplay(client *api.Client, chat *ui.Chat, replayID string) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: "replay",
+		Data:   utils.Dict{"user_id": state.UserID, "replay_id": replayID},
+	})
+	if err != nil {
+		state.Log("Replay request failed: %v", err)
+		chat.Outputs <- "Failed to get the replay."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	var replay state.Replay
+	raw, err := json.Marshal(response.Data["replay"])
+	if err == nil {
+		err = json.Unmarshal(raw, &replay)
+	}
+	if err != nil || replay.ID == "" {
+		state.Log("Invalid replay from server: %v", err)
+		chat.Outputs <- "Invalid replay from server."
+		return
+	}
+
+	state.OpenReplay = replay
+	state.ReplayStep = 1
+	chat.Outputs <- fmt.Sprintf("Replay %s: %s, match %d (rules: %s), %s. Use /replay next and /replay prev to step through the rounds.",
+		replay.ID, replay.RoomName, replay.Number, replay.RulesetID, strings.Join(replay.Players, " vs "))
+	chat.Outputs <- formatReplayStep(replay, 1)
+}
+
+// replaySteps retorna quantas etapas o registro tem: uma por rodada e, por fim, o encerramento.
+func replaySteps(replay state.Replay) int {
+	steps := 1
+	for _, event := range replay.Events {
+		steps = max(steps, event.Round)
+	}
+	return steps
+}
+
+// formatReplayStep descreve os eventos de uma etapa do registro.
+func formatReplayStep(replay state.Replay, step int) string {
+	lines := []string{fmt.Sprintf("--- Round %d of %d ---", step, replaySteps(replay))}
+	for _, event := range replay.Events {
+		if event.Round == step {
+			lines = append(lines, fmt.Sprintf("  [%s] %s", event.At.Local().Format(time.TimeOnly), formatReplayEvent(replay, event)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatReplayEvent descreve um evento do registro.
+func formatReplayEvent(replay state.Replay, event state.ReplayEvent) string {
+	switch event.Type {
+	case "match_started":
+		return "Match started: " + strings.Join(replay.Players, " vs ")
+	case "joined":
+		return event.UserID + " joined the room to watch."
+	case "left":
+		return event.UserID + " left the room."
+	case "kicked":
+		return event.UserID + " was kicked from the room."
+	case "chat":
+		return event.Text
+	case "play":
+		if event.Card == nil {
+			return event.UserID + " played."
+		}
+		return describePlay(event.UserID+" played", *event.Card)
+	case "round_result":
+		if event.Result == nil {
+			return "Round over."
+		}
+		winner := "The round was a draw."
+		if event.Result.WinnerID != "" {
+			winner = event.Result.WinnerID + " won the round."
+		}
+		return fmt.Sprintf("%s Score: %s", winner, formatScores(event.Result.Scores))
+	case "match_finished":
+		switch {
+		case replay.ForfeitedBy != "":
+			return fmt.Sprintf("Match over: %s forfeited, %s won.", replay.ForfeitedBy, replay.WinnerID)
+		case replay.WinnerID != "":
+			return fmt.Sprintf("Match over: %s won.", replay.WinnerID)
+		}
+		return "Match over: draw."
+	}
+	return event.Type
+}
@@ -7,17 +7,86 @@ import (
 	"client-of-hope/internal/ui"
 	"client-of-hope/internal/utils"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
+// createUsage resume as opções de /create.
+const createUsage = "Usage: /create [-private] [-password <password>] [-rules <ruleset>] [-commit] [-async [hours]] [-wager <coins>] [-stake <cards>] [-bot <difficulty>] <room_name>"
+
 func HandleCreateRoom(client *api.Client, chat *ui.Chat, args []string) {
 	if state.UserID == "" {
 		chat.Outputs <- "You must be logged in to create a room."
 		return
 	}
+	if state.RoomID != "" {
+		chat.Outputs <- "You must leave your current room before creating another one."
+		return
+	}
+
+	data := utils.Dict{"user_id": state.UserID}
+	var name []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-private":
+			data["private"] = true
+		case "-password":
+			if i+1 >= len(args) {
+				chat.Outputs <- createUsage
+				return
+			}
+			data["private"] = true
+			data["password"] = args[i+1]
+			i++
+		case "-rules":
+			if i+1 >= len(args) {
+				chat.Outputs <- createUsage
+				return
+			}
+			data["ruleset"] = strings.ToLower(args[i+1])
+			i++
+		case "-commit":
+			data["commit_reveal"] = true
+		case "-async":
+			data["async"] = true
+			if i+1 < len(args) {
+				if hours, err := strconv.Atoi(args[i+1]); err == nil {
+					data["turn_hours"] = hours
+					i++
+				}
+			}
+		case "-wager", "-stake":
+			if i+1 >= len(args) {
+				chat.Outputs <- createUsage
+				return
+			}
+			amount, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				chat.Outputs <- createUsage
+				return
+			}
+			if args[i] == "-wager" {
+				data["wager_coins"] = amount
+			} else {
+				data["wager_cards"] = amount
+			}
+			i++
+		case "-bot", "--bot":
+			if i+1 >= len(args) {
+				chat.Outputs <- createUsage
+				return
+			}
+			data["bot"] = strings.ToLower(args[i+1])
+			i++
+		default:
+			name = append(name, args[i])
+		}
+	}
+	data["name"] = strings.Join(name, " ")
 
 	request := protocol.Request{
 		Method: "create",
-		Data:   utils.Dict{"user_id": state.UserID},
+		Data:   data,
 	}
 
 	response, err := client.DoRequest(request)
@@ -39,27 +108,86 @@ func HandleCreateRoom(client *api.Client, chat *ui.Chat, args []string) {
 		return
 	}
 
+	room, _ := response.Data["room"].(map[string]any)
+	roomName, _ := room["name"].(string)
+	private, _ := room["private"].(bool)
+
 	state.RoomID = roomID
-	chat.Outputs <- fmt.Sprintf("Room created successfully! Room ID: %s", roomID)
+	state.RoomName = roomName
+	state.RoomHostID = state.UserID
+	state.RoomRuleset = parseRuleset(response.Data["ruleset"])
+	state.RoomCommitReveal, _ = room["commit_reveal"].(bool)
+	state.RoomAsync, _ = room["async"].(bool)
+	state.RoomWagerCards = wagerCards(room)
+	chat.Outputs <- fmt.Sprintf("Room '%s' created successfully! Room ID: %s", roomName, roomID)
+	chat.Outputs <- fmt.Sprintf("Ruleset: %s. Use /rules to see it.", state.RoomRuleset.Name)
+	if state.RoomCommitReveal {
+		chat.Outputs <- commitRevealNotice
+	}
+	if state.RoomAsync {
+		turnHours, _ := room["turn_hours"].(float64)
+		chat.Outputs <- fmt.Sprintf(asyncNotice, int(turnHours))
+	}
+	if notice := wagerNotice(room); notice != "" {
+		chat.Outputs <- notice
+	}
+	if private {
+		chat.Outputs <- "This room is private. Use /invite to generate invite codes."
+	}
+	if bots, _ := room["bots"].([]any); len(bots) > 0 {
+		chat.Outputs <- fmt.Sprintf("Bot %s took the second seat and is ready. Use /ready to start the match.", joinNames(bots))
+	}
 }
 
 func HandleJoinRoom(client *api.Client, chat *ui.Chat, args []string) {
+	joinRoom(client, chat, args, false)
+}
+
+// HandleSpectate entra em uma sala como espectador, sem ocupar uma vaga de jogador.
+//
+// Uso: /spectate <id_da_sala|#n> [senha] ou /spectate <código>
+func HandleSpectate(client *api.Client, chat *ui.Chat, args []string) {
+	joinRoom(client, chat, args, true)
+}
+
+// joinRoom entra em uma sala como jogador ou espectador, usando um ID, uma referência à
+// listagem de /rooms ou um código de convite.
+func joinRoom(client *api.Client, chat *ui.Chat, args []string, spectator bool) {
+	command := "/join"
+	if spectator {
+		command = "/spectate"
+	}
 	if state.UserID == "" {
 		chat.Outputs <- "You must be logged in to join a room."
 		return
 	}
 	if len(args) < 1 {
-		chat.Outputs <- "Usage: /join <room_id>"
+		chat.Outputs <- fmt.Sprintf("Usage: %s <room_id|#number> [password] or %s <invite_code>", command, command)
 		return
 	}
+	if state.RoomID != "" {
+		chat.Outputs <- "You must leave your current room before joining another one."
+		return
+	}
+
+	data := utils.Dict{"user_id": state.UserID, "spectator": spectator}
+	if isInviteCode(args[0]) {
+		data["invite_code"] = args[0]
+	} else {
+		roomID, ok := resolveRoomID(args[0])
+		if !ok {
+			chat.Outputs <- fmt.Sprintf("No room %s in the last listing. Use /rooms to refresh it.", args[0])
+			return
+		}
+		data["room_id"] = roomID
+		if len(args) > 1 {
+			data["password"] = args[1]
+		}
+	}
 
-	roomID := args[0]
 	request := protocol.Request{
 		Method: "join",
-		Data: utils.Dict{
-			"user_id": state.UserID,
-			"room_id": roomID,
-		},
+		Data:   data,
 	}
 
 	response, err := client.DoRequest(request)
@@ -75,8 +203,94 @@ func HandleJoinRoom(client *api.Client, chat *ui.Chat, args []string) {
 		return
 	}
 
+	roomID, _ := response.Data["room_id"].(string)
+	room, _ := response.Data["room"].(map[string]any)
+	name, _ := room["name"].(string)
+
 	state.RoomID = roomID
-	chat.Outputs <- fmt.Sprintf("Successfully joined room %s", roomID)
+	state.RoomName = name
+	state.Spectating = spectator
+	state.RoomHostID, _ = room["host_id"].(string)
+	state.InMatch = room["status"] == "playing"
+	state.RoomRuleset = parseRuleset(response.Data["ruleset"])
+	state.RoomCommitReveal, _ = room["commit_reveal"].(bool)
+	state.RoomAsync, _ = room["async"].(bool)
+	state.RoomWagerCards = wagerCards(room)
+	if spectator {
+		players, _ := room["players"].([]any)
+		chat.Outputs <- fmt.Sprintf("You are now spectating room '%s' (%s). Players: %s", name, roomID, joinNames(players))
+		chat.Outputs <- "Round results will appear here. Use /sc <message> to talk only with other spectators."
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("Successfully joined room '%s' (%s)", name, roomID)
+	chat.Outputs <- fmt.Sprintf("Ruleset: %s. Use /rules to see it.", state.RoomRuleset.Name)
+	if state.RoomCommitReveal {
+		chat.Outputs <- commitRevealNotice
+	}
+	if state.RoomAsync {
+		turnHours, _ := room["turn_hours"].(float64)
+		chat.Outputs <- fmt.Sprintf(asyncNotice, int(turnHours))
+	}
+	if notice := wagerNotice(room); notice != "" {
+		chat.Outputs <- notice
+	}
+	if status, _ := room["status"].(string); status == "ready_check" {
+		chat.Outputs <- "The room is full. Type " + readyCommand() + " when you are ready to play."
+	}
+	if hand, ok := response.Data["hand"]; ok {
+		resumeMatch(chat, hand, response.Data)
+	}
+}
+
+// HandleInvite gera um código de convite para a sala privada atual.
+//
+// Uso: /invite [-multi] [minutos]
+//
+// Por padrão o convite vale por 30 minutos e só pode ser usado uma vez.
+func HandleInvite(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" || state.RoomID == "" {
+		chat.Outputs <- "You must be logged in and in a room to create invites."
+		return
+	}
+
+	data := utils.Dict{
+		"user_id": state.UserID,
+		"room_id": state.RoomID,
+	}
+	for _, arg := range args {
+		if arg == "-multi" {
+			data["single_use"] = false
+			continue
+		}
+		minutes, err := strconv.Atoi(arg)
+		if err != nil || minutes <= 0 {
+			chat.Outputs <- "Usage: /invite [-multi] [minutes]"
+			return
+		}
+		data["minutes"] = minutes
+	}
+
+	response, err := client.DoRequest(protocol.Request{Method: "invite", Data: data})
+	if err != nil {
+		state.Log("Invite request failed: %v", err)
+		chat.Outputs <- "Failed to create invite."
+		return
+	}
+
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	code, _ := response.Data["invite_code"].(string)
+	expiresAt, _ := response.Data["expires_at"].(string)
+	singleUse, _ := response.Data["single_use"].(bool)
+	usage := "reusable"
+	if singleUse {
+		usage = "single use"
+	}
+	chat.Outputs <- fmt.Sprintf("Invite code: %s (%s, expires at %s). Share it and join with /join %s", code, usage, expiresAt, code)
 }
 
 func HandleLeaveRoom(client *api.Client, chat *ui.Chat, args []string) {
@@ -84,6 +298,12 @@ func HandleLeaveRoom(client *api.Client, chat *ui.Chat, args []string) {
 		chat.Outputs <- "You must be logged in and in a room to leave."
 		return
 	}
+	if state.RoomAsync && state.InMatch && !state.Spectating {
+		// Sair de verdade abandonaria a partida por correspondência, que segue sem o jogador conectado
+		chat.Outputs <- fmt.Sprintf("You stepped away from '%s'. The match goes on; use /games to come back to it.", state.RoomName)
+		clearRoom()
+		return
+	}
 
 	request := protocol.Request{
 		Method: "leave",
@@ -107,5 +327,210 @@ func HandleLeaveRoom(client *api.Client, chat *ui.Chat, args []string) {
 	}
 
 	chat.Outputs <- fmt.Sprintf("Successfully left room %s", state.RoomID)
+	clearRoom()
+}
+
+// HandleKick expulsa um membro da sala atual. Apenas o anfitrião pode expulsar.
+//
+// Uso: /kick <usuário>
+func HandleKick(client *api.Client, chat *ui.Chat, args []string) {
+	if len(args) != 1 {
+		chat.Outputs <- "Usage: /kick <user>"
+		return
+	}
+	sendHostCommand(client, chat, "kick", utils.Dict{"target_id": args[0]})
+}
+
+// HandleLock tranca a sala atual, impedindo a entrada de novos membros.
+func HandleLock(client *api.Client, chat *ui.Chat, args []string) {
+	sendHostCommand(client, chat, "lock", utils.Dict{"locked": true})
+}
+
+// HandleUnlock destranca a sala atual.
+func HandleUnlock(client *api.Client, chat *ui.Chat, args []string) {
+	sendHostCommand(client, chat, "lock", utils.Dict{"locked": false})
+}
+
+// HandleTransferHost passa o papel de anfitrião a outro membro da sala atual.
+//
+// Uso: /host <usuário>
+func HandleTransferHost(client *api.Client, chat *ui.Chat, args []string) {
+	if len(args) != 1 {
+		chat.Outputs <- "Usage: /host <user>"
+		return
+	}
+	sendHostCommand(client, chat, "transfer_host", utils.Dict{"target_id": args[0]})
+}
+
+// sendHostCommand envia um comando de moderação para a sala atual. O resultado é exibido
+// quando o servidor envia o room_event correspondente; aqui só são exibidos os erros.
+func sendHostCommand(client *api.Client, chat *ui.Chat, method string, data utils.Dict) {
+	if state.UserID == "" || state.RoomID == "" {
+		chat.Outputs <- "You must be logged in and in a room to moderate it."
+		return
+	}
+	if state.RoomHostID != state.UserID {
+		chat.Outputs <- "Only the room host can do that."
+		return
+	}
+
+	data["user_id"] = state.UserID
+	data["room_id"] = state.RoomID
+	response, err := client.DoRequest(protocol.Request{Method: method, Data: data})
+	if err != nil {
+		state.Log("%s request failed: %v", method, err)
+		chat.Outputs <- "Failed to reach the server."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+	}
+}
+
+// clearRoom limpa o estado da sala atual.
+func clearRoom() {
 	state.RoomID = ""
-}
\ No newline at end of file
+	state.RoomName = ""
+	state.Spectating = false
+	state.RoomHostID = ""
+	state.InMatch = false
+	state.RoomRuleset = state.Ruleset{}
+	state.RoomCommitReveal = false
+	state.RoomAsync = false
+	state.RoomWagerCards = 0
+	state.Hand = nil
+	state.DrawPile = 0
+	resetRound()
+}
+
+// HandleListRooms lista as salas disponíveis no servidor.
+//
+// Uso: /rooms [-all] [-playing|-waiting] [-public] [trecho do nome]
+//
+// As salas são numeradas na ordem exibida; use /join #<número> para entrar em uma delas.
+func HandleListRooms(client *api.Client, chat *ui.Chat, args []string) {
+	data := utils.Dict{}
+	var name []string
+	for _, arg := range args {
+		switch arg {
+		case "-all":
+			data["all"] = true
+		case "-playing":
+			data["status"] = "playing"
+		case "-waiting":
+			data["status"] = "waiting"
+		case "-public":
+			data["public"] = true
+		default:
+			name = append(name, arg)
+		}
+	}
+	data["name"] = strings.Join(name, " ")
+
+	response, err := client.DoRequest(protocol.Request{Method: "rooms", Data: data})
+	if err != nil {
+		state.Log("List rooms request failed: %v", err)
+		chat.Outputs <- "Failed to list rooms."
+		return
+	}
+
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	rooms, _ := response.Data["rooms"].([]any)
+	if len(rooms) == 0 {
+		state.RoomListing = nil
+		chat.Outputs <- "No rooms found. Create one with /create <room_name>."
+		return
+	}
+
+	listing := make([]string, 0, len(rooms))
+	lines := []string{"Rooms:"}
+	for _, item := range rooms {
+		room, _ := item.(map[string]any)
+		roomID, _ := room["room_id"].(string)
+		listing = append(listing, roomID)
+		lines = append(lines, fmt.Sprintf("  #%d %s", len(listing), formatRoom(room)))
+	}
+	lines = append(lines, "Use /join #<number> to join a room from this list, or /spectate #<number> to watch it.")
+
+	state.RoomListing = listing
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// resolveRoomID converte o argumento de /join em um ID de sala, aceitando referências
+// no formato #<número> para a última listagem de /rooms.
+func resolveRoomID(arg string) (string, bool) {
+	if !strings.HasPrefix(arg, "#") {
+		return arg, true
+	}
+	index, err := strconv.Atoi(arg[1:])
+	if err != nil || index < 1 || index > len(state.RoomListing) {
+		return "", false
+	}
+	return state.RoomListing[index-1], true
+}
+
+// isInviteCode informa se o argumento de /join é um código de convite, e não um ID de sala
+// ou uma referência #<número> à listagem.
+func isInviteCode(arg string) bool {
+	if strings.HasPrefix(arg, "#") {
+		return false
+	}
+	_, err := strconv.Atoi(arg)
+	return err != nil
+}
+
+// formatRoom descreve uma sala recebida do servidor em uma linha.
+func formatRoom(room map[string]any) string {
+	roomID, _ := room["room_id"].(string)
+	name, _ := room["name"].(string)
+	owner, _ := room["owner_id"].(string)
+	status, _ := room["status"].(string)
+	capacity, _ := room["capacity"].(float64)
+	players, _ := room["players"].([]any)
+	spectators, _ := room["spectators"].([]any)
+	private, _ := room["private"].(bool)
+	hasPassword, _ := room["has_password"].(bool)
+	access := "public"
+	if private && hasPassword {
+		access = "private, password"
+	} else if private {
+		access = "private, invite only"
+	}
+	line := fmt.Sprintf("%s [id %s] - %d/%d players - %s - %s - owner: %s", name, roomID, len(players), int(capacity), status, access, owner)
+	if len(spectators) > 0 {
+		line += fmt.Sprintf(" - spectators: %s", joinNames(spectators))
+	}
+	if locked, _ := room["locked"].(bool); locked {
+		line += " - locked"
+	}
+	if ruleset, _ := room["ruleset_id"].(string); ruleset != "" {
+		line += " - rules: " + ruleset
+	}
+	if commitReveal, _ := room["commit_reveal"].(bool); commitReveal {
+		line += " - commit-reveal"
+	}
+	if bots, _ := room["bots"].([]any); len(bots) > 0 {
+		line += " - bot: " + joinNames(bots)
+	}
+	return line
+}
+
+// joinNames junta uma lista de nomes recebida do servidor em uma única string.
+func joinNames(items []any) string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if name, ok := item.(string); ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
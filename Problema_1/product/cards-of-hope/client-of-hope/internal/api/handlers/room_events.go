@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"fmt"
+)
+
+// HandleRoomEvent exibe as mudanças de membros e de moderação da sala atual e mantém o
+// anfitrião conhecido atualizado.
+func HandleRoomEvent(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	roomID, _ := response.Data["room_id"].(string)
+	event, _ := response.Data["event"].(string)
+	userID, _ := response.Data["user_id"].(string)
+	room, _ := response.Data["room"].(map[string]any)
+	hostID, _ := room["host_id"].(string)
+
+	if roomID != state.RoomID {
+		return
+	}
+
+	switch event {
+	case "joined":
+		if userID != state.UserID {
+			chat.Outputs <- fmt.Sprintf("%s joined the room.", userID)
+		}
+	case "left":
+		chat.Outputs <- fmt.Sprintf("%s left the room.", userID)
+	case "kicked":
+		if userID == state.UserID {
+			chat.Outputs <- fmt.Sprintf("You were kicked from room '%s'.", state.RoomName)
+			clearRoom()
+			return
+		}
+		chat.Outputs <- fmt.Sprintf("%s was kicked from the room.", userID)
+	case "ready":
+		if userID != state.UserID {
+			chat.Outputs <- fmt.Sprintf("%s is ready.", userID)
+		}
+	case "unready":
+		if userID != state.UserID {
+			chat.Outputs <- fmt.Sprintf("%s is no longer ready.", userID)
+		}
+	case "rematch_requested":
+		if userID != state.UserID {
+			chat.Outputs <- fmt.Sprintf("%s wants a rematch! Type /rematch to accept or /decline.", userID)
+		}
+	case "rematch_declined":
+		if userID != state.UserID {
+			chat.Outputs <- fmt.Sprintf("%s declined the rematch. Use /ready to start a new series.", userID)
+		}
+	case "locked":
+		chat.Outputs <- "The room is now locked. Nobody else can join."
+	case "unlocked":
+		chat.Outputs <- "The room is now unlocked."
+	}
+
+	if status, _ := room["status"].(string); state.InMatch && status != "playing" {
+		state.InMatch = false
+		resetRound()
+		chat.Outputs <- "The match was abandoned because a player left."
+	}
+	if status, _ := room["status"].(string); event == "joined" && userID != state.UserID && status == "ready_check" && !state.Spectating {
+		chat.Outputs <- "The room is full. Type " + readyCommand() + " when you are ready to play."
+	}
+
+	if hostID != state.RoomHostID {
+		state.RoomHostID = hostID
+		if hostID == state.UserID {
+			chat.Outputs <- "You are now the room host. Use /kick, /lock, /unlock and /host to moderate it."
+		} else if hostID != "" {
+			chat.Outputs <- fmt.Sprintf("%s is now the room host.", hostID)
+		}
+	}
+}
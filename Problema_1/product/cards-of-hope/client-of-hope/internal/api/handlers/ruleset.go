@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// HandleRules exibe as regras da sala atual ou, fora de uma sala, os conjuntos de regras
+// oferecidos pelo servidor.
+//
+// Uso: /rules [-all]
+func HandleRules(client *api.Client, chat *ui.Chat, args []string) {
+	listAll := len(args) > 0 && args[0] == "-all"
+	if state.RoomID != "" && !listAll {
+		chat.Outputs <- formatRuleset(state.RoomRuleset)
+		return
+	}
+
+	response, err := client.DoRequest(protocol.Request{Method: "rulesets", Data: utils.Dict{}})
+	if err != nil {
+		state.Log("Rulesets request failed: %v", err)
+		chat.Outputs <- "Failed to list rulesets."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	items, _ := response.Data["rulesets"].([]any)
+	descriptions := make([]string, 0, len(items))
+	for _, item := range items {
+		descriptions = append(descriptions, formatRuleset(parseRuleset(item)))
+	}
+	chat.Outputs <- "Available rulesets (use /create -rules <id> <room_name>):\n" + strings.Join(descriptions, "\n")
+}
+
+// parseRuleset converte as regras recebidas do servidor em state.Ruleset.
+func parseRuleset(data any) state.Ruleset {
+	var ruleset state.Ruleset
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return ruleset
+	}
+	if err := json.Unmarshal(raw, &ruleset); err != nil {
+		state.Log("Invalid ruleset from server: %v", err)
+	}
+	return ruleset
+}
+
+// formatRuleset descreve um conjunto de regras: cartas, quem vence quem e condições de vitória.
+func formatRuleset(ruleset state.Ruleset) string {
+	if ruleset.ID == "" {
+		return "No ruleset information from the server."
+	}
+	lines := []string{
+		fmt.Sprintf("%s [%s]: %s", ruleset.Name, ruleset.ID, ruleset.Description),
+		"  Cards: " + strings.Join(ruleset.Cards, ", "),
+	}
+	for _, card := range ruleset.Cards {
+		if beaten := ruleset.Beats[card]; len(beaten) > 0 {
+			lines = append(lines, fmt.Sprintf("  %s beats %s", card, strings.Join(beaten, ", ")))
+		}
+	}
+	switch ruleset.StarTieBreak {
+	case "higher":
+		lines = append(lines, "  Otherwise the card with more stars wins the round.")
+	case "lower":
+		lines = append(lines, "  Otherwise the card with fewer stars wins the round.")
+	default:
+		lines = append(lines, "  Otherwise the round is a tie.")
+	}
+	goal := fmt.Sprintf("  First to win %d rounds wins the match.", ruleset.WinningRounds)
+	if ruleset.MaxRounds > 0 {
+		goal += fmt.Sprintf(" After %d rounds the leader wins, or the match is a draw.", ruleset.MaxRounds)
+	}
+	lines = append(lines, goal)
+	for _, ability := range ruleset.Abilities {
+		lines = append(lines, fmt.Sprintf("  Ability %s [%s]: %s", ability.Name, ability.ID, ability.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rulesetAbility retorna a habilidade declarada nas regras da sala atual com o ID informado.
+func rulesetAbility(abilityID string) (state.Ability, bool) {
+	for _, ability := range state.RoomRuleset.Abilities {
+		if ability.ID == abilityID {
+			return ability, true
+		}
+	}
+	return state.Ability{}, false
+}
+
+// abilityName retorna o nome de exibição de uma habilidade da sala atual, ou o próprio ID.
+func abilityName(abilityID string) string {
+	if ability, exists := rulesetAbility(abilityID); exists {
+		return ability.Name
+	}
+	return abilityID
+}
+
+// rulesetHasCard informa se o tipo de carta é aceito pelas regras da sala atual.
+func rulesetHasCard(cardType string) bool {
+	for _, card := range state.RoomRuleset.Cards {
+		if card == cardType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HandleStore mostra os tipos de pacote da loja, com o preço, as chances de cada tipo de carta,
+// raridade e estrelas, e quanto falta para a garantia de raridade de cada um.
+//
+// Uso: /store
+func HandleStore(client *api.Client, chat *ui.Chat, args []string) {
+	response, err := client.DoRequest(protocol.Request{Method: "store_catalog", Data: utils.Dict{"user_id": state.UserID}})
+	if err != nil {
+		state.Log("Store catalog request failed: %v", err)
+		chat.Outputs <- "Failed to get the store catalog."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	packs, _ := response.Data["packs"].([]any)
+	if len(packs) == 0 {
+		chat.Outputs <- "The store has no packages for sale."
+		return
+	}
+	lines := []string{"Card packages:"}
+	for _, item := range packs {
+		pack, _ := item.(map[string]any)
+		id, _ := pack["id"].(string)
+		name, _ := pack["name"].(string)
+		description, _ := pack["description"].(string)
+		price, _ := pack["price"].(float64)
+		cards, _ := pack["cards"].(float64)
+		lines = append(lines, fmt.Sprintf("  %s (/buy %s) - %d coins, %d cards. %s", name, id, int(price), int(cards), description))
+
+		types, _ := pack["types"].(map[string]any)
+		lines = append(lines, "    Types: "+formatChances(types))
+		rarities, _ := pack["rarities"].([]any)
+		for _, tierItem := range rarities {
+			tier, _ := tierItem.(map[string]any)
+			rarity, _ := tier["rarity"].(string)
+			chance, _ := tier["chance"].(float64)
+			stars, _ := tier["stars"].(map[string]any)
+			abilityChance, _ := tier["ability_chance"].(float64)
+			lines = append(lines, fmt.Sprintf("    %s %s%%: stars %s; %d%% chance of an ability",
+				rarity, formatPercent(chance), formatChances(stars), int(abilityChance)))
+		}
+		if pity, ok := pack["pity"].(map[string]any); ok {
+			lines = append(lines, "    "+formatPity(pity))
+		}
+	}
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// formatPity descreve quanto falta para a garantia de raridade de um tipo de pacote.
+func formatPity(pity map[string]any) string {
+	rarity, _ := pity["rarity"].(string)
+	packs, _ := pity["packs"].(float64)
+	opened, _ := pity["opened"].(float64)
+	remaining := int(packs - opened)
+	if remaining <= 1 {
+		return fmt.Sprintf("Your next package is guaranteed to have a %s card or better.", rarity)
+	}
+	return fmt.Sprintf("A %s card or better is guaranteed within %d packages (%d opened without one).", rarity, remaining, int(opened))
+}
+
+// formatChances descreve as chances de um mapa de opções, ordenadas pelo nome.
+func formatChances(chances map[string]any) string {
+	keys := make([]string, 0, len(chances))
+	for key := range chances {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	descriptions := make([]string, 0, len(keys))
+	for _, key := range keys {
+		chance, _ := chances[key].(float64)
+		descriptions = append(descriptions, fmt.Sprintf("%s %s%%", key, formatPercent(chance)))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// formatPercent mostra uma porcentagem sem casas decimais desnecessárias.
+func formatPercent(value float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", value), "0"), ".")
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tournamentUsage resume os subcomandos de /tournament.
+const tournamentUsage = "Usage: /tournament create [-roundrobin] [-rules <ruleset>] <size> [name] | list | show <id> | join <id> | leave <id> | start <id>"
+
+// HandleTournament cria, lista, mostra e gerencia as inscrições dos torneios.
+//
+// Uso: /tournament create [-roundrobin] [-rules <regras>] <tamanho> [nome] | list | show <id> |
+// join <id> | leave <id> | start <id>
+//
+// As salas das partidas são criadas pelo servidor; quando a sua partida fica pronta, o
+// cliente entra nela sozinho.
+func HandleTournament(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to use tournaments."
+		return
+	}
+	if len(args) == 0 {
+		chat.Outputs <- tournamentUsage
+		return
+	}
+
+	command, args := strings.ToLower(args[0]), args[1:]
+	switch command {
+	case "create":
+		createTournament(client, chat, args)
+	case "list":
+		listTournaments(client, chat)
+	case "show", "join", "leave", "start":
+		if len(args) != 1 {
+			chat.Outputs <- fmt.Sprintf("Usage: /tournament %s <id>", command)
+			return
+		}
+		tournamentCommand(client, chat, command, args[0])
+	default:
+		chat.Outputs <- tournamentUsage
+	}
+}
+
+// createTournament cria um torneio com as opções informadas.
+func createTournament(client *api.Client, chat *ui.Chat, args []string) {
+	data := utils.Dict{"user_id": state.UserID, "format": "single_elimination"}
+	var name []string
+	size := 0
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-roundrobin", "-rr":
+			data["format"] = "round_robin"
+		case "-rules":
+			if i+1 >= len(args) {
+				chat.Outputs <- tournamentUsage
+				return
+			}
+			data["ruleset"] = strings.ToLower(args[i+1])
+			i++
+		default:
+			if value, err := strconv.Atoi(args[i]); err == nil && size == 0 && len(name) == 0 {
+				size = value
+				continue
+			}
+			name = append(name, args[i])
+		}
+	}
+	if size == 0 {
+		chat.Outputs <- tournamentUsage
+		return
+	}
+	data["size"] = size
+	data["name"] = strings.Join(name, " ")
+
+	tournament, ok := tournamentRequest(client, chat, "tournament_create", data)
+	if !ok {
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("Tournament '%s' created (id %s, %s, %d players, rules: %s). Players join with /tournament join %s.",
+		tournament.Name, tournament.ID, formatName(tournament.Format), tournament.Size, tournament.RulesetID, tournament.ID)
+	chat.Outputs <- "You are not registered yet: use /tournament join to play. Use /tournament start to begin before it is full."
+}
+
+// listTournaments mostra os torneios do servidor.
+func listTournaments(client *api.Client, chat *ui.Chat) {
+	response, err := client.DoRequest(protocol.Request{Method: "tournament_list", Data: utils.Dict{}})
+	if err != nil {
+		state.Log("Tournament list request failed: %v", err)
+		chat.Outputs <- "Failed to list tournaments."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	tournaments, _ := response.Data["tournaments"].([]any)
+	if len(tournaments) == 0 {
+		chat.Outputs <- "No tournaments yet. Create one with /tournament create <size> [name]."
+		return
+	}
+	lines := []string{"Tournaments:"}
+	for _, item := range tournaments {
+		tournament := parseTournament(item)
+		line := fmt.Sprintf("  [%s] %s - %s - %d/%d players - %s - owner: %s",
+			tournament.ID, tournament.Name, formatName(tournament.Format), len(tournament.Players), tournament.Size, tournament.Status, tournament.OwnerID)
+		if tournament.WinnerID != "" {
+			line += " - champion: " + tournament.WinnerID
+		}
+		lines = append(lines, line)
+	}
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// tournamentCommand envia um comando sobre um torneio e mostra o resultado.
+func tournamentCommand(client *api.Client, chat *ui.Chat, command, tournamentID string) {
+	method := map[string]string{
+		"show":  "tournament_get",
+		"join":  "tournament_join",
+		"leave": "tournament_leave",
+		"start": "tournament_start",
+	}[command]
+
+	tournament, ok := tournamentRequest(client, chat, method, utils.Dict{"user_id": state.UserID, "tournament_id": tournamentID})
+	if !ok {
+		return
+	}
+	switch command {
+	case "show":
+		chat.Outputs <- formatTournament(tournament)
+	case "join":
+		if tournament.Status == "registering" {
+			chat.Outputs <- fmt.Sprintf("You joined '%s' (%d/%d players). Your first match room will open when it starts.",
+				tournament.Name, len(tournament.Players), tournament.Size)
+		}
+	case "leave":
+		chat.Outputs <- fmt.Sprintf("You left tournament '%s'.", tournament.Name)
+	}
+}
+
+// tournamentRequest envia uma requisição de torneio e converte o torneio da resposta.
+func tournamentRequest(client *api.Client, chat *ui.Chat, method string, data utils.Dict) (state.Tournament, bool) {
+	response, err := client.DoRequest(protocol.Request{Method: method, Data: data})
+	if err != nil {
+		state.Log("Tournament request %s failed: %v", method, err)
+		chat.Outputs <- "Failed to reach the server for the tournament."
+		return state.Tournament{}, false
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return state.Tournament{}, false
+	}
+	return parseTournament(response.Data["tournament"]), true
+}
+
+// parseTournament converte o torneio recebido do servidor.
+func parseTournament(data any) state.Tournament {
+	var tournament state.Tournament
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return tournament
+	}
+	if err := json.Unmarshal(raw, &tournament); err != nil {
+		state.Log("Invalid tournament from server: %v", err)
+	}
+	return tournament
+}
+
+// formatName troca os sublinhados de um identificador por espaços.
+func formatName(id string) string {
+	return strings.ReplaceAll(id, "_", " ")
+}
+
+// formatTournament descreve o torneio: cabeçalho, chave ou rodadas e classificação.
+func formatTournament(tournament state.Tournament) string {
+	lines := []string{fmt.Sprintf("%s [id %s] - %s - %d/%d players - rules: %s - %s",
+		tournament.Name, tournament.ID, formatName(tournament.Format), len(tournament.Players), tournament.Size, tournament.RulesetID, tournament.Status)}
+
+	switch {
+	case len(tournament.Matches) == 0:
+		lines = append(lines, "Registered: "+joinPlayers(tournament.Players))
+	case tournament.Format == "round_robin":
+		lines = append(lines, formatRoundRobin(tournament)...)
+	default:
+		lines = append(lines, formatBracket(tournament)...)
+	}
+
+	if tournament.Status == "finished" {
+		if tournament.WinnerID != "" {
+			lines = append(lines, fmt.Sprintf("Champion: %s!", tournament.WinnerID))
+		} else {
+			lines = append(lines, "The tournament ended with a tie at the top of the standings.")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// joinPlayers junta os nomes dos jogadores, indicando quando não há nenhum.
+func joinPlayers(players []string) string {
+	if len(players) == 0 {
+		return "none"
+	}
+	return strings.Join(players, ", ")
+}
+
+// formatRoundRobin descreve as partidas de cada rodada e a tabela de pontos do todos contra todos.
+func formatRoundRobin(tournament state.Tournament) []string {
+	var lines []string
+	for round := 1; round <= tournament.Rounds; round++ {
+		lines = append(lines, fmt.Sprintf("Round %d:", round))
+		for _, match := range tournament.Matches {
+			if match.Round == round {
+				lines = append(lines, fmt.Sprintf("  %-8s %s vs %s - %s", match.ID, match.Players[0], match.Players[1], matchOutcome(match)))
+			}
+		}
+	}
+	lines = append(lines, "Standings (win 3, draw 1):")
+	for index, standing := range tournament.Standings {
+		lines = append(lines, fmt.Sprintf("  %d. %-16s %2d pts  %dW %dD %dL",
+			index+1, standing.UserID, standing.Points, standing.Wins, standing.Draws, standing.Losses))
+	}
+	return lines
+}
+
+// matchOutcome descreve o estado de uma partida do torneio.
+func matchOutcome(match state.TournamentMatch) string {
+	switch match.Status {
+	case "playing":
+		return "playing in room " + match.RoomID
+	case "finished":
+		switch {
+		case match.WinnerID == "":
+			return "draw"
+		case match.Walkover:
+			return match.WinnerID + " won (walkover)"
+		}
+		return match.WinnerID + " won"
+	case "bye":
+		return match.WinnerID + " advances (bye)"
+	}
+	return "waiting"
+}
+
+// formatBracket desenha a chave da eliminação simples, com uma coluna por rodada e o campeão à
+// direita. Cada partida liga as linhas dos dois jogadores ao vencedor na coluna seguinte.
+func formatBracket(tournament state.Tournament) []string {
+	width := len("(bye)")
+	for _, playerID := range tournament.Players {
+		width = max(width, len([]rune(playerID)))
+	}
+	firstRound := 0
+	for _, match := range tournament.Matches {
+		if match.Round == 1 {
+			firstRound++
+		}
+	}
+
+	column := width + 3
+	grid := make([][]rune, 4*firstRound-1)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", column*tournament.Rounds+width))
+	}
+	write := func(row, x int, text string) {
+		copy(grid[row][x:], []rune(text))
+	}
+	// center devolve a linha do vencedor da partida (r, slot); as duas linhas dos jogadores
+	// são os centros das partidas que a alimentam.
+	center := func(round, slot int) int {
+		return (1<<(round+1))*slot + (1 << round) - 1
+	}
+
+	for _, match := range tournament.Matches {
+		x := (match.Round - 1) * column
+		middle := center(match.Round, match.Slot)
+		top, bottom := middle-(1<<(match.Round-1)), middle+(1<<(match.Round-1))
+		for side, row := range []int{top, bottom} {
+			name := match.Players[side]
+			switch {
+			case name == "" && match.Status == "bye":
+				name = "(bye)"
+			case name == "":
+				name = "?"
+			}
+			write(row, x, fmt.Sprintf("%-*s ", width, name))
+		}
+		write(top, x+width+1, "┐")
+		write(bottom, x+width+1, "┘")
+		for row := top + 1; row < bottom; row++ {
+			write(row, x+width+1, "│")
+		}
+		write(middle, x+width+1, "├─")
+	}
+
+	champion := tournament.WinnerID
+	if champion == "" {
+		champion = "?"
+	}
+	write(center(tournament.Rounds, 0), tournament.Rounds*column, champion)
+
+	lines := make([]string, 0, len(grid))
+	for _, row := range grid {
+		lines = append(lines, "  "+strings.TrimRight(string(row), " "))
+	}
+	return lines
+}
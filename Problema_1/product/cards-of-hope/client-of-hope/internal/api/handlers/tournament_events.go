@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"fmt"
+)
+
+// HandleTournamentUpdate exibe as inscrições, os resultados e o fim dos torneios de que o
+// usuário participa.
+func HandleTournamentUpdate(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	event, _ := response.Data["event"].(string)
+	userID, _ := response.Data["user_id"].(string)
+	matchID, _ := response.Data["match_id"].(string)
+	tournament := parseTournament(response.Data["tournament"])
+
+	switch event {
+	case "registered":
+		if userID != state.UserID {
+			chat.Outputs <- fmt.Sprintf("%s joined tournament '%s' (%d/%d players).", userID, tournament.Name, len(tournament.Players), tournament.Size)
+		}
+	case "unregistered":
+		if userID != state.UserID {
+			chat.Outputs <- fmt.Sprintf("%s left tournament '%s' (%d/%d players).", userID, tournament.Name, len(tournament.Players), tournament.Size)
+		}
+	case "started":
+		chat.Outputs <- fmt.Sprintf("Tournament '%s' started!\n%s", tournament.Name, formatTournament(tournament))
+	case "match_finished":
+		chat.Outputs <- fmt.Sprintf("Tournament '%s', match %s: %s.", tournament.Name, matchID, tournamentMatchOutcome(tournament, matchID))
+	case "match_drawn":
+		chat.Outputs <- fmt.Sprintf("Tournament '%s', match %s ended in a draw: the players must play again in the same room.", tournament.Name, matchID)
+	case "walkover":
+		chat.Outputs <- fmt.Sprintf("Tournament '%s', match %s: %s.", tournament.Name, matchID, tournamentMatchOutcome(tournament, matchID))
+	case "finished":
+		chat.Outputs <- fmt.Sprintf("Tournament '%s' is over!\n%s", tournament.Name, formatTournament(tournament))
+	}
+}
+
+// tournamentMatchOutcome descreve o resultado da partida informada do torneio.
+func tournamentMatchOutcome(tournament state.Tournament, matchID string) string {
+	for _, match := range tournament.Matches {
+		if match.ID == matchID {
+			return fmt.Sprintf("%s vs %s - %s", match.Players[0], match.Players[1], matchOutcome(match))
+		}
+	}
+	return "finished"
+}
+
+// HandleTournamentMatch leva o usuário à sala da sua próxima partida de torneio. Quem está
+// em outra sala sem partida em andamento sai dela; no meio de uma partida, apenas é avisado.
+func HandleTournamentMatch(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	tournamentName, _ := response.Data["tournament_name"].(string)
+	matchID, _ := response.Data["match_id"].(string)
+	roomID, _ := response.Data["room_id"].(string)
+	opponentID, _ := response.Data["opponent_id"].(string)
+	room, _ := response.Data["room"].(map[string]any)
+	name, _ := room["name"].(string)
+
+	if state.RoomID != "" && state.RoomID != roomID {
+		if state.InMatch {
+			chat.Outputs <- fmt.Sprintf("Your match %s of tournament '%s' against %s is ready in room '%s'. Finish or /leave this match, then use /join %s.",
+				matchID, tournamentName, opponentID, name, roomID)
+			return
+		}
+		HandleLeaveRoom(client, chat, nil)
+	}
+
+	state.RoomID = roomID
+	state.RoomName = name
+	state.Spectating = false
+	state.RoomHostID, _ = room["host_id"].(string)
+	state.InMatch = false
+	state.RoomRuleset = parseRuleset(response.Data["ruleset"])
+	state.RoomCommitReveal, _ = room["commit_reveal"].(bool)
+
+	chat.Outputs <- fmt.Sprintf("Your match %s of tournament '%s' against %s is ready in room '%s' (%s).", matchID, tournamentName, opponentID, name, roomID)
+	chat.Outputs <- fmt.Sprintf("Ruleset: %s. Use /rules to see it.", state.RoomRuleset.Name)
+	if state.RoomCommitReveal {
+		chat.Outputs <- commitRevealNotice
+	}
+	chat.Outputs <- "Type /ready when you are ready to play. Leaving the room forfeits the match."
+}
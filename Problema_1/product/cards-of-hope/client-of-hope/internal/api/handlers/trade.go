@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tradeUsage resume os subcomandos de /trade.
+const tradeUsage = "Usage: /trade offer <user> <your ids|-> [for <their ids>] [-minutes <n>] | list | accept <id> | decline <id> | cancel <id>"
+
+// HandleTrade propõe, lista e responde trocas de cartas com outros usuários.
+//
+// Uso: /trade offer <usuário> <seus ids|-> [for <ids dele>] [-minutes <n>] | list |
+// accept <id> | decline <id> | cancel <id>
+//
+// Os IDs são separados por vírgula; "-" não oferece nenhuma carta. Use /cards <usuário> para
+// ver os IDs das cartas de outro usuário.
+func HandleTrade(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to trade cards."
+		return
+	}
+	if len(args) == 0 {
+		chat.Outputs <- tradeUsage
+		return
+	}
+
+	command, args := strings.ToLower(args[0]), args[1:]
+	switch command {
+	case "offer":
+		offerTrade(client, chat, args)
+	case "list":
+		listTrades(client, chat)
+	case "accept", "decline", "cancel":
+		if len(args) != 1 {
+			chat.Outputs <- fmt.Sprintf("Usage: /trade %s <id>", command)
+			return
+		}
+		answerTrade(client, chat, command, args[0])
+	default:
+		chat.Outputs <- tradeUsage
+	}
+}
+
+// offerTrade propõe uma troca de cartas a outro usuário.
+func offerTrade(client *api.Client, chat *ui.Chat, args []string) {
+	if len(args) < 2 {
+		chat.Outputs <- tradeUsage
+		return
+	}
+	data := utils.Dict{
+		"user_id":   state.UserID,
+		"target_id": args[0],
+		"offer":     splitCardIDs(args[1]),
+		"request":   []string{},
+	}
+	for i := 2; i < len(args); i++ {
+		switch {
+		case args[i] == "for" && i+1 < len(args):
+			data["request"] = splitCardIDs(args[i+1])
+			i++
+		case args[i] == "-minutes" && i+1 < len(args):
+			minutes, err := strconv.Atoi(args[i+1])
+			if err != nil || minutes <= 0 {
+				chat.Outputs <- tradeUsage
+				return
+			}
+			data["minutes"] = minutes
+			i++
+		default:
+			chat.Outputs <- tradeUsage
+			return
+		}
+	}
+
+	trade, ok := tradeRequest(client, chat, "trade_offer", data)
+	if !ok {
+		return
+	}
+	chat.Outputs <- fmt.Sprintf("Trade %s offered to %s: %s. It expires at %s; /trade cancel %s withdraws it.",
+		trade.ID, trade.ToID, describeTrade(trade), formatExpiry(trade.ExpiresAt), trade.ID)
+}
+
+// splitCardIDs separa os IDs de cartas escritos com vírgulas; "-" é a lista vazia.
+func splitCardIDs(arg string) []string {
+	cardIDs := []string{}
+	for _, cardID := range strings.Split(arg, ",") {
+		if cardID = strings.TrimSpace(cardID); cardID != "" && cardID != "-" {
+			cardIDs = append(cardIDs, cardID)
+		}
+	}
+	return cardIDs
+}
+
+// listTrades mostra as propostas pendentes feitas e recebidas pelo usuário.
+func listTrades(client *api.Client, chat *ui.Chat) {
+	response, err := client.DoRequest(protocol.Request{Method: "trades", Data: utils.Dict{"user_id": state.UserID}})
+	if err != nil {
+		state.Log("Trade list request failed: %v", err)
+		chat.Outputs <- "Failed to list trades."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	trades, _ := response.Data["trades"].([]any)
+	if len(trades) == 0 {
+		chat.Outputs <- "No pending trades."
+		return
+	}
+	lines := []string{"Pending trades:"}
+	for _, item := range trades {
+		trade := parseTrade(item)
+		direction := "from " + trade.FromID
+		if trade.FromID == state.UserID {
+			direction = "to " + trade.ToID
+		}
+		lines = append(lines, fmt.Sprintf("  [%s] %s - %s - expires at %s", trade.ID, direction, describeTrade(trade), formatExpiry(trade.ExpiresAt)))
+	}
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// answerTrade aceita, recusa ou cancela uma proposta de troca.
+func answerTrade(client *api.Client, chat *ui.Chat, command, tradeID string) {
+	response, err := client.DoRequest(protocol.Request{
+		Method: "trade_" + command,
+		Data:   utils.Dict{"user_id": state.UserID, "trade_id": tradeID},
+	})
+	if err != nil {
+		state.Log("Trade %s request failed: %v", command, err)
+		chat.Outputs <- "Failed to reach the server for the trade."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	trade := parseTrade(response.Data["trade"])
+	switch command {
+	case "accept":
+		chat.Outputs <- fmt.Sprintf("Trade %s done. You received: %s.", trade.ID, formatCards(parseCards(response.Data["received"])))
+	case "decline":
+		chat.Outputs <- fmt.Sprintf("You declined trade %s from %s.", trade.ID, trade.FromID)
+	case "cancel":
+		chat.Outputs <- fmt.Sprintf("You cancelled trade %s to %s.", trade.ID, trade.ToID)
+	}
+}
+
+// tradeRequest envia uma requisição de troca e converte a proposta da resposta.
+func tradeRequest(client *api.Client, chat *ui.Chat, method string, data utils.Dict) (state.Trade, bool) {
+	response, err := client.DoRequest(protocol.Request{Method: method, Data: data})
+	if err != nil {
+		state.Log("Trade request %s failed: %v", method, err)
+		chat.Outputs <- "Failed to reach the server for the trade."
+		return state.Trade{}, false
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return state.Trade{}, false
+	}
+	return parseTrade(response.Data["trade"]), true
+}
+
+// HandleTradeUpdate avisa as propostas recebidas e as respostas às propostas do usuário.
+func HandleTradeUpdate(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	event, _ := response.Data["event"].(string)
+	trade := parseTrade(response.Data["trade"])
+
+	switch event {
+	case "offered":
+		chat.Outputs <- fmt.Sprintf("%s offers you a trade [%s]: %s. It expires at %s. Use /trade accept %s or /trade decline %s.",
+			trade.FromID, trade.ID, describeTrade(trade), formatExpiry(trade.ExpiresAt), trade.ID, trade.ID)
+	case "accepted":
+		chat.Outputs <- fmt.Sprintf("%s accepted trade %s. You received: %s.", trade.ToID, trade.ID, formatCards(parseCards(response.Data["received"])))
+	case "declined":
+		chat.Outputs <- fmt.Sprintf("%s declined trade %s.", trade.ToID, trade.ID)
+	case "cancelled":
+		chat.Outputs <- fmt.Sprintf("%s cancelled trade %s.", trade.FromID, trade.ID)
+	case "expired":
+		chat.Outputs <- fmt.Sprintf("Trade %s between %s and %s expired.", trade.ID, trade.FromID, trade.ToID)
+	case "failed":
+		chat.Outputs <- fmt.Sprintf("Trade %s between %s and %s failed: some of the cards are no longer in their owner's collection.", trade.ID, trade.FromID, trade.ToID)
+	}
+}
+
+// parseTrade converte a proposta de troca recebida do servidor.
+func parseTrade(data any) state.Trade {
+	var trade state.Trade
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return trade
+	}
+	if err := json.Unmarshal(raw, &trade); err != nil {
+		state.Log("Invalid trade from server: %v", err)
+	}
+	return trade
+}
+
+// describeTrade descreve as cartas de cada lado da proposta.
+func describeTrade(trade state.Trade) string {
+	return fmt.Sprintf("%s gives %s for %s's %s", trade.FromID, formatCards(trade.Offered), trade.ToID, formatCards(trade.Requested))
+}
+
+// formatCards descreve uma lista de cartas, indicando quando não há nenhuma.
+func formatCards(cards []state.HandCard) string {
+	if len(cards) == 0 {
+		return "nothing"
+	}
+	descriptions := make([]string, 0, len(cards))
+	for _, card := range cards {
+		descriptions = append(descriptions, formatCard(card))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// formatExpiry mostra um prazo no horário local, com a data quando não é hoje.
+func formatExpiry(expiresAt string) string {
+	moment, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return expiresAt
+	}
+	moment = moment.Local()
+	if moment.Format(time.DateOnly) != time.Now().Format(time.DateOnly) {
+		return moment.Format("2006-01-02 15:04")
+	}
+	return moment.Format("15:04")
+}
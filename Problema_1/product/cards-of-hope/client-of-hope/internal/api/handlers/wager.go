@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"fmt"
+	"strings"
+)
+
+// HandleWagerSettled exibe o destino das apostas de uma partida: pagas ao vencedor ou devolvidas.
+func HandleWagerSettled(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	status, _ := response.Data["status"].(string)
+	winnerID, _ := response.Data["winner_id"].(string)
+	roomID, _ := response.Data["room_id"].(string)
+	received := parseCards(response.Data["received"])
+
+	switch {
+	case status == "refunded":
+		chat.Outputs <- fmt.Sprintf("The wager of room %s was returned: everyone got their stake back.", roomID)
+	case winnerID == state.UserID:
+		chat.Outputs <- fmt.Sprintf("You won the wager of room %s: %s.", roomID, formatPot(response.Data))
+	default:
+		chat.Outputs <- fmt.Sprintf("%s won the wager of room %s: %s.", winnerID, roomID, formatPot(response.Data))
+	}
+	if len(received) > 0 {
+		chat.Outputs <- "Cards added to your collection: " + formatCards(received)
+	}
+}
+
+// wagerCards lê quantas cartas cada jogador aposta nas partidas da sala.
+func wagerCards(room map[string]any) int {
+	cards, _ := room["wager_cards"].(float64)
+	return int(cards)
+}
+
+// wagerNotice explica a aposta da sala, ou retorna vazio se a sala não aposta.
+func wagerNotice(room map[string]any) string {
+	coins, _ := room["wager_coins"].(float64)
+	cards := wagerCards(room)
+	if coins == 0 && cards == 0 {
+		return ""
+	}
+	var stakes []string
+	if coins > 0 {
+		stakes = append(stakes, fmt.Sprintf("%d coins", int(coins)))
+	}
+	if cards > 0 {
+		stakes = append(stakes, fmt.Sprintf("%d card(s) of your choice", cards))
+	}
+	notice := fmt.Sprintf("This is a wager room: each player stakes %s per match. Stakes are held in escrow when the match starts; the winner takes them all and a draw returns them. Leaving or staying disconnected during the match forfeits it.", strings.Join(stakes, " and "))
+	if cards > 0 {
+		notice += " Choose your cards with /ready <ids>."
+	}
+	return notice
+}
+
+// readyCommand mostra como ficar pronto na sala atual, pedindo as cartas da aposta quando há.
+func readyCommand() string {
+	if state.RoomWagerCards > 0 {
+		return "/ready <ids>"
+	}
+	return "/ready"
+}
+
+// formatPot descreve as moedas e as cartas apostadas em uma partida.
+func formatPot(wager map[string]any) string {
+	coins, _ := wager["pot_coins"].(float64)
+	cards := parseCards(wager["pot_cards"])
+	var parts []string
+	if coins > 0 {
+		parts = append(parts, fmt.Sprintf("%d coins", int(coins)))
+	}
+	for _, card := range cards {
+		parts = append(parts, fmt.Sprintf("%s (%d stars%s)", card.Type, card.Stars, rarityNote(card.Rarity)))
+	}
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
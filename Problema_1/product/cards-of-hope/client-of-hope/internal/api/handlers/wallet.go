@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"client-of-hope/internal/api"
+	"client-of-hope/internal/api/protocol"
+	"client-of-hope/internal/state"
+	"client-of-hope/internal/ui"
+	"client-of-hope/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ledgerReasons descreve os motivos dos lançamentos da carteira.
+var ledgerReasons = map[string]string{
+	"starter":         "starting coins",
+	"round_win":       "round won",
+	"match_win":       "match won",
+	"purchase":        "card package",
+	"refund":          "refund",
+	"grant":           "granted by an admin",
+	"market_purchase": "market purchase",
+	"bid":             "auction bid held",
+	"bid_refund":      "outbid, bid returned",
+	"sale":            "market sale",
+	"craft":           "card crafted",
+	"disenchant":      "cards disenchanted",
+	"wager":           "wager held in escrow",
+	"wager_win":       "wager won",
+	"wager_refund":    "wager returned",
+}
+
+// HandleBalance mostra o saldo de moedas e os lançamentos mais recentes da carteira.
+//
+// Uso: /balance
+func HandleBalance(client *api.Client, chat *ui.Chat, args []string) {
+	if state.UserID == "" {
+		chat.Outputs <- "You must be logged in to see your balance."
+		return
+	}
+
+	response, err := client.DoRequest(protocol.Request{Method: "balance", Data: utils.Dict{"user_id": state.UserID}})
+	if err != nil {
+		state.Log("Balance request failed: %v", err)
+		chat.Outputs <- "Failed to get your balance."
+		return
+	}
+	if response.Status != "ok" {
+		message, _ := response.Data["message"].(string)
+		chat.Outputs <- message
+		return
+	}
+
+	balance, _ := response.Data["balance"].(float64)
+	dust, _ := response.Data["dust"].(float64)
+	price, _ := response.Data["package_price"].(float64)
+	roundWin, _ := response.Data["round_win_coins"].(float64)
+	matchWin, _ := response.Data["match_win_coins"].(float64)
+	lines := []string{
+		fmt.Sprintf("Balance: %d coins and %d crafting dust. A card package costs %d; you earn %d per round and %d per match you win against players.",
+			int(balance), int(dust), int(price), int(roundWin), int(matchWin)),
+	}
+	history, _ := response.Data["history"].([]any)
+	if len(history) > 0 {
+		lines = append(lines, "Recent entries:")
+	}
+	for _, item := range history {
+		entry, _ := item.(map[string]any)
+		amount, _ := entry["amount"].(float64)
+		entryBalance, _ := entry["balance"].(float64)
+		reason, _ := entry["reason"].(string)
+		reference, _ := entry["reference"].(string)
+		currency, _ := entry["currency"].(string)
+		when := ""
+		if createdAt, err := time.Parse(time.RFC3339, fmt.Sprint(entry["created_at"])); err == nil {
+			when = createdAt.Local().Format("2006-01-02 15:04")
+		}
+		line := fmt.Sprintf("  %s  %+5d  %5d  %s", when, int(amount), int(entryBalance), formatLedgerReason(reason))
+		if currency != "" {
+			line += " [" + currency + "]"
+		}
+		if reference != "" {
+			line += " (" + reference + ")"
+		}
+		lines = append(lines, line)
+	}
+	chat.Outputs <- strings.Join(lines, "\n")
+}
+
+// HandleCoins avisa os prêmios em moedas recebidos nas partidas.
+func HandleCoins(client *api.Client, chat *ui.Chat, response protocol.Response) {
+	amount, _ := response.Data["amount"].(float64)
+	balance, _ := response.Data["balance"].(float64)
+	reason, _ := response.Data["reason"].(string)
+	chat.Outputs <- fmt.Sprintf("+%d coins (%s). Balance: %d.", int(amount), formatLedgerReason(reason), int(balance))
+}
+
+// formatLedgerReason descreve o motivo de um lançamento.
+func formatLedgerReason(reason string) string {
+	if description, ok := ledgerReasons[reason]; ok {
+		return description
+	}
+	return reason
+}
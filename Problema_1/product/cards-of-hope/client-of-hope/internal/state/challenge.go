@@ -0,0 +1,28 @@
+// Pacote state descreve os desafios recebidos do servidor.
+package state
+
+// Challenge descreve o desafio de um usuário a outro para um duelo, enviado pelo servidor.
+//
+// Campos:
+//   - ID: identificador do desafio.
+//   - FromID: quem desafiou.
+//   - ToID: quem foi desafiado.
+//   - RulesetID: conjunto de regras do duelo.
+//   - CommitReveal: o duelo usa jogadas com compromisso e revelação.
+//   - Status: estado do desafio (pending, accepted, declined, cancelled, expired).
+//   - RoomID: sala do duelo, depois do aceite.
+//   - ExpiresAt: momento em que o desafio deixa de valer (RFC 3339).
+type Challenge struct {
+	ID           string `json:"id"`
+	FromID       string `json:"from_id"`
+	ToID         string `json:"to_id"`
+	RulesetID    string `json:"ruleset_id"`
+	CommitReveal bool   `json:"commit_reveal"`
+	Status       string `json:"status"`
+	RoomID       string `json:"room_id"`
+	ExpiresAt    string `json:"expires_at"`
+}
+
+// LastChallengeID guarda o último desafio recebido, respondido por /challenge accept e
+// /challenge decline quando nenhum ID é informado.
+var LastChallengeID string
@@ -0,0 +1,9 @@
+// Pacote state armazena os canais de chat em que o usuário está.
+package state
+
+// Channel armazena o canal de chat atual, que recebe as mensagens digitadas fora de uma sala e as enviadas com /c.
+// Channels armazena os canais de chat em que o usuário está, na ordem em que entrou.
+var (
+	Channel  string
+	Channels []string
+)
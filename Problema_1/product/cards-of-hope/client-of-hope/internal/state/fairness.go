@@ -0,0 +1,46 @@
+// Pacote state descreve as sementes e os pacotes abertos do sorteio verificável da loja.
+package state
+
+// PackSeed descreve uma semente dos sorteios de pacotes, enviada pelo servidor.
+//
+// Campos:
+//   - ID: identificador da semente.
+//   - Hash: hash SHA-256 da semente do servidor, publicado antes do uso.
+//   - ServerSeed: semente do servidor, só enviada depois de revelada.
+//   - ClientSeed: semente escolhida pelo usuário.
+//   - Nonce: pacotes abertos com a semente; é o nonce do próximo pacote.
+//   - RevealedAt: momento em que a semente foi revelada (RFC 3339), vazio enquanto está em uso.
+type PackSeed struct {
+	ID         string `json:"id"`
+	Hash       string `json:"hash"`
+	ServerSeed string `json:"server_seed"`
+	ClientSeed string `json:"client_seed"`
+	Nonce      int    `json:"nonce"`
+	RevealedAt string `json:"revealed_at"`
+}
+
+// PackOpening descreve um pacote aberto, com a semente e o nonce do seu sorteio.
+//
+// Campos:
+//   - ID: identificador do pacote aberto.
+//   - Pack: tipo de pacote.
+//   - SeedID: semente do servidor usada no sorteio.
+//   - ClientSeed: semente do usuário no momento do sorteio.
+//   - Nonce: nonce do pacote na semente.
+//   - Guarantee: indica se o pacote cumpriu a garantia de raridade.
+//   - Cards: cartas sorteadas.
+//   - CreatedAt: momento da abertura (RFC 3339).
+type PackOpening struct {
+	ID         string     `json:"id"`
+	Pack       string     `json:"pack"`
+	SeedID     string     `json:"seed_id"`
+	ClientSeed string     `json:"client_seed"`
+	Nonce      int        `json:"nonce"`
+	Guarantee  bool       `json:"guarantee"`
+	Cards      []HandCard `json:"cards"`
+	CreatedAt  string     `json:"created_at"`
+}
+
+// SeedHashes guarda, por ID de semente, os hashes publicados que o cliente recebeu nesta sessão,
+// para conferir a semente quando ela for revelada.
+var SeedHashes = map[string]string{}
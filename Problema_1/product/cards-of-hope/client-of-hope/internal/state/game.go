@@ -1,23 +1,39 @@
-// Pacote state armazena o estado do jogo, incluindo cartas, jogadas e regras de vitória.
+// Pacote state armazena o estado do jogo, incluindo cartas, jogadas e o andamento da partida.
 package state
 
-import "client-of-hope/internal/utils"
+// HandCard descreve uma carta da coleção do usuário, na mão durante uma partida.
+//
+// Campos:
+//   - ID: identificador da carta na coleção.
+//   - Type: tipo da carta.
+//   - Stars: estrelas da carta.
+//   - Ability: habilidade especial da carta (vazio se não tiver).
+//   - Rarity: raridade da carta (vazio para as cartas comuns anteriores às raridades).
+type HandCard struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Stars   int    `json:"stars"`
+	Ability string `json:"ability,omitempty"`
+	Rarity  string `json:"rarity,omitempty"`
+}
 
-// Cards armazena o número de cartas disponíveis para o usuário.
+// Hand armazena as cartas na mão do usuário na partida atual, enviadas pelo servidor.
+// DrawPile armazena quantas cartas do baralho o usuário ainda vai comprar na partida atual.
 // PlayedCard representa a última carta jogada pelo usuário.
 // PlayedCardStar representa o valor especial da carta jogada pelo usuário.
 // OpponentCard representa a última carta jogada pelo oponente.
 // OpponentCardStar representa o valor especial da carta do oponente.
-// CardWins define as regras de vitória entre as cartas (pedra, papel, tesoura).
+// InMatch indica se há uma partida em andamento na sala atual; o vencedor de cada rodada é decidido pelo servidor.
+// PendingCard armazena a carta comprometida na rodada, revelada quando o oponente também se comprometer.
+// PendingNonce armazena o nonce usado no compromisso da carta pendente.
 var (
-	Cards            *utils.Map[string, int] = utils.NewMap[string, int]()
-	PlayedCard       string                  = ""
-	PlayedCardStar   int                     = 0
-	OpponentCard     string                  = ""
-	OpponentCardStar int                     = 0
-	CardWins         map[string]string       = map[string]string{
-		"rock":     "scissors",
-		"paper":    "rock",
-		"scissors": "paper",
-	}
+	Hand             []HandCard = nil
+	DrawPile         int        = 0
+	PlayedCard       string     = ""
+	PlayedCardStar   int        = 0
+	OpponentCard     string     = ""
+	OpponentCardStar int        = 0
+	InMatch          bool       = false
+	PendingCard      HandCard   = HandCard{}
+	PendingNonce     string     = ""
 )
@@ -0,0 +1,32 @@
+// Pacote state descreve os anúncios do mercado recebidos do servidor.
+package state
+
+// Listing descreve um anúncio do mercado de cartas, enviado pelo servidor.
+//
+// Campos:
+//   - ID: identificador do anúncio.
+//   - SellerID: quem anunciou as cartas.
+//   - Kind: tipo do anúncio (fixed, auction).
+//   - Cards: cartas anunciadas, sem IDs de coleção enquanto estão retidas.
+//   - Price: preço pedido ou lance mínimo, em moedas.
+//   - Status: estado do anúncio (active, sold, cancelled, expired).
+//   - BuyerID: quem levou as cartas, quando vendido.
+//   - Bids: quantidade de lances do leilão.
+//   - MinimumBid: menor lance aceito pelo leilão.
+//   - HighestBid: maior lance do leilão.
+//   - HighestBidder: quem deu o maior lance.
+//   - EndsAt: momento em que o anúncio termina (RFC 3339).
+type Listing struct {
+	ID            string     `json:"id"`
+	SellerID      string     `json:"seller_id"`
+	Kind          string     `json:"kind"`
+	Cards         []HandCard `json:"cards"`
+	Price         int        `json:"price"`
+	Status        string     `json:"status"`
+	BuyerID       string     `json:"buyer_id"`
+	Bids          int        `json:"bids"`
+	MinimumBid    int        `json:"minimum_bid"`
+	HighestBid    int        `json:"highest_bid"`
+	HighestBidder string     `json:"highest_bidder"`
+	EndsAt        string     `json:"ends_at"`
+}
@@ -0,0 +1,65 @@
+// Pacote state armazena o registro de partida aberto no modo de replay.
+package state
+
+import "time"
+
+// Replay descreve o registro de uma partida encerrada, enviado pelo servidor.
+//
+// Campos:
+//   - ID: identificador do registro.
+//   - RoomName: nome da sala da partida.
+//   - Number: número da partida dentro da série de revanches.
+//   - RulesetID: regras da partida.
+//   - Players: jogadores na ordem dos assentos.
+//   - WinnerID: vencedor da partida (vazio em empate ou abandono).
+//   - ForfeitedBy: jogador que perdeu por revelações inválidas.
+//   - Abandoned: a partida terminou porque um jogador saiu da sala.
+//   - Events: eventos da partida, em ordem.
+type Replay struct {
+	ID          string        `json:"id"`
+	RoomName    string        `json:"room_name"`
+	Number      int           `json:"match_number"`
+	RulesetID   string        `json:"ruleset_id"`
+	Players     []string      `json:"players"`
+	WinnerID    string        `json:"winner_id"`
+	ForfeitedBy string        `json:"forfeited_by"`
+	Abandoned   bool          `json:"abandoned"`
+	Events      []ReplayEvent `json:"events"`
+}
+
+// ReplayEvent descreve um evento do registro de uma partida.
+//
+// Campos:
+//   - At: momento do evento.
+//   - Type: tipo do evento (match_started, joined, left, kicked, chat, play, round_result ou match_finished).
+//   - UserID: usuário que causou o evento.
+//   - Round: rodada em que o evento aconteceu.
+//   - Card: carta jogada, nos eventos play.
+//   - Text: mensagem enviada, nos eventos chat.
+//   - Result: resultado da rodada, nos eventos round_result.
+type ReplayEvent struct {
+	At     time.Time    `json:"at"`
+	Type   string       `json:"type"`
+	UserID string       `json:"user_id"`
+	Round  int          `json:"round"`
+	Card   *HandCard    `json:"card"`
+	Text   string       `json:"text"`
+	Result *ReplayRound `json:"result"`
+}
+
+// ReplayRound descreve o resultado de uma rodada no registro.
+//
+// Campos:
+//   - WinnerID: vencedor da rodada (vazio em empate).
+//   - Scores: rodadas vencidas por jogador após a rodada.
+type ReplayRound struct {
+	WinnerID string         `json:"winner_id"`
+	Scores   map[string]any `json:"scores"`
+}
+
+// OpenReplay armazena o registro aberto com /replay (ID vazio fora do modo de replay).
+// ReplayStep armazena a rodada do registro exibida no momento.
+var (
+	OpenReplay Replay
+	ReplayStep int
+)
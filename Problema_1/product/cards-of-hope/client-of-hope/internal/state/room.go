@@ -0,0 +1,19 @@
+// Pacote state armazena informações sobre a sala atual e a última listagem de salas.
+package state
+
+// RoomName armazena o nome de exibição da sala em que o usuário está.
+// RoomListing armazena os IDs das salas exibidas na última listagem, na ordem apresentada.
+// Spectating indica se o usuário assiste à sala atual como espectador.
+// RoomHostID armazena o ID do anfitrião atual da sala.
+// RoomCommitReveal indica se a sala atual usa jogadas com compromisso e revelação.
+// RoomAsync indica se a sala atual joga partidas por correspondência.
+// RoomWagerCards armazena quantas cartas cada jogador aposta nas partidas da sala atual.
+var (
+	RoomName         string
+	RoomListing      []string
+	Spectating       bool
+	RoomHostID       string
+	RoomCommitReveal bool
+	RoomAsync        bool
+	RoomWagerCards   int
+)
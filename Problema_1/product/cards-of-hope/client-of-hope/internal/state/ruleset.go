@@ -0,0 +1,41 @@
+// Pacote state armazena as regras da sala atual, recebidas do servidor ao entrar nela.
+package state
+
+// Ruleset descreve um conjunto de regras definido pelo servidor.
+//
+// Campos:
+//   - ID: identificador do conjunto de regras.
+//   - Name: nome de exibição.
+//   - Description: resumo das regras.
+//   - Cards: tipos de carta aceitos.
+//   - Beats: tipos que cada tipo vence.
+//   - StarTieBreak: política de desempate por estrelas (higher, lower ou none).
+//   - WinningRounds: rodadas necessárias para vencer a partida.
+//   - MaxRounds: limite de rodadas da partida (0 sem limite).
+//   - Abilities: habilidades especiais que as cartas podem ter nestas regras.
+type Ruleset struct {
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Description   string              `json:"description"`
+	Cards         []string            `json:"cards"`
+	Beats         map[string][]string `json:"beats"`
+	StarTieBreak  string              `json:"star_tie_break"`
+	WinningRounds int                 `json:"winning_rounds"`
+	MaxRounds     int                 `json:"max_rounds"`
+	Abilities     []Ability           `json:"abilities"`
+}
+
+// Ability descreve uma habilidade especial declarada nas regras.
+//
+// Campos:
+//   - ID: identificador da habilidade.
+//   - Name: nome de exibição.
+//   - Description: explicação da habilidade.
+type Ability struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// RoomRuleset armazena as regras da sala atual (vazio fora de uma sala).
+var RoomRuleset Ruleset
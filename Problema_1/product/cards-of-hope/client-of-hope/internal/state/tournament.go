@@ -0,0 +1,72 @@
+// Pacote state descreve os torneios recebidos do servidor.
+package state
+
+// Tournament descreve um torneio e sua chave, enviados pelo servidor.
+//
+// Campos:
+//   - ID: identificador do torneio.
+//   - Name: nome de exibição.
+//   - OwnerID: quem criou o torneio.
+//   - Format: formato (single_elimination ou round_robin).
+//   - Size: quantidade máxima de jogadores.
+//   - RulesetID: regras das partidas.
+//   - Status: estado do torneio (registering, running, finished).
+//   - Players: jogadores inscritos.
+//   - Matches: partidas, ordenadas por rodada.
+//   - WinnerID: campeão (vazio enquanto não há um).
+//   - Rounds: quantidade de rodadas da chave.
+//   - Standings: classificação, do primeiro ao último colocado.
+type Tournament struct {
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	OwnerID   string               `json:"owner_id"`
+	Format    string               `json:"format"`
+	Size      int                  `json:"size"`
+	RulesetID string               `json:"ruleset_id"`
+	Status    string               `json:"status"`
+	Players   []string             `json:"players"`
+	Matches   []TournamentMatch    `json:"matches"`
+	WinnerID  string               `json:"winner_id"`
+	Rounds    int                  `json:"rounds"`
+	Standings []TournamentStanding `json:"standings"`
+}
+
+// TournamentMatch descreve uma partida da chave.
+//
+// Campos:
+//   - ID: identificador da partida no torneio ("<rodada>-<posição>").
+//   - Round: rodada da partida.
+//   - Slot: posição da partida na rodada.
+//   - Players: os dois jogadores (vazio enquanto a vaga não é definida).
+//   - RoomID: sala da partida.
+//   - Status: estado da partida (pending, playing, finished, bye).
+//   - WinnerID: vencedor da partida (vazio em empate).
+//   - Walkover: a partida foi decidida porque um jogador saiu da sala.
+type TournamentMatch struct {
+	ID       string    `json:"id"`
+	Round    int       `json:"round"`
+	Slot     int       `json:"slot"`
+	Players  [2]string `json:"players"`
+	RoomID   string    `json:"room_id"`
+	Status   string    `json:"status"`
+	WinnerID string    `json:"winner_id"`
+	Walkover bool      `json:"walkover"`
+}
+
+// TournamentStanding descreve a campanha de um jogador no torneio.
+//
+// Campos:
+//   - UserID: jogador.
+//   - Played: partidas disputadas.
+//   - Wins: vitórias.
+//   - Draws: empates.
+//   - Losses: derrotas.
+//   - Points: pontos no todos contra todos.
+type TournamentStanding struct {
+	UserID string `json:"user_id"`
+	Played int    `json:"played"`
+	Wins   int    `json:"wins"`
+	Draws  int    `json:"draws"`
+	Losses int    `json:"losses"`
+	Points int    `json:"points"`
+}
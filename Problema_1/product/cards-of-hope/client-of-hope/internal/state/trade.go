@@ -0,0 +1,22 @@
+// Pacote state descreve as propostas de troca recebidas do servidor.
+package state
+
+// Trade descreve uma proposta de troca de cartas, enviada pelo servidor.
+//
+// Campos:
+//   - ID: identificador da proposta.
+//   - FromID: quem fez a proposta.
+//   - ToID: quem recebeu a proposta.
+//   - Offered: cartas oferecidas por FromID.
+//   - Requested: cartas pedidas a ToID.
+//   - Status: estado da proposta (pending, accepted, declined, cancelled, expired, failed).
+//   - ExpiresAt: momento em que a proposta deixa de valer (RFC 3339).
+type Trade struct {
+	ID        string     `json:"id"`
+	FromID    string     `json:"from_id"`
+	ToID      string     `json:"to_id"`
+	Offered   []HandCard `json:"offered"`
+	Requested []HandCard `json:"requested"`
+	Status    string     `json:"status"`
+	ExpiresAt string     `json:"expires_at"`
+}
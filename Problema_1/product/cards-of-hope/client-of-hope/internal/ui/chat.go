@@ -33,6 +33,12 @@ func logToFile(message string) {
 // chatMsg representa uma mensagem recebida do servidor ou lógica do app.
 type chatMsg string
 
+// directMsg representa uma linha do painel de mensagens diretas.
+type directMsg string
+
+// directPaneTitle é o título exibido acima do painel de mensagens diretas.
+const directPaneTitle = "── Direct messages ──"
+
 // errMsg encapsula um erro para ser tratado pela interface.
 type errMsg struct{ err error }
 
@@ -40,12 +46,14 @@ type errMsg struct{ err error }
 //
 // Campos:
 //   - Outputs: canal para mensagens enviadas da lógica para a UI.
+//   - Direct: canal para as linhas do painel de mensagens diretas.
 //   - Inputs: canal para comandos/mensagens do usuário.
 //   - program: instância do programa Bubble Tea.
 //   - ctx, cancel: contexto para controle de execução.
 //   - Done: canal para sinalizar o término da UI.
 type Chat struct {
 	Outputs chan string
+	Direct  chan string
 	Inputs  chan string
 	program *tea.Program
 	ctx     context.Context
@@ -61,6 +69,7 @@ func NewChat() *Chat {
 	logToFile("NewChat called")
 	return &Chat{
 		Outputs: make(chan string, 100),
+		Direct:  make(chan string, 100),
 		Inputs:  make(chan string, 100),
 		Done:    make(chan struct{}), // Inicializa o canal Done
 	}
@@ -112,7 +121,7 @@ func (c *Chat) Clear() {
 	}
 }
 
-// listenToOutputs escuta o canal Outputs e envia mensagens para a interface Bubble Tea.
+// listenToOutputs escuta os canais Outputs e Direct e envia mensagens para a interface Bubble Tea.
 func (c *Chat) listenToOutputs() {
 	logToFile("listenToOutputs goroutine started.")
 	for {
@@ -125,6 +134,10 @@ func (c *Chat) listenToOutputs() {
 			if c.program != nil {
 				c.program.Send(chatMsg(msg))
 			}
+		case msg := <-c.Direct:
+			if c.program != nil {
+				c.program.Send(directMsg(msg))
+			}
 		}
 	}
 }
@@ -159,6 +172,12 @@ type model struct {
 	outputsChan <-chan string
 	// history armazena o histórico de mensagens exibidas.
 	history []string
+	// directViewport exibe as mensagens diretas, acima das mensagens do chat.
+	directViewport viewport.Model
+	// directHistory armazena as linhas do painel de mensagens diretas.
+	directHistory []string
+	// width e height guardam o tamanho da janela, para dividir a tela entre os painéis.
+	width, height int
 }
 
 // newModel cria e configura o modelo Bubble Tea para a interface de chat.
@@ -185,11 +204,13 @@ func newModel(inputs chan<- string, outputs <-chan string) model {
 	vp := viewport.New(0, 0)
 
 	return model{
-		textarea:    ta,
-		viewport:    vp,
-		inputsChan:  inputs,
-		outputsChan: outputs,
-		history:     []string{},
+		textarea:       ta,
+		viewport:       vp,
+		inputsChan:     inputs,
+		outputsChan:    outputs,
+		history:        []string{},
+		directViewport: viewport.New(0, 0),
+		directHistory:  []string{},
 	}
 }
 
@@ -238,15 +259,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.addHistory(string(msg))
 		return m, nil
 
+	case directMsg:
+		m.addDirect(string(msg))
+		return m, nil
+
 	case errMsg:
 		logToFile(fmt.Sprintf("Update received errMsg: '%s'", msg.err.Error()))
 		m.addHistory("Erro: " + msg.err.Error())
 		return m, nil
 
 	case tea.WindowSizeMsg:
-		m.viewport.Width = msg.Width
-		m.viewport.Height = msg.Height - m.textarea.Height()
+		m.width, m.height = msg.Width, msg.Height
 		m.textarea.SetWidth(msg.Width)
+		m.layout()
 
 	case clearHistoryMsg:
 		m.clearHistory()
@@ -266,6 +291,34 @@ func (m *model) addHistory(msg string) {
 	m.viewport.GotoBottom()
 }
 
+// addDirect adiciona uma linha ao painel de mensagens diretas, abrindo o painel na primeira delas.
+//
+// Parâmetros:
+//   - msg: linha a ser adicionada ao painel.
+func (m *model) addDirect(msg string) {
+	m.directHistory = append(m.directHistory, msg)
+	if len(m.directHistory) == 1 {
+		m.layout()
+	}
+	m.directViewport.SetContent(strings.Join(m.directHistory, "\n"))
+	m.directViewport.GotoBottom()
+}
+
+// layout divide a altura da janela entre o painel de mensagens diretas, quando há alguma, e o chat.
+func (m *model) layout() {
+	available := m.height - m.textarea.Height()
+	directHeight := 0
+	if len(m.directHistory) > 0 {
+		directHeight = max(available/3, 3)
+		available -= directHeight + 2 // Título e separador do painel
+	}
+	m.directViewport.Width = m.width
+	m.directViewport.Height = directHeight
+	m.viewport.Width = m.width
+	m.viewport.Height = max(available, 0)
+	m.viewport.GotoBottom()
+}
+
 func (m *model) clearHistory() {
 	m.history = []string{}
 	m.viewport.SetContent("")
@@ -281,5 +334,10 @@ func (m model) View() string {
 	} else {
 		m.textarea.Prompt = ": "
 	}
+	if len(m.directHistory) > 0 {
+		title := lipgloss.NewStyle().Foreground(lipgloss.Color("5")).Render(directPaneTitle)
+		separator := strings.Repeat("─", max(m.width, 0))
+		return fmt.Sprintf("%s\n%s\n%s\n%s\n%s", title, m.directViewport.View(), separator, m.viewport.View(), m.textarea.View())
+	}
 	return fmt.Sprintf("%s\n%s", m.viewport.View(), m.textarea.View())
 }
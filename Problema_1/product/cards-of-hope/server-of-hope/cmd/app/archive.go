@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/state"
+)
+
+// exportArchive lê o arquivo de dados do servidor e grava um backup na versão atual do esquema.
+//
+// Parâmetros:
+//   - args: opções de linha de comando do subcomando.
+//
+// Retorno:
+//   - erro caso o arquivo de dados seja inválido ou o backup não possa ser gravado.
+func exportArchive(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	dataPath := flags.String("data", "data/state.json", "Arquivo de dados do servidor")
+	outPath := flags.String("out", "-", "Arquivo de saída do backup (- para a saída padrão)")
+	flags.Parse(args)
+
+	archiveData, version, err := readArchiveFile(*dataPath)
+	if err != nil {
+		return err
+	}
+	if err := archiveData.Verify(); err != nil {
+		return fmt.Errorf("arquivo de dados inconsistente: %w", err)
+	}
+
+	if *outPath == "-" {
+		return data.WriteArchive(os.Stdout, archiveData)
+	}
+	if err := writeArchiveFile(*outPath, archiveData); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Backup gerado em %s (esquema v%d, origem v%d): %s\n", *outPath, data.SchemaVersion, version, summary(archiveData))
+	return nil
+}
+
+// importArchive valida um backup e o grava como arquivo de dados do servidor.
+// O servidor deve estar parado, pois ele sobrescreve o arquivo de dados ao encerrar.
+//
+// Parâmetros:
+//   - args: opções de linha de comando do subcomando.
+//
+// Retorno:
+//   - erro caso o backup seja inválido ou o arquivo de dados já exista sem -force.
+func importArchive(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	inPath := flags.String("in", "", "Arquivo de backup a ser restaurado")
+	dataPath := flags.String("data", "data/state.json", "Arquivo de dados do servidor")
+	force := flags.Bool("force", false, "Sobrescreve o arquivo de dados existente")
+	flags.Parse(args)
+
+	if *inPath == "" {
+		return errors.New("informe o backup com -in")
+	}
+	archiveData, version, err := readArchiveFile(*inPath)
+	if err != nil {
+		return err
+	}
+	if err := archiveData.Verify(); err != nil {
+		return fmt.Errorf("backup inconsistente: %w", err)
+	}
+	if _, err := os.Stat(*dataPath); err == nil && !*force {
+		return fmt.Errorf("%s já existe, use -force para sobrescrever", *dataPath)
+	}
+
+	if err := writeArchiveFile(*dataPath, archiveData); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Backup restaurado em %s (esquema v%d, origem v%d): %s\n", *dataPath, data.SchemaVersion, version, summary(archiveData))
+	return nil
+}
+
+// verifyArchive confere checksum, versão e consistência de um backup sem alterá-lo.
+//
+// Parâmetros:
+//   - args: opções de linha de comando do subcomando.
+//
+// Retorno:
+//   - erro descrevendo as inconsistências encontradas.
+func verifyArchive(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	inPath := flags.String("in", "", "Arquivo de backup a ser verificado")
+	flags.Parse(args)
+
+	if *inPath == "" {
+		return errors.New("informe o backup com -in")
+	}
+	archiveData, version, err := readArchiveFile(*inPath)
+	if err != nil {
+		return err
+	}
+	if err := archiveData.Verify(); err != nil {
+		return fmt.Errorf("backup inconsistente:\n%w", err)
+	}
+	fmt.Printf("Backup íntegro (esquema v%d): %s\n", version, summary(archiveData))
+	return nil
+}
+
+// loadDataFile restaura o estado do servidor a partir do arquivo de dados, se ele existir.
+func loadDataFile(path string) error {
+	archiveData, version, err := readArchiveFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		state.Logger.Info("Data file not found, starting with empty state", "path", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := state.Restore(archiveData); err != nil {
+		return fmt.Errorf("falha ao restaurar %s: %w", path, err)
+	}
+	state.Logger.Info("State restored", "path", path, "schema_version", version, "summary", summary(archiveData))
+	return nil
+}
+
+// saveDataFile grava o estado atual do servidor no arquivo de dados.
+func saveDataFile(path string) error {
+	archiveData, err := state.Snapshot()
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveFile(path, archiveData); err != nil {
+		return err
+	}
+	state.Logger.Info("State saved", "path", path, "summary", summary(archiveData))
+	return nil
+}
+
+// readArchiveFile abre e lê um backup do disco.
+func readArchiveFile(path string) (data.ArchiveData, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return data.ArchiveData{}, 0, err
+	}
+	defer file.Close()
+	return data.ReadArchive(file)
+}
+
+// writeArchiveFile grava um backup no disco de forma atômica, usando um arquivo temporário.
+func writeArchiveFile(path string, archiveData data.ArchiveData) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if err := writeAndClose(temp, archiveData); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// writeAndClose serializa o backup no arquivo e o fecha, sincronizando o conteúdo em disco.
+func writeAndClose(file *os.File, archiveData data.ArchiveData) error {
+	if err := data.WriteArchive(file, archiveData); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// summary descreve a quantidade de registros de um backup.
+func summary(archiveData data.ArchiveData) string {
+	return fmt.Sprintf("%d usuários, %d salas, %d partidas, %d pacotes em estoque, %d registros de partidas, %d torneios, %d lançamentos do livro-caixa, %d propostas de troca, %d anúncios do mercado, %d garantias de pacotes, %d sementes de pacotes, %d pacotes abertos, %d apostas, %d mensagens diretas",
+		len(archiveData.Users), len(archiveData.Rooms), len(archiveData.Games), len(archiveData.Stock), len(archiveData.Replays), len(archiveData.Tournaments), len(archiveData.Ledger), len(archiveData.Trades), len(archiveData.Listings), len(archiveData.PackPity), len(archiveData.PackSeeds), len(archiveData.PackOpenings), len(archiveData.Wagers), len(archiveData.DirectMessages))
+}
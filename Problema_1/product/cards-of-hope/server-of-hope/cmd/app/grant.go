@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"server-of-hope/internal/state"
+)
+
+// grantCoins credita (ou debita, com valor negativo) moedas na carteira de um usuário direto no
+// arquivo de dados, registrando o ajuste no livro-caixa. O servidor deve estar parado, pois ele
+// sobrescreve o arquivo de dados ao encerrar.
+//
+// Parâmetros:
+//   - args: opções de linha de comando do subcomando.
+//
+// Retorno:
+//   - erro caso o arquivo de dados seja inválido, o usuário não exista ou o saldo fique negativo.
+func grantCoins(args []string) error {
+	flags := flag.NewFlagSet("grant", flag.ExitOnError)
+	dataPath := flags.String("data", "data/state.json", "Arquivo de dados do servidor")
+	userID := flags.String("user", "", "Usuário que recebe as moedas")
+	amount := flags.Int("amount", 0, "Moedas a creditar (negativo para debitar)")
+	note := flags.String("note", "", "Motivo do ajuste, registrado no livro-caixa")
+	flags.Parse(args)
+
+	if *userID == "" || *amount == 0 {
+		return errors.New("informe o usuário com -user e um valor diferente de zero com -amount")
+	}
+	archiveData, _, err := readArchiveFile(*dataPath)
+	if err != nil {
+		return err
+	}
+
+	state.Initialize()
+	defer state.Finalize()
+	if err := state.Restore(archiveData); err != nil {
+		return fmt.Errorf("falha ao restaurar %s: %w", *dataPath, err)
+	}
+	entry, err := state.WalletService.Grant(*userID, *amount, *note)
+	if err != nil {
+		return fmt.Errorf("falha ao ajustar o saldo de %s: %w", *userID, err)
+	}
+
+	archiveData, err = state.Snapshot()
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveFile(*dataPath, archiveData); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Saldo de %s ajustado em %+d moedas: agora %d (lançamento %d)\n", entry.UserID, entry.Amount, entry.Balance, entry.Seq)
+	return nil
+}
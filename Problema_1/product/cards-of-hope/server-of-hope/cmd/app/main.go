@@ -1,12 +1,21 @@
 // main é o ponto de entrada do servidor Cards of Hope.
 //
-// Este programa inicializa o estado global, configura o servidor TCP, registra rotas de comandos e mantém o servidor em execução.
+// Este programa expõe subcomandos para executar o servidor e administrar seus dados:
+//   - serve: inicializa o estado global, configura o servidor TCP, registra rotas e mantém o servidor em execução (padrão).
+//   - export: gera um backup versionado a partir de um arquivo de dados.
+//   - import: restaura um backup em um arquivo de dados.
+//   - verify: confere a integridade de um backup.
+//   - grant: ajusta o saldo de moedas de um usuário no arquivo de dados.
 //
-// Fluxo principal:
+// Fluxo principal do serve:
 //   - Inicializa o estado global e recursos do servidor.
+//   - Restaura o arquivo de dados informado em -data, se existir, e resolve as apostas retidas
+//     de partidas que terminaram antes do último salvamento.
 //   - Cria o servidor TCP e o roteador de comandos.
-//   - Registra rotas para autenticação, sala, chat, jogo e utilidades.
-//   - Inicia o servidor e aguarda indefinidamente.
+//   - Registra rotas para autenticação, sala, chat, jogo, mercado e utilidades.
+//   - Inicia o servidor e aguarda um sinal de encerramento, fechando periodicamente os anúncios
+//     vencidos do mercado, os desafios sem resposta, as rodadas vencidas das partidas por
+//     correspondência e as partidas com aposta abandonadas e salvando o estado no arquivo de dados.
 //
 // Efeitos colaterais:
 //   - Pode encerrar o programa caso haja falha na inicialização.
@@ -15,18 +24,94 @@
 // Exemplo de uso:
 //
 //	go run main.go
+//	go run main.go serve -data data/state.json
+//	go run main.go export -data data/state.json -out backup.json
+//	go run main.go grant -data data/state.json -user alice -amount 50 -note "prêmio do evento"
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
 	"server-of-hope/internal/api"
 	"server-of-hope/internal/api/handlers"
+	"server-of-hope/internal/application"
 	"server-of-hope/internal/state"
+	"strings"
+	"syscall"
+	"time"
 )
 
+const usage = `Uso: server <comando> [opções]
+
+Comandos:
+  serve    Executa o servidor (padrão)
+  export   Gera um backup versionado a partir do arquivo de dados
+  import   Restaura um backup no arquivo de dados
+  verify   Confere a integridade de um backup
+  grant    Ajusta o saldo de moedas de um usuário no arquivo de dados
+
+Use "server <comando> -h" para ver as opções de cada comando.
+`
+
 func main() {
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "serve":
+		err = serve(args)
+	case "export":
+		err = exportArchive(args)
+	case "import":
+		err = importArchive(args)
+	case "verify":
+		err = verifyArchive(args)
+	case "grant":
+		err = grantCoins(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Erro:", err)
+		os.Exit(1)
+	}
+}
+
+// serve executa o servidor TCP até receber um sinal de encerramento.
+//
+// Parâmetros:
+//   - args: opções de linha de comando do subcomando.
+//
+// Retorno:
+//   - erro caso o estado não possa ser restaurado ou salvo.
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	dataPath := flags.String("data", "", "Arquivo de dados para restaurar na inicialização e salvar no encerramento")
+	autosave := flags.Duration("autosave", 0, "Intervalo entre salvamentos automáticos do arquivo de dados (0 desativa)")
+	flags.Parse(args)
+
 	state.Initialize()
 	defer state.Finalize()
 
+	if *dataPath != "" {
+		if err := loadDataFile(*dataPath); err != nil {
+			return err
+		}
+		wagers, err := state.WagerService.Reconcile()
+		if err != nil {
+			state.Logger.Error("Failed to reconcile held wagers", "error", err)
+		}
+		for _, wager := range wagers {
+			state.Logger.Info("Held wager reconciled", "wager_id", wager.ID, "room_id", wager.RoomID, "status", wager.Status, "winner_id", wager.WinnerID)
+		}
+	}
+
 	server := api.NewServer(state.HOST + ":" + state.PORT)
 	router := api.NewRouter(server)
 
@@ -36,17 +121,124 @@ func main() {
 	router.AddRoute("create", handlers.HandleCreateRoom)
 	router.AddRoute("join", handlers.HandleJoinRoom)
 	router.AddRoute("leave", handlers.HandleLeaveRoom)
+	router.AddRoute("rooms", handlers.HandleListRooms)
+	router.AddRoute("invite", handlers.HandleCreateInvite)
+	router.AddRoute("kick", handlers.HandleKick)
+	router.AddRoute("lock", handlers.HandleLockRoom)
+	router.AddRoute("transfer_host", handlers.HandleTransferHost)
+	router.AddRoute("challenge", handlers.HandleChallenge)
+	router.AddRoute("challenge_accept", handlers.HandleAcceptChallenge)
+	router.AddRoute("challenge_decline", handlers.HandleDeclineChallenge)
+	router.AddRoute("challenge_cancel", handlers.HandleCancelChallenge)
+	router.AddRoute("challenges", handlers.HandleListChallenges)
 
 	router.AddRoute("send", handlers.HandleSendMessage)
 	router.AddRoute("fetch", handlers.HandleFetchMessage)
 
+	router.AddRoute("ready", handlers.HandleReady)
+	router.AddRoute("unready", handlers.HandleUnready)
+	router.AddRoute("rematch", handlers.HandleRematch)
 	router.AddRoute("play", handlers.HandlePlayCard)
+	router.AddRoute("commit", handlers.HandleCommit)
+	router.AddRoute("reveal", handlers.HandleReveal)
+	router.AddRoute("my_games", handlers.HandleMyGames)
+	router.AddRoute("dm", handlers.HandleDirectMessage)
+	router.AddRoute("dm_history", handlers.HandleDirectHistory)
+	router.AddRoute("channel_join", handlers.HandleChannelJoin)
+	router.AddRoute("channel_leave", handlers.HandleChannelLeave)
+	router.AddRoute("channel_list", handlers.HandleChannelList)
+	router.AddRoute("channel_members", handlers.HandleChannelMembers)
+	router.AddRoute("channel_send", handlers.HandleChannelSend)
+	router.AddRoute("rulesets", handlers.HandleListRulesets)
+	router.AddRoute("collection", handlers.HandleCollection)
+	router.AddRoute("deck_save", handlers.HandleSaveDeck)
+	router.AddRoute("deck_list", handlers.HandleListDecks)
+	router.AddRoute("deck_select", handlers.HandleSelectDeck)
+	router.AddRoute("replays", handlers.HandleListReplays)
+	router.AddRoute("replay", handlers.HandleGetReplay)
+
+	router.AddRoute("tournament_create", handlers.HandleCreateTournament)
+	router.AddRoute("tournament_list", handlers.HandleListTournaments)
+	router.AddRoute("tournament_get", handlers.HandleGetTournament)
+	router.AddRoute("tournament_join", handlers.HandleJoinTournament)
+	router.AddRoute("tournament_leave", handlers.HandleLeaveTournament)
+	router.AddRoute("tournament_start", handlers.HandleStartTournament)
 
 	router.AddRoute("buy", handlers.HandleBuyPackage)
+	router.AddRoute("store_catalog", handlers.HandleStoreCatalog)
+	router.AddRoute("pack_seed", handlers.HandlePackSeed)
+	router.AddRoute("pack_seed_rotate", handlers.HandleRotatePackSeed)
+	router.AddRoute("pack_history", handlers.HandlePackHistory)
+	router.AddRoute("verify_pack", handlers.HandleVerifyPack)
+	router.AddRoute("recipes", handlers.HandleRecipes)
+	router.AddRoute("craft", handlers.HandleCraft)
+	router.AddRoute("disenchant", handlers.HandleDisenchant)
+	router.AddRoute("balance", handlers.HandleBalance)
+
+	router.AddRoute("trade_offer", handlers.HandleOfferTrade)
+	router.AddRoute("trade_accept", handlers.HandleAcceptTrade)
+	router.AddRoute("trade_decline", handlers.HandleDeclineTrade)
+	router.AddRoute("trade_cancel", handlers.HandleCancelTrade)
+	router.AddRoute("trades", handlers.HandleListTrades)
+
+	router.AddRoute("market_list", handlers.HandleListMarket)
+	router.AddRoute("market_sell", handlers.HandleSellCards)
+	router.AddRoute("market_buy", handlers.HandleBuyListing)
+	router.AddRoute("market_bid", handlers.HandleBid)
+	router.AddRoute("market_cancel", handlers.HandleCancelListing)
 
 	router.AddRoute("ping", handlers.HandlePing)
-	server.Start(router)
+
+	server.OnDisconnect(handlers.HandleRoomDisconnect)
+	server.OnDisconnect(handlers.HandleChallengeDisconnect)
+	server.OnDisconnect(handlers.HandleChannelDisconnect)
+	if err := server.Start(router); err != nil {
+		return err
+	}
 	defer server.Stop()
 
-	select {}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	var ticks <-chan time.Time
+	if *dataPath != "" && *autosave > 0 {
+		ticker := time.NewTicker(*autosave)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	settle := time.NewTicker(application.MarketSettleInterval)
+	defer settle.Stop()
+
+	sweep := time.NewTicker(application.ChallengeSweepInterval)
+	defer sweep.Stop()
+
+	turns := time.NewTicker(application.TurnSweepInterval)
+	defer turns.Stop()
+
+	wagers := time.NewTicker(application.WagerSweepInterval)
+	defer wagers.Stop()
+
+	for {
+		select {
+		case <-settle.C:
+			handlers.SettleMarket(server)
+		case <-sweep.C:
+			handlers.ExpireChallenges(server)
+		case <-turns.C:
+			handlers.ExpireTurns(server)
+		case <-wagers.C:
+			handlers.ForfeitAbsentWagers(server)
+		case <-ticks:
+			if err := saveDataFile(*dataPath); err != nil {
+				state.Logger.Error("Failed to autosave data file", "path", *dataPath, "error", err)
+			}
+		case sig := <-signals:
+			state.Logger.Info("Shutting down", "signal", sig)
+			if *dataPath != "" {
+				return saveDataFile(*dataPath)
+			}
+			return nil
+		}
+	}
 }
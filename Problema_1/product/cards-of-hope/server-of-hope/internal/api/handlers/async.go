@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+func HandleMyGames(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list async games")
+	if !loggedIn {
+		return
+	}
+	all, _ := request.Data["all"].(bool)
+
+	games, err := state.GameService.AsyncGames(userID)
+	if err != nil {
+		responder.SetError("Could not list games", "Failed to list async games", "user_id", userID, "error", err)
+		return
+	}
+
+	views := make([]utils.Dict, 0, len(games))
+	for _, game := range games {
+		if all || game.YourTurn {
+			views = append(views, asyncGameView(game, userID))
+		}
+	}
+
+	data := utils.Dict{"games": views, "total": len(games)}
+	responder.SetSuccess(data, "Async games listed successfully", "user_id", userID, "count", len(views))
+}
+
+// ExpireTurns encerra as partidas por correspondência com a rodada vencida e avisa os jogadores.
+func ExpireTurns(server *api.Server) {
+	results, err := state.GameService.ExpireTurns()
+	if err != nil {
+		state.Logger.Error("Failed to expire async turns", "error", err)
+	}
+	for roomID, result := range results {
+		state.Logger.Info("Async match timed out", "room_id", roomID, "forfeited_by", result.ForfeitedBy)
+		notifyMatchFinished(server, roomID, &result)
+	}
+}
+
+// notifyAsyncGames avisa o usuário que acabou de entrar das partidas por correspondência que aguardam a sua jogada.
+func notifyAsyncGames(server *api.Server, userID string) {
+	games, err := state.GameService.AsyncGames(userID)
+	if err != nil {
+		state.Logger.Error("Failed to list async games on login", "user_id", userID, "error", err)
+		return
+	}
+
+	views := make([]utils.Dict, 0, len(games))
+	for _, game := range games {
+		if game.YourTurn {
+			views = append(views, asyncGameView(game, userID))
+		}
+	}
+	if len(views) == 0 {
+		return
+	}
+	notifyUser(server, userID, "my_games", utils.Dict{"games": views, "total": len(games)})
+}
+
+// asyncGameView converte uma partida por correspondência nos dados enviados ao jogador.
+func asyncGameView(game application.AsyncGame, userID string) utils.Dict {
+	scores := make(map[string]int, len(game.Game.Seats))
+	opponent := ""
+	for _, playerID := range game.Game.Seats {
+		scores[playerID], _ = game.Game.Scores.Get(playerID)
+		if playerID != userID {
+			opponent = playerID
+		}
+	}
+
+	view := utils.Dict{
+		"room_id":       game.Room.ID,
+		"name":          game.Room.Name,
+		"opponent":      opponent,
+		"round":         game.Game.Round,
+		"scores":        scores,
+		"your_turn":     game.YourTurn,
+		"commit_reveal": game.Room.CommitReveal,
+		"turn_hours":    game.Room.TurnHours,
+	}
+	if !game.Game.TurnDeadline.IsZero() {
+		view["turn_deadline"] = game.Game.TurnDeadline.Format(time.RFC3339)
+	}
+	if game.Game.LastRound != nil {
+		view["last_round"] = roundSummary(game.Room.ID, game.Game.LastRound)
+	}
+	return view
+}
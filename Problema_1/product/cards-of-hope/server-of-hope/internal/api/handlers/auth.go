@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"server-of-hope/internal/api"
 	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
 	"server-of-hope/internal/state"
 	"server-of-hope/internal/utils"
 )
@@ -20,6 +22,10 @@ func HandleRegisterUser(server *api.Server, request protocol.Request) {
 	}
 
 	err := state.AuthService.Register(username, password)
+	if errors.Is(err, application.ErrReservedUsername) || errors.Is(err, application.ErrInvalidUsername) {
+		responder.SetError(err.Error(), "User registration failed", "username", username, "error", err)
+		return
+	}
 	if err != nil {
 		responder.SetError("User already exists", "User registration failed", "username", username, "error", err)
 		return
@@ -31,19 +37,20 @@ func HandleRegisterUser(server *api.Server, request protocol.Request) {
 
 func HandleLoginUser(server *api.Server, request protocol.Request) {
 	responder := NewResponder(server, request)
-	defer responder.Send()
 
 	username, usernameOk := request.Data["username"].(string)
 	password, passwordOk := request.Data["password"].(string)
 
 	if !usernameOk || !passwordOk {
 		responder.SetError("Invalid username or password", "User login failed", "from", request.From)
+		responder.Send()
 		return
 	}
 
 	userId, err := state.AuthService.Login(username, password)
 	if err != nil {
 		responder.SetError("Invalid username or password", "User login failed", "username", username, "error", err)
+		responder.Send()
 		return
 	}
 
@@ -57,5 +64,24 @@ func HandleLoginUser(server *api.Server, request protocol.Request) {
 		"message": "User logged in successfully",
 		"user_id": userId,
 	}
+	if exists {
+		data["channel"] = joinLobby(userId)
+	}
 	responder.SetSuccess(data, "User logged in successfully", "username", username, "userId", userId)
+	responder.Send()
+
+	if exists {
+		notifyAsyncGames(server, userId)
+		notifyInbox(server, userId)
+	}
+}
+
+// sessionUser retorna o usuário autenticado na conexão que enviou a requisição. Os dados da
+// requisição nunca decidem quem age, já que qualquer conexão pode informar qualquer user_id.
+func sessionUser(server *api.Server, request protocol.Request) (string, bool) {
+	client, exists := server.Clients.Get(request.From)
+	if !exists || client.UserID == "" {
+		return "", false
+	}
+	return client.UserID, true
 }
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+// botReceive faz o bot reagir a uma mensagem do servidor, como o cliente de um jogador faria.
+func botReceive(server *api.Server, botID, method string, data utils.Dict) {
+	roomID, _ := data["room_id"].(string)
+	switch method {
+	case "hand":
+		time.Sleep(application.BotThinkTime)
+		botPlay(server, roomID, botID)
+	case "committed":
+		if reveal, _ := data["reveal"].(bool); reveal {
+			time.Sleep(application.BotThinkTime)
+			botReveal(server, roomID, botID)
+		}
+	case "opponent_played":
+		cardType, _ := data["opponent_card"].(string)
+		state.BotService.Observe(roomID, botID, cardType)
+	case "room_event":
+		event, _ := data["event"].(string)
+		userID, _ := data["user_id"].(string)
+		botRoomEvent(server, roomID, botID, event, userID)
+	}
+}
+
+// botPlay faz o bot jogar a rodada, com compromisso nas salas que o exigem.
+func botPlay(server *api.Server, roomID, botID string) {
+	room, err := state.RoomService.GetRoom(roomID)
+	if err != nil {
+		state.Logger.Error("Failed to get room for bot play", "room_id", roomID, "bot_id", botID, "error", err)
+		return
+	}
+	if room.CommitReveal {
+		complete, err := state.BotService.Commit(roomID, botID)
+		if err != nil {
+			state.Logger.Error("Bot commit failed", "room_id", roomID, "bot_id", botID, "error", err)
+			return
+		}
+		notifyCommitted(server, roomID, botID, complete)
+		return
+	}
+
+	result, err := state.BotService.Play(roomID, botID)
+	if err != nil {
+		state.Logger.Error("Bot play failed", "room_id", roomID, "bot_id", botID, "error", err)
+		return
+	}
+	notifyPlay(server, roomID, botID, result)
+}
+
+// botReveal faz o bot revelar a carta com que se comprometeu.
+func botReveal(server *api.Server, roomID, botID string) {
+	result, err := state.BotService.Reveal(roomID, botID)
+	if err != nil {
+		state.Logger.Error("Bot reveal failed", "room_id", roomID, "bot_id", botID, "error", err)
+		if result != nil {
+			notifyMatchFinished(server, roomID, result)
+		}
+		return
+	}
+	notifyPlay(server, roomID, botID, result)
+}
+
+// botRoomEvent faz o bot acompanhar o outro jogador: fica pronto quando ele fica pronto, aceita
+// revanches e sai da sala quando não resta nenhum jogador humano.
+func botRoomEvent(server *api.Server, roomID, botID, event, userID string) {
+	if userID == botID {
+		if event == "kicked" {
+			state.BotService.Forget(roomID, botID)
+		}
+		return
+	}
+	room, err := state.RoomService.GetRoom(roomID)
+	if err != nil || !room.UserIDs.Contains(botID) {
+		return
+	}
+
+	switch event {
+	case "ready":
+		if room.Status != domain.RoomStatusReadyCheck || room.Ready.Contains(botID) {
+			return
+		}
+		started, err := state.GameService.SetReady(roomID, botID, true)
+		if err != nil {
+			state.Logger.Error("Bot ready failed", "room_id", roomID, "bot_id", botID, "error", err)
+			return
+		}
+		announceReady(server, roomID, botID, true, started)
+	case "rematch_requested":
+		started, err := state.GameService.Rematch(roomID, botID, true)
+		if err != nil {
+			state.Logger.Error("Bot rematch failed", "room_id", roomID, "bot_id", botID, "error", err)
+			return
+		}
+		announceRematch(server, roomID, botID, true, started)
+	case "left", "kicked":
+		for _, playerID := range room.UserIDs.Items() {
+			if !domain.IsBotID(playerID) {
+				return
+			}
+		}
+		if err := state.RoomService.LeaveRoom(roomID, botID); err != nil {
+			state.Logger.Error("Bot failed to leave room", "room_id", roomID, "bot_id", botID, "error", err)
+			return
+		}
+		state.BotService.Forget(roomID, botID)
+		if room, err := state.RoomService.GetRoom(roomID); err == nil {
+			notifyRoomEvent(server, room, "left", botID)
+		}
+	}
+}
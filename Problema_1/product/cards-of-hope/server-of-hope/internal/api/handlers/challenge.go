@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+func HandleChallenge(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to challenge user")
+	if !loggedIn {
+		return
+	}
+	target, targetOk := request.Data["target"].(string)
+	ruleset, _ := request.Data["ruleset"].(string)
+	commitReveal, _ := request.Data["commit_reveal"].(bool)
+
+	if !targetOk || target == "" {
+		responder.SetError("Invalid parameters", "Failed to challenge user", "from", request.From)
+		return
+	}
+
+	challenge, err := state.ChallengeService.Challenge(userID, target, application.ChallengeOptions{
+		Ruleset:      ruleset,
+		CommitReveal: commitReveal,
+	}, userOnline)
+	if err != nil {
+		responder.SetError(challengeErrorMessage(err), "Failed to challenge user", "user_id", userID, "target", target, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Challenge sent successfully", "challenge": challengeView(challenge)}
+	responder.SetSuccess(data, "Challenge sent successfully", "user_id", userID, "target", target, "challenge_id", challenge.ID)
+
+	notifyUser(server, challenge.ToID, "challenge_received", utils.Dict{"challenge": challengeView(challenge)})
+}
+
+func HandleAcceptChallenge(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to accept challenge")
+	if !loggedIn {
+		return
+	}
+	challengeID, ok := request.Data["challenge_id"].(string)
+	if !ok {
+		responder.SetError("Invalid parameters", "Failed to accept challenge", "from", request.From)
+		return
+	}
+
+	challenge, err := state.ChallengeService.Accept(challengeID, userID)
+	if err != nil {
+		responder.SetError(challengeErrorMessage(err), "Failed to accept challenge", "user_id", userID, "challenge_id", challengeID, "error", err)
+		announceChallengeExpired(server, challenge, userID)
+		return
+	}
+
+	data := challengeRoomData(challenge)
+	data["message"] = "Challenge accepted successfully"
+	responder.SetSuccess(data, "Challenge accepted successfully", "user_id", userID, "challenge_id", challengeID, "room_id", challenge.RoomID)
+
+	notifyChallenge(server, challenge, "accepted", challenge.FromID)
+}
+
+func HandleDeclineChallenge(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to decline challenge")
+	if !loggedIn {
+		return
+	}
+	challengeID, ok := request.Data["challenge_id"].(string)
+	if !ok {
+		responder.SetError("Invalid parameters", "Failed to decline challenge", "from", request.From)
+		return
+	}
+
+	challenge, err := state.ChallengeService.Decline(challengeID, userID)
+	if err != nil {
+		responder.SetError(challengeErrorMessage(err), "Failed to decline challenge", "user_id", userID, "challenge_id", challengeID, "error", err)
+		announceChallengeExpired(server, challenge, userID)
+		return
+	}
+
+	data := utils.Dict{"message": "Challenge declined successfully", "challenge": challengeView(challenge)}
+	responder.SetSuccess(data, "Challenge declined successfully", "user_id", userID, "challenge_id", challengeID)
+
+	notifyChallenge(server, challenge, "declined", challenge.FromID)
+}
+
+func HandleCancelChallenge(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to cancel challenge")
+	if !loggedIn {
+		return
+	}
+	challengeID, ok := request.Data["challenge_id"].(string)
+	if !ok {
+		responder.SetError("Invalid parameters", "Failed to cancel challenge", "from", request.From)
+		return
+	}
+
+	challenge, err := state.ChallengeService.Cancel(challengeID, userID)
+	if err != nil {
+		responder.SetError(challengeErrorMessage(err), "Failed to cancel challenge", "user_id", userID, "challenge_id", challengeID, "error", err)
+		announceChallengeExpired(server, challenge, userID)
+		return
+	}
+
+	data := utils.Dict{"message": "Challenge cancelled successfully", "challenge": challengeView(challenge)}
+	responder.SetSuccess(data, "Challenge cancelled successfully", "user_id", userID, "challenge_id", challengeID)
+
+	notifyChallenge(server, challenge, "cancelled", challenge.ToID)
+}
+
+func HandleListChallenges(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list challenges")
+	if !loggedIn {
+		return
+	}
+
+	challenges := state.ChallengeService.ListChallenges(userID)
+	views := make([]utils.Dict, 0, len(challenges))
+	for _, challenge := range challenges {
+		views = append(views, challengeView(challenge))
+	}
+
+	data := utils.Dict{"challenges": views}
+	responder.SetSuccess(data, "Challenges listed successfully", "user_id", userID, "count", len(views))
+}
+
+// ExpireChallenges encerra os desafios vencidos e avisa os dois usuários de cada um.
+func ExpireChallenges(server *api.Server) {
+	for _, challenge := range state.ChallengeService.Expire() {
+		state.Logger.Info("Challenge expired", "challenge_id", challenge.ID, "from_id", challenge.FromID, "to_id", challenge.ToID)
+		notifyChallenge(server, challenge, domain.ChallengeExpired, challenge.FromID)
+		notifyChallenge(server, challenge, domain.ChallengeExpired, challenge.ToID)
+	}
+}
+
+// HandleChallengeDisconnect cancela os desafios de um usuário que se desconectou e avisa a outra parte.
+func HandleChallengeDisconnect(server *api.Server, userID string) {
+	for _, challenge := range state.ChallengeService.Withdraw(userID) {
+		state.Logger.Info("Challenge cancelled after disconnect", "challenge_id", challenge.ID, "user_id", userID)
+		notifyChallenge(server, challenge, "disconnected", challenge.Opponent(userID))
+	}
+}
+
+// userOnline informa se o usuário tem uma conexão ativa com o servidor.
+func userOnline(userID string) bool {
+	_, connected := state.UserConnections.Get(userID)
+	return connected
+}
+
+// announceChallengeExpired avisa a outra parte quando uma resposta encontrou o desafio vencido.
+func announceChallengeExpired(server *api.Server, challenge domain.Challenge, userID string) {
+	if challenge.Involves(userID) && challenge.Status == domain.ChallengeExpired {
+		notifyChallenge(server, challenge, domain.ChallengeExpired, challenge.Opponent(userID))
+	}
+}
+
+// notifyChallenge envia a um dos envolvidos a mudança de um desafio e, no aceite, a sala do duelo.
+func notifyChallenge(server *api.Server, challenge domain.Challenge, event, userID string) {
+	data := utils.Dict{"challenge": challengeView(challenge)}
+	if event == domain.ChallengeAccepted {
+		data = challengeRoomData(challenge)
+	}
+	data["event"] = event
+	notifyUser(server, userID, "challenge_update", data)
+}
+
+// challengeRoomData monta os dados de um desafio aceito com a sala do duelo e as suas regras.
+func challengeRoomData(challenge domain.Challenge) utils.Dict {
+	data := utils.Dict{"challenge": challengeView(challenge), "room_id": challenge.RoomID}
+	if room, err := state.RoomService.GetRoom(challenge.RoomID); err == nil {
+		data["room"] = roomSummary(room)
+		if ruleset, err := state.RulesetService.GetRuleset(room.RulesetID); err == nil {
+			data["ruleset"] = ruleset
+		}
+	}
+	return data
+}
+
+// challengeView converte um desafio nos dados enviados aos clientes.
+func challengeView(challenge domain.Challenge) utils.Dict {
+	return utils.Dict{
+		"id":            challenge.ID,
+		"from_id":       challenge.FromID,
+		"to_id":         challenge.ToID,
+		"ruleset_id":    challenge.RulesetID,
+		"commit_reveal": challenge.CommitReveal,
+		"status":        challenge.Status,
+		"room_id":       challenge.RoomID,
+		"created_at":    challenge.CreatedAt.Format(time.RFC3339),
+		"expires_at":    challenge.ExpiresAt.Format(time.RFC3339),
+	}
+}
+
+// challengeErrorMessage traduz erros do serviço de desafios na mensagem exibida ao cliente.
+func challengeErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrChallengeNotFound),
+		errors.Is(err, application.ErrChallengeClosed),
+		errors.Is(err, application.ErrChallengeExpired),
+		errors.Is(err, application.ErrChallengeSelf),
+		errors.Is(err, application.ErrChallengeBot),
+		errors.Is(err, application.ErrChallengeUnknownUser),
+		errors.Is(err, application.ErrChallengeOffline),
+		errors.Is(err, application.ErrChallengePending),
+		errors.Is(err, application.ErrChallengeBusy),
+		errors.Is(err, application.ErrNotChallenged),
+		errors.Is(err, application.ErrNotChallenger),
+		errors.Is(err, application.ErrUnknownRuleset):
+		return err.Error()
+	default:
+		return "Challenge request failed"
+	}
+}
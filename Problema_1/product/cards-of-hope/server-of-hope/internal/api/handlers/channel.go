@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"sort"
+	"time"
+)
+
+func HandleChannelJoin(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to join channel")
+	if !loggedIn {
+		return
+	}
+	name, nameOk := request.Data["channel"].(string)
+
+	if !nameOk {
+		responder.SetError("Invalid parameters", "Failed to join channel", "from", request.From)
+		return
+	}
+
+	channel, created, err := state.ChatService.JoinChannel(userID, name)
+	if err != nil {
+		responder.SetError(channelErrorMessage(err), "Failed to join channel", "user_id", userID, "channel", name, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Joined channel successfully", "channel": channelView(channel, userID), "created": created}
+	responder.SetSuccess(data, "Joined channel successfully", "user_id", userID, "channel", channel.Name, "created", created)
+
+	notifyChannelEvent(server, channel, "joined", userID)
+}
+
+func HandleChannelLeave(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to leave channel")
+	if !loggedIn {
+		return
+	}
+	name, nameOk := request.Data["channel"].(string)
+
+	if !nameOk {
+		responder.SetError("Invalid parameters", "Failed to leave channel", "from", request.From)
+		return
+	}
+
+	channel, err := state.ChatService.LeaveChannel(userID, name)
+	if err != nil {
+		responder.SetError(channelErrorMessage(err), "Failed to leave channel", "user_id", userID, "channel", name, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Left channel successfully", "channel": channel.Name}
+	responder.SetSuccess(data, "Left channel successfully", "user_id", userID, "channel", channel.Name)
+
+	notifyChannelEvent(server, channel, "left", userID)
+}
+
+func HandleChannelList(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list channels")
+	if !loggedIn {
+		return
+	}
+
+	channels := state.ChatService.Channels()
+	views := make([]utils.Dict, 0, len(channels))
+	for _, channel := range channels {
+		views = append(views, utils.Dict{
+			"name":     channel.Name,
+			"owner_id": channel.OwnerID,
+			"members":  channel.Members.Size(),
+			"joined":   channel.Members.Contains(userID),
+		})
+	}
+
+	data := utils.Dict{"channels": views}
+	responder.SetSuccess(data, "Channels listed successfully", "user_id", userID, "count", len(views))
+}
+
+func HandleChannelMembers(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list channel members")
+	if !loggedIn {
+		return
+	}
+	name, nameOk := request.Data["channel"].(string)
+
+	if !nameOk {
+		responder.SetError("Invalid parameters", "Failed to list channel members", "from", request.From)
+		return
+	}
+
+	channel, err := state.ChatService.GetChannel(name)
+	if err != nil {
+		responder.SetError(channelErrorMessage(err), "Failed to list channel members", "user_id", userID, "channel", name, "error", err)
+		return
+	}
+
+	data := utils.Dict{"channel": channelView(channel, userID)}
+	responder.SetSuccess(data, "Channel members listed successfully", "user_id", userID, "channel", channel.Name)
+}
+
+func HandleChannelSend(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to send channel message")
+	if !loggedIn {
+		return
+	}
+	name, nameOk := request.Data["channel"].(string)
+	text, textOk := request.Data["text"].(string)
+
+	if !nameOk || !textOk {
+		responder.SetError("Invalid parameters", "Failed to send channel message", "from", request.From)
+		return
+	}
+
+	channel, text, err := state.ChatService.SendChannelMessage(userID, name, text)
+	if err != nil {
+		responder.SetError(channelErrorMessage(err), "Failed to send channel message", "user_id", userID, "channel", name, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Channel message sent", "channel": channel.Name}
+	responder.SetSuccess(data, "Channel message sent", "user_id", userID, "channel", channel.Name)
+
+	message := utils.Dict{
+		"channel": channel.Name,
+		"from_id": userID,
+		"text":    text,
+		"sent_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, memberID := range channel.Members.Items() {
+		if memberID != userID {
+			notifyUser(server, memberID, "channel_message", message)
+		}
+	}
+}
+
+// HandleChannelDisconnect tira dos canais de chat um usuário que se desconectou e avisa os demais membros.
+func HandleChannelDisconnect(server *api.Server, userID string) {
+	for _, channel := range state.ChatService.LeaveChannels(userID) {
+		notifyChannelEvent(server, channel, "disconnected", userID)
+	}
+}
+
+// joinLobby coloca no saguão global o usuário que acabou de fazer login.
+func joinLobby(userID string) string {
+	channel, _, err := state.ChatService.JoinChannel(userID, domain.LobbyChannel)
+	if err != nil {
+		state.Logger.Error("Failed to join lobby on login", "user_id", userID, "error", err)
+		return ""
+	}
+	return channel.Name
+}
+
+// notifyChannelEvent avisa os membros de um canal de assunto da entrada ou da saída de um usuário.
+// O saguão não tem esses avisos, porque todo usuário entra nele ao fazer login.
+func notifyChannelEvent(server *api.Server, channel domain.Channel, event, userID string) {
+	if channel.Permanent() {
+		return
+	}
+	data := utils.Dict{"channel": channel.Name, "event": event, "user_id": userID, "members": channel.Members.Size()}
+	for _, memberID := range channel.Members.Items() {
+		if memberID != userID {
+			notifyUser(server, memberID, "channel_event", data)
+		}
+	}
+}
+
+// channelView converte um canal nos dados enviados ao usuário, com a lista de membros em ordem alfabética.
+func channelView(channel domain.Channel, userID string) utils.Dict {
+	members := channel.Members.Items()
+	sort.Strings(members)
+	return utils.Dict{
+		"name":       channel.Name,
+		"owner_id":   channel.OwnerID,
+		"members":    members,
+		"joined":     channel.Members.Contains(userID),
+		"created_at": channel.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// channelErrorMessage traduz erros dos canais de chat na mensagem exibida ao cliente.
+func channelErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrChannelName),
+		errors.Is(err, application.ErrChannelNotFound),
+		errors.Is(err, application.ErrChannelMember),
+		errors.Is(err, application.ErrChannelLimit),
+		errors.Is(err, application.ErrMessageEmpty),
+		errors.Is(err, application.ErrMessageLength):
+		return err.Error()
+	default:
+		return "Channel request failed"
+	}
+}
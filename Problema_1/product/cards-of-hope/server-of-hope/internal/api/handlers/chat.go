@@ -3,6 +3,7 @@ package handlers
 import (
 	"server-of-hope/internal/api"
 	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/domain"
 	"server-of-hope/internal/state"
 	"server-of-hope/internal/utils"
 )
@@ -11,21 +12,38 @@ func HandleSendMessage(server *api.Server, request protocol.Request) {
 	responder := NewResponder(server, request)
 	defer responder.Send()
 
+	userID, loggedIn := responder.RequireLogin("Failed to send message")
+	if !loggedIn {
+		return
+	}
 	roomID, roomIDOk := request.Data["room_id"].(string)
 	message, messageOk := request.Data["message"].(string)
-	userID, userIDOk := request.Data["user_id"].(string)
+	channel, _ := request.Data["channel"].(string)
 
-	if !roomIDOk || !messageOk || !userIDOk {
+	if !roomIDOk || !messageOk {
 		responder.SetError("Invalid parameters", "Failed to send message", "from", request.From)
 		return
 	}
 
-	err := state.ChatService.SendMessage(roomID, userID, message)
+	var err error
+	switch channel {
+	case "", "room":
+		err = state.ChatService.SendMessage(roomID, userID, message)
+	case "spectators":
+		err = state.ChatService.SendSpectatorMessage(roomID, userID, message)
+	default:
+		responder.SetError("Invalid chat channel", "Failed to send message", "from", request.From, "channel", channel)
+		return
+	}
 	if err != nil {
 		responder.SetError("Room does not exist or user not in room", "Failed to send message", "from", request.From, "room_id", roomID, "error", err)
 		return
 	}
 
+	if channel != "spectators" {
+		state.ReplayService.Record(roomID, domain.ReplayEvent{Type: domain.ReplayChat, UserID: userID, Text: message})
+	}
+
 	data := utils.Dict{
 		"message": "Message sent successfully",
 		"room_id": roomID,
@@ -37,10 +55,13 @@ func HandleFetchMessage(server *api.Server, request protocol.Request) {
 	responder := NewResponder(server, request)
 	defer responder.Send()
 
+	userID, loggedIn := responder.RequireLogin("Failed to fetch message")
+	if !loggedIn {
+		return
+	}
 	roomID, roomIDOk := request.Data["room_id"].(string)
-	userID, userIDOk := request.Data["user_id"].(string)
 
-	if !roomIDOk || !userIDOk {
+	if !roomIDOk {
 		responder.SetError("Invalid parameters", "Failed to fetch message", "from", request.From)
 		return
 	}
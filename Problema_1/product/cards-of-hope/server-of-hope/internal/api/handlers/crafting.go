@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+)
+
+func HandleRecipes(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, _ := sessionUser(server, request)
+
+	rules := state.CraftingService.Rules()
+	data := utils.Dict{
+		"recipes":       rules.Recipes,
+		"dust_per_star": rules.DustPerStar,
+		"ability_dust":  rules.AbilityDust,
+		"rarity_dust":   rules.RarityDust,
+	}
+	if userID != "" {
+		dust, err := state.WalletService.Dust(userID)
+		if err != nil {
+			responder.SetError("Could not list the recipes", "Failed to list recipes", "user_id", userID, "error", err)
+			return
+		}
+		data["dust"] = dust
+	}
+	responder.SetSuccess(data, "Recipes sent successfully", "from", request.From, "count", len(rules.Recipes))
+}
+
+func HandleCraft(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to craft card")
+	if !loggedIn {
+		return
+	}
+	cardIDs, cardIDsOk := stringList(request.Data["card_ids"])
+
+	if !cardIDsOk {
+		responder.SetError("Invalid parameters", "Failed to craft card", "from", request.From)
+		return
+	}
+
+	crafted, consumed, dust, err := state.CraftingService.Craft(userID, cardIDs)
+	if err != nil {
+		responder.SetError(craftingErrorMessage(err), "Failed to craft card", "user_id", userID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Card crafted successfully", "card": crafted, "consumed": consumed, "dust": dust}
+	responder.SetSuccess(data, "Card crafted successfully", "user_id", userID, "card_id", crafted.ID, "stars", crafted.Stars)
+}
+
+func HandleDisenchant(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to disenchant cards")
+	if !loggedIn {
+		return
+	}
+	cardIDs, cardIDsOk := stringList(request.Data["card_ids"])
+
+	if !cardIDsOk {
+		responder.SetError("Invalid parameters", "Failed to disenchant cards", "from", request.From)
+		return
+	}
+
+	consumed, gained, dust, err := state.CraftingService.Disenchant(userID, cardIDs)
+	if err != nil {
+		responder.SetError(craftingErrorMessage(err), "Failed to disenchant cards", "user_id", userID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Cards disenchanted successfully", "consumed": consumed, "gained": gained, "dust": dust}
+	responder.SetSuccess(data, "Cards disenchanted successfully", "user_id", userID, "count", len(consumed), "gained", gained)
+}
+
+// craftingErrorMessage traduz erros da criação e do desmanche na mensagem exibida ao cliente.
+func craftingErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrCraftRecipe),
+		errors.Is(err, application.ErrCraftCardOwner),
+		errors.Is(err, application.ErrCraftBot),
+		errors.Is(err, application.ErrDisenchantCards),
+		errors.Is(err, application.ErrInsufficientDust),
+		errors.Is(err, application.ErrBotWallet):
+		return err.Error()
+	default:
+		return "Crafting request failed"
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+)
+
+func HandleCollection(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list collection")
+	if !loggedIn {
+		return
+	}
+	if ownerID, _ := request.Data["owner_id"].(string); ownerID != "" {
+		userID = ownerID // As coleções são públicas, para que se possa pedir cartas em uma troca
+	}
+
+	cards, err := state.DeckService.Collection(userID)
+	if err != nil {
+		responder.SetError(err.Error(), "Failed to list collection", "user_id", userID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"cards": cards}
+	responder.SetSuccess(data, "Collection listed successfully", "user_id", userID, "count", len(cards))
+}
+
+func HandleSaveDeck(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to save deck")
+	if !loggedIn {
+		return
+	}
+	name, nameOk := request.Data["name"].(string)
+	rawIDs, cardIDsOk := request.Data["card_ids"].([]any)
+
+	if !nameOk || !cardIDsOk {
+		responder.SetError("Invalid parameters", "Failed to save deck", "from", request.From)
+		return
+	}
+
+	cardIDs := make([]string, 0, len(rawIDs))
+	for _, rawID := range rawIDs {
+		cardID, ok := rawID.(string)
+		if !ok {
+			responder.SetError("Invalid parameters", "Failed to save deck", "user_id", userID)
+			return
+		}
+		cardIDs = append(cardIDs, cardID)
+	}
+
+	if err := state.DeckService.SaveDeck(userID, name, cardIDs); err != nil {
+		responder.SetError(err.Error(), "Failed to save deck", "user_id", userID, "name", name, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Deck saved", "name": name}
+	responder.SetSuccess(data, "Deck saved successfully", "user_id", userID, "name", name)
+}
+
+func HandleListDecks(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list decks")
+	if !loggedIn {
+		return
+	}
+
+	decks, err := state.DeckService.ListDecks(userID)
+	if err != nil {
+		responder.SetError(err.Error(), "Failed to list decks", "user_id", userID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"decks": decks}
+	responder.SetSuccess(data, "Decks listed successfully", "user_id", userID, "count", len(decks))
+}
+
+func HandleSelectDeck(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to select deck")
+	if !loggedIn {
+		return
+	}
+	roomID, roomIDOk := request.Data["room_id"].(string)
+	name, _ := request.Data["name"].(string)
+
+	if !roomIDOk {
+		responder.SetError("Invalid parameters", "Failed to select deck", "from", request.From)
+		return
+	}
+
+	if err := state.DeckService.SelectDeck(roomID, userID, name); err != nil {
+		responder.SetError(err.Error(), "Failed to select deck", "user_id", userID, "room_id", roomID, "name", name, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Deck selected", "room_id": roomID, "name": name}
+	responder.SetSuccess(data, "Deck selected successfully", "user_id", userID, "room_id", roomID, "name", name)
+}
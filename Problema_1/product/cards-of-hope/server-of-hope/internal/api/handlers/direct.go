@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+func HandleDirectMessage(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to send direct message")
+	if !loggedIn {
+		return
+	}
+	to, toOk := request.Data["to"].(string)
+	text, textOk := request.Data["text"].(string)
+
+	if !toOk || !textOk || to == "" {
+		responder.SetError("Invalid parameters", "Failed to send direct message", "from", request.From)
+		return
+	}
+
+	message, err := state.ChatService.SendDirect(userID, to, text)
+	if err != nil {
+		responder.SetError(directErrorMessage(err), "Failed to send direct message", "user_id", userID, "to", to, "error", err)
+		return
+	}
+
+	_, online := state.UserConnections.Get(to)
+	if online {
+		if err := state.ChatService.MarkDelivered(message.ID); err != nil {
+			state.Logger.Error("Failed to mark direct message as delivered", "message_id", message.ID, "error", err)
+		}
+		message.Delivered = true
+		notifyUser(server, to, "dm", directMessageView(message))
+	}
+
+	data := utils.Dict{"message": "Direct message sent", "direct_message": directMessageView(message), "delivered": online}
+	responder.SetSuccess(data, "Direct message sent", "user_id", userID, "to", to, "message_id", message.ID, "delivered", online)
+}
+
+func HandleDirectHistory(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to read direct messages")
+	if !loggedIn {
+		return
+	}
+	with, withOk := request.Data["with"].(string)
+	limit, _ := request.Data["limit"].(float64)
+
+	if !withOk || with == "" {
+		responder.SetError("Invalid parameters", "Failed to read direct messages", "from", request.From)
+		return
+	}
+
+	messages, err := state.ChatService.History(userID, with, int(limit))
+	if err != nil {
+		responder.SetError(directErrorMessage(err), "Failed to read direct messages", "user_id", userID, "with", with, "error", err)
+		return
+	}
+
+	views := make([]utils.Dict, 0, len(messages))
+	for _, message := range messages {
+		views = append(views, directMessageView(message))
+	}
+	data := utils.Dict{"with": with, "messages": views}
+	responder.SetSuccess(data, "Direct messages listed", "user_id", userID, "with", with, "count", len(views))
+}
+
+// notifyInbox entrega ao usuário que acabou de entrar as mensagens diretas recebidas enquanto estava desconectado.
+func notifyInbox(server *api.Server, userID string) {
+	messages, err := state.ChatService.Inbox(userID)
+	if err != nil {
+		state.Logger.Error("Failed to read direct message inbox on login", "user_id", userID, "error", err)
+		return
+	}
+	if len(messages) == 0 {
+		return
+	}
+	views := make([]utils.Dict, 0, len(messages))
+	for _, message := range messages {
+		views = append(views, directMessageView(message))
+	}
+	notifyUser(server, userID, "dm_inbox", utils.Dict{"messages": views})
+}
+
+// directMessageView converte uma mensagem direta nos dados enviados aos usuários.
+func directMessageView(message domain.DirectMessage) utils.Dict {
+	return utils.Dict{
+		"id":        message.ID,
+		"from_id":   message.FromID,
+		"to_id":     message.ToID,
+		"text":      message.Text,
+		"sent_at":   message.SentAt.Format(time.RFC3339),
+		"delivered": message.Delivered,
+	}
+}
+
+// directErrorMessage traduz erros das mensagens diretas na mensagem exibida ao cliente.
+func directErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrMessageSelf),
+		errors.Is(err, application.ErrMessageUnknownUser),
+		errors.Is(err, application.ErrMessageBot),
+		errors.Is(err, application.ErrMessageEmpty),
+		errors.Is(err, application.ErrMessageLength),
+		errors.Is(err, application.ErrInboxFull):
+		return err.Error()
+	default:
+		return "Could not deliver the message"
+	}
+}
@@ -3,31 +3,176 @@ package handlers
 import (
 	"server-of-hope/internal/api"
 	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
 	"server-of-hope/internal/domain"
 	"server-of-hope/internal/state"
 	"server-of-hope/internal/utils"
+	"sort"
+	"time"
 )
 
+func HandleReady(server *api.Server, request protocol.Request) {
+	setReady(server, request, true)
+}
+
+func HandleUnready(server *api.Server, request protocol.Request) {
+	setReady(server, request, false)
+}
+
+func setReady(server *api.Server, request protocol.Request, ready bool) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to update ready state")
+	if !loggedIn {
+		return
+	}
+	roomID, roomIDOk := request.Data["room_id"].(string)
+	stake, stakeOk := request.Data["stake"].([]any)
+
+	if !roomIDOk {
+		responder.SetError("Invalid parameters", "Failed to update ready state", "from", request.From)
+		return
+	}
+	if ready && stakeOk {
+		cardIDs := make([]string, 0, len(stake))
+		for _, item := range stake {
+			cardID, _ := item.(string)
+			cardIDs = append(cardIDs, cardID)
+		}
+		if err := state.WagerService.ChooseStake(roomID, userID, cardIDs); err != nil {
+			responder.SetError(err.Error(), "Failed to choose wager stake", "user_id", userID, "room_id", roomID, "error", err)
+			return
+		}
+	}
+
+	started, err := state.GameService.SetReady(roomID, userID, ready)
+	if err != nil {
+		responder.SetError(err.Error(), "Failed to update ready state", "user_id", userID, "room_id", roomID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Ready state updated", "room_id": roomID, "ready": ready, "started": started}
+	responder.SetSuccess(data, "Ready state updated", "user_id", userID, "room_id", roomID, "ready", ready, "started", started)
+
+	announceReady(server, roomID, userID, ready, started)
+}
+
+// announceReady avisa a sala da mudança de prontidão de um jogador e, se ela começou, da partida.
+func announceReady(server *api.Server, roomID, userID string, ready, started bool) {
+	room, err := state.RoomService.GetRoom(roomID)
+	if err != nil {
+		state.Logger.Error("Failed to get room after ready update", "room_id", roomID, "error", err)
+		return
+	}
+	event := "unready"
+	if ready {
+		event = "ready"
+	}
+	notifyRoomEvent(server, room, event, userID)
+
+	if started {
+		notifyMatchStarted(server, room)
+	}
+}
+
+func HandleRematch(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to answer rematch")
+	if !loggedIn {
+		return
+	}
+	roomID, roomIDOk := request.Data["room_id"].(string)
+	accept, acceptOk := request.Data["accept"].(bool)
+
+	if !roomIDOk {
+		responder.SetError("Invalid parameters", "Failed to answer rematch", "from", request.From)
+		return
+	}
+	if !acceptOk {
+		accept = true
+	}
+
+	started, err := state.GameService.Rematch(roomID, userID, accept)
+	if err != nil {
+		responder.SetError(err.Error(), "Failed to answer rematch", "user_id", userID, "room_id", roomID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Rematch answer registered", "room_id": roomID, "accept": accept, "started": started}
+	responder.SetSuccess(data, "Rematch answer registered", "user_id", userID, "room_id", roomID, "accept", accept, "started", started)
+
+	announceRematch(server, roomID, userID, accept, started)
+}
+
+// announceRematch avisa a sala da resposta de um jogador ao pedido de revanche ou do início da nova partida.
+func announceRematch(server *api.Server, roomID, userID string, accept, started bool) {
+	room, err := state.RoomService.GetRoom(roomID)
+	if err != nil {
+		state.Logger.Error("Failed to get room after rematch answer", "room_id", roomID, "error", err)
+		return
+	}
+	switch {
+	case started:
+		notifyMatchStarted(server, room)
+	case accept:
+		notifyRoomEvent(server, room, "rematch_requested", userID)
+	default:
+		notifyRoomEvent(server, room, "rematch_declined", userID)
+	}
+}
+
+// notifyMatchStarted avisa todos os membros da sala que uma partida começou, com os assentos,
+// o placar da série e as condições de vitória das regras da sala.
+func notifyMatchStarted(server *api.Server, room domain.Room) {
+	game, err := state.GameService.GetGame(room.ID)
+	if err != nil {
+		state.Logger.Error("Failed to get game after match start", "room_id", room.ID, "error", err)
+		return
+	}
+	ruleset, err := state.RulesetService.GetRuleset(room.RulesetID)
+	if err != nil {
+		state.Logger.Error("Failed to get ruleset after match start", "room_id", room.ID, "ruleset", room.RulesetID, "error", err)
+		return
+	}
+	state.ReplayService.StartMatch(room, game)
+	notifyRoom(server, room, "match_started", utils.Dict{
+		"room_id":        room.ID,
+		"players":        game.Seats,
+		"match_number":   game.Number,
+		"series":         game.Series,
+		"ruleset_id":     ruleset.ID,
+		"winning_rounds": ruleset.WinningRounds,
+		"max_rounds":     ruleset.MaxRounds,
+		"commit_reveal":  room.CommitReveal,
+		"wager":          matchWager(room),
+	})
+	notifyHands(server, room.ID)
+}
+
 func HandlePlayCard(server *api.Server, request protocol.Request) {
 	responder := NewResponder(server, request)
 
-	userID, _ := request.Data["user_id"].(string)
+	userID, loggedIn := responder.RequireLogin("Card play failed")
+	if !loggedIn {
+		responder.Send()
+		return
+	}
 	gameID, _ := request.Data["room_id"].(string) // In client, it's room_id
-	cardType, _ := request.Data["card"].(string)
-	cardStars, _ := request.Data["stars"].(float64)
+	cardRef, _ := request.Data["card_id"].(string)
+	if cardRef == "" {
+		cardRef, _ = request.Data["card"].(string)
+	}
 
-	if userID == "" || gameID == "" || cardType == "" {
+	if gameID == "" || cardRef == "" {
 		responder.SetError("Invalid parameters", "Card play failed", "from", request.From)
 		responder.Send()
 		return
 	}
 
-	card := domain.Card{
-		Type:  cardType,
-		Stars: int(cardStars),
-	}
-
-	err := state.GameService.PlayCard(gameID, userID, card)
+	result, err := state.GameService.PlayCard(gameID, userID, cardRef)
 	if err != nil {
 		responder.SetError(err.Error(), "Card play failed", "user_id", userID, "game_id", gameID, "error", err)
 		responder.Send()
@@ -35,45 +180,296 @@ func HandlePlayCard(server *api.Server, request protocol.Request) {
 	}
 
 	data := utils.Dict{"message": "Card played successfully"}
-	responder.SetSuccess(data, "Card played successfully", "user_id", userID, "game_id", gameID, "card", cardType, "stars", cardStars)
+	if game, err := state.GameService.GetGame(gameID); err == nil {
+		hand, _ := game.Hands.Get(userID)
+		data["hand"] = handCards(hand)
+	}
+	responder.SetSuccess(data, "Card played successfully", "user_id", userID, "game_id", gameID, "card", cardRef)
 	responder.Send()
 
-	// Check if game is ready
+	notifyPlay(server, gameID, userID, result)
+}
+
+func HandleCommit(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Commit failed")
+	if !loggedIn {
+		return
+	}
+	gameID, gameIDOk := request.Data["room_id"].(string)
+	commitment, commitmentOk := request.Data["commitment"].(string)
+
+	if !gameIDOk || !commitmentOk {
+		responder.SetError("Invalid parameters", "Commit failed", "from", request.From)
+		return
+	}
+
+	complete, err := state.GameService.Commit(gameID, userID, commitment)
+	if err != nil {
+		responder.SetError(err.Error(), "Commit failed", "user_id", userID, "game_id", gameID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Commitment registered", "room_id": gameID, "reveal": complete}
+	responder.SetSuccess(data, "Commitment registered", "user_id", userID, "game_id", gameID, "reveal", complete)
+
+	notifyCommitted(server, gameID, userID, complete)
+}
+
+// notifyCommitted avisa os jogadores de um novo compromisso e se já é hora de revelar.
+func notifyCommitted(server *api.Server, gameID, userID string, complete bool) {
 	game, err := state.GameService.GetGame(gameID)
 	if err != nil {
-		state.Logger.Error("Failed to get game state after play", "game_id", gameID, "error", err)
+		state.Logger.Error("Failed to get game after commit", "room_id", gameID, "error", err)
 		return
 	}
+	notifyUsers(server, game.Seats, "committed", utils.Dict{
+		"room_id": gameID,
+		"user_id": userID,
+		"reveal":  complete,
+	})
+}
 
-	if game.Plays.Size() == 2 {
-		// Notify both players
-		playerIDs := game.Plays.Keys()
-		card1, _ := game.Plays.Get(playerIDs[0])
-		card2, _ := game.Plays.Get(playerIDs[1])
+func HandleReveal(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
 
-		notifyPlayer(server, playerIDs[0], card2)
-		notifyPlayer(server, playerIDs[1], card1)
+	userID, loggedIn := responder.RequireLogin("Reveal failed")
+	if !loggedIn {
+		responder.Send()
+		return
+	}
+	gameID, _ := request.Data["room_id"].(string)
+	nonce, _ := request.Data["nonce"].(string)
+	cardRef, _ := request.Data["card_id"].(string)
+	if cardRef == "" {
+		cardRef, _ = request.Data["card"].(string)
+	}
+
+	if gameID == "" || cardRef == "" || nonce == "" {
+		responder.SetError("Invalid parameters", "Reveal failed", "from", request.From)
+		responder.Send()
+		return
+	}
+
+	result, err := state.GameService.Reveal(gameID, userID, cardRef, nonce)
+	if err != nil {
+		responder.SetError(err.Error(), "Reveal failed", "user_id", userID, "game_id", gameID, "error", err)
+		responder.Send()
+		if result != nil {
+			notifyMatchFinished(server, gameID, result) // As revelações inválidas encerraram a partida
+		}
+		return
+	}
 
-		// Reset round
-		state.GameService.ResetRound(gameID)
+	data := utils.Dict{"message": "Card revealed successfully"}
+	if game, err := state.GameService.GetGame(gameID); err == nil {
+		hand, _ := game.Hands.Get(userID)
+		data["hand"] = handCards(hand)
 	}
+	responder.SetSuccess(data, "Card revealed successfully", "user_id", userID, "game_id", gameID, "card", cardRef)
+	responder.Send()
+
+	notifyPlay(server, gameID, userID, result)
 }
 
-func notifyPlayer(server *api.Server, playerID string, opponentCard domain.Card) {
-	playerAddress, ok := state.UserConnections.Get(playerID)
-	if !ok {
-		state.Logger.Warn("Could not find connection for user to notify", "user_id", playerID)
+// notifyPlay avisa os envolvidos depois de uma jogada: revela a carta a quem tiver uma
+// habilidade de revelação ou, se a rodada foi resolvida, envia o resultado aos jogadores e
+// espectadores, as novas mãos e o fim da partida.
+func notifyPlay(server *api.Server, gameID, userID string, result *domain.RoundResult) {
+	recordPlay(gameID, userID, result)
+	if result == nil {
+		notifyReveal(server, gameID, userID)
+		return // Aguardando a jogada do oponente
+	}
+
+	// Notify both players
+	for playerID := range result.Plays {
+		for opponentID, opponentCard := range result.Plays {
+			if opponentID != playerID {
+				notifyPlayer(server, gameID, playerID, opponentCard, result)
+			}
+		}
+	}
+	notifySpectators(server, gameID, roundSummary(gameID, result))
+	rewardRound(server, gameID, result)
+
+	if !result.MatchFinished {
+		notifyHands(server, gameID)
 		return
 	}
 
-	response := protocol.Response{
-		Method: "opponent_played",
-		Status: "ok",
-		Data: utils.Dict{
-			"opponent_card":      opponentCard.Type,
-			"opponent_card_star": opponentCard.Stars,
-		},
-		To: playerAddress,
+	notifyMatchFinished(server, gameID, result)
+}
+
+// notifyMatchFinished avisa todos os membros da sala que a partida terminou e acerta os prêmios,
+// a aposta e o torneio da partida.
+func notifyMatchFinished(server *api.Server, gameID string, result *domain.RoundResult) {
+	room, err := state.RoomService.GetRoom(gameID)
+	if err != nil {
+		state.Logger.Error("Failed to get room after match end", "room_id", gameID, "error", err)
+		return
+	}
+	// O fim de cada partida é acertado uma única vez, mesmo que ela seja encerrada por dois
+	// caminhos ao mesmo tempo (revelações inválidas, saída da sala, prazo da rodada, ausência na
+	// partida com aposta); só o primeiro avisa a sala, paga os prêmios e registra o resultado.
+	claimed, err := state.GameService.ClaimSettlement(gameID)
+	if err != nil {
+		state.Logger.Error("Failed to claim match settlement", "room_id", gameID, "error", err)
+		return
+	}
+	if !claimed {
+		state.Logger.Info("Match already settled", "room_id", gameID)
+		return
+	}
+	replayID, err := state.ReplayService.FinishMatch(gameID, *result)
+	if err != nil {
+		state.Logger.Error("Failed to save match replay", "room_id", gameID, "error", err)
+	}
+	state.Logger.Info("Match finished", "room_id", gameID, "winner_id", result.MatchWinnerID, "replay_id", replayID)
+	notifyRoom(server, room, "match_finished", utils.Dict{
+		"room_id":         gameID,
+		"winner_id":       result.MatchWinnerID,
+		"draw":            result.MatchWinnerID == "",
+		"scores":          result.Scores,
+		"series":          result.Series,
+		"forfeited_by":    result.ForfeitedBy,
+		"timed_out":       result.TimedOut,
+		"replay_id":       replayID,
+		"rematch_seconds": int(application.RematchWindow.Seconds()),
+	})
+	rewardMatch(server, gameID, result)
+	settleWager(server, room, result.MatchWinnerID)
+	recordTournamentResult(server, room, result.MatchWinnerID)
+}
+
+func notifyPlayer(server *api.Server, gameID, playerID string, opponentCard domain.Card, result *domain.RoundResult) {
+	notifyUser(server, playerID, "opponent_played", utils.Dict{
+		"room_id":            gameID,
+		"opponent_card":      opponentCard.Type,
+		"opponent_card_star": opponentCard.Stars,
+		"opponent_ability":   opponentCard.Ability,
+		"round":              result.Round,
+		"winner_id":          result.WinnerID,
+		"scores":             result.Scores,
+		"effective_stars":    effectiveStars(result),
+		"effects":            roundEffects(result),
+	})
+}
+
+// notifyReveal mostra a carta recém-jogada ao oponente que tem uma habilidade de revelação
+// ativa nesta rodada, desde que ele ainda não tenha jogado.
+func notifyReveal(server *api.Server, gameID, playerID string) {
+	game, err := state.GameService.GetGame(gameID)
+	if err != nil || game.Reveals == nil {
+		return
+	}
+	card, played := game.Plays.Get(playerID)
+	if !played {
+		return
+	}
+	for _, opponentID := range game.Seats {
+		if opponentID == playerID || !game.Reveals.Contains(opponentID) {
+			continue
+		}
+		if _, played := game.Plays.Get(opponentID); played {
+			continue
+		}
+		notifyUser(server, opponentID, "opponent_revealed", utils.Dict{
+			"room_id": gameID,
+			"user_id": playerID,
+			"card":    card.Type,
+			"stars":   card.Stars,
+			"ability": card.Ability,
+		})
+	}
+}
+
+// notifyHands envia a cada jogador da partida as cartas da sua mão e quantas ainda restam para
+// comprar.
+func notifyHands(server *api.Server, gameID string) {
+	game, err := state.GameService.GetGame(gameID)
+	if err != nil {
+		state.Logger.Error("Failed to get game to notify hands", "room_id", gameID, "error", err)
+		return
+	}
+	for _, playerID := range game.Seats {
+		hand, _ := game.Hands.Get(playerID)
+		pile, _ := game.DrawPiles.Get(playerID)
+		data := utils.Dict{
+			"room_id":   gameID,
+			"hand":      handCards(hand),
+			"draw_pile": len(pile),
+		}
+		if !game.TurnDeadline.IsZero() {
+			data["turn_deadline"] = game.TurnDeadline.Format(time.RFC3339)
+		}
+		notifyUser(server, playerID, "hand", data)
+	}
+}
+
+// handCards retorna as cartas da mão, nunca nulo para que o JSON traga uma lista.
+func handCards(hand []domain.OwnedCard) []domain.OwnedCard {
+	if hand == nil {
+		return []domain.OwnedCard{}
+	}
+	return hand
+}
+
+// effectiveStars retorna as estrelas de cada carta após as habilidades da rodada.
+func effectiveStars(result *domain.RoundResult) map[string]int {
+	stars := make(map[string]int, len(result.Effective))
+	for playerID, card := range result.Effective {
+		stars[playerID] = card.Stars
+	}
+	return stars
+}
+
+// roundEffects retorna as habilidades aplicadas na rodada, nunca nulo para que o JSON traga uma lista.
+func roundEffects(result *domain.RoundResult) []domain.AppliedEffect {
+	if result.Effects == nil {
+		return []domain.AppliedEffect{}
+	}
+	return result.Effects
+}
+
+// notifySpectators envia o resultado de uma rodada aos espectadores da sala. As jogadas só são
+// reveladas depois que os dois jogadores jogaram.
+func notifySpectators(server *api.Server, roomID string, result utils.Dict) {
+	room, err := state.RoomService.GetRoom(roomID)
+	if err != nil {
+		state.Logger.Error("Failed to get room to notify spectators", "room_id", roomID, "error", err)
+		return
+	}
+	notifyUsers(server, room.Spectators.Items(), "round_result", result)
+}
+
+// roundSummary converte o resultado de uma rodada na mensagem round_result.
+func roundSummary(roomID string, result *domain.RoundResult) utils.Dict {
+	playerIDs := make([]string, 0, len(result.Plays))
+	for playerID := range result.Plays {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+
+	plays := make([]utils.Dict, 0, len(playerIDs))
+	for _, playerID := range playerIDs {
+		card := result.Plays[playerID]
+		plays = append(plays, utils.Dict{
+			"user_id":         playerID,
+			"card":            card.Type,
+			"stars":           card.Stars,
+			"ability":         card.Ability,
+			"effective_stars": result.Effective[playerID].Stars,
+		})
+	}
+	return utils.Dict{
+		"room_id":   roomID,
+		"round":     result.Round,
+		"plays":     plays,
+		"winner_id": result.WinnerID,
+		"scores":    result.Scores,
+		"effects":   roundEffects(result),
 	}
-	server.Responses <- response
 }
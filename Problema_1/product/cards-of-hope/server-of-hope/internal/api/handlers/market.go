@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+func HandleListMarket(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	cardType, _ := request.Data["type"].(string)
+	minStars, _ := request.Data["min_stars"].(float64)
+	maxStars, _ := request.Data["max_stars"].(float64)
+	sellerID, _ := request.Data["seller_id"].(string)
+
+	filter := domain.ListingFilter{Type: cardType, MinStars: int(minStars), MaxStars: int(maxStars), SellerID: sellerID}
+	listings, err := state.MarketService.List(filter)
+	if err != nil {
+		responder.SetError("Could not list the market", "Failed to list market", "from", request.From, "error", err)
+		return
+	}
+
+	views := make([]utils.Dict, 0, len(listings))
+	for _, listing := range listings {
+		views = append(views, listingView(listing))
+	}
+
+	data := utils.Dict{"listings": views}
+	responder.SetSuccess(data, "Market listed successfully", "from", request.From, "count", len(views))
+}
+
+func HandleSellCards(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list cards")
+	if !loggedIn {
+		return
+	}
+	cardIDs, cardIDsOk := stringList(request.Data["card_ids"])
+	price, priceOk := request.Data["price"].(float64)
+	auction, _ := request.Data["auction"].(bool)
+	minutes, _ := request.Data["minutes"].(float64)
+
+	if !cardIDsOk || !priceOk {
+		responder.SetError("Invalid parameters", "Failed to list cards", "from", request.From)
+		return
+	}
+
+	kind := domain.ListingFixed
+	if auction {
+		kind = domain.ListingAuction
+	}
+	listing, err := state.MarketService.Sell(userID, kind, cardIDs, int(price), time.Duration(minutes)*time.Minute)
+	if err != nil {
+		responder.SetError(marketErrorMessage(err), "Failed to list cards", "user_id", userID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Cards listed successfully", "listing": listingView(listing)}
+	responder.SetSuccess(data, "Cards listed successfully", "user_id", userID, "listing_id", listing.ID, "kind", kind)
+}
+
+func HandleBuyListing(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to buy listing")
+	if !loggedIn {
+		return
+	}
+	listingID, ok := request.Data["listing_id"].(string)
+	if !ok {
+		responder.SetError("Invalid parameters", "Failed to buy listing", "from", request.From)
+		return
+	}
+
+	listing, received, err := state.MarketService.Buy(listingID, userID)
+	if err != nil {
+		responder.SetError(marketErrorMessage(err), "Failed to buy listing", "user_id", userID, "listing_id", listingID, "error", err)
+		return
+	}
+
+	balance, _, _ := state.WalletService.Balance(userID)
+	data := utils.Dict{"message": "Listing bought successfully", "listing": listingView(listing), "received": received, "balance": balance}
+	responder.SetSuccess(data, "Listing bought successfully", "user_id", userID, "listing_id", listingID, "price", listing.Price)
+
+	notifyMarket(server, listing, "sold", listing.SellerID)
+}
+
+func HandleBid(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to bid")
+	if !loggedIn {
+		return
+	}
+	listingID, ok := request.Data["listing_id"].(string)
+	amount, amountOk := request.Data["amount"].(float64)
+	if !ok || !amountOk {
+		responder.SetError("Invalid parameters", "Failed to bid", "from", request.From)
+		return
+	}
+
+	listing, err := state.MarketService.Bid(listingID, userID, int(amount))
+	if err != nil {
+		responder.SetError(marketErrorMessage(err), "Failed to bid", "user_id", userID, "listing_id", listingID, "error", err)
+		return
+	}
+
+	balance, _, _ := state.WalletService.Balance(userID)
+	data := utils.Dict{"message": "Bid placed successfully", "listing": listingView(listing), "balance": balance}
+	responder.SetSuccess(data, "Bid placed successfully", "user_id", userID, "listing_id", listingID, "amount", int(amount))
+
+	notifyMarket(server, listing, "bid", listing.SellerID)
+	if len(listing.Bids) > 1 {
+		if previous := listing.Bids[len(listing.Bids)-2]; previous.UserID != userID {
+			notifyMarket(server, listing, "outbid", previous.UserID)
+		}
+	}
+}
+
+func HandleCancelListing(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to cancel listing")
+	if !loggedIn {
+		return
+	}
+	listingID, ok := request.Data["listing_id"].(string)
+	if !ok {
+		responder.SetError("Invalid parameters", "Failed to cancel listing", "from", request.From)
+		return
+	}
+
+	listing, err := state.MarketService.Cancel(listingID, userID)
+	if err != nil {
+		responder.SetError(marketErrorMessage(err), "Failed to cancel listing", "user_id", userID, "listing_id", listingID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Listing cancelled successfully", "listing": listingView(listing)}
+	responder.SetSuccess(data, "Listing cancelled successfully", "user_id", userID, "listing_id", listingID)
+}
+
+// SettleMarket fecha os anúncios que terminaram e avisa vendedores e vencedores dos leilões.
+func SettleMarket(server *api.Server) {
+	settled, err := state.MarketService.Settle()
+	if err != nil {
+		state.Logger.Error("Failed to settle some listings", "error", err)
+	}
+	for _, listing := range settled {
+		state.Logger.Info("Listing settled", "listing_id", listing.ID, "status", listing.Status, "buyer_id", listing.BuyerID)
+		notifyMarket(server, listing, listing.Status, listing.SellerID)
+		if listing.Status == domain.ListingSold {
+			notifyMarket(server, listing, "won", listing.BuyerID)
+		}
+	}
+}
+
+// notifyMarket envia a um usuário a mudança de um anúncio em que ele vende ou deu lances.
+func notifyMarket(server *api.Server, listing domain.Listing, event, userID string) {
+	notifyUser(server, userID, "market_update", utils.Dict{"event": event, "listing": listingView(listing)})
+}
+
+// listingView converte um anúncio nos dados enviados aos clientes.
+func listingView(listing domain.Listing) utils.Dict {
+	view := utils.Dict{
+		"id":          listing.ID,
+		"seller_id":   listing.SellerID,
+		"kind":        listing.Kind,
+		"cards":       listing.Cards,
+		"price":       listing.Price,
+		"status":      listing.Status,
+		"buyer_id":    listing.BuyerID,
+		"bids":        len(listing.Bids),
+		"minimum_bid": listing.MinimumBid(),
+		"created_at":  listing.CreatedAt.Format(time.RFC3339),
+		"ends_at":     listing.EndsAt.Format(time.RFC3339),
+	}
+	if bid, ok := listing.HighestBid(); ok {
+		view["highest_bid"] = bid.Amount
+		view["highest_bidder"] = bid.UserID
+	}
+	return view
+}
+
+// marketErrorMessage traduz erros do mercado na mensagem exibida ao cliente.
+func marketErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrListingNotFound),
+		errors.Is(err, application.ErrListingClosed),
+		errors.Is(err, application.ErrListingEnded),
+		errors.Is(err, application.ErrListingKind),
+		errors.Is(err, application.ErrListingCards),
+		errors.Is(err, application.ErrListingPrice),
+		errors.Is(err, application.ErrListingDuration),
+		errors.Is(err, application.ErrListingBot),
+		errors.Is(err, application.ErrListingCardOwner),
+		errors.Is(err, application.ErrOwnListing),
+		errors.Is(err, application.ErrNotFixedPrice),
+		errors.Is(err, application.ErrNotAuction),
+		errors.Is(err, application.ErrBidTooLow),
+		errors.Is(err, application.ErrNotListingSeller),
+		errors.Is(err, application.ErrListingHasBids),
+		errors.Is(err, application.ErrInsufficientCoins),
+		errors.Is(err, application.ErrBotWallet):
+		return err.Error()
+	default:
+		return "Market request failed"
+	}
+}
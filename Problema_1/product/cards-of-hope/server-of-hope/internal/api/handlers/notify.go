@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+)
+
+// notifyUser envia uma mensagem do servidor para a conexão de um usuário, se ele estiver conectado.
+// Mensagens para bots não passam pela rede: o bot reage a elas no próprio servidor.
+func notifyUser(server *api.Server, userID, method string, data utils.Dict) {
+	if domain.IsBotID(userID) {
+		go botReceive(server, userID, method, data)
+		return
+	}
+
+	address, ok := state.UserConnections.Get(userID)
+	if !ok {
+		state.Logger.Warn("Could not find connection for user to notify", "user_id", userID, "method", method)
+		return
+	}
+
+	server.Responses <- protocol.Response{
+		Method: method,
+		Status: "ok",
+		Data:   data,
+		To:     address,
+	}
+}
+
+// notifyUsers envia a mesma mensagem do servidor para cada um dos usuários informados.
+func notifyUsers(server *api.Server, userIDs []string, method string, data utils.Dict) {
+	for _, userID := range userIDs {
+		notifyUser(server, userID, method, data)
+	}
+}
+
+// notifyRoom envia a mesma mensagem do servidor para todos os jogadores e espectadores da sala.
+func notifyRoom(server *api.Server, room domain.Room, method string, data utils.Dict) {
+	notifyUsers(server, room.UserIDs.Items(), method, data)
+	notifyUsers(server, room.Spectators.Items(), method, data)
+}
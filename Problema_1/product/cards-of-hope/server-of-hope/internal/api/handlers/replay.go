@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"sort"
+	"time"
+)
+
+func HandleListReplays(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list replays")
+	if !loggedIn {
+		return
+	}
+
+	replays, err := state.ReplayService.ListReplays(userID)
+	if err != nil {
+		responder.SetError("Could not list replays", "Failed to list replays", "user_id", userID, "error", err)
+		return
+	}
+
+	summaries := make([]utils.Dict, 0, len(replays))
+	for _, replay := range replays {
+		summaries = append(summaries, replaySummary(replay))
+	}
+
+	data := utils.Dict{"replays": summaries}
+	responder.SetSuccess(data, "Replays listed successfully", "user_id", userID, "count", len(summaries))
+}
+
+func HandleGetReplay(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to get replay")
+	if !loggedIn {
+		return
+	}
+	replayID, replayIDOk := request.Data["replay_id"].(string)
+
+	if !replayIDOk {
+		responder.SetError("Invalid parameters", "Failed to get replay", "from", request.From)
+		return
+	}
+
+	replay, err := state.ReplayService.GetReplay(replayID, userID)
+	if err != nil {
+		responder.SetError("Replay not found", "Failed to get replay", "user_id", userID, "replay_id", replayID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"replay": replay, "rounds": replay.Rounds()}
+	responder.SetSuccess(data, "Replay sent successfully", "user_id", userID, "replay_id", replayID)
+}
+
+// replaySummary converte um registro de partida nos metadados da listagem, sem os eventos.
+func replaySummary(replay domain.Replay) utils.Dict {
+	return utils.Dict{
+		"id":           replay.ID,
+		"room_id":      replay.RoomID,
+		"room_name":    replay.RoomName,
+		"match_number": replay.Number,
+		"ruleset_id":   replay.RulesetID,
+		"players":      replay.Players,
+		"started_at":   replay.StartedAt.Format(time.RFC3339),
+		"finished_at":  replay.FinishedAt.Format(time.RFC3339),
+		"winner_id":    replay.WinnerID,
+		"abandoned":    replay.Abandoned,
+		"rounds":       replay.Rounds(),
+	}
+}
+
+// recordPlay registra a jogada do usuário no registro da partida e, se ela completou a rodada,
+// as cartas dos dois jogadores e o resultado.
+func recordPlay(gameID, userID string, result *domain.RoundResult) {
+	if result == nil {
+		game, err := state.GameService.GetGame(gameID)
+		if err != nil {
+			return
+		}
+		if card, played := game.Plays.Get(userID); played {
+			state.ReplayService.Record(gameID, domain.ReplayEvent{Type: domain.ReplayPlay, UserID: userID, Card: &card})
+		}
+		return
+	}
+
+	// A jogada do oponente já foi registrada, a menos que a rodada tenha sido resolvida antes disso
+	playerIDs := make([]string, 0, len(result.Plays))
+	for playerID := range result.Plays {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+	for _, playerID := range playerIDs {
+		card := result.Plays[playerID]
+		state.ReplayService.Record(gameID, domain.ReplayEvent{Type: domain.ReplayPlay, UserID: playerID, Card: &card})
+	}
+	state.ReplayService.Record(gameID, domain.ReplayEvent{
+		Type: domain.ReplayRoundResult,
+		Result: &domain.ReplayRound{
+			WinnerID:  result.WinnerID,
+			Plays:     result.Plays,
+			Effective: effectiveStars(result),
+			Effects:   roundEffects(result),
+			Scores:    result.Scores,
+		},
+	})
+}
+
+// recordRoomEvent registra no registro da partida as entradas e saídas de membros da sala.
+func recordRoomEvent(room domain.Room, event, userID string) {
+	switch event {
+	case domain.ReplayJoined, domain.ReplayLeft, domain.ReplayKicked:
+		state.ReplayService.Record(room.ID, domain.ReplayEvent{Type: event, UserID: userID})
+	}
+}
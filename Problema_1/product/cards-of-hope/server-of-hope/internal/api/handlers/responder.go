@@ -46,3 +46,13 @@ func (r *Responder) SetError(errorMessage string, logMessage string, logFields .
 	r.response.Data["message"] = errorMessage
 	state.Logger.Error(logMessage, logFields...)
 }
+
+// RequireLogin retorna o usuário autenticado na conexão que enviou a requisição.
+// Se a conexão ainda não fez login, a resposta passa a ser um erro e o retorno é false.
+func (r *Responder) RequireLogin(logMessage string) (string, bool) {
+	userID, loggedIn := sessionUser(r.server, r.request)
+	if !loggedIn {
+		r.SetError("You must be logged in", logMessage, "from", r.request.From)
+	}
+	return userID, loggedIn
+}
@@ -1,67 +1,144 @@
 package handlers
 
 import (
+	"errors"
 	"server-of-hope/internal/api"
 	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
 	"server-of-hope/internal/state"
 	"server-of-hope/internal/utils"
+	"sort"
+	"time"
 )
 
 func HandleCreateRoom(server *api.Server, request protocol.Request) {
 	responder := NewResponder(server, request)
 	defer responder.Send()
 
-	roomID, err := state.RoomService.CreateRoom()
+	userID, loggedIn := responder.RequireLogin("Failed to create room")
+	if !loggedIn {
+		return
+	}
+	name, _ := request.Data["name"].(string)
+	private, _ := request.Data["private"].(bool)
+	password, _ := request.Data["password"].(string)
+	ruleset, _ := request.Data["ruleset"].(string)
+	commitReveal, _ := request.Data["commit_reveal"].(bool)
+	async, _ := request.Data["async"].(bool)
+	turnHours, _ := request.Data["turn_hours"].(float64)
+	wagerCoins, _ := request.Data["wager_coins"].(float64)
+	wagerCards, _ := request.Data["wager_cards"].(float64)
+	bot, _ := request.Data["bot"].(string)
+
+	if _, ok := domain.NewBotStrategy(bot); bot != "" && !ok {
+		responder.SetError(application.ErrUnknownBotDifficulty.Error(), "Failed to create room", "from", request.From, "bot", bot)
+		return
+	}
+	if bot != "" && (wagerCoins > 0 || wagerCards > 0) {
+		// Recusa antes de criar a sala, para não deixar para trás uma sala sem o bot pedido
+		responder.SetError("Could not add bot: "+application.ErrWagerBot.Error(), "Failed to create room", "from", request.From, "bot", bot)
+		return
+	}
+
+	roomID, err := state.RoomService.CreateRoom(userID, application.RoomOptions{
+		Name:         name,
+		Private:      private,
+		Password:     password,
+		Ruleset:      ruleset,
+		CommitReveal: commitReveal,
+		Async:        async,
+		TurnHours:    int(turnHours),
+		WagerCoins:   int(wagerCoins),
+		WagerCards:   int(wagerCards),
+	})
+	if err != nil {
+		responder.SetError("Could not create room: "+err.Error(), "Failed to create room", "from", request.From, "error", err)
+		return
+	}
+
+	if bot != "" {
+		if _, err := state.BotService.AddBot(roomID, userID, bot); err != nil {
+			responder.SetError("Could not add bot: "+err.Error(), "Failed to add bot", "from", request.From, "room_id", roomID, "bot", bot, "error", err)
+			return
+		}
+	}
+
+	room, err := state.RoomService.GetRoom(roomID)
 	if err != nil {
-		responder.SetError("Could not create room", "Failed to create room", "from", request.From, "error", err)
+		responder.SetError("Could not create room", "Failed to read created room", "from", request.From, "room_id", roomID, "error", err)
 		return
 	}
 
 	data := utils.Dict{
 		"message": "Room created successfully",
 		"room_id": roomID,
+		"room":    roomSummary(room),
+	}
+	if ruleset, err := state.RulesetService.GetRuleset(room.RulesetID); err == nil {
+		data["ruleset"] = ruleset
 	}
-	responder.SetSuccess(data, "Room created successfully", "from", request.From, "room_id", roomID)
+	responder.SetSuccess(data, "Room created successfully", "from", request.From, "room_id", roomID, "name", room.Name, "ruleset", room.RulesetID, "bot", bot)
 }
 
 func HandleJoinRoom(server *api.Server, request protocol.Request) {
 	responder := NewResponder(server, request)
 	defer responder.Send()
 
-	roomID, roomIDOk := request.Data["room_id"].(string)
-	userID, userIDOk := request.Data["user_id"].(string)
+	userID, loggedIn := responder.RequireLogin("Failed to join room")
+	if !loggedIn {
+		return
+	}
+	roomID, _ := request.Data["room_id"].(string)
+	inviteCode, _ := request.Data["invite_code"].(string)
+	password, _ := request.Data["password"].(string)
+	spectator, _ := request.Data["spectator"].(bool)
 
-	if !roomIDOk || !userIDOk {
+	if roomID == "" && inviteCode == "" {
 		responder.SetError("Invalid parameters", "Failed to join room", "from", request.From)
 		return
 	}
 
-	err := state.RoomService.JoinRoom(roomID, userID)
+	roomID, err := state.RoomService.JoinRoom(roomID, userID, application.JoinOptions{
+		Password:   password,
+		InviteCode: inviteCode,
+		Spectator:  spectator,
+	})
 	if err != nil {
-		if err.Error() == "A sala está cheia" {
-			responder.SetError("A sala está cheia", "Failed to join room", "from", request.From, "room_id", roomID, "error", err)
-		} else {
-			responder.SetError("Room does not exist", "Failed to join room", "from", request.From, "room_id", roomID, "error", err)
-		}
+		responder.SetError(roomErrorMessage(err), "Failed to join room", "from", request.From, "room_id", roomID, "error", err)
 		return
 	}
 
-	data := utils.Dict{"message": "Joined room successfully"}
-	responder.SetSuccess(data, "Joined room successfully", "from", request.From, "room_id", roomID)
+	data := utils.Dict{"message": "Joined room successfully", "room_id": roomID, "spectator": spectator}
+	if room, err := state.RoomService.GetRoom(roomID); err == nil {
+		data["room"] = roomSummary(room)
+		if ruleset, err := state.RulesetService.GetRuleset(room.RulesetID); err == nil {
+			data["ruleset"] = ruleset
+		}
+		if room.Status == domain.RoomStatusPlaying && room.UserIDs.Contains(userID) {
+			addSeatState(data, room.ID, userID)
+		}
+		notifyRoomEvent(server, room, "joined", userID)
+	}
+	responder.SetSuccess(data, "Joined room successfully", "from", request.From, "room_id", roomID, "spectator", spectator)
 }
 
 func HandleLeaveRoom(server *api.Server, request protocol.Request) {
 	responder := NewResponder(server, request)
 	defer responder.Send()
 
+	userID, loggedIn := responder.RequireLogin("Failed to leave room")
+	if !loggedIn {
+		return
+	}
 	roomID, roomIDOk := request.Data["room_id"].(string)
-	userID, userIDOk := request.Data["user_id"].(string)
 
-	if !roomIDOk || !userIDOk {
+	if !roomIDOk {
 		responder.SetError("Invalid parameters", "Failed to leave room", "from", request.From)
 		return
 	}
 
+	forfeitWager(server, roomID, userID)
 	err := state.RoomService.LeaveRoom(roomID, userID)
 	if err != nil {
 		responder.SetError("Room does not exist or user not in room", "Failed to leave room", "from", request.From, "room_id", roomID, "error", err)
@@ -73,4 +150,297 @@ func HandleLeaveRoom(server *api.Server, request protocol.Request) {
 		"room_id": roomID,
 	}
 	responder.SetSuccess(data, "Left room successfully", "from", request.From, "room_id", roomID)
+
+	if room, err := state.RoomService.GetRoom(roomID); err == nil {
+		notifyRoomEvent(server, room, "left", userID)
+		forfeitTournamentMatch(server, room, userID)
+	}
+}
+
+func HandleKick(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to kick user")
+	if !loggedIn {
+		return
+	}
+	roomID, roomIDOk := request.Data["room_id"].(string)
+	targetID, targetIDOk := request.Data["target_id"].(string)
+
+	if !roomIDOk || !targetIDOk {
+		responder.SetError("Invalid parameters", "Failed to kick user", "from", request.From)
+		return
+	}
+
+	err := state.RoomService.Kick(roomID, userID, targetID)
+	if err != nil {
+		responder.SetError(roomErrorMessage(err), "Failed to kick user", "from", request.From, "room_id", roomID, "target_id", targetID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "User kicked successfully", "room_id": roomID, "target_id": targetID}
+	responder.SetSuccess(data, "User kicked successfully", "from", request.From, "room_id", roomID, "target_id", targetID)
+
+	if room, err := state.RoomService.GetRoom(roomID); err == nil {
+		recordRoomEvent(room, "kicked", targetID)
+		event := roomEvent(room, "kicked", targetID)
+		notifyRoom(server, room, "room_event", event)
+		notifyUser(server, targetID, "room_event", event)
+	}
+}
+
+func HandleLockRoom(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to lock room")
+	if !loggedIn {
+		return
+	}
+	roomID, roomIDOk := request.Data["room_id"].(string)
+	locked, lockedOk := request.Data["locked"].(bool)
+
+	if !roomIDOk {
+		responder.SetError("Invalid parameters", "Failed to lock room", "from", request.From)
+		return
+	}
+	if !lockedOk {
+		locked = true
+	}
+
+	err := state.RoomService.SetLocked(roomID, userID, locked)
+	if err != nil {
+		responder.SetError(roomErrorMessage(err), "Failed to lock room", "from", request.From, "room_id", roomID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Room lock updated successfully", "room_id": roomID, "locked": locked}
+	responder.SetSuccess(data, "Room lock updated successfully", "from", request.From, "room_id", roomID, "locked", locked)
+
+	if room, err := state.RoomService.GetRoom(roomID); err == nil {
+		event := "unlocked"
+		if locked {
+			event = "locked"
+		}
+		notifyRoomEvent(server, room, event, userID)
+	}
+}
+
+func HandleTransferHost(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to transfer host")
+	if !loggedIn {
+		return
+	}
+	roomID, roomIDOk := request.Data["room_id"].(string)
+	targetID, targetIDOk := request.Data["target_id"].(string)
+
+	if !roomIDOk || !targetIDOk {
+		responder.SetError("Invalid parameters", "Failed to transfer host", "from", request.From)
+		return
+	}
+
+	err := state.RoomService.TransferHost(roomID, userID, targetID)
+	if err != nil {
+		responder.SetError(roomErrorMessage(err), "Failed to transfer host", "from", request.From, "room_id", roomID, "target_id", targetID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Host transferred successfully", "room_id": roomID, "host_id": targetID}
+	responder.SetSuccess(data, "Host transferred successfully", "from", request.From, "room_id", roomID, "host_id", targetID)
+
+	if room, err := state.RoomService.GetRoom(roomID); err == nil {
+		notifyRoomEvent(server, room, "host_changed", targetID)
+	}
+}
+
+// HandleRoomDisconnect passa adiante o papel de anfitrião das salas de um usuário que se desconectou.
+func HandleRoomDisconnect(server *api.Server, userID string) {
+	rooms, err := state.RoomService.ReleaseHost(userID, func(memberID string) bool {
+		_, connected := state.UserConnections.Get(memberID)
+		return connected
+	})
+	if err != nil {
+		state.Logger.Error("Failed to release host after disconnect", "user_id", userID, "error", err)
+	}
+	for _, room := range rooms {
+		state.Logger.Info("Host moved after disconnect", "room_id", room.ID, "from", userID, "to", room.HostID)
+		notifyRoomEvent(server, room, "host_changed", room.HostID)
+	}
+}
+
+func HandleCreateInvite(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to create invite")
+	if !loggedIn {
+		return
+	}
+	roomID, roomIDOk := request.Data["room_id"].(string)
+	minutes, minutesOk := request.Data["minutes"].(float64)
+	singleUse, singleUseOk := request.Data["single_use"].(bool)
+
+	if !roomIDOk {
+		responder.SetError("Invalid parameters", "Failed to create invite", "from", request.From)
+		return
+	}
+	if !minutesOk {
+		minutes = 30
+	}
+	if !singleUseOk {
+		singleUse = true
+	}
+
+	invite, err := state.RoomService.CreateInvite(roomID, userID, time.Duration(minutes)*time.Minute, singleUse)
+	if err != nil {
+		responder.SetError(roomErrorMessage(err), "Failed to create invite", "from", request.From, "room_id", roomID, "error", err)
+		return
+	}
+
+	data := utils.Dict{
+		"message":     "Invite created successfully",
+		"room_id":     roomID,
+		"invite_code": invite.Code,
+		"expires_at":  invite.ExpiresAt.Format(time.RFC3339),
+		"single_use":  invite.SingleUse,
+	}
+	responder.SetSuccess(data, "Invite created successfully", "from", request.From, "room_id", roomID, "single_use", invite.SingleUse)
+}
+
+func HandleListRooms(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	name, _ := request.Data["name"].(string)
+	status, _ := request.Data["status"].(string)
+	includeFull, _ := request.Data["all"].(bool)
+	onlyPublic, _ := request.Data["public"].(bool)
+
+	if status != "" && !domain.ValidRoomStatus(status) {
+		responder.SetError("Invalid status filter", "Failed to list rooms", "from", request.From, "status", status)
+		return
+	}
+
+	rooms, err := state.RoomService.ListRooms(application.RoomFilter{
+		Name:        name,
+		Status:      status,
+		IncludeFull: includeFull || (status != "" && status != domain.RoomStatusWaiting),
+		OnlyPublic:  onlyPublic,
+	})
+	if err != nil {
+		responder.SetError("Could not list rooms", "Failed to list rooms", "from", request.From, "error", err)
+		return
+	}
+
+	summaries := make([]utils.Dict, 0, len(rooms))
+	for _, room := range rooms {
+		summaries = append(summaries, roomSummary(room))
+	}
+
+	data := utils.Dict{"rooms": summaries}
+	responder.SetSuccess(data, "Rooms listed successfully", "from", request.From, "count", len(summaries))
+}
+
+// notifyRoomEvent avisa todos os membros da sala sobre uma mudança de membros ou de moderação.
+func notifyRoomEvent(server *api.Server, room domain.Room, event, userID string) {
+	recordRoomEvent(room, event, userID)
+	notifyRoom(server, room, "room_event", roomEvent(room, event, userID))
+}
+
+// roomEvent monta a mensagem room_event com o evento, o usuário envolvido e o estado atual da sala.
+func roomEvent(room domain.Room, event, userID string) utils.Dict {
+	return utils.Dict{
+		"room_id": room.ID,
+		"event":   event,
+		"user_id": userID,
+		"room":    roomSummary(room),
+	}
+}
+
+// roomSummary converte uma sala nos metadados enviados aos clientes.
+func roomSummary(room domain.Room) utils.Dict {
+	players := room.UserIDs.Items()
+	sort.Strings(players)
+	spectators := room.Spectators.Items()
+	sort.Strings(spectators)
+	ready := room.Ready.Items()
+	sort.Strings(ready)
+	rematch := room.RematchRequests.Items()
+	sort.Strings(rematch)
+	bots := []string{}
+	for _, playerID := range players {
+		if domain.IsBotID(playerID) {
+			bots = append(bots, playerID)
+		}
+	}
+	return utils.Dict{
+		"room_id":       room.ID,
+		"name":          room.Name,
+		"owner_id":      room.OwnerID,
+		"host_id":       room.HostID,
+		"locked":        room.Locked,
+		"players":       players,
+		"bots":          bots,
+		"spectators":    spectators,
+		"ready":         ready,
+		"rematch":       rematch,
+		"capacity":      room.Capacity,
+		"status":        room.Status,
+		"created_at":    room.CreatedAt.Format(time.RFC3339),
+		"private":       room.Private,
+		"async":         room.Async,
+		"turn_hours":    room.TurnHours,
+		"wager_coins":   room.WagerCoins,
+		"wager_cards":   room.WagerCards,
+		"has_password":  room.PasswordHash != "",
+		"ruleset_id":    room.RulesetID,
+		"commit_reveal": room.CommitReveal,
+		"tournament_id": room.TournamentID,
+	}
+}
+
+// addSeatState acrescenta à resposta a mão e a jogada da rodada do jogador que volta a uma partida em andamento.
+func addSeatState(data utils.Dict, roomID, userID string) {
+	game, err := state.GameService.GetGame(roomID)
+	if err != nil {
+		return
+	}
+	hand, _ := game.Hands.Get(userID)
+	pile, _ := game.DrawPiles.Get(userID)
+	data["hand"] = handCards(hand)
+	data["draw_pile"] = len(pile)
+	if card, played := game.Plays.Get(userID); played {
+		data["played_card"] = card
+	}
+	if !game.TurnDeadline.IsZero() {
+		data["turn_deadline"] = game.TurnDeadline.Format(time.RFC3339)
+	}
+}
+
+// roomErrorMessage traduz erros do serviço de salas na mensagem exibida ao cliente.
+func roomErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrRoomFull),
+		errors.Is(err, application.ErrRoomPrivate),
+		errors.Is(err, application.ErrWrongPassword),
+		errors.Is(err, application.ErrInvalidInvite),
+		errors.Is(err, application.ErrNotRoomHost),
+		errors.Is(err, application.ErrRoomPublic),
+		errors.Is(err, application.ErrInviteTTL),
+		errors.Is(err, application.ErrAlreadyInRoom),
+		errors.Is(err, application.ErrRoomLocked),
+		errors.Is(err, application.ErrBanned),
+		errors.Is(err, application.ErrNotInRoom),
+		errors.Is(err, application.ErrSelfTarget),
+		errors.Is(err, application.ErrTournamentRoom),
+		errors.Is(err, application.ErrWagerKick),
+		errors.Is(err, application.ErrUnknownRuleset):
+		return err.Error()
+	default:
+		return "Room does not exist"
+	}
 }
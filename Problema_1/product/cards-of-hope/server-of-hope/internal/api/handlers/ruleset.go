@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+)
+
+func HandleListRulesets(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	rulesets := state.RulesetService.ListRulesets()
+	data := utils.Dict{"rulesets": rulesets}
+	responder.SetSuccess(data, "Rulesets listed successfully", "from", request.From, "count", len(rulesets))
+}
@@ -1,39 +1,329 @@
 package handlers
 
 import (
-	"math/rand"
+	"errors"
+	"fmt"
+	"math"
 	"server-of-hope/internal/api"
 	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
 	"server-of-hope/internal/domain"
 	"server-of-hope/internal/state"
 	"server-of-hope/internal/utils"
+	"strconv"
+	"time"
 )
 
 func HandleBuyPackage(server *api.Server, request protocol.Request) {
 	responder := NewResponder(server, request)
 	defer responder.Send()
 
-	r, p, s := rand.Intn(5)+1, rand.Intn(5)+1, rand.Intn(5)+1
-	pack := domain.CardPackage{
-		domain.Card{Type: "rock", Stars: r},
-		domain.Card{Type: "paper", Stars: p},
-		domain.Card{Type: "scissors", Stars: s},
+	userID, loggedIn := responder.RequireLogin("Buy package failed")
+	if !loggedIn {
+		return
+	}
+	packID, _ := request.Data["pack"].(string)
+	if packID == "" {
+		packID = domain.DefaultPackID
+	}
+
+	packType, err := state.StoreService.PackType(packID)
+	if err != nil {
+		responder.SetError(fmt.Sprintf("Unknown package type: %s", packID), "Buy package failed", "user_id", userID, "pack", packID, "error", err)
+		return
+	}
+
+	entry, err := state.WalletService.Debit(userID, packType.Price, domain.LedgerPurchase, packType.ID)
+	if errors.Is(err, application.ErrInsufficientCoins) {
+		responder.SetError(fmt.Sprintf("Not enough coins: a %s package costs %d. Win rounds and matches to earn more.", packType.ID, packType.Price), "Buy package failed", "user_id", userID, "error", err)
+		return
+	}
+	if err != nil {
+		responder.SetError("Could not charge the package", "Buy package failed", "user_id", userID, "error", err)
+		return
+	}
+
+	// O pacote vai direto para a coleção, sem passar pelo estoque, para que o usuário receba
+	// exatamente as cartas do sorteio registrado e possa verificá-lo depois
+	opening, err := state.StoreService.OpenPackage(userID, packType.ID)
+	if err != nil {
+		refundPackage(userID, packType)
+		responder.SetError("Could not open the package", "Buy package failed", "user_id", userID, "pack", packType.ID, "error", err)
+		return
+	}
+	pack := opening.Cards
+
+	cards, err := state.DeckService.AddCards(userID, pack[:]...)
+	if err != nil {
+		if cancelErr := state.StoreService.CancelOpening(opening); cancelErr != nil {
+			state.Logger.Error("Failed to cancel package opening", "user_id", userID, "opening_id", opening.ID, "error", cancelErr)
+		}
+		refundPackage(userID, packType)
+		responder.SetError(err.Error(), "Buy package failed", "user_id", userID, "error", err)
+		return
+	}
+
+	abilities := utils.Dict{}
+	for _, card := range pack {
+		if card.Ability != "" {
+			abilities[card.Type] = card.Ability
+		}
+	}
+	progress, _ := state.StoreService.PityProgress(userID)
+	data := utils.Dict{
+		"pack":      packType.ID,
+		"abilities": abilities,
+		"cards":     cards,
+		"price":     packType.Price,
+		"balance":   entry.Balance,
+		"pity":      pityView(packType, progress[packType.ID]),
+		"opening":   openingView(opening),
+	}
+	responder.SetSuccess(data, "Package bought successfully", "user_id", userID, "pack", packType.ID, "count", len(cards))
+}
+
+func HandleStoreCatalog(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, _ := sessionUser(server, request)
+
+	packs, err := state.StoreService.Catalog()
+	if err != nil {
+		responder.SetError("Could not list the store catalog", "Failed to list store catalog", "from", request.From, "error", err)
+		return
+	}
+	progress := map[string]int{}
+	if userID != "" {
+		if progress, err = state.StoreService.PityProgress(userID); err != nil {
+			responder.SetError("Could not list the store catalog", "Failed to list store catalog", "user_id", userID, "error", err)
+			return
+		}
+	}
+
+	views := make([]utils.Dict, 0, len(packs))
+	for _, pack := range packs {
+		views = append(views, packView(pack, progress[pack.ID]))
+	}
+
+	data := utils.Dict{"packs": views}
+	responder.SetSuccess(data, "Store catalog sent successfully", "from", request.From, "count", len(views))
+}
+
+func HandlePackSeed(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to get pack seed")
+	if !loggedIn {
+		return
+	}
+	clientSeed, _ := request.Data["client_seed"].(string)
+
+	var seed domain.PackSeed
+	var err error
+	if clientSeed == "" {
+		seed, err = state.StoreService.Seed(userID)
+	} else {
+		seed, err = state.StoreService.SetClientSeed(userID, clientSeed)
+	}
+	if err != nil {
+		responder.SetError(seedErrorMessage(err), "Failed to get pack seed", "user_id", userID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"seed": seedView(seed)}
+	responder.SetSuccess(data, "Pack seed sent successfully", "user_id", userID, "seed_id", seed.ID)
+}
+
+func HandleRotatePackSeed(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to rotate pack seed")
+	if !loggedIn {
+		return
+	}
+	clientSeed, _ := request.Data["client_seed"].(string)
+
+	revealed, seed, err := state.StoreService.RotateSeed(userID, clientSeed)
+	if err != nil {
+		responder.SetError(seedErrorMessage(err), "Failed to rotate pack seed", "user_id", userID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"revealed": seedView(revealed), "seed": seedView(seed)}
+	responder.SetSuccess(data, "Pack seed rotated successfully", "user_id", userID, "revealed_id", revealed.ID, "seed_id", seed.ID)
+}
+
+func HandlePackHistory(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list opened packs")
+	if !loggedIn {
+		return
 	}
-	state.StoreService.AddPackage(pack)
 
-	pack = state.StoreService.GetPackage()
-	if len(pack) < 3 {
-		responder.SetError("Package is empty", "Buy package failed", "from", request.From)
+	openings, err := state.StoreService.Openings(userID)
+	if err != nil {
+		responder.SetError("Could not list your packages", "Failed to list opened packs", "user_id", userID, "error", err)
 		return
 	}
+	if len(openings) > application.PackHistorySize {
+		openings = openings[:application.PackHistorySize]
+	}
+
+	views := make([]utils.Dict, 0, len(openings))
+	for _, opening := range openings {
+		views = append(views, openingView(opening))
+	}
+
+	data := utils.Dict{"openings": views}
+	responder.SetSuccess(data, "Opened packs listed successfully", "user_id", userID, "count", len(views))
+}
 
-	rock, paper, scissors := pack[0], pack[1], pack[2]
+func HandleVerifyPack(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to verify pack")
+	if !loggedIn {
+		return
+	}
+	openingID, openingIDOk := request.Data["opening_id"].(string)
+	if !openingIDOk {
+		responder.SetError("Invalid parameters", "Failed to verify pack", "from", request.From)
+		return
+	}
+
+	opening, seed, cards, err := state.StoreService.VerifyOpening(userID, openingID)
+	if err != nil {
+		responder.SetError(seedErrorMessage(err), "Failed to verify pack", "user_id", userID, "opening_id", openingID, "error", err)
+		return
+	}
+
+	verified := cards == opening.Cards
 	data := utils.Dict{
-		"package": utils.Dict{
-			"rock":     rock.Stars,
-			"paper":    paper.Stars,
-			"scissors": scissors.Stars,
-		},
+		"opening":  openingView(opening),
+		"seed":     seedView(seed),
+		"cards":    cards,
+		"verified": verified,
+	}
+	responder.SetSuccess(data, "Pack verified successfully", "user_id", userID, "opening_id", openingID, "verified", verified)
+}
+
+// refundPackage devolve o preço de um pacote que não chegou à coleção do usuário.
+func refundPackage(userID string, packType domain.PackType) {
+	if _, err := state.WalletService.Credit(userID, packType.Price, domain.LedgerRefund, packType.ID); err != nil {
+		state.Logger.Error("Failed to refund package", "user_id", userID, "pack", packType.ID, "error", err)
+	}
+}
+
+// packView converte um tipo de pacote nos dados do catálogo, com as chances em porcentagem.
+func packView(pack domain.PackType, opened int) utils.Dict {
+	typeTotal := 0
+	for _, weight := range pack.Types {
+		typeTotal += weight.Weight
+	}
+	types := utils.Dict{}
+	for _, weight := range pack.Types {
+		types[weight.Type] = percent(weight.Weight, typeTotal)
+	}
+
+	rarityTotal := 0
+	for _, tier := range pack.Rarities {
+		rarityTotal += tier.Weight
+	}
+	rarities := make([]utils.Dict, 0, len(pack.Rarities))
+	for _, tier := range pack.Rarities {
+		starTotal := 0
+		for _, weight := range tier.Stars {
+			starTotal += weight.Weight
+		}
+		stars := utils.Dict{}
+		for _, weight := range tier.Stars {
+			stars[strconv.Itoa(weight.Stars)] = percent(weight.Weight, starTotal)
+		}
+		rarities = append(rarities, utils.Dict{
+			"rarity":         tier.Rarity,
+			"chance":         percent(tier.Weight, rarityTotal),
+			"stars":          stars,
+			"ability_chance": tier.AbilityChance,
+		})
+	}
+
+	return utils.Dict{
+		"id":          pack.ID,
+		"name":        pack.Name,
+		"description": pack.Description,
+		"price":       pack.Price,
+		"cards":       len(domain.CardPackage{}),
+		"types":       types,
+		"rarities":    rarities,
+		"pity":        pityView(pack, opened),
+	}
+}
+
+// pityView descreve a garantia de raridade de um pacote e o andamento do usuário nela.
+func pityView(pack domain.PackType, opened int) utils.Dict {
+	if pack.Pity.Packs == 0 {
+		return nil
+	}
+	return utils.Dict{"rarity": pack.Pity.Rarity, "packs": pack.Pity.Packs, "opened": opened}
+}
+
+// percent calcula a chance de um peso em porcentagem, com duas casas decimais.
+func percent(weight, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(weight)*10000/float64(total)) / 100
+}
+
+// seedView converte uma semente nos dados enviados aos clientes; a semente do servidor só é
+// enviada depois de revelada.
+func seedView(seed domain.PackSeed) utils.Dict {
+	view := utils.Dict{
+		"id":          seed.ID,
+		"hash":        seed.Hash,
+		"client_seed": seed.ClientSeed,
+		"nonce":       seed.Nonce,
+		"created_at":  seed.CreatedAt.Format(time.RFC3339),
+	}
+	if seed.Revealed() {
+		view["server_seed"] = seed.ServerSeed
+		view["revealed_at"] = seed.RevealedAt.Format(time.RFC3339)
+	}
+	return view
+}
+
+// openingView converte um pacote aberto nos dados enviados aos clientes.
+func openingView(opening domain.PackOpening) utils.Dict {
+	return utils.Dict{
+		"id":          opening.ID,
+		"pack":        opening.PackID,
+		"seed_id":     opening.SeedID,
+		"client_seed": opening.ClientSeed,
+		"nonce":       opening.Nonce,
+		"guarantee":   opening.Guarantee,
+		"pity_opened": opening.PityOpened,
+		"table_hash":  opening.TableHash,
+		"cards":       opening.Cards,
+		"created_at":  opening.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// seedErrorMessage traduz erros do sorteio verificável na mensagem exibida ao cliente.
+func seedErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrInvalidClientSeed),
+		errors.Is(err, application.ErrOpeningNotFound),
+		errors.Is(err, application.ErrSeedNotRevealed),
+		errors.Is(err, application.ErrPackTablesChanged),
+		errors.Is(err, application.ErrPackNotFound):
+		return err.Error()
+	default:
+		return "Pack seed request failed"
 	}
-	responder.SetSuccess(data, "Package bought successfully", "from", request.From)
 }
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"errors"
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+func HandleCreateTournament(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to create tournament")
+	if !loggedIn {
+		return
+	}
+	name, _ := request.Data["name"].(string)
+	format, _ := request.Data["format"].(string)
+	size, sizeOk := request.Data["size"].(float64)
+	ruleset, _ := request.Data["ruleset"].(string)
+
+	if !sizeOk {
+		responder.SetError("Invalid parameters", "Failed to create tournament", "from", request.From)
+		return
+	}
+
+	tournament, err := state.TournamentService.CreateTournament(userID, application.TournamentOptions{
+		Name:    name,
+		Format:  format,
+		Size:    int(size),
+		Ruleset: ruleset,
+	})
+	if err != nil {
+		responder.SetError("Could not create tournament: "+err.Error(), "Failed to create tournament", "user_id", userID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Tournament created successfully", "tournament": tournamentView(tournament)}
+	responder.SetSuccess(data, "Tournament created successfully", "user_id", userID, "tournament_id", tournament.ID, "format", tournament.Format, "size", tournament.Size)
+}
+
+func HandleListTournaments(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	tournaments, err := state.TournamentService.ListTournaments()
+	if err != nil {
+		responder.SetError("Could not list tournaments", "Failed to list tournaments", "from", request.From, "error", err)
+		return
+	}
+
+	summaries := make([]utils.Dict, 0, len(tournaments))
+	for _, tournament := range tournaments {
+		summaries = append(summaries, tournamentSummary(tournament))
+	}
+
+	data := utils.Dict{"tournaments": summaries}
+	responder.SetSuccess(data, "Tournaments listed successfully", "from", request.From, "count", len(summaries))
+}
+
+func HandleGetTournament(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	tournamentID, tournamentIDOk := request.Data["tournament_id"].(string)
+
+	if !tournamentIDOk {
+		responder.SetError("Invalid parameters", "Failed to get tournament", "from", request.From)
+		return
+	}
+
+	tournament, err := state.TournamentService.GetTournament(tournamentID)
+	if err != nil {
+		responder.SetError(tournamentErrorMessage(err), "Failed to get tournament", "tournament_id", tournamentID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"tournament": tournamentView(tournament)}
+	responder.SetSuccess(data, "Tournament sent successfully", "tournament_id", tournamentID)
+}
+
+func HandleJoinTournament(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to join tournament")
+	if !loggedIn {
+		return
+	}
+	tournamentID, tournamentIDOk := request.Data["tournament_id"].(string)
+
+	if !tournamentIDOk {
+		responder.SetError("Invalid parameters", "Failed to join tournament", "from", request.From)
+		return
+	}
+
+	tournament, opened, err := state.TournamentService.Register(tournamentID, userID)
+	if err != nil && !errors.Is(err, application.ErrMatchRoomUnavailable) {
+		responder.SetError(tournamentErrorMessage(err), "Failed to join tournament", "user_id", userID, "tournament_id", tournamentID, "error", err)
+		return
+	}
+
+	if err != nil {
+		// A inscrição foi gravada; só uma das salas ficou para o próximo avanço do torneio
+		responder.SetError(tournamentErrorMessage(err), "Failed to open tournament match room", "user_id", userID, "tournament_id", tournamentID, "error", err)
+	} else {
+		data := utils.Dict{"message": "Joined tournament successfully", "tournament": tournamentView(tournament)}
+		responder.SetSuccess(data, "Joined tournament successfully", "user_id", userID, "tournament_id", tournamentID, "started", len(opened) > 0)
+	}
+
+	notifyTournament(server, tournament, "registered", userID, "")
+	if tournament.Status != domain.TournamentStatusRegistering {
+		notifyTournament(server, tournament, "started", "", "")
+	}
+	notifyTournamentMatches(server, tournament, opened)
+}
+
+func HandleLeaveTournament(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to leave tournament")
+	if !loggedIn {
+		return
+	}
+	tournamentID, tournamentIDOk := request.Data["tournament_id"].(string)
+
+	if !tournamentIDOk {
+		responder.SetError("Invalid parameters", "Failed to leave tournament", "from", request.From)
+		return
+	}
+
+	tournament, err := state.TournamentService.Unregister(tournamentID, userID)
+	if err != nil {
+		responder.SetError(tournamentErrorMessage(err), "Failed to leave tournament", "user_id", userID, "tournament_id", tournamentID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Left tournament successfully", "tournament": tournamentView(tournament)}
+	responder.SetSuccess(data, "Left tournament successfully", "user_id", userID, "tournament_id", tournamentID)
+
+	notifyTournament(server, tournament, "unregistered", userID, "")
+}
+
+func HandleStartTournament(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to start tournament")
+	if !loggedIn {
+		return
+	}
+	tournamentID, tournamentIDOk := request.Data["tournament_id"].(string)
+
+	if !tournamentIDOk {
+		responder.SetError("Invalid parameters", "Failed to start tournament", "from", request.From)
+		return
+	}
+
+	tournament, opened, err := state.TournamentService.Start(tournamentID, userID)
+	if err != nil && !errors.Is(err, application.ErrMatchRoomUnavailable) {
+		responder.SetError(tournamentErrorMessage(err), "Failed to start tournament", "user_id", userID, "tournament_id", tournamentID, "error", err)
+		return
+	}
+
+	if err != nil {
+		// O torneio começou; só uma das salas ficou para o próximo avanço
+		responder.SetError(tournamentErrorMessage(err), "Failed to open tournament match room", "user_id", userID, "tournament_id", tournamentID, "error", err)
+	} else {
+		data := utils.Dict{"message": "Tournament started successfully", "tournament": tournamentView(tournament)}
+		responder.SetSuccess(data, "Tournament started successfully", "user_id", userID, "tournament_id", tournamentID, "matches", len(opened))
+	}
+
+	notifyTournament(server, tournament, "started", "", "")
+	notifyTournamentMatches(server, tournament, opened)
+}
+
+// recordTournamentResult leva o resultado da partida encerrada em uma sala de torneio à chave.
+func recordTournamentResult(server *api.Server, room domain.Room, winnerID string) {
+	if room.TournamentID == "" {
+		return
+	}
+	tournament, opened, err := state.TournamentService.RecordResult(room.TournamentID, room.ID, winnerID)
+	if errors.Is(err, application.ErrMatchRoomUnavailable) {
+		state.Logger.Error("Failed to open tournament match room", "tournament_id", room.TournamentID, "room_id", room.ID, "error", err)
+	} else if err != nil {
+		state.Logger.Debug("Match result ignored by tournament", "tournament_id", room.TournamentID, "room_id", room.ID, "error", err)
+		return
+	}
+	index, _ := tournament.MatchIndex(room.ID)
+	event := "match_finished"
+	if !tournament.Matches[index].Decided() {
+		event = "match_drawn" // Na eliminação simples, o empate é desfeito com outra partida na sala
+	}
+	announceTournamentProgress(server, tournament, event, tournament.Matches[index], opened)
+}
+
+// forfeitTournamentMatch dá a vitória ao oponente de quem saiu da sala de uma partida de torneio.
+func forfeitTournamentMatch(server *api.Server, room domain.Room, userID string) {
+	if room.TournamentID == "" {
+		return
+	}
+	tournament, opened, err := state.TournamentService.Forfeit(room.TournamentID, room.ID, userID)
+	if errors.Is(err, application.ErrMatchRoomUnavailable) {
+		state.Logger.Error("Failed to open tournament match room", "tournament_id", room.TournamentID, "room_id", room.ID, "error", err)
+	} else if err != nil {
+		state.Logger.Debug("Room exit ignored by tournament", "tournament_id", room.TournamentID, "room_id", room.ID, "user_id", userID, "error", err)
+		return
+	}
+	index, _ := tournament.MatchIndex(room.ID)
+	announceTournamentProgress(server, tournament, "walkover", tournament.Matches[index], opened)
+}
+
+// announceTournamentProgress avisa os participantes do resultado de uma partida, das partidas
+// abertas e do fim do torneio.
+func announceTournamentProgress(server *api.Server, tournament domain.Tournament, event string, match domain.TournamentMatch, opened []domain.TournamentMatch) {
+	notifyTournament(server, tournament, event, match.WinnerID, match.ID)
+	notifyTournamentMatches(server, tournament, opened)
+	if tournament.Status == domain.TournamentStatusFinished {
+		state.Logger.Info("Tournament finished", "tournament_id", tournament.ID, "winner_id", tournament.WinnerID)
+		notifyTournament(server, tournament, "finished", tournament.WinnerID, "")
+	}
+}
+
+// notifyTournament envia a chave atualizada ao criador e aos inscritos no torneio, com o evento,
+// o usuário e a partida envolvidos.
+func notifyTournament(server *api.Server, tournament domain.Tournament, event, userID, matchID string) {
+	recipients := append([]string(nil), tournament.Players...)
+	if !tournament.IsRegistered(tournament.OwnerID) {
+		recipients = append(recipients, tournament.OwnerID)
+	}
+	if userID != "" && !tournament.IsRegistered(userID) && userID != tournament.OwnerID {
+		recipients = append(recipients, userID) // Quem acabou de cancelar a inscrição também é avisado
+	}
+	notifyUsers(server, recipients, "tournament_update", utils.Dict{
+		"tournament_id": tournament.ID,
+		"event":         event,
+		"user_id":       userID,
+		"match_id":      matchID,
+		"tournament":    tournamentView(tournament),
+	})
+}
+
+// notifyTournamentMatches avisa os dois jogadores de cada partida aberta em que sala jogar.
+func notifyTournamentMatches(server *api.Server, tournament domain.Tournament, matches []domain.TournamentMatch) {
+	for _, match := range matches {
+		room, err := state.RoomService.GetRoom(match.RoomID)
+		if err != nil {
+			state.Logger.Error("Failed to get tournament match room", "tournament_id", tournament.ID, "room_id", match.RoomID, "error", err)
+			continue
+		}
+		data := utils.Dict{
+			"tournament_id":   tournament.ID,
+			"tournament_name": tournament.Name,
+			"match_id":        match.ID,
+			"round":           match.Round,
+			"room_id":         room.ID,
+			"room":            roomSummary(room),
+		}
+		if ruleset, err := state.RulesetService.GetRuleset(room.RulesetID); err == nil {
+			data["ruleset"] = ruleset
+		}
+		for _, playerID := range match.Players {
+			playerData := utils.Dict{"opponent_id": match.Opponent(playerID)}
+			for key, value := range data {
+				playerData[key] = value
+			}
+			notifyUser(server, playerID, "tournament_match", playerData)
+		}
+	}
+}
+
+// tournamentSummary converte um torneio nos metadados da listagem, sem a chave.
+func tournamentSummary(tournament domain.Tournament) utils.Dict {
+	return utils.Dict{
+		"id":         tournament.ID,
+		"name":       tournament.Name,
+		"owner_id":   tournament.OwnerID,
+		"format":     tournament.Format,
+		"size":       tournament.Size,
+		"ruleset_id": tournament.RulesetID,
+		"status":     tournament.Status,
+		"players":    tournament.Players,
+		"winner_id":  tournament.WinnerID,
+		"created_at": tournament.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// tournamentView converte um torneio nos metadados, na chave e na classificação enviados aos clientes.
+func tournamentView(tournament domain.Tournament) utils.Dict {
+	view := tournamentSummary(tournament)
+	matches := tournament.Matches
+	if matches == nil {
+		matches = []domain.TournamentMatch{}
+	}
+	view["matches"] = matches
+	view["rounds"] = tournament.Rounds()
+	view["standings"] = tournament.Standings()
+	return view
+}
+
+// tournamentErrorMessage traduz erros do serviço de torneios na mensagem exibida ao cliente.
+func tournamentErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrTournamentNotFound),
+		errors.Is(err, application.ErrTournamentClosed),
+		errors.Is(err, application.ErrTournamentFull),
+		errors.Is(err, application.ErrAlreadyRegistered),
+		errors.Is(err, application.ErrNotRegistered),
+		errors.Is(err, application.ErrNotTournamentOwner),
+		errors.Is(err, application.ErrTournamentTooFew),
+		errors.Is(err, application.ErrTournamentBotPlayers),
+		errors.Is(err, application.ErrMatchRoomUnavailable):
+		return err.Error()
+	default:
+		return "Tournament request failed"
+	}
+}
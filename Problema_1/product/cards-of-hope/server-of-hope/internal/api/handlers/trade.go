@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+func HandleOfferTrade(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to offer trade")
+	if !loggedIn {
+		return
+	}
+	targetID, targetIDOk := request.Data["target_id"].(string)
+	offered, offeredOk := stringList(request.Data["offer"])
+	requested, requestedOk := stringList(request.Data["request"])
+	minutes, _ := request.Data["minutes"].(float64)
+
+	if !targetIDOk || !offeredOk || !requestedOk {
+		responder.SetError("Invalid parameters", "Failed to offer trade", "from", request.From)
+		return
+	}
+
+	trade, err := state.TradeService.Offer(userID, targetID, offered, requested, time.Duration(minutes)*time.Minute)
+	if err != nil {
+		responder.SetError(tradeErrorMessage(err), "Failed to offer trade", "user_id", userID, "target_id", targetID, "error", err)
+		return
+	}
+
+	data := utils.Dict{"message": "Trade offered successfully", "trade": tradeView(trade)}
+	responder.SetSuccess(data, "Trade offered successfully", "user_id", userID, "target_id", targetID, "trade_id", trade.ID)
+
+	notifyTrade(server, trade, "offered", trade.ToID, nil)
+}
+
+func HandleAcceptTrade(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to accept trade")
+	if !loggedIn {
+		return
+	}
+	tradeID, ok := request.Data["trade_id"].(string)
+	if !ok {
+		responder.SetError("Invalid parameters", "Failed to accept trade", "from", request.From)
+		return
+	}
+
+	trade, received, err := state.TradeService.Accept(tradeID, userID)
+	if err != nil {
+		responder.SetError(tradeErrorMessage(err), "Failed to accept trade", "user_id", userID, "trade_id", tradeID, "error", err)
+		announceTradeClosed(server, trade, userID)
+		return
+	}
+
+	data := utils.Dict{"message": "Trade accepted successfully", "trade": tradeView(trade), "received": received[userID]}
+	responder.SetSuccess(data, "Trade accepted successfully", "user_id", userID, "trade_id", tradeID)
+
+	notifyTrade(server, trade, "accepted", trade.FromID, received[trade.FromID])
+}
+
+func HandleDeclineTrade(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to decline trade")
+	if !loggedIn {
+		return
+	}
+	tradeID, ok := request.Data["trade_id"].(string)
+	if !ok {
+		responder.SetError("Invalid parameters", "Failed to decline trade", "from", request.From)
+		return
+	}
+
+	trade, err := state.TradeService.Decline(tradeID, userID)
+	if err != nil {
+		responder.SetError(tradeErrorMessage(err), "Failed to decline trade", "user_id", userID, "trade_id", tradeID, "error", err)
+		announceTradeClosed(server, trade, userID)
+		return
+	}
+
+	data := utils.Dict{"message": "Trade declined successfully", "trade": tradeView(trade)}
+	responder.SetSuccess(data, "Trade declined successfully", "user_id", userID, "trade_id", tradeID)
+
+	notifyTrade(server, trade, "declined", trade.FromID, nil)
+}
+
+func HandleCancelTrade(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to cancel trade")
+	if !loggedIn {
+		return
+	}
+	tradeID, ok := request.Data["trade_id"].(string)
+	if !ok {
+		responder.SetError("Invalid parameters", "Failed to cancel trade", "from", request.From)
+		return
+	}
+
+	trade, err := state.TradeService.Cancel(tradeID, userID)
+	if err != nil {
+		responder.SetError(tradeErrorMessage(err), "Failed to cancel trade", "user_id", userID, "trade_id", tradeID, "error", err)
+		announceTradeClosed(server, trade, userID)
+		return
+	}
+
+	data := utils.Dict{"message": "Trade cancelled successfully", "trade": tradeView(trade)}
+	responder.SetSuccess(data, "Trade cancelled successfully", "user_id", userID, "trade_id", tradeID)
+
+	notifyTrade(server, trade, "cancelled", trade.ToID, nil)
+}
+
+func HandleListTrades(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to list trades")
+	if !loggedIn {
+		return
+	}
+
+	trades, err := state.TradeService.ListTrades(userID)
+	if err != nil {
+		responder.SetError("Could not list trades", "Failed to list trades", "user_id", userID, "error", err)
+		return
+	}
+
+	views := make([]utils.Dict, 0, len(trades))
+	for _, trade := range trades {
+		views = append(views, tradeView(trade))
+	}
+
+	data := utils.Dict{"trades": views}
+	responder.SetSuccess(data, "Trades listed successfully", "user_id", userID, "count", len(views))
+}
+
+// stringList converte uma lista JSON em strings; a lista ausente é tratada como vazia.
+func stringList(raw any) ([]string, bool) {
+	if raw == nil {
+		return nil, true
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, false
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
+
+// announceTradeClosed avisa a outra parte quando uma resposta encontrou a proposta vencida ou
+// sem alguma das cartas, o que a encerra.
+func announceTradeClosed(server *api.Server, trade domain.Trade, userID string) {
+	if !trade.Involves(userID) {
+		return
+	}
+	switch trade.Status {
+	case domain.TradeExpired, domain.TradeFailed:
+		otherID := trade.FromID
+		if otherID == userID {
+			otherID = trade.ToID
+		}
+		notifyTrade(server, trade, trade.Status, otherID, nil)
+	}
+}
+
+// notifyTrade envia a um dos envolvidos a mudança de uma proposta de troca e, no aceite, as
+// cartas que ele recebeu.
+func notifyTrade(server *api.Server, trade domain.Trade, event, userID string, received []domain.OwnedCard) {
+	data := utils.Dict{"event": event, "trade": tradeView(trade)}
+	if received != nil {
+		data["received"] = received
+	}
+	notifyUser(server, userID, "trade_update", data)
+}
+
+// tradeView converte uma proposta de troca nos dados enviados aos clientes.
+func tradeView(trade domain.Trade) utils.Dict {
+	return utils.Dict{
+		"id":         trade.ID,
+		"from_id":    trade.FromID,
+		"to_id":      trade.ToID,
+		"offered":    trade.Offered,
+		"requested":  trade.Requested,
+		"status":     trade.Status,
+		"created_at": trade.CreatedAt.Format(time.RFC3339),
+		"expires_at": trade.ExpiresAt.Format(time.RFC3339),
+	}
+}
+
+// tradeErrorMessage traduz erros do serviço de trocas na mensagem exibida ao cliente.
+func tradeErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, application.ErrTradeNotFound),
+		errors.Is(err, application.ErrTradeClosed),
+		errors.Is(err, application.ErrTradeExpired),
+		errors.Is(err, application.ErrTradeEmpty),
+		errors.Is(err, application.ErrTradeTooLarge),
+		errors.Is(err, application.ErrTradeTTL),
+		errors.Is(err, application.ErrTradeSelf),
+		errors.Is(err, application.ErrTradeBot),
+		errors.Is(err, application.ErrTradeUnknownUser),
+		errors.Is(err, application.ErrNotTradeRecipient),
+		errors.Is(err, application.ErrNotTradeOwner),
+		errors.Is(err, application.ErrTradeCardNotOwned),
+		errors.Is(err, application.ErrTradeFailed):
+		return err.Error()
+	default:
+		return "Trade request failed"
+	}
+}
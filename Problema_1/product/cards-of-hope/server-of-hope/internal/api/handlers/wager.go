@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+)
+
+// ForfeitAbsentWagers encerra as partidas com aposta cujos jogadores ficaram desconectados além
+// do prazo de volta: quem sumiu perde a partida e, se os dois sumiram, as apostas são devolvidas.
+func ForfeitAbsentWagers(server *api.Server) {
+	absentees, err := state.WagerService.Absentees(func(userID string) bool {
+		_, connected := state.UserConnections.Get(userID)
+		return connected
+	})
+	if err != nil {
+		state.Logger.Error("Failed to look for absent wager players", "error", err)
+		return
+	}
+	for roomID, playerIDs := range absentees {
+		result, err := state.GameService.Forfeit(roomID, playerIDs)
+		if err != nil {
+			state.Logger.Error("Failed to forfeit absent wager players", "room_id", roomID, "players", playerIDs, "error", err)
+			continue
+		}
+		state.Logger.Info("Wager match abandoned", "room_id", roomID, "absent", playerIDs)
+		notifyMatchFinished(server, roomID, &result)
+	}
+}
+
+// forfeitWager dá a partida com aposta em andamento ao oponente de quem deixa a sala, para que
+// sair da sala não desfaça a aposta.
+func forfeitWager(server *api.Server, roomID, userID string) {
+	room, err := state.RoomService.GetRoom(roomID)
+	if err != nil || room.WagerID == "" || room.Status != domain.RoomStatusPlaying || !room.UserIDs.Contains(userID) {
+		return
+	}
+	result, err := state.GameService.Forfeit(roomID, []string{userID})
+	if err != nil {
+		state.Logger.Error("Failed to forfeit wager match", "room_id", roomID, "user_id", userID, "error", err)
+		return
+	}
+	notifyMatchFinished(server, roomID, &result)
+}
+
+// settleWager paga ou devolve a aposta da partida encerrada e avisa os jogadores do resultado.
+func settleWager(server *api.Server, room domain.Room, winnerID string) {
+	if room.WagerID == "" {
+		return
+	}
+	wager, received, err := state.WagerService.Settle(room.WagerID, winnerID)
+	if errors.Is(err, application.ErrWagerSettled) {
+		return
+	}
+	if err != nil {
+		state.Logger.Error("Failed to settle wager", "room_id", room.ID, "wager_id", room.WagerID, "error", err)
+		if wager.ID == "" {
+			return
+		}
+	}
+	state.Logger.Info("Wager settled", "room_id", room.ID, "wager_id", wager.ID, "status", wager.Status, "winner_id", wager.WinnerID)
+	for playerID := range wager.Stakes {
+		view := wagerView(wager)
+		view["received"] = received[playerID]
+		notifyUser(server, playerID, "wager_settled", view)
+	}
+}
+
+// matchWager descreve a aposta retida da partida que começa, ou nil se a sala não aposta.
+func matchWager(room domain.Room) utils.Dict {
+	if room.WagerID == "" {
+		return nil
+	}
+	wager, err := state.WagerService.Get(room.WagerID)
+	if err != nil {
+		return nil
+	}
+	return wagerView(wager)
+}
+
+// wagerView converte uma aposta nos dados enviados aos jogadores.
+func wagerView(wager domain.Wager) utils.Dict {
+	coins, cards := wager.Pot()
+	return utils.Dict{
+		"wager_id":  wager.ID,
+		"room_id":   wager.RoomID,
+		"match":     wager.Match,
+		"status":    wager.Status,
+		"winner_id": wager.WinnerID,
+		"stakes":    wager.Stakes,
+		"pot_coins": coins,
+		"pot_cards": cards,
+	}
+}
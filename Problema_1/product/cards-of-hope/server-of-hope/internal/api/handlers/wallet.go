@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"server-of-hope/internal/api"
+	"server-of-hope/internal/api/protocol"
+	"server-of-hope/internal/application"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/state"
+	"server-of-hope/internal/utils"
+)
+
+func HandleBalance(server *api.Server, request protocol.Request) {
+	responder := NewResponder(server, request)
+	defer responder.Send()
+
+	userID, loggedIn := responder.RequireLogin("Failed to get balance")
+	if !loggedIn {
+		return
+	}
+
+	balance, history, err := state.WalletService.Balance(userID)
+	if err != nil {
+		responder.SetError("Could not get balance", "Failed to get balance", "user_id", userID, "error", err)
+		return
+	}
+	if history == nil {
+		history = []domain.LedgerEntry{}
+	}
+	dust, err := state.WalletService.Dust(userID)
+	if err != nil {
+		responder.SetError("Could not get balance", "Failed to get balance", "user_id", userID, "error", err)
+		return
+	}
+
+	basic, _ := state.StoreService.PackType(domain.DefaultPackID)
+	data := utils.Dict{
+		"balance":         balance,
+		"dust":            dust,
+		"history":         history,
+		"package_price":   basic.Price,
+		"round_win_coins": application.RoundWinCoins,
+		"match_win_coins": application.MatchWinCoins,
+	}
+	responder.SetSuccess(data, "Balance sent successfully", "user_id", userID, "balance", balance)
+}
+
+// rewardRound paga o prêmio ao vencedor de uma rodada resolvida.
+func rewardRound(server *api.Server, gameID string, result *domain.RoundResult) {
+	if result.WinnerID == "" || againstBot(gameID) {
+		return
+	}
+	creditReward(server, result.WinnerID, application.RoundWinCoins, domain.LedgerRoundWin, gameID)
+}
+
+// rewardMatch paga o prêmio ao vencedor de uma partida encerrada.
+func rewardMatch(server *api.Server, gameID string, result *domain.RoundResult) {
+	if result.MatchWinnerID == "" || againstBot(gameID) {
+		return
+	}
+	creditReward(server, result.MatchWinnerID, application.MatchWinCoins, domain.LedgerMatchWin, gameID)
+}
+
+// againstBot informa se um bot joga a partida; vitórias sobre bots não rendem moedas.
+func againstBot(gameID string) bool {
+	game, err := state.GameService.GetGame(gameID)
+	if err != nil {
+		return true
+	}
+	for _, playerID := range game.Seats {
+		if domain.IsBotID(playerID) {
+			return true
+		}
+	}
+	return false
+}
+
+// creditReward credita um prêmio e avisa o usuário do novo saldo.
+func creditReward(server *api.Server, userID string, amount int, reason, roomID string) {
+	entry, err := state.WalletService.Credit(userID, amount, reason, roomID)
+	if err != nil {
+		state.Logger.Error("Failed to credit reward", "user_id", userID, "reason", reason, "room_id", roomID, "error", err)
+		return
+	}
+	notifyUser(server, userID, "coins", utils.Dict{
+		"room_id": roomID,
+		"amount":  entry.Amount,
+		"balance": entry.Balance,
+		"reason":  entry.Reason,
+	})
+}
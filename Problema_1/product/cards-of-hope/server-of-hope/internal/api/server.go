@@ -2,6 +2,7 @@
 package api
 
 import (
+	"errors"
 	"net"
 	"server-of-hope/internal/api/protocol"
 	"server-of-hope/internal/state"
@@ -17,15 +18,23 @@ import (
 //   - Router: interface responsável pelo roteamento de comandos.
 //   - Requests: canal de requisições recebidas.
 //   - Responses: canal de respostas a serem enviadas.
+//   - disconnectHandlers: funções chamadas quando um usuário autenticado se desconecta.
 type Server struct {
-	Address   string
-	Listener  net.Listener
-	Clients   *utils.Map[string, *Client]
-	Router    RouterInterface
-	Requests  chan protocol.Request
-	Responses chan protocol.Response
+	Address            string
+	Listener           net.Listener
+	Clients            *utils.Map[string, *Client]
+	Router             RouterInterface
+	Requests           chan protocol.Request
+	Responses          chan protocol.Response
+	disconnectHandlers []DisconnectFunc
 }
 
+// DisconnectFunc define o tipo de função chamada quando um usuário autenticado se desconecta.
+// Parâmetros:
+//   - server: ponteiro para o servidor.
+//   - userID: ID do usuário desconectado.
+type DisconnectFunc func(server *Server, userID string)
+
 // NewServer cria e retorna uma nova instância de Server para o endereço fornecido.
 //
 // Parâmetros:
@@ -83,9 +92,21 @@ func (server *Server) Stop() error {
 	return nil
 }
 
+// OnDisconnect registra uma função a ser chamada quando um usuário autenticado se desconecta.
+// Deve ser chamado antes de Start.
+//
+// Parâmetros:
+//   - handler: função chamada com o ID do usuário desconectado.
+func (server *Server) OnDisconnect(handler DisconnectFunc) {
+	server.disconnectHandlers = append(server.disconnectHandlers, handler)
+}
+
 func (server *Server) acceptConnections() {
 	for {
 		conn, err := server.Listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
 		if err != nil {
 			state.Logger.Error("Failed to accept connection", "error", err)
 			continue
@@ -101,8 +122,12 @@ func (server *Server) getRequests(client *Client) {
 	defer func() {
 		client.Close()
 		server.Clients.Delete(client.Address)
-		if client.UserID != "" {
+		// Só considera o usuário desconectado se ele não tiver feito login em outra conexão.
+		if address, ok := state.UserConnections.Get(client.UserID); ok && address == client.Address {
 			state.UserConnections.Delete(client.UserID)
+			for _, handler := range server.disconnectHandlers {
+				handler(server, client.UserID)
+			}
 		}
 		state.Logger.Info("Client disconnected", "address", client.Address)
 	}()
@@ -4,8 +4,15 @@ import (
 	"errors"
 	"server-of-hope/internal/data"
 	"server-of-hope/internal/domain"
+	"strings"
 )
 
+// ErrReservedUsername indica que o nome de usuário usa o prefixo reservado aos bots.
+var ErrReservedUsername = errors.New("nomes de usuário começando com " + domain.BotIDPrefix + " são reservados aos bots")
+
+// ErrInvalidUsername indica que o nome de usuário contém uma barra, usada como separador nos IDs.
+var ErrInvalidUsername = errors.New("nomes de usuário não podem conter /")
+
 // AuthServiceInterface descreve as operações de autenticação de usuários.
 //
 // Métodos:
@@ -60,8 +67,14 @@ func NewAuthService(userRepo data.RepositoryInterface[domain.User]) *AuthService
 //   - password: senha do usuário.
 //
 // Retorno:
-//   - erro caso o usuário já exista ou haja falha no cadastro.
+//   - erro caso o usuário já exista, o nome seja reservado ou inválido ou haja falha no cadastro.
 func (service *AuthService) Register(username, password string) error {
+	if domain.IsBotID(username) {
+		return ErrReservedUsername
+	}
+	if strings.Contains(username, "/") {
+		return ErrInvalidUsername
+	}
 	_, err := service.UserRepo.Read(username)
 	if err == nil {
 		return err // Usuário já existe
@@ -85,6 +98,9 @@ func (service *AuthService) Login(username, password string) (string, error) {
 	if err != nil {
 		return "", err // Usuário não encontrado
 	}
+	if user.Bot {
+		return "", errors.New("bots não fazem login")
+	}
 	if user.Password != password {
 		return "", errors.New("senha inválida")
 	}
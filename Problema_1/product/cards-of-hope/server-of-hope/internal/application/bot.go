@@ -0,0 +1,328 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BotThinkTime define quanto um bot espera antes de jogar, para a partida não parecer instantânea.
+const BotThinkTime = 500 * time.Millisecond
+
+// ErrUnknownBotDifficulty indica que não existe bot com a dificuldade pedida.
+var ErrUnknownBotDifficulty = errors.New("dificuldade de bot desconhecida: use easy, medium ou hard")
+
+// errNoBotCommit indica que o bot não tem compromisso pendente para revelar.
+var errNoBotCommit = errors.New("o bot não tem compromisso pendente")
+
+// BotServiceInterface descreve as operações dos bots que ocupam o segundo assento das salas.
+//
+// Métodos:
+//   - AddBot: coloca um bot em uma sala.
+//   - Play: faz o bot jogar a rodada em andamento.
+//   - Commit: faz o bot se comprometer com uma carta em salas com compromisso e revelação.
+//   - Reveal: faz o bot revelar a carta com que se comprometeu.
+//   - Observe: registra uma carta jogada pelo oponente do bot.
+//   - Forget: descarta o que o bot guardou sobre uma sala.
+type BotServiceInterface interface {
+	// AddBot coloca um bot da dificuldade informada em uma sala, já pronto para jogar.
+	// Apenas o anfitrião da sala pode chamar um bot.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - hostID: identificador do anfitrião.
+	//   - difficulty: dificuldade do bot (easy, medium ou hard).
+	//
+	// Retorno:
+	//   - string: ID do bot.
+	//   - erro caso a dificuldade não exista ou o bot não possa entrar na sala.
+	AddBot(roomID, hostID, difficulty string) (string, error)
+
+	// Play escolhe uma carta da mão do bot e a joga pelo GameService.
+	//
+	// Parâmetros:
+	//   - gameID: identificador da partida.
+	//   - botID: identificador do bot.
+	//
+	// Retorno:
+	//   - *domain.RoundResult: resultado da rodada, se a jogada a completou.
+	//   - erro caso a jogada seja recusada.
+	Play(gameID, botID string) (*domain.RoundResult, error)
+
+	// Commit escolhe uma carta da mão do bot e envia o compromisso dela pelo GameService.
+	//
+	// Parâmetros:
+	//   - gameID: identificador da partida.
+	//   - botID: identificador do bot.
+	//
+	// Retorno:
+	//   - bool: true se os dois jogadores já se comprometeram.
+	//   - erro caso o compromisso seja recusado.
+	Commit(gameID, botID string) (bool, error)
+
+	// Reveal revela a carta e o nonce do compromisso pendente do bot.
+	//
+	// Parâmetros:
+	//   - gameID: identificador da partida.
+	//   - botID: identificador do bot.
+	//
+	// Retorno:
+	//   - *domain.RoundResult: resultado da rodada, se a revelação a completou.
+	//   - erro caso não haja compromisso pendente ou a revelação seja recusada.
+	Reveal(gameID, botID string) (*domain.RoundResult, error)
+
+	// Observe registra o tipo de uma carta jogada pelo oponente do bot.
+	//
+	// Parâmetros:
+	//   - gameID: identificador da partida.
+	//   - botID: identificador do bot.
+	//   - cardType: tipo da carta do oponente.
+	Observe(gameID, botID, cardType string)
+
+	// Forget descarta o histórico e o compromisso pendente do bot em uma sala.
+	//
+	// Parâmetros:
+	//   - gameID: identificador da partida.
+	//   - botID: identificador do bot.
+	Forget(gameID, botID string)
+}
+
+// botCommit guarda a carta e o nonce de um compromisso que o bot ainda vai revelar.
+type botCommit struct {
+	cardID string
+	nonce  string
+}
+
+// BotService implementa os bots. Eles jogam pelos mesmos serviços que os jogadores humanos e
+// cada dificuldade usa uma domain.BotStrategy.
+//
+// Campos:
+//   - userRepo: repositório dos usuários, onde ficam as identidades dos bots.
+//   - roomRepo: repositório das salas.
+//   - rulesetRepo: repositório dos conjuntos de regras.
+//   - rooms: serviço pelo qual os bots entram nas salas.
+//   - games: serviço pelo qual os bots jogam.
+//   - history: cartas jogadas pelo oponente, por partida e bot.
+//   - commits: compromissos pendentes, por partida e bot.
+type BotService struct {
+	userRepo    data.RepositoryInterface[domain.User]
+	roomRepo    data.RepositoryInterface[domain.Room]
+	rulesetRepo data.RepositoryInterface[domain.Ruleset]
+	rooms       RoomServiceInterface
+	games       GameServiceInterface
+	history     *utils.Map[string, []string]
+	commits     *utils.Map[string, botCommit]
+	mutex       sync.Mutex // serializa a criação das identidades e as mudanças no histórico
+}
+
+// NewBotService cria uma nova instância de BotService.
+//
+// Parâmetros:
+//   - userRepo: repositório dos usuários.
+//   - roomRepo: repositório das salas.
+//   - rulesetRepo: repositório dos conjuntos de regras.
+//   - rooms: serviço de salas.
+//   - games: serviço de partidas.
+//
+// Retorno:
+//   - ponteiro para BotService.
+func NewBotService(
+	userRepo data.RepositoryInterface[domain.User],
+	roomRepo data.RepositoryInterface[domain.Room],
+	rulesetRepo data.RepositoryInterface[domain.Ruleset],
+	rooms RoomServiceInterface,
+	games GameServiceInterface,
+) *BotService {
+	return &BotService{
+		userRepo:    userRepo,
+		roomRepo:    roomRepo,
+		rulesetRepo: rulesetRepo,
+		rooms:       rooms,
+		games:       games,
+		history:     utils.NewMap[string, []string](),
+		commits:     utils.NewMap[string, botCommit](),
+	}
+}
+
+// botKey identifica um bot dentro de uma partida; o mesmo bot pode estar em várias salas.
+func botKey(gameID, botID string) string {
+	return gameID + "/" + botID
+}
+
+// ensureBot cria a identidade do bot de uma dificuldade, se ela ainda não existir.
+func (service *BotService) ensureBot(difficulty string) (string, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	botID := domain.BotID(difficulty)
+	if _, err := service.userRepo.Read(botID); err == nil {
+		return botID, nil
+	}
+	return botID, service.userRepo.Create(botID, domain.User{ID: botID, Username: botID, Bot: true})
+}
+
+// AddBot coloca um bot da dificuldade informada em uma sala, já pronto para jogar. Em salas
+// privadas, o bot entra com um convite de uso único gerado pelo anfitrião.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//   - hostID: identificador do anfitrião.
+//   - difficulty: dificuldade do bot (easy, medium ou hard).
+//
+// Retorno:
+//   - string: ID do bot.
+//   - erro caso a dificuldade não exista ou o bot não possa entrar na sala.
+func (service *BotService) AddBot(roomID, hostID, difficulty string) (string, error) {
+	if _, ok := domain.NewBotStrategy(difficulty); !ok {
+		return "", ErrUnknownBotDifficulty
+	}
+	room, err := service.roomRepo.Read(roomID)
+	if err != nil {
+		return "", err
+	}
+	if room.HostID != hostID {
+		return "", ErrNotRoomHost
+	}
+	if room.HasWager() {
+		return "", ErrWagerBot // O bot não tem coleção nem carteira para apostar
+	}
+	botID, err := service.ensureBot(difficulty)
+	if err != nil {
+		return "", err
+	}
+
+	options := JoinOptions{}
+	if room.Private {
+		invite, err := service.rooms.CreateInvite(roomID, hostID, time.Minute, true)
+		if err != nil {
+			return "", err
+		}
+		options.InviteCode = invite.Code
+	}
+	if _, err := service.rooms.JoinRoom(roomID, botID, options); err != nil {
+		return "", err
+	}
+	service.Forget(roomID, botID)
+	if _, err := service.games.SetReady(roomID, botID, true); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
+
+// chooseCard escolhe, com a estratégia da dificuldade do bot, uma carta da mão dele.
+func (service *BotService) chooseCard(gameID, botID string) (domain.OwnedCard, error) {
+	strategy, ok := domain.NewBotStrategy(strings.TrimPrefix(botID, domain.BotIDPrefix))
+	if !ok {
+		return domain.OwnedCard{}, ErrUnknownBotDifficulty
+	}
+	room, err := service.roomRepo.Read(gameID)
+	if err != nil {
+		return domain.OwnedCard{}, err
+	}
+	ruleset, err := readRuleset(service.rulesetRepo, room.RulesetID)
+	if err != nil {
+		return domain.OwnedCard{}, err
+	}
+	game, err := service.games.GetGame(gameID)
+	if err != nil {
+		return domain.OwnedCard{}, err
+	}
+	hand, _ := game.Hands.Get(botID)
+	if len(hand) == 0 {
+		return domain.OwnedCard{}, errNotInHand
+	}
+	history, _ := service.history.Get(botKey(gameID, botID))
+	return strategy.Choose(hand, history, ruleset), nil
+}
+
+// Play escolhe uma carta da mão do bot e a joga pelo GameService.
+//
+// Parâmetros:
+//   - gameID: identificador da partida.
+//   - botID: identificador do bot.
+//
+// Retorno:
+//   - *domain.RoundResult: resultado da rodada, se a jogada a completou.
+//   - erro caso a jogada seja recusada.
+func (service *BotService) Play(gameID, botID string) (*domain.RoundResult, error) {
+	card, err := service.chooseCard(gameID, botID)
+	if err != nil {
+		return nil, err
+	}
+	return service.games.PlayCard(gameID, botID, card.ID)
+}
+
+// Commit escolhe uma carta da mão do bot e envia o compromisso dela pelo GameService,
+// guardando a carta e o nonce para a revelação.
+//
+// Parâmetros:
+//   - gameID: identificador da partida.
+//   - botID: identificador do bot.
+//
+// Retorno:
+//   - bool: true se os dois jogadores já se comprometeram.
+//   - erro caso o compromisso seja recusado.
+func (service *BotService) Commit(gameID, botID string) (bool, error) {
+	card, err := service.chooseCard(gameID, botID)
+	if err != nil {
+		return false, err
+	}
+	commit := botCommit{cardID: card.ID, nonce: utils.RandomCode(2 * domain.MinNonceLength)}
+	service.commits.Set(botKey(gameID, botID), commit)
+	complete, err := service.games.Commit(gameID, botID, domain.Commitment(commit.cardID, commit.nonce))
+	if err != nil {
+		service.commits.Delete(botKey(gameID, botID))
+	}
+	return complete, err
+}
+
+// Reveal revela a carta e o nonce do compromisso pendente do bot.
+//
+// Parâmetros:
+//   - gameID: identificador da partida.
+//   - botID: identificador do bot.
+//
+// Retorno:
+//   - *domain.RoundResult: resultado da rodada, se a revelação a completou.
+//   - erro caso não haja compromisso pendente ou a revelação seja recusada.
+func (service *BotService) Reveal(gameID, botID string) (*domain.RoundResult, error) {
+	service.mutex.Lock()
+	commit, ok := service.commits.Get(botKey(gameID, botID))
+	service.commits.Delete(botKey(gameID, botID))
+	service.mutex.Unlock()
+	if !ok {
+		return nil, errNoBotCommit
+	}
+	return service.games.Reveal(gameID, botID, commit.cardID, commit.nonce)
+}
+
+// Observe registra o tipo de uma carta jogada pelo oponente do bot.
+//
+// Parâmetros:
+//   - gameID: identificador da partida.
+//   - botID: identificador do bot.
+//   - cardType: tipo da carta do oponente.
+func (service *BotService) Observe(gameID, botID, cardType string) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	history, _ := service.history.Get(botKey(gameID, botID))
+	service.history.Set(botKey(gameID, botID), append(history, cardType))
+}
+
+// Forget descarta o histórico e o compromisso pendente do bot em uma sala.
+//
+// Parâmetros:
+//   - gameID: identificador da partida.
+//   - botID: identificador do bot.
+func (service *BotService) Forget(gameID, botID string) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.history.Delete(botKey(gameID, botID))
+	service.commits.Delete(botKey(gameID, botID))
+}
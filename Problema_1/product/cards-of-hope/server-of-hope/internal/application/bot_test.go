@@ -0,0 +1,108 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sync"
+	"testing"
+)
+
+// newTestBotService cria os serviços de salas, partidas e bots com repositórios vazios.
+func newTestBotService(t *testing.T) (*RoomService, *GameService, *BotService, *data.InMemoryRepository[domain.User]) {
+	t.Helper()
+	roomMutex := &sync.Mutex{}
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	rooms := NewRoomService(data.NewInMemoryRepository[domain.Room](), newTestRulesets(t), roomMutex)
+	games := NewGameService(data.NewInMemoryRepository[domain.Game](), userRepo, rooms.RoomRepo, rooms.RulesetRepo, nil, roomMutex)
+	bots := NewBotService(userRepo, rooms.RoomRepo, rooms.RulesetRepo, rooms, games)
+	return rooms, games, bots, userRepo
+}
+
+func TestAddBot(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    RoomOptions
+		hostID     string
+		difficulty string
+		wantErr    error
+	}{
+		{name: "sala pública", hostID: "alice", difficulty: domain.BotHard},
+		{name: "sala privada entra por convite", options: RoomOptions{Private: true, Password: "segredo"}, hostID: "alice", difficulty: domain.BotEasy},
+		{name: "dificuldade desconhecida", hostID: "alice", difficulty: "impossible", wantErr: ErrUnknownBotDifficulty},
+		{name: "quem não é anfitrião", hostID: "bob", difficulty: domain.BotHard, wantErr: ErrNotRoomHost},
+		{name: "sala com aposta", options: RoomOptions{WagerCoins: 10}, hostID: "alice", difficulty: domain.BotHard, wantErr: ErrWagerBot},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rooms, _, bots, userRepo := newTestBotService(t)
+			roomID, err := rooms.CreateRoom("alice", test.options)
+			if err != nil {
+				t.Fatalf("CreateRoom: %v", err)
+			}
+
+			botID, err := bots.AddBot(roomID, test.hostID, test.difficulty)
+			room, _ := rooms.GetRoom(roomID)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+				}
+				if room.UserIDs.Size() != 1 {
+					t.Errorf("%d jogadores após o erro, esperado 1", room.UserIDs.Size())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddBot: %v", err)
+			}
+			if botID != domain.BotID(test.difficulty) || !room.UserIDs.Contains(botID) || !room.Ready.Contains(botID) {
+				t.Errorf("bot %q na sala: %v, pronto: %v", botID, room.UserIDs.Contains(botID), room.Ready.Contains(botID))
+			}
+			if user, err := userRepo.Read(botID); err != nil || !user.Bot {
+				t.Errorf("identidade do bot = %+v, %v", user, err)
+			}
+		})
+	}
+}
+
+func TestBotPlaysThroughGameService(t *testing.T) {
+	for _, commitReveal := range []bool{false, true} {
+		rooms, games, bots, _ := newTestBotService(t)
+		roomID, _ := rooms.CreateRoom("alice", RoomOptions{CommitReveal: commitReveal})
+		botID, err := bots.AddBot(roomID, "alice", domain.BotMedium)
+		if err != nil {
+			t.Fatalf("AddBot: %v", err)
+		}
+		if started, err := games.SetReady(roomID, "alice", true); err != nil || !started {
+			t.Fatalf("SetReady = %v, %v", started, err)
+		}
+
+		if commitReveal {
+			if _, err := bots.Play(roomID, botID); !errors.Is(err, ErrCommitRequired) {
+				t.Errorf("jogada direta em sala com compromisso: erro = %v", err)
+			}
+			if _, err := bots.Commit(roomID, botID); err != nil {
+				t.Fatalf("Commit: %v", err)
+			}
+			if _, err := games.Commit(roomID, "alice", domain.Commitment("rock", revealNonce)); err != nil {
+				t.Fatalf("Commit de alice: %v", err)
+			}
+			if _, err := bots.Reveal(roomID, botID); err != nil {
+				t.Fatalf("Reveal: %v", err)
+			}
+			if _, err := bots.Reveal(roomID, botID); !errors.Is(err, errNoBotCommit) {
+				t.Errorf("segunda revelação: erro = %v, esperado %v", err, errNoBotCommit)
+			}
+		} else if _, err := bots.Play(roomID, botID); err != nil {
+			t.Fatalf("Play: %v", err)
+		}
+
+		game, _ := games.GetGame(roomID)
+		if _, played := game.Plays.Get(botID); !played {
+			t.Errorf("jogadas fechadas %v: o bot não jogou", commitReveal)
+		}
+		if hand, _ := game.Hands.Get(botID); len(hand) != domain.HandSize-1 {
+			t.Errorf("jogadas fechadas %v: %d cartas na mão do bot", commitReveal, len(hand))
+		}
+	}
+}
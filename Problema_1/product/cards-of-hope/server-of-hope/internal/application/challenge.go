@@ -0,0 +1,385 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChallengeTTL define por quanto tempo um desafio aguarda a resposta do desafiado.
+const ChallengeTTL = time.Minute
+
+// ChallengeSweepInterval define de quanto em quanto tempo os desafios vencidos são encerrados.
+const ChallengeSweepInterval = 5 * time.Second
+
+// challengeInviteTTL define a validade do convite que leva o desafiado à sala do duelo.
+const challengeInviteTTL = time.Minute
+
+// Erros de desafio exibidos diretamente aos usuários.
+var (
+	ErrChallengeNotFound    = errors.New("Desafio não encontrado")
+	ErrChallengeClosed      = errors.New("O desafio não está mais pendente")
+	ErrChallengeExpired     = errors.New("O desafio expirou")
+	ErrChallengeSelf        = errors.New("Não é possível desafiar a si mesmo")
+	ErrChallengeBot         = errors.New("Bots não recebem desafios, crie uma sala com um bot")
+	ErrChallengeUnknownUser = errors.New("Usuário não encontrado")
+	ErrChallengeOffline     = errors.New("O usuário não está conectado")
+	ErrChallengePending     = errors.New("Já existe um desafio pendente entre vocês")
+	ErrChallengeBusy        = errors.New("O usuário está no meio de uma partida")
+	ErrNotChallenged        = errors.New("Apenas quem foi desafiado pode aceitar ou recusar o desafio")
+	ErrNotChallenger        = errors.New("Apenas quem desafiou pode cancelar o desafio")
+)
+
+// ChallengeOptions descreve as opções da sala do duelo de um desafio.
+//
+// Campos:
+//   - Ruleset: ID do conjunto de regras do duelo (vazio usa o conjunto padrão).
+//   - CommitReveal: as jogadas do duelo são feitas com compromisso e revelação.
+type ChallengeOptions struct {
+	Ruleset      string
+	CommitReveal bool
+}
+
+// ChallengeServiceInterface descreve as operações dos desafios entre usuários conectados.
+//
+// Métodos:
+//   - Challenge: desafia outro usuário para um duelo.
+//   - Accept: aceita um desafio, criando a sala do duelo.
+//   - Decline: recusa um desafio.
+//   - Cancel: cancela um desafio feito.
+//   - ListChallenges: lista os desafios pendentes de um usuário.
+//   - Expire: encerra os desafios vencidos.
+//   - Withdraw: encerra os desafios pendentes de um usuário que se desconectou.
+type ChallengeServiceInterface interface {
+	// Challenge desafia outro usuário conectado para um duelo, que vale por ChallengeTTL.
+	//
+	// Parâmetros:
+	//   - fromID: usuário que desafia.
+	//   - toID: usuário desafiado.
+	//   - options: regras da sala do duelo.
+	//   - online: informa se um usuário está conectado.
+	//
+	// Retorno:
+	//   - domain.Challenge: desafio registrado.
+	//   - erro caso o desafio seja inválido ou o desafiado não possa recebê-lo.
+	Challenge(fromID, toID string, options ChallengeOptions, online func(userID string) bool) (domain.Challenge, error)
+
+	// Accept aceita um desafio pendente: cria uma sala privada, só com convite, com quem
+	// desafiou como anfitrião e coloca os dois usuários nela.
+	//
+	// Parâmetros:
+	//   - challengeID: identificador do desafio.
+	//   - userID: usuário desafiado.
+	//
+	// Retorno:
+	//   - domain.Challenge: desafio aceito, com a sala do duelo em RoomID.
+	//   - erro caso o desafio não possa ser aceito ou a sala não possa ser criada.
+	Accept(challengeID, userID string) (domain.Challenge, error)
+
+	// Decline recusa um desafio pendente.
+	//
+	// Parâmetros:
+	//   - challengeID: identificador do desafio.
+	//   - userID: usuário desafiado.
+	//
+	// Retorno:
+	//   - domain.Challenge: desafio atualizado.
+	//   - erro caso o desafio não possa ser recusado.
+	Decline(challengeID, userID string) (domain.Challenge, error)
+
+	// Cancel cancela um desafio pendente.
+	//
+	// Parâmetros:
+	//   - challengeID: identificador do desafio.
+	//   - userID: usuário que desafiou.
+	//
+	// Retorno:
+	//   - domain.Challenge: desafio atualizado.
+	//   - erro caso o desafio não possa ser cancelado.
+	Cancel(challengeID, userID string) (domain.Challenge, error)
+
+	// ListChallenges lista os desafios pendentes feitos ou recebidos por um usuário, dos mais
+	// recentes para os mais antigos.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - []domain.Challenge: desafios pendentes.
+	ListChallenges(userID string) []domain.Challenge
+
+	// Expire encerra como expired os desafios pendentes que passaram do prazo.
+	//
+	// Retorno:
+	//   - []domain.Challenge: desafios encerrados.
+	Expire() []domain.Challenge
+
+	// Withdraw cancela os desafios pendentes feitos ou recebidos por um usuário que se
+	// desconectou.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - []domain.Challenge: desafios cancelados.
+	Withdraw(userID string) []domain.Challenge
+}
+
+// ChallengeService implementa os desafios. Como os convites das salas, os desafios só valem
+// enquanto os dois usuários estão conectados e ficam apenas na memória, fora dos backups.
+//
+// Campos:
+//   - userRepo: repositório dos usuários.
+//   - rulesetRepo: repositório dos conjuntos de regras.
+//   - roomService: serviço das salas, que cria a sala do duelo.
+//   - challenges: desafios, indexados pelo ID.
+//   - mutex: serializa as mudanças de estado dos desafios.
+type ChallengeService struct {
+	userRepo    data.RepositoryInterface[domain.User]
+	rulesetRepo data.RepositoryInterface[domain.Ruleset]
+	roomService RoomServiceInterface
+	challenges  *utils.Map[string, domain.Challenge]
+	mutex       sync.Mutex
+}
+
+// NewChallengeService cria uma nova instância de ChallengeService.
+//
+// Parâmetros:
+//   - userRepo: repositório dos usuários.
+//   - rulesetRepo: repositório dos conjuntos de regras.
+//   - roomService: serviço das salas.
+//
+// Retorno:
+//   - ponteiro para ChallengeService.
+func NewChallengeService(
+	userRepo data.RepositoryInterface[domain.User],
+	rulesetRepo data.RepositoryInterface[domain.Ruleset],
+	roomService RoomServiceInterface,
+) *ChallengeService {
+	return &ChallengeService{
+		userRepo:    userRepo,
+		rulesetRepo: rulesetRepo,
+		roomService: roomService,
+		challenges:  utils.NewMap[string, domain.Challenge](),
+	}
+}
+
+// Challenge desafia outro usuário conectado para um duelo.
+func (service *ChallengeService) Challenge(fromID, toID string, options ChallengeOptions, online func(userID string) bool) (domain.Challenge, error) {
+	if fromID == toID {
+		return domain.Challenge{}, ErrChallengeSelf
+	}
+	target, err := service.userRepo.Read(toID)
+	if err != nil {
+		return domain.Challenge{}, ErrChallengeUnknownUser
+	}
+	if target.Bot {
+		return domain.Challenge{}, ErrChallengeBot
+	}
+	if !online(toID) {
+		return domain.Challenge{}, ErrChallengeOffline
+	}
+	ruleset, err := readRuleset(service.rulesetRepo, options.Ruleset)
+	if err != nil {
+		return domain.Challenge{}, err
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	for _, other := range service.challenges.Values() {
+		if other.Status == domain.ChallengePending && !other.Expired() && other.Involves(fromID) && other.Involves(toID) {
+			return domain.Challenge{}, ErrChallengePending
+		}
+	}
+	if err := service.checkIdle(fromID, toID); err != nil {
+		return domain.Challenge{}, err
+	}
+
+	now := time.Now().UTC()
+	challenge := domain.Challenge{
+		ID:           utils.Count(),
+		FromID:       fromID,
+		ToID:         toID,
+		RulesetID:    ruleset.ID,
+		CommitReveal: options.CommitReveal,
+		Status:       domain.ChallengePending,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(ChallengeTTL),
+	}
+	service.challenges.Set(challenge.ID, challenge)
+	return challenge, nil
+}
+
+// checkIdle confere se nenhum dos usuários joga uma partida em andamento.
+func (service *ChallengeService) checkIdle(userIDs ...string) error {
+	rooms, err := service.roomService.ListRooms(RoomFilter{Status: domain.RoomStatusPlaying, IncludeFull: true})
+	if err != nil {
+		return err
+	}
+	for _, room := range rooms {
+		for _, userID := range userIDs {
+			if room.UserIDs.Contains(userID) {
+				return fmt.Errorf("%w: %s", ErrChallengeBusy, userID)
+			}
+		}
+	}
+	return nil
+}
+
+// Accept aceita um desafio pendente e cria a sala do duelo com os dois usuários.
+func (service *ChallengeService) Accept(challengeID, userID string) (domain.Challenge, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	challenge, err := service.pending(challengeID)
+	if err != nil {
+		return challenge, err
+	}
+	if challenge.ToID != userID {
+		return challenge, ErrNotChallenged
+	}
+	if err := service.checkIdle(challenge.FromID, challenge.ToID); err != nil {
+		return challenge, err
+	}
+
+	roomID, err := service.openRoom(challenge)
+	if err != nil {
+		return challenge, err
+	}
+	challenge.RoomID = roomID
+	return service.resolve(challenge, domain.ChallengeAccepted), nil
+}
+
+// openRoom cria a sala privada do duelo, com quem desafiou como anfitrião, e leva o desafiado
+// até ela por um convite de uso único.
+func (service *ChallengeService) openRoom(challenge domain.Challenge) (string, error) {
+	name := []rune(fmt.Sprintf("Duelo %s x %s", challenge.FromID, challenge.ToID))
+	if len(name) > MaxRoomNameLength {
+		name = name[:MaxRoomNameLength]
+	}
+	roomID, err := service.roomService.CreateRoom(challenge.FromID, RoomOptions{
+		Name:         string(name),
+		Private:      true,
+		Ruleset:      challenge.RulesetID,
+		CommitReveal: challenge.CommitReveal,
+	})
+	if err != nil {
+		return "", err
+	}
+	invite, err := service.roomService.CreateInvite(roomID, challenge.FromID, challengeInviteTTL, true)
+	if err != nil {
+		return "", err
+	}
+	if _, err := service.roomService.JoinRoom(roomID, challenge.ToID, JoinOptions{InviteCode: invite.Code}); err != nil {
+		return "", err
+	}
+	return roomID, nil
+}
+
+// Decline recusa um desafio pendente.
+func (service *ChallengeService) Decline(challengeID, userID string) (domain.Challenge, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	challenge, err := service.pending(challengeID)
+	if err != nil {
+		return challenge, err
+	}
+	if challenge.ToID != userID {
+		return challenge, ErrNotChallenged
+	}
+	return service.resolve(challenge, domain.ChallengeDeclined), nil
+}
+
+// Cancel cancela um desafio pendente.
+func (service *ChallengeService) Cancel(challengeID, userID string) (domain.Challenge, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	challenge, err := service.pending(challengeID)
+	if err != nil {
+		return challenge, err
+	}
+	if challenge.FromID != userID {
+		return challenge, ErrNotChallenger
+	}
+	return service.resolve(challenge, domain.ChallengeCancelled), nil
+}
+
+// ListChallenges lista os desafios pendentes feitos ou recebidos por um usuário.
+func (service *ChallengeService) ListChallenges(userID string) []domain.Challenge {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	var pending []domain.Challenge
+	for _, challenge := range service.challenges.Values() {
+		if challenge.Involves(userID) && challenge.Status == domain.ChallengePending && !challenge.Expired() {
+			pending = append(pending, challenge)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.After(pending[j].CreatedAt)
+	})
+	return pending
+}
+
+// Expire encerra os desafios vencidos e descarta os já encerrados, que não são mais consultados.
+func (service *ChallengeService) Expire() []domain.Challenge {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	var expired []domain.Challenge
+	for _, challenge := range service.challenges.Values() {
+		switch {
+		case challenge.Expired():
+			expired = append(expired, service.resolve(challenge, domain.ChallengeExpired))
+		case challenge.Status != domain.ChallengePending:
+			service.challenges.Delete(challenge.ID)
+		}
+	}
+	return expired
+}
+
+// Withdraw cancela os desafios pendentes de um usuário que se desconectou.
+func (service *ChallengeService) Withdraw(userID string) []domain.Challenge {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	var cancelled []domain.Challenge
+	for _, challenge := range service.challenges.Values() {
+		if challenge.Involves(userID) && challenge.Status == domain.ChallengePending {
+			cancelled = append(cancelled, service.resolve(challenge, domain.ChallengeCancelled))
+		}
+	}
+	return cancelled
+}
+
+// pending lê um desafio que ainda aguarda resposta, encerrando-o se já venceu. Deve ser chamado
+// com mutex travado.
+func (service *ChallengeService) pending(challengeID string) (domain.Challenge, error) {
+	challenge, exists := service.challenges.Get(challengeID)
+	if !exists {
+		return domain.Challenge{}, ErrChallengeNotFound
+	}
+	if challenge.Expired() {
+		return service.resolve(challenge, domain.ChallengeExpired), ErrChallengeExpired
+	}
+	if challenge.Status != domain.ChallengePending {
+		return challenge, ErrChallengeClosed
+	}
+	return challenge, nil
+}
+
+// resolve encerra o desafio com o estado informado. Deve ser chamado com mutex travado.
+func (service *ChallengeService) resolve(challenge domain.Challenge, status string) domain.Challenge {
+	challenge.Status = status
+	challenge.ResolvedAt = time.Now().UTC()
+	service.challenges.Set(challenge.ID, challenge)
+	return challenge
+}
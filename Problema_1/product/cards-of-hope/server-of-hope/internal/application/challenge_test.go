@@ -0,0 +1,235 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestChallengeService cria um serviço de desafios com alice, bob, dave e carol, que está
+// desconectada, e um bot.
+func newTestChallengeService(t *testing.T) (*ChallengeService, *RoomService, *data.InMemoryRepository[domain.Room]) {
+	t.Helper()
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	for _, user := range []domain.User{{ID: "alice"}, {ID: "bob"}, {ID: "carol"}, {ID: "dave"}, {ID: "bot", Bot: true}} {
+		userRepo.Create(user.ID, user)
+	}
+	rulesets := newTestRulesets(t)
+	roomRepo := data.NewInMemoryRepository[domain.Room]()
+	rooms := NewRoomService(roomRepo, rulesets, &sync.Mutex{})
+	return NewChallengeService(userRepo, rulesets, rooms), rooms, roomRepo
+}
+
+// onlineButCarol considera conectados todos os usuários menos carol.
+func onlineButCarol(userID string) bool {
+	return userID != "carol"
+}
+
+// sendChallenge registra um desafio de fromID a toID com as regras padrão.
+func sendChallenge(t *testing.T, service *ChallengeService, fromID, toID string) domain.Challenge {
+	t.Helper()
+	challenge, err := service.Challenge(fromID, toID, ChallengeOptions{}, onlineButCarol)
+	if err != nil {
+		t.Fatalf("Challenge(%s, %s): %v", fromID, toID, err)
+	}
+	return challenge
+}
+
+// expireChallenge faz o desafio vencer sem esperar ChallengeTTL.
+func expireChallenge(service *ChallengeService, challenge domain.Challenge) {
+	challenge.ExpiresAt = time.Now().Add(-time.Second)
+	service.challenges.Set(challenge.ID, challenge)
+}
+
+func TestChallenge(t *testing.T) {
+	tests := []struct {
+		name    string
+		toID    string
+		options ChallengeOptions
+		prepare func(t *testing.T, service *ChallengeService, rooms *RoomService, roomRepo *data.InMemoryRepository[domain.Room])
+		wantErr error
+	}{
+		{name: "regras padrão", toID: "bob"},
+		{name: "outras regras com jogadas fechadas", toID: "bob", options: ChallengeOptions{Ruleset: "rpsls", CommitReveal: true}},
+		{name: "a si mesmo", toID: "alice", wantErr: ErrChallengeSelf},
+		{name: "usuário desconhecido", toID: "erin", wantErr: ErrChallengeUnknownUser},
+		{name: "bot", toID: "bot", wantErr: ErrChallengeBot},
+		{name: "usuário desconectado", toID: "carol", wantErr: ErrChallengeOffline},
+		{name: "regras desconhecidas", toID: "bob", options: ChallengeOptions{Ruleset: "chess"}, wantErr: ErrUnknownRuleset},
+		{
+			name: "desafio pendente no sentido contrário",
+			toID: "bob",
+			prepare: func(t *testing.T, service *ChallengeService, rooms *RoomService, roomRepo *data.InMemoryRepository[domain.Room]) {
+				sendChallenge(t, service, "bob", "alice")
+			},
+			wantErr: ErrChallengePending,
+		},
+		{
+			name: "desafio anterior vencido",
+			toID: "bob",
+			prepare: func(t *testing.T, service *ChallengeService, rooms *RoomService, roomRepo *data.InMemoryRepository[domain.Room]) {
+				expireChallenge(service, sendChallenge(t, service, "alice", "bob"))
+			},
+		},
+		{
+			name: "desafiado no meio de uma partida",
+			toID: "bob",
+			prepare: func(t *testing.T, service *ChallengeService, rooms *RoomService, roomRepo *data.InMemoryRepository[domain.Room]) {
+				roomID, err := rooms.CreateRoom("bob", RoomOptions{})
+				if err != nil {
+					t.Fatalf("CreateRoom: %v", err)
+				}
+				room, _ := roomRepo.Read(roomID)
+				room.Status = domain.RoomStatusPlaying
+				roomRepo.Update(roomID, room)
+			},
+			wantErr: ErrChallengeBusy,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, rooms, roomRepo := newTestChallengeService(t)
+			if test.prepare != nil {
+				test.prepare(t, service, rooms, roomRepo)
+			}
+			challenge, err := service.Challenge("alice", test.toID, test.options, onlineButCarol)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			wantRuleset := test.options.Ruleset
+			if wantRuleset == "" {
+				wantRuleset = domain.DefaultRulesetID
+			}
+			if challenge.Status != domain.ChallengePending || challenge.RulesetID != wantRuleset || challenge.CommitReveal != test.options.CommitReveal {
+				t.Errorf("desafio %s com regras %q e jogadas fechadas %v", challenge.Status, challenge.RulesetID, challenge.CommitReveal)
+			}
+			if got := challenge.ExpiresAt.Sub(challenge.CreatedAt); got != ChallengeTTL {
+				t.Errorf("validade = %v, esperado %v", got, ChallengeTTL)
+			}
+		})
+	}
+}
+
+func TestAcceptChallenge(t *testing.T) {
+	service, rooms, _ := newTestChallengeService(t)
+	sent, err := service.Challenge("alice", "bob", ChallengeOptions{Ruleset: "underdog", CommitReveal: true}, onlineButCarol)
+	if err != nil {
+		t.Fatalf("Challenge: %v", err)
+	}
+	if _, err := service.Accept(sent.ID, "alice"); !errors.Is(err, ErrNotChallenged) {
+		t.Fatalf("aceite de quem desafiou: erro = %v, esperado %v", err, ErrNotChallenged)
+	}
+
+	accepted, err := service.Accept(sent.ID, "bob")
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if accepted.Status != domain.ChallengeAccepted || accepted.RoomID == "" {
+		t.Fatalf("desafio %s com sala %q", accepted.Status, accepted.RoomID)
+	}
+	room, err := rooms.GetRoom(accepted.RoomID)
+	if err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+	if !room.Private || room.HostID != "alice" || room.RulesetID != "underdog" || !room.CommitReveal {
+		t.Errorf("sala privada %v, anfitrião %q, regras %q, jogadas fechadas %v", room.Private, room.HostID, room.RulesetID, room.CommitReveal)
+	}
+	if !room.UserIDs.Contains("alice") || !room.UserIDs.Contains("bob") {
+		t.Errorf("jogadores da sala = %v", room.UserIDs.Items())
+	}
+
+	if _, err := service.Accept(sent.ID, "bob"); !errors.Is(err, ErrChallengeClosed) {
+		t.Errorf("segundo aceite: erro = %v, esperado %v", err, ErrChallengeClosed)
+	}
+	if _, err := service.Accept("404", "bob"); !errors.Is(err, ErrChallengeNotFound) {
+		t.Errorf("desafio inexistente: erro = %v, esperado %v", err, ErrChallengeNotFound)
+	}
+}
+
+func TestAnswerChallenge(t *testing.T) {
+	tests := []struct {
+		name       string
+		answer     func(service *ChallengeService, challengeID, userID string) (domain.Challenge, error)
+		userID     string
+		expired    bool
+		wantErr    error
+		wantStatus string
+	}{
+		{name: "recusa", answer: (*ChallengeService).Decline, userID: "bob", wantStatus: domain.ChallengeDeclined},
+		{name: "recusa de quem desafiou", answer: (*ChallengeService).Decline, userID: "alice", wantErr: ErrNotChallenged, wantStatus: domain.ChallengePending},
+		{name: "cancelamento", answer: (*ChallengeService).Cancel, userID: "alice", wantStatus: domain.ChallengeCancelled},
+		{name: "cancelamento do desafiado", answer: (*ChallengeService).Cancel, userID: "bob", wantErr: ErrNotChallenger, wantStatus: domain.ChallengePending},
+		{name: "aceite vencido", answer: (*ChallengeService).Accept, userID: "bob", expired: true, wantErr: ErrChallengeExpired, wantStatus: domain.ChallengeExpired},
+		{name: "recusa vencida", answer: (*ChallengeService).Decline, userID: "bob", expired: true, wantErr: ErrChallengeExpired, wantStatus: domain.ChallengeExpired},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _, _ := newTestChallengeService(t)
+			sent := sendChallenge(t, service, "alice", "bob")
+			if test.expired {
+				expireChallenge(service, sent)
+			}
+			answered, err := test.answer(service, sent.ID, test.userID)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if answered.Status != test.wantStatus {
+				t.Errorf("estado = %s, esperado %s", answered.Status, test.wantStatus)
+			}
+			if answered.Status != domain.ChallengePending && answered.ResolvedAt.IsZero() {
+				t.Error("desafio encerrado sem ResolvedAt")
+			}
+		})
+	}
+}
+
+// challengeIDs lista os IDs dos desafios na ordem recebida.
+func challengeIDs(challenges []domain.Challenge) []string {
+	ids := make([]string, 0, len(challenges))
+	for _, challenge := range challenges {
+		ids = append(ids, challenge.ID)
+	}
+	return ids
+}
+
+func TestListExpireAndWithdrawChallenges(t *testing.T) {
+	service, _, _ := newTestChallengeService(t)
+	older := sendChallenge(t, service, "alice", "bob")
+	older.CreatedAt = older.CreatedAt.Add(-time.Second)
+	service.challenges.Set(older.ID, older)
+	newer := sendChallenge(t, service, "dave", "alice")
+	other := sendChallenge(t, service, "bob", "dave")
+
+	if got := challengeIDs(service.ListChallenges("alice")); !equalStrings(got, []string{newer.ID, older.ID}) {
+		t.Errorf("desafios de alice = %v, esperado %v", got, []string{newer.ID, older.ID})
+	}
+
+	expireChallenge(service, older)
+	if got := challengeIDs(service.ListChallenges("alice")); !equalStrings(got, []string{newer.ID}) {
+		t.Errorf("desafios de alice depois do prazo = %v, esperado %v", got, []string{newer.ID})
+	}
+	if _, err := service.Decline(newer.ID, "alice"); err != nil {
+		t.Fatalf("Decline: %v", err)
+	}
+	expired := service.Expire()
+	if got := challengeIDs(expired); !equalStrings(got, []string{older.ID}) || expired[0].Status != domain.ChallengeExpired {
+		t.Errorf("desafios vencidos = %v, esperado %v", got, []string{older.ID})
+	}
+	if _, exists := service.challenges.Get(newer.ID); exists {
+		t.Error("o desafio recusado não foi descartado")
+	}
+
+	cancelled := service.Withdraw("dave")
+	if got := challengeIDs(cancelled); !equalStrings(got, []string{other.ID}) || cancelled[0].Status != domain.ChallengeCancelled {
+		t.Errorf("desafios retirados = %v, esperado %v", got, []string{other.ID})
+	}
+	if got := service.ListChallenges("bob"); len(got) != 0 {
+		t.Errorf("bob ainda tem %d desafios pendentes", len(got))
+	}
+}
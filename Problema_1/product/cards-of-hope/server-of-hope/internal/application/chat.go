@@ -1,15 +1,60 @@
 package application
 
 import (
+	"errors"
+	"fmt"
 	"server-of-hope/internal/data"
 	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxInboxMessages define quantas mensagens diretas podem aguardar na caixa de entrada de um usuário.
+const MaxInboxMessages = 100
+
+// DefaultHistoryLimit e MaxHistoryLimit definem quantas mensagens de uma conversa são devolvidas
+// quando o limite não é informado e, no máximo, quando é.
+const (
+	DefaultHistoryLimit = 50
+	MaxHistoryLimit     = 200
+)
+
+// MaxChannelsPerUser define em quantos canais de chat, contando o saguão, um usuário pode estar ao mesmo tempo.
+const MaxChannelsPerUser = 10
+
+// Erros das mensagens diretas e dos canais exibidos diretamente aos usuários.
+var (
+	ErrMessageSelf        = errors.New("Não é possível enviar uma mensagem para si mesmo")
+	ErrMessageUnknownUser = errors.New("Usuário não encontrado")
+	ErrMessageBot         = errors.New("Bots não recebem mensagens")
+	ErrMessageEmpty       = errors.New("A mensagem está vazia")
+	ErrMessageLength      = fmt.Errorf("A mensagem deve ter até %d caracteres", domain.MaxDirectMessageLength)
+	ErrInboxFull          = errors.New("A caixa de entrada do usuário está cheia")
+	ErrChannelName        = fmt.Errorf("O nome do canal deve ter de 1 a %d letras minúsculas, números, - ou _", domain.MaxChannelNameLength)
+	ErrChannelNotFound    = errors.New("Canal não encontrado")
+	ErrChannelMember      = errors.New("Você não está no canal")
+	ErrChannelLimit       = fmt.Errorf("Você pode estar em até %d canais ao mesmo tempo", MaxChannelsPerUser)
 )
 
 // ChatServiceInterface descreve as operações para envio e recebimento de mensagens em salas de chat.
 //
 // Métodos:
 //   - SendMessage: envia mensagem para todos da sala, exceto o remetente.
+//   - SendSpectatorMessage: envia mensagem apenas para os espectadores da sala.
 //   - ReceiveMessage: recebe mensagem para um usuário específico.
+//   - SendDirect: envia uma mensagem direta para outro usuário.
+//   - MarkDelivered: marca uma mensagem direta como entregue.
+//   - Inbox: retira as mensagens diretas que aguardam o usuário.
+//   - History: lista as mensagens de uma conversa entre dois usuários.
+//   - JoinChannel: entra em um canal de chat, criando-o se não existir.
+//   - LeaveChannel: sai de um canal de chat.
+//   - LeaveChannels: tira o usuário de todos os canais.
+//   - Channels: lista os canais de chat.
+//   - GetChannel: recupera um canal de chat.
+//   - SendChannelMessage: envia uma mensagem para os membros de um canal.
 type ChatServiceInterface interface {
 	// SendMessage envia uma mensagem para todos os usuários da sala, exceto o remetente.
 	//
@@ -22,6 +67,17 @@ type ChatServiceInterface interface {
 	//   - erro caso não seja possível enviar a mensagem.
 	SendMessage(roomID, userID, message string) error
 
+	// SendSpectatorMessage envia uma mensagem de um espectador apenas para os demais espectadores.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - userID: identificador do espectador remetente.
+	//   - message: mensagem a ser enviada.
+	//
+	// Retorno:
+	//   - erro caso o remetente não seja espectador da sala.
+	SendSpectatorMessage(roomID, userID, message string) error
+
 	// ReceiveMessage recebe uma mensagem para um usuário específico na sala.
 	//
 	// Parâmetros:
@@ -32,28 +88,147 @@ type ChatServiceInterface interface {
 	//   - string: mensagem recebida.
 	//   - erro caso não seja possível receber a mensagem.
 	ReceiveMessage(roomID, userID string) (string, error)
+
+	// SendDirect envia uma mensagem direta para outro usuário. A mensagem fica na caixa de
+	// entrada do destinatário até ser marcada como entregue.
+	//
+	// Parâmetros:
+	//   - fromID: usuário que envia a mensagem.
+	//   - toID: nome do usuário destinatário.
+	//   - text: texto da mensagem.
+	//
+	// Retorno:
+	//   - domain.DirectMessage: mensagem enviada.
+	//   - erro caso o destinatário não exista, seja um bot ou o próprio remetente, o texto seja
+	//     vazio ou longo demais ou a caixa de entrada do destinatário esteja cheia.
+	SendDirect(fromID, toID, text string) (domain.DirectMessage, error)
+
+	// MarkDelivered marca uma mensagem direta como entregue, tirando-a da caixa de entrada.
+	//
+	// Parâmetros:
+	//   - messageID: identificador da mensagem.
+	//
+	// Retorno:
+	//   - erro caso a mensagem não exista.
+	MarkDelivered(messageID string) error
+
+	// Inbox retira da caixa de entrada as mensagens diretas que aguardam o usuário, marcando-as
+	// como entregues.
+	//
+	// Parâmetros:
+	//   - userID: usuário destinatário.
+	//
+	// Retorno:
+	//   - []domain.DirectMessage: mensagens na ordem de envio.
+	//   - erro caso as mensagens não possam ser lidas.
+	Inbox(userID string) ([]domain.DirectMessage, error)
+
+	// History lista as mensagens mais recentes da conversa entre dois usuários.
+	//
+	// Parâmetros:
+	//   - userID: usuário que consulta a conversa.
+	//   - peerID: outro usuário da conversa.
+	//   - limit: quantidade máxima de mensagens (zero usa DefaultHistoryLimit).
+	//
+	// Retorno:
+	//   - []domain.DirectMessage: mensagens na ordem de envio.
+	//   - erro caso o outro usuário não exista.
+	History(userID, peerID string, limit int) ([]domain.DirectMessage, error)
+
+	// JoinChannel coloca o usuário em um canal de chat. Um canal de assunto que ainda não
+	// existe é criado, com o usuário como dono.
+	//
+	// Parâmetros:
+	//   - userID: usuário que entra no canal.
+	//   - name: nome do canal (letras minúsculas, números, - e _).
+	//
+	// Retorno:
+	//   - domain.Channel: canal, já com o usuário entre os membros.
+	//   - bool: o canal foi criado agora.
+	//   - erro caso o nome seja inválido ou o usuário já esteja em MaxChannelsPerUser canais.
+	JoinChannel(userID, name string) (domain.Channel, bool, error)
+
+	// LeaveChannel tira o usuário de um canal de chat. O canal de assunto que fica sem membros
+	// deixa de existir.
+	//
+	// Parâmetros:
+	//   - userID: usuário que sai do canal.
+	//   - name: nome do canal.
+	//
+	// Retorno:
+	//   - domain.Channel: canal, já sem o usuário.
+	//   - erro caso o canal não exista ou o usuário não esteja nele.
+	LeaveChannel(userID, name string) (domain.Channel, error)
+
+	// LeaveChannels tira o usuário de todos os canais em que está, como quando ele se desconecta.
+	//
+	// Parâmetros:
+	//   - userID: usuário que sai dos canais.
+	//
+	// Retorno:
+	//   - []domain.Channel: canais de que o usuário saiu, já sem ele.
+	LeaveChannels(userID string) []domain.Channel
+
+	// Channels lista os canais de chat existentes, com o saguão primeiro e os demais pelo nome.
+	//
+	// Retorno:
+	//   - []domain.Channel: canais existentes.
+	Channels() []domain.Channel
+
+	// GetChannel recupera um canal de chat.
+	//
+	// Parâmetros:
+	//   - name: nome do canal.
+	//
+	// Retorno:
+	//   - domain.Channel: canal encontrado.
+	//   - erro caso o canal não exista.
+	GetChannel(name string) (domain.Channel, error)
+
+	// SendChannelMessage confere uma mensagem para um canal de chat e devolve o canal, para que
+	// a mensagem seja entregue aos demais membros.
+	//
+	// Parâmetros:
+	//   - userID: membro do canal que envia a mensagem.
+	//   - name: nome do canal.
+	//   - text: texto da mensagem.
+	//
+	// Retorno:
+	//   - domain.Channel: canal da mensagem.
+	//   - string: texto da mensagem, sem os espaços das pontas.
+	//   - erro caso o canal não exista, o usuário não esteja nele ou o texto seja vazio ou longo demais.
+	SendChannelMessage(userID, name, text string) (domain.Channel, string, error)
 }
 
-// ChatService implementa a lógica de chat entre usuários em salas.
+// ChatService implementa a lógica de chat entre usuários em salas e das mensagens diretas.
 //
 // Campos:
 //   - RoomRepo: repositório das salas.
 //   - UserRepo: repositório dos usuários.
+//   - MessageRepo: repositório das mensagens diretas.
+//   - channels: canais de chat, indexados pelo nome; ficam só na memória.
+//   - mutex: serializa as mudanças nas caixas de entrada e nos canais.
 type ChatService struct {
-	RoomRepo data.RepositoryInterface[domain.Room]
-	UserRepo data.RepositoryInterface[domain.User]
+	RoomRepo    data.RepositoryInterface[domain.Room]
+	UserRepo    data.RepositoryInterface[domain.User]
+	MessageRepo data.RepositoryInterface[domain.DirectMessage]
+	channels    *utils.Map[string, domain.Channel]
+	mutex       sync.Mutex
 }
 
-// NewChatService cria uma nova instância de ChatService.
+// NewChatService cria uma nova instância de ChatService, já com o saguão global.
 //
 // Parâmetros:
 //   - roomRepo: repositório das salas.
 //   - userRepo: repositório dos usuários.
+//   - messageRepo: repositório das mensagens diretas.
 //
 // Retorno:
 //   - ponteiro para ChatService.
-func NewChatService(roomRepo data.RepositoryInterface[domain.Room], userRepo data.RepositoryInterface[domain.User]) *ChatService {
-	return &ChatService{RoomRepo: roomRepo, UserRepo: userRepo}
+func NewChatService(roomRepo data.RepositoryInterface[domain.Room], userRepo data.RepositoryInterface[domain.User], messageRepo data.RepositoryInterface[domain.DirectMessage]) *ChatService {
+	channels := utils.NewMap[string, domain.Channel]()
+	channels.Set(domain.LobbyChannel, *domain.NewChannel(domain.LobbyChannel, ""))
+	return &ChatService{RoomRepo: roomRepo, UserRepo: userRepo, MessageRepo: messageRepo, channels: channels}
 }
 
 // SendMessage envia uma mensagem para todos os usuários da sala, exceto o remetente.
@@ -70,17 +245,55 @@ func (service *ChatService) SendMessage(roomID, userID, message string) error {
 	if err != nil {
 		return err // Sala não encontrada
 	}
-	if !room.UserIDs.Contains(userID) {
-		return err // Usuário não está na sala
+	if !room.IsMember(userID) {
+		return errors.New("usuário não está na sala")
 	}
 	room.Messages.ForEach(func(id string, messages chan string) {
 		if id != userID {
-			messages <- message
+			deliver(room, id, messages, message)
 		}
 	})
 	return nil
 }
 
+// SendSpectatorMessage envia uma mensagem de um espectador apenas para os demais espectadores.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//   - userID: identificador do espectador remetente.
+//   - message: mensagem a ser enviada.
+//
+// Retorno:
+//   - erro caso o remetente não seja espectador da sala.
+func (service *ChatService) SendSpectatorMessage(roomID, userID, message string) error {
+	room, err := service.RoomRepo.Read(roomID)
+	if err != nil {
+		return err // Sala não encontrada
+	}
+	if !room.IsSpectator(userID) {
+		return errors.New("apenas espectadores podem usar o chat dos espectadores")
+	}
+	room.Messages.ForEach(func(id string, messages chan string) {
+		if id != userID && room.IsSpectator(id) {
+			deliver(room, id, messages, message)
+		}
+	})
+	return nil
+}
+
+// deliver entrega uma mensagem no canal de um membro da sala. Jogadores aguardam espaço no canal;
+// para espectadores a entrega não bloqueia, e a mensagem é descartada se o canal estiver cheio.
+func deliver(room domain.Room, userID string, messages chan string, message string) {
+	if !room.IsSpectator(userID) {
+		messages <- message
+		return
+	}
+	select {
+	case messages <- message:
+	default:
+	}
+}
+
 // ReceiveMessage recebe uma mensagem para um usuário específico na sala, se disponível.
 //
 // Parâmetros:
@@ -95,8 +308,8 @@ func (service *ChatService) ReceiveMessage(roomID, userID string) (string, error
 	if err != nil {
 		return "", err // Sala não encontrada
 	}
-	if !room.UserIDs.Contains(userID) {
-		return "", err // Usuário não está na sala
+	if !room.IsMember(userID) {
+		return "", errors.New("usuário não está na sala")
 	}
 	messages, exists := room.Messages.Get(userID)
 	if !exists {
@@ -109,3 +322,247 @@ func (service *ChatService) ReceiveMessage(roomID, userID string) (string, error
 		return "", nil // Nenhuma mensagem disponível
 	}
 }
+
+// SendDirect envia uma mensagem direta, que fica na caixa de entrada do destinatário até ser entregue.
+func (service *ChatService) SendDirect(fromID, toID, text string) (domain.DirectMessage, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return domain.DirectMessage{}, ErrMessageEmpty
+	}
+	if len([]rune(text)) > domain.MaxDirectMessageLength {
+		return domain.DirectMessage{}, ErrMessageLength
+	}
+	if fromID == toID {
+		return domain.DirectMessage{}, ErrMessageSelf
+	}
+	recipient, err := service.UserRepo.Read(toID)
+	if err != nil {
+		return domain.DirectMessage{}, ErrMessageUnknownUser
+	}
+	if recipient.Bot {
+		return domain.DirectMessage{}, ErrMessageBot
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	messages, err := service.MessageRepo.List()
+	if err != nil {
+		return domain.DirectMessage{}, err
+	}
+	waiting := 0
+	for _, message := range messages {
+		if message.ToID == toID && !message.Delivered {
+			waiting++
+		}
+	}
+	if waiting >= MaxInboxMessages {
+		return domain.DirectMessage{}, ErrInboxFull
+	}
+
+	message := domain.DirectMessage{
+		ID:     utils.Count(),
+		FromID: fromID,
+		ToID:   toID,
+		Text:   text,
+		SentAt: time.Now().UTC(),
+	}
+	if err := service.MessageRepo.Create(message.ID, message); err != nil {
+		return domain.DirectMessage{}, err
+	}
+	return message, nil
+}
+
+// MarkDelivered marca uma mensagem direta como entregue.
+func (service *ChatService) MarkDelivered(messageID string) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	message, err := service.MessageRepo.Read(messageID)
+	if err != nil {
+		return err
+	}
+	message.Delivered = true
+	return service.MessageRepo.Update(message.ID, message)
+}
+
+// Inbox retira as mensagens diretas que aguardam o usuário, marcando-as como entregues.
+func (service *ChatService) Inbox(userID string) ([]domain.DirectMessage, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	messages, err := service.MessageRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var inbox []domain.DirectMessage
+	for _, message := range messages {
+		if message.ToID != userID || message.Delivered {
+			continue
+		}
+		message.Delivered = true
+		if err := service.MessageRepo.Update(message.ID, message); err != nil {
+			return nil, err
+		}
+		inbox = append(inbox, message)
+	}
+	sortMessages(inbox)
+	return inbox, nil
+}
+
+// History lista as mensagens mais recentes da conversa entre dois usuários, na ordem de envio.
+func (service *ChatService) History(userID, peerID string, limit int) ([]domain.DirectMessage, error) {
+	if _, err := service.UserRepo.Read(peerID); err != nil {
+		return nil, ErrMessageUnknownUser
+	}
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	limit = min(limit, MaxHistoryLimit)
+
+	messages, err := service.MessageRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var conversation []domain.DirectMessage
+	for _, message := range messages {
+		if message.Between(userID, peerID) {
+			conversation = append(conversation, message)
+		}
+	}
+	sortMessages(conversation)
+	if len(conversation) > limit {
+		conversation = conversation[len(conversation)-limit:]
+	}
+	return conversation, nil
+}
+
+// JoinChannel coloca o usuário em um canal de chat, criando o canal de assunto que não existe.
+func (service *ChatService) JoinChannel(userID, name string) (domain.Channel, bool, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !validChannelName(name) {
+		return domain.Channel{}, false, ErrChannelName
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	channel, exists := service.channels.Get(name)
+	if exists && channel.Members.Contains(userID) {
+		return channel, false, nil
+	}
+	joined := 0
+	service.channels.ForEach(func(_ string, other domain.Channel) {
+		if other.Members.Contains(userID) {
+			joined++
+		}
+	})
+	if joined >= MaxChannelsPerUser {
+		return domain.Channel{}, false, ErrChannelLimit
+	}
+	if !exists {
+		channel = *domain.NewChannel(name, userID)
+	}
+	channel.Members.Add(userID)
+	service.channels.Set(name, channel)
+	return channel, !exists, nil
+}
+
+// LeaveChannel tira o usuário de um canal de chat, apagando o canal de assunto que fica vazio.
+func (service *ChatService) LeaveChannel(userID, name string) (domain.Channel, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	channel, exists := service.channels.Get(strings.ToLower(strings.TrimSpace(name)))
+	if !exists {
+		return domain.Channel{}, ErrChannelNotFound
+	}
+	if !channel.Members.Contains(userID) {
+		return domain.Channel{}, ErrChannelMember
+	}
+	service.removeFromChannel(channel, userID)
+	return channel, nil
+}
+
+// LeaveChannels tira o usuário de todos os canais em que está.
+func (service *ChatService) LeaveChannels(userID string) []domain.Channel {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	var left []domain.Channel
+	for _, channel := range service.channels.Values() {
+		if channel.Members.Contains(userID) {
+			service.removeFromChannel(channel, userID)
+			left = append(left, channel)
+		}
+	}
+	return left
+}
+
+// removeFromChannel tira o membro do canal e apaga o canal de assunto vazio. Deve ser chamado com mutex travado.
+func (service *ChatService) removeFromChannel(channel domain.Channel, userID string) {
+	channel.Members.Remove(userID)
+	if channel.Members.Size() == 0 && !channel.Permanent() {
+		service.channels.Delete(channel.Name)
+	}
+}
+
+// Channels lista os canais de chat, com o saguão primeiro e os demais pelo nome.
+func (service *ChatService) Channels() []domain.Channel {
+	channels := service.channels.Values()
+	sort.Slice(channels, func(i, j int) bool {
+		if channels[i].Permanent() != channels[j].Permanent() {
+			return channels[i].Permanent()
+		}
+		return channels[i].Name < channels[j].Name
+	})
+	return channels
+}
+
+// GetChannel recupera um canal de chat pelo nome.
+func (service *ChatService) GetChannel(name string) (domain.Channel, error) {
+	channel, exists := service.channels.Get(strings.ToLower(strings.TrimSpace(name)))
+	if !exists {
+		return domain.Channel{}, ErrChannelNotFound
+	}
+	return channel, nil
+}
+
+// SendChannelMessage confere a mensagem de um membro para um canal de chat.
+func (service *ChatService) SendChannelMessage(userID, name, text string) (domain.Channel, string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return domain.Channel{}, "", ErrMessageEmpty
+	}
+	if len([]rune(text)) > domain.MaxDirectMessageLength {
+		return domain.Channel{}, "", ErrMessageLength
+	}
+	channel, err := service.GetChannel(name)
+	if err != nil {
+		return domain.Channel{}, "", err
+	}
+	if !channel.Members.Contains(userID) {
+		return domain.Channel{}, "", ErrChannelMember
+	}
+	return channel, text, nil
+}
+
+// validChannelName informa se o nome de canal tem só letras minúsculas, números, - e _, dentro do tamanho máximo.
+func validChannelName(name string) bool {
+	if name == "" || len(name) > domain.MaxChannelNameLength {
+		return false
+	}
+	for _, char := range name {
+		if (char < 'a' || char > 'z') && (char < '0' || char > '9') && char != '-' && char != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// sortMessages ordena as mensagens diretas pelo momento do envio.
+func sortMessages(messages []domain.DirectMessage) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].SentAt.Before(messages[j].SentAt)
+	})
+}
@@ -0,0 +1,389 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newChatRoom cria uma sala com os jogadores e espectadores informados e um serviço de chat sobre ela.
+func newChatRoom(t *testing.T, players, spectators []string) *ChatService {
+	t.Helper()
+	roomRepo := data.NewInMemoryRepository[domain.Room]()
+	room := domain.NewRoom("7", "Sala 7", players[0])
+	for _, userID := range players {
+		room.UserIDs.Add(userID)
+		room.Messages.Set(userID, make(chan string, 1))
+	}
+	for _, userID := range spectators {
+		room.Spectators.Add(userID)
+		room.Messages.Set(userID, make(chan string, domain.SpectatorMessageBuffer))
+	}
+	roomRepo.Create(room.ID, *room)
+	return NewChatService(roomRepo, data.NewInMemoryRepository[domain.User](), data.NewInMemoryRepository[domain.DirectMessage]())
+}
+
+func TestRoomChatChannels(t *testing.T) {
+	tests := []struct {
+		name     string
+		senderID string
+		channel  string
+		wantErr  bool
+		want     map[string]string
+	}{
+		{
+			name:     "jogador fala com a sala toda",
+			senderID: "alice",
+			channel:  "room",
+			want:     map[string]string{"alice": "", "bob": "oi", "carol": "oi", "dave": "oi"},
+		},
+		{
+			name:     "espectador fala com a sala toda",
+			senderID: "carol",
+			channel:  "room",
+			want:     map[string]string{"alice": "oi", "bob": "oi", "carol": "", "dave": "oi"},
+		},
+		{
+			name:     "chat dos espectadores não chega aos jogadores",
+			senderID: "carol",
+			channel:  "spectators",
+			want:     map[string]string{"alice": "", "bob": "", "carol": "", "dave": "oi"},
+		},
+		{name: "jogador no chat dos espectadores", senderID: "alice", channel: "spectators", wantErr: true},
+		{name: "quem não está na sala", senderID: "erin", channel: "room", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newChatRoom(t, []string{"alice", "bob"}, []string{"carol", "dave"})
+			send := service.SendMessage
+			if test.channel == "spectators" {
+				send = service.SendSpectatorMessage
+			}
+			if err := send("7", test.senderID, "oi"); (err != nil) != test.wantErr {
+				t.Fatalf("erro = %v, esperado erro: %v", err, test.wantErr)
+			}
+			for userID, want := range test.want {
+				if got, err := service.ReceiveMessage("7", userID); err != nil || got != want {
+					t.Errorf("%s recebeu %q (erro %v), esperado %q", userID, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpectatorChatOverflow(t *testing.T) {
+	// Um espectador que não lê as mensagens não pode travar quem fala na sala.
+	service := newChatRoom(t, []string{"alice"}, []string{"carol"})
+	for range domain.SpectatorMessageBuffer + 4 {
+		if err := service.SendMessage("7", "alice", "oi"); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+	}
+	received := 0
+	for {
+		message, err := service.ReceiveMessage("7", "carol")
+		if err != nil {
+			t.Fatalf("ReceiveMessage: %v", err)
+		}
+		if message == "" {
+			break
+		}
+		received++
+	}
+	if received != domain.SpectatorMessageBuffer {
+		t.Errorf("%d mensagens recebidas, esperado %d", received, domain.SpectatorMessageBuffer)
+	}
+}
+
+// newTestChatService cria um serviço de chat sem salas, com alice, bob, carol e um bot.
+func newTestChatService() (*ChatService, *data.InMemoryRepository[domain.DirectMessage]) {
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	for _, user := range []domain.User{{ID: "alice"}, {ID: "bob"}, {ID: "carol"}, {ID: "bot", Bot: true}} {
+		userRepo.Create(user.ID, user)
+	}
+	messageRepo := data.NewInMemoryRepository[domain.DirectMessage]()
+	return NewChatService(data.NewInMemoryRepository[domain.Room](), userRepo, messageRepo), messageRepo
+}
+
+// storeMessages grava mensagens de fromID para toID com o texto igual à ordem de envio, a partir
+// de 1, e um minuto entre elas.
+func storeMessages(messageRepo *data.InMemoryRepository[domain.DirectMessage], fromID, toID string, count int, delivered bool) {
+	start := time.Now().UTC().Add(-time.Hour)
+	existing, _ := messageRepo.List()
+	for index := range count {
+		id := strconv.Itoa(len(existing) + index + 1)
+		messageRepo.Create(id, domain.DirectMessage{
+			ID:        id,
+			FromID:    fromID,
+			ToID:      toID,
+			Text:      strconv.Itoa(index + 1),
+			SentAt:    start.Add(time.Duration(len(existing)+index) * time.Minute),
+			Delivered: delivered,
+		})
+	}
+}
+
+// messageTexts lista os textos das mensagens na ordem recebida.
+func messageTexts(messages []domain.DirectMessage) []string {
+	texts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		texts = append(texts, message.Text)
+	}
+	return texts
+}
+
+func TestSendDirect(t *testing.T) {
+	tests := []struct {
+		name     string
+		toID     string
+		text     string
+		waiting  int
+		wantErr  error
+		wantText string
+	}{
+		{name: "mensagem entregue sem os espaços das pontas", toID: "bob", text: "  oi, bob  ", wantText: "oi, bob"},
+		{name: "mensagem no tamanho máximo", toID: "bob", text: strings.Repeat("á", domain.MaxDirectMessageLength), wantText: strings.Repeat("á", domain.MaxDirectMessageLength)},
+		{name: "mensagem vazia", toID: "bob", text: "   ", wantErr: ErrMessageEmpty},
+		{name: "mensagem longa demais", toID: "bob", text: strings.Repeat("a", domain.MaxDirectMessageLength+1), wantErr: ErrMessageLength},
+		{name: "para si mesmo", toID: "alice", text: "oi", wantErr: ErrMessageSelf},
+		{name: "usuário desconhecido", toID: "dave", text: "oi", wantErr: ErrMessageUnknownUser},
+		{name: "bot", toID: "bot", text: "oi", wantErr: ErrMessageBot},
+		{name: "caixa de entrada cheia", toID: "bob", text: "oi", waiting: MaxInboxMessages, wantErr: ErrInboxFull},
+		{name: "caixa de entrada quase cheia", toID: "bob", text: "oi", waiting: MaxInboxMessages - 1, wantText: "oi"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, messageRepo := newTestChatService()
+			storeMessages(messageRepo, "carol", test.toID, test.waiting, false)
+			// As mensagens já entregues não ocupam a caixa de entrada.
+			storeMessages(messageRepo, "carol", test.toID, MaxInboxMessages, true)
+
+			message, err := service.SendDirect("alice", test.toID, test.text)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if message.Text != test.wantText || message.FromID != "alice" || message.ToID != test.toID || message.Delivered {
+				t.Errorf("mensagem = %+v", message)
+			}
+			if _, err := messageRepo.Read(message.ID); err != nil {
+				t.Errorf("mensagem não gravada: %v", err)
+			}
+		})
+	}
+}
+
+func TestInbox(t *testing.T) {
+	service, messageRepo := newTestChatService()
+	storeMessages(messageRepo, "alice", "bob", 1, true)
+	storeMessages(messageRepo, "carol", "bob", 2, false)
+	storeMessages(messageRepo, "bob", "alice", 1, false)
+
+	inbox, err := service.Inbox("bob")
+	if err != nil {
+		t.Fatalf("Inbox: %v", err)
+	}
+	if got := messageTexts(inbox); !equalStrings(got, []string{"1", "2"}) {
+		t.Errorf("caixa de entrada de bob = %v, esperado [1 2]", got)
+	}
+	for _, message := range inbox {
+		if stored, _ := messageRepo.Read(message.ID); message.FromID != "carol" || !stored.Delivered {
+			t.Errorf("mensagem %s de %s entregue: %v", message.ID, message.FromID, stored.Delivered)
+		}
+	}
+	if inbox, _ := service.Inbox("bob"); len(inbox) != 0 {
+		t.Errorf("a segunda leitura trouxe %d mensagens", len(inbox))
+	}
+	if inbox, _ := service.Inbox("alice"); len(inbox) != 1 {
+		t.Errorf("caixa de entrada de alice com %d mensagens, esperado 1", len(inbox))
+	}
+}
+
+func TestHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		peerID  string
+		limit   int
+		want    []string
+		wantErr error
+	}{
+		{name: "conversa toda na ordem de envio", peerID: "bob", want: []string{"1", "2", "3", "1", "2"}},
+		{name: "as mais recentes", peerID: "bob", limit: 2, want: []string{"1", "2"}},
+		{name: "sem conversa", peerID: "bot"},
+		{name: "usuário desconhecido", peerID: "dave", wantErr: ErrMessageUnknownUser},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, messageRepo := newTestChatService()
+			storeMessages(messageRepo, "alice", "bob", 3, true)
+			storeMessages(messageRepo, "carol", "bob", 2, false)
+			storeMessages(messageRepo, "bob", "alice", 2, false)
+
+			history, err := service.History("alice", test.peerID, test.limit)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if got := messageTexts(history); !equalStrings(got, test.want) {
+				t.Errorf("conversa = %v, esperado %v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("limite máximo", func(t *testing.T) {
+		service, messageRepo := newTestChatService()
+		storeMessages(messageRepo, "alice", "bob", MaxHistoryLimit+1, true)
+		if history, _ := service.History("alice", "bob", MaxHistoryLimit+50); len(history) != MaxHistoryLimit || history[0].Text != "2" {
+			t.Errorf("conversa com %d mensagens", len(history))
+		}
+	})
+}
+
+// channelNames lista os nomes dos canais na ordem recebida.
+func channelNames(channels []domain.Channel) []string {
+	names := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		names = append(names, channel.Name)
+	}
+	return names
+}
+
+// channelList lista os nomes canal-1 a canal-n.
+func channelList(n int) []string {
+	names := make([]string, 0, n)
+	for index := 1; index <= n; index++ {
+		names = append(names, "canal-"+strconv.Itoa(index))
+	}
+	return names
+}
+
+func TestJoinChannel(t *testing.T) {
+	tests := []struct {
+		name        string
+		channel     string
+		joined      []string
+		wantErr     error
+		wantName    string
+		wantCreated bool
+	}{
+		{name: "cria o canal com o nome em minúsculas", channel: "  Xadrez_2 ", wantName: "xadrez_2", wantCreated: true},
+		{name: "entra no saguão", channel: domain.LobbyChannel, wantName: domain.LobbyChannel},
+		{name: "entra em canal existente", channel: "torneio", joined: []string{"torneio"}, wantName: "torneio"},
+		{name: "nome vazio", channel: "  ", wantErr: ErrChannelName},
+		{name: "nome com espaço", channel: "sala livre", wantErr: ErrChannelName},
+		{name: "nome com acento", channel: "canção", wantErr: ErrChannelName},
+		{name: "nome longo demais", channel: strings.Repeat("a", domain.MaxChannelNameLength+1), wantErr: ErrChannelName},
+		{name: "limite de canais", channel: "outro", joined: channelList(MaxChannelsPerUser), wantErr: ErrChannelLimit},
+		{name: "volta a canal em que já está no limite", channel: "canal-1", joined: channelList(MaxChannelsPerUser), wantName: "canal-1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestChatService()
+			for _, name := range test.joined {
+				if _, _, err := service.JoinChannel("bob", name); err != nil {
+					t.Fatalf("JoinChannel(bob, %s): %v", name, err)
+				}
+				if _, _, err := service.JoinChannel("alice", name); err != nil {
+					t.Fatalf("JoinChannel(alice, %s): %v", name, err)
+				}
+			}
+
+			channel, created, err := service.JoinChannel("alice", test.channel)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if channel.Name != test.wantName || created != test.wantCreated || !channel.Members.Contains("alice") {
+				t.Errorf("canal %q criado %v com membros %v", channel.Name, created, channel.Members.Items())
+			}
+			if created && channel.OwnerID != "alice" {
+				t.Errorf("dono = %q, esperado alice", channel.OwnerID)
+			}
+		})
+	}
+}
+
+func TestLeaveChannel(t *testing.T) {
+	service, _ := newTestChatService()
+	for _, join := range [][2]string{{"alice", domain.LobbyChannel}, {"alice", "xadrez"}, {"alice", "damas"}, {"bob", "damas"}} {
+		if _, _, err := service.JoinChannel(join[0], join[1]); err != nil {
+			t.Fatalf("JoinChannel(%s, %s): %v", join[0], join[1], err)
+		}
+	}
+
+	if _, err := service.LeaveChannel("bob", "xadrez"); !errors.Is(err, ErrChannelMember) {
+		t.Errorf("saída de quem não está no canal: erro = %v, esperado %v", err, ErrChannelMember)
+	}
+	if _, err := service.LeaveChannel("alice", "go"); !errors.Is(err, ErrChannelNotFound) {
+		t.Errorf("saída de canal inexistente: erro = %v, esperado %v", err, ErrChannelNotFound)
+	}
+	if _, err := service.LeaveChannel("alice", " XADREZ "); err != nil {
+		t.Fatalf("LeaveChannel: %v", err)
+	}
+	if _, err := service.GetChannel("xadrez"); !errors.Is(err, ErrChannelNotFound) {
+		t.Errorf("o canal vazio continuou existindo: erro = %v", err)
+	}
+
+	left := service.LeaveChannels("alice")
+	if got := channelNames(left); len(got) != 2 {
+		t.Errorf("alice saiu de %v, esperado o saguão e damas", got)
+	}
+	// O saguão fica mesmo vazio, e damas ainda tem bob.
+	if got := channelNames(service.Channels()); !equalStrings(got, []string{domain.LobbyChannel, "damas"}) {
+		t.Errorf("canais = %v, esperado [%s damas]", got, domain.LobbyChannel)
+	}
+	if channel, _ := service.GetChannel("damas"); channel.Members.Contains("alice") || !channel.Members.Contains("bob") {
+		t.Errorf("membros de damas = %v", channel.Members.Items())
+	}
+}
+
+func TestChannels(t *testing.T) {
+	service, _ := newTestChatService()
+	for _, name := range []string{"xadrez", "damas", "go"} {
+		if _, _, err := service.JoinChannel("alice", name); err != nil {
+			t.Fatalf("JoinChannel(%s): %v", name, err)
+		}
+	}
+	if got := channelNames(service.Channels()); !equalStrings(got, []string{domain.LobbyChannel, "damas", "go", "xadrez"}) {
+		t.Errorf("canais = %v", got)
+	}
+}
+
+func TestSendChannelMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		senderID string
+		channel  string
+		text     string
+		wantErr  error
+		wantText string
+	}{
+		{name: "membro", senderID: "alice", channel: "Xadrez", text: "  oi  ", wantText: "oi"},
+		{name: "quem não está no canal", senderID: "bob", channel: "xadrez", text: "oi", wantErr: ErrChannelMember},
+		{name: "canal inexistente", senderID: "alice", channel: "damas", text: "oi", wantErr: ErrChannelNotFound},
+		{name: "mensagem vazia", senderID: "alice", channel: "xadrez", text: " ", wantErr: ErrMessageEmpty},
+		{name: "mensagem longa demais", senderID: "alice", channel: "xadrez", text: strings.Repeat("a", domain.MaxDirectMessageLength+1), wantErr: ErrMessageLength},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestChatService()
+			if _, _, err := service.JoinChannel("alice", "xadrez"); err != nil {
+				t.Fatalf("JoinChannel: %v", err)
+			}
+			channel, text, err := service.SendChannelMessage(test.senderID, test.channel, test.text)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err == nil && (channel.Name != "xadrez" || text != test.wantText) {
+				t.Errorf("mensagem %q no canal %q", text, channel.Name)
+			}
+		})
+	}
+}
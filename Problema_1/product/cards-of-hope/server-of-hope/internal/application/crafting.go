@@ -0,0 +1,204 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"strings"
+)
+
+// MaxDisenchantCards define quantas cartas podem ser desfeitas de uma vez.
+const MaxDisenchantCards = 24
+
+// Erros da criação e do desmanche de cartas.
+var (
+	ErrCraftRecipe     = errors.New("As cartas não seguem nenhuma receita")
+	ErrCraftCardOwner  = errors.New("A carta não está na sua coleção")
+	ErrCraftBot        = errors.New("Bots não criam nem desfazem cartas")
+	ErrDisenchantCards = fmt.Errorf("Desfaça de 1 a %d cartas por vez", MaxDisenchantCards)
+)
+
+// CraftingServiceInterface descreve as operações de criação e desmanche de cartas.
+//
+// Métodos:
+//   - Rules: retorna as receitas de criação e os valores em pó das cartas.
+//   - Craft: consome cartas repetidas e pó para criar uma carta com mais estrelas.
+//   - Disenchant: desfaz cartas em pó de criação.
+type CraftingServiceInterface interface {
+	// Rules retorna as receitas de criação e os valores em pó das cartas.
+	//
+	// Retorno:
+	//   - domain.CraftingRules: receitas e valores em pó.
+	Rules() domain.CraftingRules
+
+	// Craft consome as cartas informadas, todas do mesmo tipo e com as estrelas de uma receita,
+	// e o pó da receita para criar uma carta com mais estrelas. As cartas saem, o pó é debitado e
+	// a carta criada entra na coleção de uma só vez; se algo falhar, nada muda.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - cardIDs: IDs das cartas da coleção consumidas.
+	//
+	// Retorno:
+	//   - domain.OwnedCard: carta criada, com o ID que recebeu na coleção.
+	//   - []domain.OwnedCard: cartas consumidas.
+	//   - int: saldo de pó depois da criação.
+	//   - erro caso as cartas não sigam uma receita, não estejam na coleção ou falte pó.
+	Craft(userID string, cardIDs []string) (domain.OwnedCard, []domain.OwnedCard, int, error)
+
+	// Disenchant desfaz as cartas informadas, creditando o valor em pó de cada uma. As cartas
+	// saem e o pó é creditado de uma só vez.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - cardIDs: IDs das cartas da coleção desfeitas.
+	//
+	// Retorno:
+	//   - []domain.OwnedCard: cartas desfeitas.
+	//   - int: pó recebido.
+	//   - int: saldo de pó depois do desmanche.
+	//   - erro caso alguma carta não esteja na coleção.
+	Disenchant(userID string, cardIDs []string) ([]domain.OwnedCard, int, int, error)
+}
+
+// CraftingService implementa a criação e o desmanche de cartas sobre as coleções e o livro-caixa.
+//
+// Campos:
+//   - rules: receitas de criação e valores em pó das cartas.
+//   - userRepo: repositório dos usuários, para recusar os bots.
+//   - deckService: coleções de cartas, de onde as cartas saem e para onde vão.
+//   - walletService: carteiras, onde o pó é lançado.
+type CraftingService struct {
+	rules         domain.CraftingRules
+	userRepo      data.RepositoryInterface[domain.User]
+	deckService   DeckServiceInterface
+	walletService WalletServiceInterface
+}
+
+// NewCraftingService cria uma nova instância de CraftingService.
+//
+// Parâmetros:
+//   - rules: receitas de criação e valores em pó das cartas.
+//   - userRepo: repositório dos usuários.
+//   - deckService: serviço das coleções de cartas.
+//   - walletService: serviço das carteiras.
+//
+// Retorno:
+//   - ponteiro para CraftingService.
+func NewCraftingService(
+	rules domain.CraftingRules,
+	userRepo data.RepositoryInterface[domain.User],
+	deckService DeckServiceInterface,
+	walletService WalletServiceInterface,
+) *CraftingService {
+	return &CraftingService{
+		rules:         rules,
+		userRepo:      userRepo,
+		deckService:   deckService,
+		walletService: walletService,
+	}
+}
+
+// Rules retorna as receitas de criação e os valores em pó das cartas.
+func (service *CraftingService) Rules() domain.CraftingRules {
+	return service.rules
+}
+
+// Craft consome cartas repetidas e pó para criar uma carta com mais estrelas. O pó é debitado com
+// a coleção travada, então a carta só é criada se o débito passar; se a coleção não puder ser
+// gravada depois do débito, o pó é devolvido.
+func (service *CraftingService) Craft(userID string, cardIDs []string) (domain.OwnedCard, []domain.OwnedCard, int, error) {
+	if err := service.checkUser(userID); err != nil {
+		return domain.OwnedCard{}, nil, 0, err
+	}
+
+	reference := strings.Join(cardIDs, ",")
+	var failure error
+	var debit *domain.LedgerEntry
+	taken, added, err := service.deckService.Transform(userID, cardIDs, func(taken []domain.OwnedCard) ([]domain.Card, error) {
+		crafted, recipe, err := service.rules.Craft(plainCards(taken))
+		if err != nil {
+			failure = fmt.Errorf("%w: %v", ErrCraftRecipe, err)
+			return nil, failure
+		}
+		if recipe.Dust > 0 {
+			entry, err := service.walletService.DebitDust(userID, recipe.Dust, domain.LedgerCraft, reference)
+			if err != nil {
+				failure = err
+				return nil, failure
+			}
+			debit = &entry
+		}
+		return []domain.Card{crafted}, nil
+	})
+	switch {
+	case err != nil && failure != nil:
+		return domain.OwnedCard{}, nil, 0, failure
+	case err != nil && debit != nil:
+		if _, refundErr := service.walletService.CreditDust(userID, -debit.Amount, domain.LedgerRefund, reference); refundErr != nil {
+			return domain.OwnedCard{}, nil, 0, errors.Join(err, refundErr)
+		}
+		return domain.OwnedCard{}, nil, 0, err
+	case err != nil:
+		return domain.OwnedCard{}, nil, 0, fmt.Errorf("%w (%v)", ErrCraftCardOwner, err)
+	}
+
+	dust, err := service.walletService.Dust(userID)
+	if err != nil {
+		return domain.OwnedCard{}, nil, 0, err
+	}
+	return added[0], taken, dust, nil
+}
+
+// Disenchant desfaz cartas em pó de criação. O pó é creditado com a coleção travada; se a coleção
+// não puder ser gravada depois do crédito, o pó é debitado de volta.
+func (service *CraftingService) Disenchant(userID string, cardIDs []string) ([]domain.OwnedCard, int, int, error) {
+	if len(cardIDs) == 0 || len(cardIDs) > MaxDisenchantCards {
+		return nil, 0, 0, ErrDisenchantCards
+	}
+	if err := service.checkUser(userID); err != nil {
+		return nil, 0, 0, err
+	}
+
+	reference := strings.Join(cardIDs, ",")
+	var failure error
+	var credit *domain.LedgerEntry
+	taken, _, err := service.deckService.Transform(userID, cardIDs, func(taken []domain.OwnedCard) ([]domain.Card, error) {
+		dust := 0
+		for _, card := range taken {
+			dust += service.rules.DustValue(card.Card)
+		}
+		entry, err := service.walletService.CreditDust(userID, dust, domain.LedgerDisenchant, reference)
+		if err != nil {
+			failure = err
+			return nil, failure
+		}
+		credit = &entry
+		return nil, nil
+	})
+	switch {
+	case err != nil && failure != nil:
+		return nil, 0, 0, failure
+	case err != nil && credit != nil:
+		if _, revertErr := service.walletService.DebitDust(userID, credit.Amount, domain.LedgerRefund, reference); revertErr != nil {
+			return nil, 0, 0, errors.Join(err, revertErr)
+		}
+		return nil, 0, 0, err
+	case err != nil:
+		return nil, 0, 0, fmt.Errorf("%w (%v)", ErrCraftCardOwner, err)
+	}
+	return taken, credit.Amount, credit.Balance, nil
+}
+
+// checkUser confere se o usuário existe e não é um bot.
+func (service *CraftingService) checkUser(userID string) error {
+	user, err := service.userRepo.Read(userID)
+	if err != nil {
+		return err
+	}
+	if user.Bot {
+		return ErrCraftBot
+	}
+	return nil
+}
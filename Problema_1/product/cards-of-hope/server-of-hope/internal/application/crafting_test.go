@@ -0,0 +1,121 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sync"
+	"testing"
+)
+
+// newTestCraftingService cria um serviço de criação com as receitas do jogo, em que alice tem
+// três pedras de 1 estrela (IDs 1 a 3) e um papel de 1 estrela (ID 4), e "bot" é um bot.
+func newTestCraftingService(t *testing.T) (*CraftingService, *WalletService, *failingRepository[domain.User]) {
+	t.Helper()
+	rules, err := data.LoadCraftingRules()
+	if err != nil {
+		t.Fatalf("carregar receitas: %v", err)
+	}
+	userRepo := newFailingRepository[domain.User]()
+	bot := collector("bot", rocks(3)...)
+	bot.Bot = true
+	for _, user := range []domain.User{collector("alice", append(rocks(3), domain.Card{Type: "paper", Stars: 1})...), bot} {
+		userRepo.Create(user.ID, user)
+	}
+	wallet := NewWalletService(data.NewInMemoryRepository[domain.LedgerEntry](), userRepo)
+	decks := NewDeckService(userRepo, data.NewInMemoryRepository[domain.Room](), newTestRulesets(t), &sync.Mutex{})
+	return NewCraftingService(rules, userRepo, decks, wallet), wallet, userRepo
+}
+
+func TestCraft(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		cardIDs   []string
+		dust      int
+		failWrite bool
+		wantErr   error
+		wantDust  int
+		wantCards []string
+	}{
+		{name: "três pedras viram uma de 2 estrelas", userID: "alice", cardIDs: cardRange(1, 3), dust: 7, wantDust: 2, wantCards: []string{"paper", "rock"}},
+		{name: "pó insuficiente", userID: "alice", cardIDs: cardRange(1, 3), dust: 4, wantErr: ErrInsufficientDust, wantDust: 4, wantCards: []string{"rock", "rock", "rock", "paper"}},
+		{name: "tipos misturados", userID: "alice", cardIDs: []string{"1", "2", "4"}, dust: 7, wantErr: ErrCraftRecipe, wantDust: 7, wantCards: []string{"rock", "rock", "rock", "paper"}},
+		{name: "carta fora da coleção", userID: "alice", cardIDs: []string{"1", "2", "9"}, dust: 7, wantErr: ErrCraftCardOwner, wantDust: 7, wantCards: []string{"rock", "rock", "rock", "paper"}},
+		{name: "coleção não gravada devolve o pó", userID: "alice", cardIDs: cardRange(1, 3), dust: 7, failWrite: true, wantErr: errWriteFailed, wantDust: 7, wantCards: []string{"rock", "rock", "rock", "paper"}},
+		{name: "bot", userID: "bot", cardIDs: cardRange(1, 3), wantErr: ErrCraftBot, wantCards: []string{"rock", "rock", "rock"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, wallet, userRepo := newTestCraftingService(t)
+			if test.dust > 0 {
+				if _, err := wallet.CreditDust(test.userID, test.dust, domain.LedgerGrant, ""); err != nil {
+					t.Fatalf("CreditDust: %v", err)
+				}
+			}
+			userRepo.failUpdate = func(string) bool { return test.failWrite }
+
+			crafted, consumed, dust, err := service.Craft(test.userID, test.cardIDs)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err == nil {
+				if crafted.Type != "rock" || crafted.Stars != 2 || len(consumed) != 3 || dust != test.wantDust {
+					t.Errorf("criou %+v com %d cartas, saldo %d", crafted, len(consumed), dust)
+				}
+			}
+			if test.userID != "bot" {
+				if balance, _ := wallet.Dust(test.userID); balance != test.wantDust {
+					t.Errorf("saldo de pó = %d, esperado %d", balance, test.wantDust)
+				}
+			}
+			if got := collectionTypes(t, userRepo, test.userID); !equalStrings(got, test.wantCards) {
+				t.Errorf("coleção = %v, esperado %v", got, test.wantCards)
+			}
+		})
+	}
+}
+
+func TestDisenchant(t *testing.T) {
+	tests := []struct {
+		name      string
+		cardIDs   []string
+		failWrite bool
+		wantErr   error
+		wantDust  int
+		wantCards []string
+	}{
+		{name: "duas pedras", cardIDs: []string{"1", "2"}, wantDust: 4, wantCards: []string{"rock", "paper"}},
+		{name: "nenhuma carta", wantErr: ErrDisenchantCards, wantCards: []string{"rock", "rock", "rock", "paper"}},
+		{name: "cartas demais", cardIDs: cardRange(1, MaxDisenchantCards+1), wantErr: ErrDisenchantCards, wantCards: []string{"rock", "rock", "rock", "paper"}},
+		{name: "carta fora da coleção", cardIDs: []string{"1", "9"}, wantErr: ErrCraftCardOwner, wantCards: []string{"rock", "rock", "rock", "paper"}},
+		{name: "coleção não gravada estorna o pó", cardIDs: []string{"1", "2"}, failWrite: true, wantErr: errWriteFailed, wantCards: []string{"rock", "rock", "rock", "paper"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, wallet, userRepo := newTestCraftingService(t)
+			userRepo.failUpdate = func(string) bool { return test.failWrite }
+
+			taken, received, dust, err := service.Disenchant("alice", test.cardIDs)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err == nil && (len(taken) != len(test.cardIDs) || received != test.wantDust || dust != test.wantDust) {
+				t.Errorf("desfez %d cartas por %d de pó, saldo %d", len(taken), received, dust)
+			}
+			if balance, _ := wallet.Dust("alice"); balance != test.wantDust {
+				t.Errorf("saldo de pó = %d, esperado %d", balance, test.wantDust)
+			}
+			if got := collectionTypes(t, userRepo, "alice"); !equalStrings(got, test.wantCards) {
+				t.Errorf("coleção = %v, esperado %v", got, test.wantCards)
+			}
+		})
+	}
+
+	t.Run("bot", func(t *testing.T) {
+		service, _, _ := newTestCraftingService(t)
+		if _, _, _, err := service.Disenchant("bot", []string{"1"}); !errors.Is(err, ErrCraftBot) {
+			t.Errorf("erro = %v, esperado %v", err, ErrCraftBot)
+		}
+	})
+}
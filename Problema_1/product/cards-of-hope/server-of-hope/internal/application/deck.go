@@ -0,0 +1,417 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sort"
+	"sync"
+)
+
+// StarterCopies define quantas cópias de cada tipo das regras padrão a coleção inicial recebe.
+const StarterCopies = 3
+
+// DeckServiceInterface descreve as operações sobre a coleção de cartas e os baralhos dos usuários.
+//
+// Métodos:
+//   - Collection: lista as cartas da coleção de um usuário.
+//   - AddCards: adiciona cartas à coleção de um usuário.
+//   - SaveDeck: salva um baralho com cartas da coleção.
+//   - ListDecks: lista os baralhos salvos de um usuário.
+//   - SelectDeck: escolhe o baralho de um jogador para as próximas partidas de uma sala.
+//   - Swap: troca cartas entre as coleções de dois usuários.
+//   - TakeCards: retira cartas da coleção de um usuário.
+type DeckServiceInterface interface {
+	// Collection lista as cartas da coleção de um usuário, criando a coleção inicial se necessário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - []domain.OwnedCard: cartas da coleção.
+	//   - erro caso o usuário não exista.
+	Collection(userID string) ([]domain.OwnedCard, error)
+
+	// AddCards adiciona cartas à coleção de um usuário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - cards: cartas a serem adicionadas.
+	//
+	// Retorno:
+	//   - []domain.OwnedCard: cartas adicionadas, com seus IDs.
+	//   - erro caso o usuário não exista.
+	AddCards(userID string, cards ...domain.Card) ([]domain.OwnedCard, error)
+
+	// SaveDeck salva (ou substitui) um baralho com cartas da coleção do usuário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - name: nome do baralho.
+	//   - cardIDs: IDs das cartas da coleção.
+	//
+	// Retorno:
+	//   - erro caso o baralho seja inválido.
+	SaveDeck(userID, name string, cardIDs []string) error
+
+	// ListDecks lista os baralhos salvos de um usuário, ordenados pelo nome.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - []domain.Deck: baralhos com as cartas resolvidas.
+	//   - erro caso o usuário não exista.
+	ListDecks(userID string) ([]domain.Deck, error)
+
+	// SelectDeck escolhe o baralho de um jogador para as próximas partidas da sala. O nome
+	// vazio volta ao baralho básico das regras da sala.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - userID: identificador do jogador.
+	//   - name: nome do baralho salvo.
+	//
+	// Retorno:
+	//   - erro caso o jogador não esteja na sala ou o baralho não sirva às regras da sala.
+	SelectDeck(roomID, userID, name string) error
+
+	// Swap troca cartas entre as coleções de dois usuários de uma só vez: ou todas as cartas
+	// mudam de dono, ou nenhuma muda. As cartas recebidas ganham novos IDs na coleção de destino,
+	// e os baralhos que usavam as cartas que saíram são apagados.
+	//
+	// Parâmetros:
+	//   - firstID: identificador do primeiro usuário.
+	//   - firstCards: IDs das cartas que saem da coleção do primeiro usuário.
+	//   - secondID: identificador do segundo usuário.
+	//   - secondCards: IDs das cartas que saem da coleção do segundo usuário.
+	//
+	// Retorno:
+	//   - []domain.OwnedCard: cartas recebidas pelo primeiro usuário, com os novos IDs.
+	//   - []domain.OwnedCard: cartas recebidas pelo segundo usuário, com os novos IDs.
+	//   - erro caso algum usuário não exista ou alguma carta não esteja mais na coleção do dono.
+	Swap(firstID string, firstCards []string, secondID string, secondCards []string) ([]domain.OwnedCard, []domain.OwnedCard, error)
+
+	// TakeCards retira cartas da coleção de um usuário, como no anúncio de cartas no mercado:
+	// ou todas saem, ou nenhuma sai. Os baralhos que usavam as cartas retiradas são apagados.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - cardIDs: IDs das cartas da coleção.
+	//
+	// Retorno:
+	//   - []domain.OwnedCard: cartas retiradas.
+	//   - erro caso o usuário não exista ou alguma carta não esteja na coleção.
+	TakeCards(userID string, cardIDs []string) ([]domain.OwnedCard, error)
+
+	// Transform retira cartas da coleção de um usuário e põe no lugar as cartas produzidas por
+	// transform, de uma só vez: se transform recusar as cartas, ou alguma delas não estiver na
+	// coleção, a coleção não muda. Os baralhos que usavam as cartas retiradas são apagados.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - cardIDs: IDs das cartas da coleção.
+	//   - transform: recebe as cartas retiradas e retorna as cartas que entram na coleção.
+	//
+	// Retorno:
+	//   - []domain.OwnedCard: cartas retiradas.
+	//   - []domain.OwnedCard: cartas adicionadas, com os novos IDs.
+	//   - erro caso o usuário não exista, alguma carta não esteja na coleção ou transform falhe.
+	Transform(userID string, cardIDs []string, transform func(taken []domain.OwnedCard) ([]domain.Card, error)) ([]domain.OwnedCard, []domain.OwnedCard, error)
+}
+
+// DeckService implementa a coleção de cartas e os baralhos dos usuários.
+type DeckService struct {
+	userRepo    data.RepositoryInterface[domain.User]
+	roomRepo    data.RepositoryInterface[domain.Room]
+	rulesetRepo data.RepositoryInterface[domain.Ruleset]
+	mutex       sync.Mutex  // serializa as mudanças nas coleções
+	roomMutex   *sync.Mutex // serializa as mudanças nas salas, junto com RoomService
+}
+
+// NewDeckService cria uma nova instância de DeckService.
+//
+// Parâmetros:
+//   - userRepo: repositório dos usuários, onde ficam as coleções e os baralhos.
+//   - roomRepo: repositório das salas, onde fica o baralho escolhido por cada jogador.
+//   - rulesetRepo: repositório dos conjuntos de regras.
+//   - roomMutex: mutex compartilhado por todos os serviços que alteram as salas.
+//
+// Retorno:
+//   - ponteiro para DeckService.
+func NewDeckService(
+	userRepo data.RepositoryInterface[domain.User],
+	roomRepo data.RepositoryInterface[domain.Room],
+	rulesetRepo data.RepositoryInterface[domain.Ruleset],
+	roomMutex *sync.Mutex,
+) *DeckService {
+	return &DeckService{
+		userRepo:    userRepo,
+		roomRepo:    roomRepo,
+		rulesetRepo: rulesetRepo,
+		roomMutex:   roomMutex,
+	}
+}
+
+// readUser lê um usuário e, se ele ainda não tiver coleção, cria a coleção inicial e o
+// baralho inicial com as cartas das regras padrão.
+func (service *DeckService) readUser(userID string) (domain.User, error) {
+	user, err := service.userRepo.Read(userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.Collection != nil {
+		return user, nil
+	}
+
+	ruleset, err := readRuleset(service.rulesetRepo, domain.DefaultRulesetID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	var starter []domain.Card
+	for copies := 0; copies < StarterCopies; copies++ {
+		for _, cardType := range ruleset.Cards {
+			starter = append(starter, domain.Card{Type: cardType, Stars: 1})
+		}
+	}
+	added := user.AddCards(starter...)
+	user.Decks = map[string][]string{}
+	if len(added) >= domain.DeckSize {
+		cardIDs := make([]string, 0, domain.DeckSize)
+		for _, card := range added[:domain.DeckSize] {
+			cardIDs = append(cardIDs, card.ID)
+		}
+		user.Decks[domain.StarterDeckName] = cardIDs
+	}
+	return user, service.userRepo.Update(userID, user)
+}
+
+// Collection lista as cartas da coleção de um usuário, criando a coleção inicial se necessário.
+func (service *DeckService) Collection(userID string) ([]domain.OwnedCard, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	user, err := service.readUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Collection, nil
+}
+
+// AddCards adiciona cartas à coleção de um usuário e retorna as cartas com seus IDs.
+func (service *DeckService) AddCards(userID string, cards ...domain.Card) ([]domain.OwnedCard, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	user, err := service.readUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	added := user.AddCards(cards...)
+	return added, service.userRepo.Update(userID, user)
+}
+
+// SaveDeck salva (ou substitui) um baralho com DeckSize cartas da coleção do usuário.
+func (service *DeckService) SaveDeck(userID, name string, cardIDs []string) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	user, err := service.readUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := user.ValidateDeck(name, cardIDs); err != nil {
+		return err
+	}
+	// O mapa é copiado porque outras leituras do usuário podem estar usando o atual.
+	decks := make(map[string][]string, len(user.Decks)+1)
+	for deckName, deckCards := range user.Decks {
+		decks[deckName] = deckCards
+	}
+	decks[name] = append([]string(nil), cardIDs...)
+	user.Decks = decks
+	return service.userRepo.Update(userID, user)
+}
+
+// ListDecks lista os baralhos salvos de um usuário, ordenados pelo nome. Baralhos que
+// referenciam cartas fora da coleção são omitidos.
+func (service *DeckService) ListDecks(userID string) ([]domain.Deck, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	user, err := service.readUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(user.Decks))
+	for name := range user.Decks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	decks := make([]domain.Deck, 0, len(names))
+	for _, name := range names {
+		deck, err := user.Deck(name)
+		if err != nil {
+			continue // Baralhos com cartas que saíram da coleção não podem ser usados
+		}
+		decks = append(decks, deck)
+	}
+	return decks, nil
+}
+
+// SelectDeck escolhe o baralho de um jogador para as próximas partidas da sala, conferindo se
+// ele serve às regras da sala. O nome vazio volta ao baralho básico.
+func (service *DeckService) SelectDeck(roomID, userID, name string) error {
+	// A sala é travada antes da coleção, na mesma ordem em que as partidas retêm as apostas.
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	room, err := service.roomRepo.Read(roomID)
+	if err != nil {
+		return err
+	}
+	if !room.UserIDs.Contains(userID) {
+		return errors.New("apenas jogadores da sala podem escolher um baralho")
+	}
+	if name == "" {
+		room.DeckChoices.Delete(userID)
+		return service.roomRepo.Update(roomID, room)
+	}
+
+	user, err := service.readUser(userID)
+	if err != nil {
+		return err
+	}
+	deck, err := user.Deck(name)
+	if err != nil {
+		return err
+	}
+	ruleset, err := readRuleset(service.rulesetRepo, room.RulesetID)
+	if err != nil {
+		return err
+	}
+	if err := deckFitsRuleset(deck.Cards, ruleset); err != nil {
+		return err
+	}
+	room.DeckChoices.Set(userID, name)
+	return service.roomRepo.Update(roomID, room)
+}
+
+// Swap troca cartas entre as coleções de dois usuários de uma só vez. Como todas as mudanças nas
+// coleções passam pelo mutex, nenhuma outra troca ou compra vê as coleções pela metade.
+func (service *DeckService) Swap(firstID string, firstCards []string, secondID string, secondCards []string) ([]domain.OwnedCard, []domain.OwnedCard, error) {
+	if firstID == secondID {
+		return nil, nil, errors.New("não é possível trocar cartas consigo mesmo")
+	}
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	first, err := service.readUser(firstID)
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := service.readUser(secondID)
+	if err != nil {
+		return nil, nil, err
+	}
+	original := first
+	fromFirst, err := first.TakeCards(firstCards)
+	if err != nil {
+		return nil, nil, err
+	}
+	fromSecond, err := second.TakeCards(secondCards)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	receivedByFirst := first.AddCards(plainCards(fromSecond)...)
+	receivedBySecond := second.AddCards(plainCards(fromFirst)...)
+	if err := service.userRepo.Update(firstID, first); err != nil {
+		return nil, nil, err
+	}
+	if err := service.userRepo.Update(secondID, second); err != nil {
+		// A coleção do primeiro volta ao que era para nenhuma carta aparecer nas duas coleções.
+		return nil, nil, errors.Join(err, service.userRepo.Update(firstID, original))
+	}
+	return receivedByFirst, receivedBySecond, nil
+}
+
+// TakeCards retira cartas da coleção de um usuário de uma só vez.
+func (service *DeckService) TakeCards(userID string, cardIDs []string) ([]domain.OwnedCard, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	user, err := service.readUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	taken, err := user.TakeCards(cardIDs)
+	if err != nil {
+		return nil, err
+	}
+	return taken, service.userRepo.Update(userID, user)
+}
+
+// Transform retira cartas da coleção de um usuário e adiciona as produzidas por transform de uma
+// só vez. transform roda com o mutex travado, então nenhuma outra mudança na coleção acontece
+// entre a retirada e a adição.
+func (service *DeckService) Transform(userID string, cardIDs []string, transform func(taken []domain.OwnedCard) ([]domain.Card, error)) ([]domain.OwnedCard, []domain.OwnedCard, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	user, err := service.readUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	taken, err := user.TakeCards(cardIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	cards, err := transform(taken)
+	if err != nil {
+		return nil, nil, err
+	}
+	added := user.AddCards(cards...)
+	return taken, added, service.userRepo.Update(userID, user)
+}
+
+// plainCards retorna as cartas sem os IDs da coleção de origem.
+func plainCards(owned []domain.OwnedCard) []domain.Card {
+	cards := make([]domain.Card, 0, len(owned))
+	for _, card := range owned {
+		cards = append(cards, card.Card)
+	}
+	return cards
+}
+
+// deckFitsRuleset confere se todas as cartas do baralho têm tipos aceitos pelas regras.
+func deckFitsRuleset(cards []domain.OwnedCard, ruleset domain.Ruleset) error {
+	for _, card := range cards {
+		if !ruleset.HasCard(card.Type) {
+			return fmt.Errorf("a carta %s (%s) não é aceita pelas regras %s", card.ID, card.Type, ruleset.ID)
+		}
+	}
+	return nil
+}
+
+// matchDeck retorna as cartas com que um jogador disputa a próxima partida da sala: o baralho
+// escolhido, se ainda servir às regras, ou o baralho básico das regras.
+func matchDeck(userRepo data.RepositoryInterface[domain.User], room domain.Room, ruleset domain.Ruleset, userID string) []domain.OwnedCard {
+	name, chosen := room.DeckChoices.Get(userID)
+	if !chosen {
+		return domain.BasicDeck(ruleset)
+	}
+	user, err := userRepo.Read(userID)
+	if err != nil {
+		return domain.BasicDeck(ruleset)
+	}
+	deck, err := user.Deck(name)
+	if err != nil || deckFitsRuleset(deck.Cards, ruleset) != nil {
+		return domain.BasicDeck(ruleset)
+	}
+	return deck.Cards
+}
@@ -0,0 +1,205 @@
+package application
+
+import (
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newTestDeckService cria um serviço de coleções com os usuários informados e as regras embutidas.
+func newTestDeckService(t *testing.T, users ...domain.User) (*DeckService, *data.InMemoryRepository[domain.User], *data.InMemoryRepository[domain.Room]) {
+	t.Helper()
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	for _, user := range users {
+		userRepo.Create(user.ID, user)
+	}
+	roomRepo := data.NewInMemoryRepository[domain.Room]()
+	return NewDeckService(userRepo, roomRepo, newTestRulesets(t), &sync.Mutex{}), userRepo, roomRepo
+}
+
+// cardRange lista os IDs de first a last, como os gerados por collector.
+func cardRange(first, last int) []string {
+	cardIDs := make([]string, 0, last-first+1)
+	for id := first; id <= last; id++ {
+		cardIDs = append(cardIDs, strconv.Itoa(id))
+	}
+	return cardIDs
+}
+
+// rocks cria n cartas de pedra com uma estrela.
+func rocks(n int) []domain.Card {
+	cards := make([]domain.Card, n)
+	for index := range cards {
+		cards[index] = domain.Card{Type: "rock", Stars: 1}
+	}
+	return cards
+}
+
+func TestStarterCollection(t *testing.T) {
+	service, _, _ := newTestDeckService(t, domain.User{ID: "alice"})
+	cards, err := service.Collection("alice")
+	if err != nil {
+		t.Fatalf("Collection: %v", err)
+	}
+	if len(cards) != StarterCopies*3 {
+		t.Errorf("%d cartas na coleção inicial, esperado %d", len(cards), StarterCopies*3)
+	}
+	decks, err := service.ListDecks("alice")
+	if err != nil {
+		t.Fatalf("ListDecks: %v", err)
+	}
+	if len(decks) != 1 || decks[0].Name != domain.StarterDeckName || len(decks[0].Cards) != domain.DeckSize {
+		t.Errorf("baralhos iniciais = %+v", decks)
+	}
+	// A coleção inicial só é criada uma vez.
+	if again, _ := service.Collection("alice"); len(again) != len(cards) {
+		t.Errorf("%d cartas na segunda leitura, esperado %d", len(again), len(cards))
+	}
+}
+
+func TestSaveDeck(t *testing.T) {
+	full := collector("alice", rocks(10)...)
+	full.Decks = map[string][]string{}
+	for index := range domain.MaxDecks {
+		full.Decks["deck"+strconv.Itoa(index)] = cardRange(1, domain.DeckSize)
+	}
+	tests := []struct {
+		name    string
+		user    domain.User
+		deck    string
+		cardIDs []string
+		wantErr string
+	}{
+		{name: "baralho válido", user: collector("alice", rocks(10)...), deck: "aggro", cardIDs: cardRange(1, 8)},
+		{name: "cartas de menos", user: collector("alice", rocks(10)...), deck: "aggro", cardIDs: cardRange(1, 7), wantErr: "exatamente"},
+		{name: "carta repetida", user: collector("alice", rocks(10)...), deck: "aggro", cardIDs: append(cardRange(1, 7), "1"), wantErr: "mais de uma vez"},
+		{name: "carta de fora da coleção", user: collector("alice", rocks(10)...), deck: "aggro", cardIDs: cardRange(4, 11), wantErr: "não está na sua coleção"},
+		{name: "nome com espaço", user: collector("alice", rocks(10)...), deck: "meu baralho", cardIDs: cardRange(1, 8), wantErr: "nome de baralho inválido"},
+		{name: "limite de baralhos", user: full, deck: "novo", cardIDs: cardRange(1, 8), wantErr: "limite"},
+		{name: "substitui no limite", user: full, deck: "deck0", cardIDs: cardRange(3, 10)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, userRepo, _ := newTestDeckService(t, test.user)
+			err := service.SaveDeck("alice", test.deck, test.cardIDs)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("erro = %v, esperado %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SaveDeck: %v", err)
+			}
+			user, _ := userRepo.Read("alice")
+			if !equalStrings(user.Decks[test.deck], test.cardIDs) {
+				t.Errorf("baralho salvo = %v, esperado %v", user.Decks[test.deck], test.cardIDs)
+			}
+		})
+	}
+}
+
+func TestListDecksSkipsBrokenDecks(t *testing.T) {
+	user := collector("alice", rocks(10)...)
+	user.Decks = map[string][]string{
+		"zeta":   cardRange(1, 8),
+		"alpha":  cardRange(3, 10),
+		"broken": append(cardRange(1, 7), "99"),
+	}
+	service, _, _ := newTestDeckService(t, user)
+	decks, err := service.ListDecks("alice")
+	if err != nil {
+		t.Fatalf("ListDecks: %v", err)
+	}
+	names := make([]string, 0, len(decks))
+	for _, deck := range decks {
+		names = append(names, deck.Name)
+	}
+	if !equalStrings(names, []string{"alpha", "zeta"}) {
+		t.Errorf("baralhos = %v, esperado [alpha zeta]", names)
+	}
+}
+
+func TestSelectDeck(t *testing.T) {
+	tests := []struct {
+		name       string
+		ruleset    string
+		userID     string
+		deck       string
+		wantErr    string
+		wantChoice string
+	}{
+		{name: "jogador escolhe", ruleset: "classic", userID: "alice", deck: "mixed", wantChoice: "mixed"},
+		{name: "nome vazio volta ao básico", ruleset: "classic", userID: "alice", deck: ""},
+		{name: "espectador", ruleset: "classic", userID: "carol", deck: "mixed", wantErr: "apenas jogadores"},
+		{name: "baralho desconhecido", ruleset: "classic", userID: "alice", deck: "nenhum", wantErr: "baralho não encontrado", wantChoice: "old"},
+		{name: "carta fora das regras", ruleset: "classic", userID: "alice", deck: "spock", wantErr: "não é aceita", wantChoice: "old"},
+		{name: "carta aceita pela variante", ruleset: "rpsls", userID: "alice", deck: "spock", wantChoice: "spock"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cards := rocks(9)
+			cards[8] = domain.Card{Type: "spock", Stars: 1}
+			user := collector("alice", cards...)
+			user.Decks = map[string][]string{"mixed": cardRange(1, 8), "spock": cardRange(2, 9)}
+			service, _, roomRepo := newTestDeckService(t, user, collector("carol"))
+			room := newDuelRoom(domain.RoomStatusReadyCheck)
+			room.RulesetID = test.ruleset
+			room.DeckChoices.Set("alice", "old")
+			roomRepo.Create(room.ID, room)
+
+			err := service.SelectDeck("7", test.userID, test.deck)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("erro = %v, esperado %q", err, test.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("SelectDeck: %v", err)
+			}
+			got, _ := roomRepo.Read("7")
+			if choice, _ := got.DeckChoices.Get("alice"); test.userID == "alice" && choice != test.wantChoice {
+				t.Errorf("baralho escolhido = %q, esperado %q", choice, test.wantChoice)
+			}
+		})
+	}
+}
+
+func TestMatchDeck(t *testing.T) {
+	rulesets := newTestRulesets(t)
+	classic, _ := rulesets.Read("classic")
+	user := collector("alice", rocks(8)...)
+	user.Decks = map[string][]string{"rocks": cardRange(1, 8), "broken": append(cardRange(1, 7), "99")}
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	userRepo.Create(user.ID, user)
+
+	tests := []struct {
+		name      string
+		choice    string
+		wantRocks int
+	}{
+		{name: "baralho escolhido", choice: "rocks", wantRocks: domain.DeckSize},
+		{name: "sem escolha usa o básico", wantRocks: 3},
+		{name: "baralho quebrado usa o básico", choice: "broken", wantRocks: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			room := newDuelRoom(domain.RoomStatusReadyCheck)
+			if test.choice != "" {
+				room.DeckChoices.Set("alice", test.choice)
+			}
+			deck := matchDeck(userRepo, room, classic, "alice")
+			count := 0
+			for _, card := range deck {
+				if card.Type == "rock" {
+					count++
+				}
+			}
+			if len(deck) != domain.DeckSize || count != test.wantRocks {
+				t.Errorf("%d cartas, %d pedras, esperado %d pedras", len(deck), count, test.wantRocks)
+			}
+		})
+	}
+}
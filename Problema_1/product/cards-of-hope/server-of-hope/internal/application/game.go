@@ -2,23 +2,66 @@ package application
 
 import (
 	"errors"
+	"fmt"
 	"server-of-hope/internal/data"
 	"server-of-hope/internal/domain"
 	"server-of-hope/internal/utils"
+	"sort"
+	"sync"
+	"time"
 )
 
+// RematchWindow define o prazo para o outro jogador aceitar um pedido de revanche.
+const RematchWindow = time.Minute
+
+// TurnSweepInterval define de quanto em quanto tempo os prazos das rodadas das partidas por
+// correspondência são conferidos.
+const TurnSweepInterval = time.Minute
+
+// ErrCommitRequired indica que a sala usa jogadas com compromisso e revelação, e não aceita play.
+var ErrCommitRequired = errors.New("esta sala usa jogadas com compromisso: envie commit e depois reveal")
+
+// ErrRevealForfeit indica que o jogador esgotou as revelações inválidas e perdeu a partida.
+var ErrRevealForfeit = errors.New("a revelação não confere com o compromisso e as tentativas acabaram: você perdeu a partida")
+
+// errNotInHand indica que a carta jogada não está na mão do jogador.
+var errNotInHand = errors.New("carta não está na sua mão")
+
 // GameServiceInterface descreve as operações para manipulação da lógica do jogo.
 type GameServiceInterface interface {
-	PlayCard(gameID string, playerID string, card domain.Card) error
+	SetReady(roomID string, playerID string, ready bool) (bool, error)
+	Rematch(roomID string, playerID string, accept bool) (bool, error)
+	PlayCard(gameID string, playerID string, cardRef string) (*domain.RoundResult, error)
+	Commit(gameID string, playerID string, commitment string) (bool, error)
+	Reveal(gameID string, playerID string, cardRef string, nonce string) (*domain.RoundResult, error)
 	GetGame(gameID string) (domain.Game, error)
 	ResetRound(gameID string) error
+	AsyncGames(userID string) ([]AsyncGame, error)
+	ExpireTurns() (map[string]domain.RoundResult, error)
+	Forfeit(roomID string, playerIDs []string) (domain.RoundResult, error)
+	ClaimSettlement(gameID string) (bool, error)
+}
+
+// AsyncGame descreve uma partida por correspondência em andamento do ponto de vista de um jogador.
+//
+// Campos:
+//   - Room: sala da partida.
+//   - Game: partida em andamento.
+//   - YourTurn: a rodada aguarda uma ação do jogador.
+type AsyncGame struct {
+	Room     domain.Room
+	Game     domain.Game
+	YourTurn bool
 }
 
 // GameService implementa a lógica do jogo, incluindo jogadas e controle de estado.
 type GameService struct {
-	gameRepo data.RepositoryInterface[domain.Game]
-	userRepo data.RepositoryInterface[domain.User]
-	roomRepo data.RepositoryInterface[domain.Room]
+	gameRepo    data.RepositoryInterface[domain.Game]
+	userRepo    data.RepositoryInterface[domain.User]
+	roomRepo    data.RepositoryInterface[domain.Room]
+	rulesetRepo data.RepositoryInterface[domain.Ruleset]
+	wagers      WagerServiceInterface // retém as apostas no início das partidas das salas com aposta
+	mutex       *sync.Mutex           // serializa as mudanças de estado das partidas e das salas, junto com RoomService
 }
 
 // NewGameService cria uma nova instância de GameService.
@@ -26,87 +69,467 @@ func NewGameService(
 	gameRepo data.RepositoryInterface[domain.Game],
 	userRepo data.RepositoryInterface[domain.User],
 	roomRepo data.RepositoryInterface[domain.Room],
+	rulesetRepo data.RepositoryInterface[domain.Ruleset],
+	wagers WagerServiceInterface,
+	roomMutex *sync.Mutex,
 ) *GameService {
 	return &GameService{
-		gameRepo: gameRepo,
-		userRepo: userRepo,
-		roomRepo: roomRepo,
+		gameRepo:    gameRepo,
+		userRepo:    userRepo,
+		roomRepo:    roomRepo,
+		rulesetRepo: rulesetRepo,
+		wagers:      wagers,
+		mutex:       roomMutex,
 	}
 }
 
-// getOrCreateGame busca uma partida existente ou cria uma nova se não existir.
-func (s *GameService) getOrCreateGame(gameID string) (domain.Game, error) {
-	game, err := s.gameRepo.Read(gameID)
-	if err != nil {
-		if err.Error() == "item not found" {
-			game = domain.Game{
-				ID:             gameID,
-				Plays:          utils.NewMap[string, domain.Card](),
-				ResultsSeenBy:  utils.NewSet[string](),
-				FailedAttempts: utils.NewMap[string, int](),
-			}
-			if err := s.gameRepo.Create(gameID, game); err != nil {
-				return domain.Game{}, err
-			}
-		} else {
-			return domain.Game{}, err
-		}
-	} else {
-		if game.Plays == nil {
-			game.Plays = utils.NewMap[string, domain.Card]()
+// startGame cria a primeira partida de uma série para a sala, substituindo a anterior se existir.
+// O anfitrião, se estiver jogando, ocupa o primeiro assento.
+func (s *GameService) startGame(room *domain.Room) error {
+	seats := room.UserIDs.Items()
+	sort.Slice(seats, func(i, j int) bool {
+		if seats[i] == room.HostID || seats[j] == room.HostID {
+			return seats[i] == room.HostID
 		}
-		if game.ResultsSeenBy == nil {
-			game.ResultsSeenBy = utils.NewSet[string]()
+		return seats[i] < seats[j]
+	})
+	return s.startMatch(room, domain.NewGame(room.ID, seats))
+}
+
+// startMatch retém as apostas da sala, se houver, dá as cartas dos baralhos escolhidos aos
+// jogadores e grava a partida. Se a partida não puder ser gravada, as apostas são devolvidas.
+func (s *GameService) startMatch(room *domain.Room, game domain.Game) error {
+	ruleset, err := readRuleset(s.rulesetRepo, room.RulesetID)
+	if err != nil {
+		return err
+	}
+	if room.HasWager() {
+		wager, err := s.wagers.Hold(*room, game.Number)
+		if err != nil {
+			return err
 		}
-		if game.FailedAttempts == nil {
-			game.FailedAttempts = utils.NewMap[string, int]()
+		room.WagerID = wager.ID
+		room.Stakes.Clear() // As cartas apostadas saíram da coleção; a próxima partida pede outras
+	}
+	for _, playerID := range game.Seats {
+		game.Deal(playerID, matchDeck(s.userRepo, *room, ruleset, playerID))
+	}
+	game.TurnDeadline = turnDeadline(*room)
+	if err := s.saveGame(game); err != nil {
+		if room.HasWager() {
+			s.wagers.Settle(room.WagerID, "")
 		}
+		return err
+	}
+	return nil
+}
+
+// saveGame grava a partida, criando-a ou substituindo a anterior da sala.
+func (s *GameService) saveGame(game domain.Game) error {
+	if _, err := s.gameRepo.Read(game.ID); err == nil {
+		return s.gameRepo.Update(game.ID, game)
 	}
-	return game, nil
+	return s.gameRepo.Create(game.ID, game)
 }
 
 func (s *GameService) GetGame(gameID string) (domain.Game, error) {
 	return s.gameRepo.Read(gameID)
 }
 
-// PlayCard registra a jogada de um jogador em uma partida, validando a carta e o estado do jogo.
-func (s *GameService) PlayCard(gameID string, playerID string, card domain.Card) error {
-	if card.Type != "rock" && card.Type != "paper" && card.Type != "scissors" {
-		return errors.New("tipo de carta inválido")
+// SetReady marca ou desmarca um jogador como pronto. Quando os dois jogadores da sala estão
+// prontos, uma partida nova começa e o retorno indica que ela foi iniciada.
+func (s *GameService) SetReady(roomID string, playerID string, ready bool) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, err := s.roomRepo.Read(roomID)
+	if err != nil {
+		return false, err
 	}
-	if card.Stars < 1 || card.Stars > 5 {
-		return errors.New("quantidade de estrelas inválida")
+	if room.IsSpectator(playerID) {
+		return false, errors.New("espectadores não podem jogar")
 	}
+	if !room.UserIDs.Contains(playerID) {
+		return false, errors.New("jogador não está na sala")
+	}
+
+	switch room.Status {
+	case domain.RoomStatusWaiting:
+		return false, errors.New("aguarde outro jogador entrar na sala")
+	case domain.RoomStatusPlaying:
+		return false, errors.New("a partida já começou")
+	case domain.RoomStatusFinished:
+		// Ficar pronto após uma partida encerrada descarta a revanche e começa uma série nova.
+		room.Status = domain.RoomStatusReadyCheck
+		room.RematchRequests.Clear()
+		room.RematchDeadline = time.Time{}
+	}
+
+	if !ready {
+		room.Ready.Remove(playerID)
+		return false, s.roomRepo.Update(roomID, room)
+	}
+	if _, chosen := room.Stakes.Get(playerID); room.WagerCards > 0 && !chosen {
+		return false, fmt.Errorf("%w: a sala aposta %d cartas", ErrWagerStake, room.WagerCards)
+	}
+
+	room.Ready.Add(playerID)
+	started := room.IsFull() && room.Ready.Size() == room.UserIDs.Size()
+	if started {
+		if err := s.startGame(&room); err != nil {
+			return false, err
+		}
+		room.Ready.Clear()
+		room.Status = domain.RoomStatusPlaying
+	}
+	return started, s.roomRepo.Update(roomID, room)
+}
+
+// Rematch registra o pedido ou a recusa de revanche de um jogador após uma partida encerrada.
+// Quando os dois jogadores pedem revanche dentro de RematchWindow, a próxima partida da série
+// começa com os assentos invertidos e o retorno indica que ela foi iniciada. A recusa cancela
+// os pedidos pendentes.
+func (s *GameService) Rematch(roomID string, playerID string, accept bool) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	game, err := s.getOrCreateGame(gameID)
+	room, err := s.roomRepo.Read(roomID)
 	if err != nil {
-		return err
+		return false, err
+	}
+	if !room.UserIDs.Contains(playerID) {
+		return false, errors.New("jogador não está na sala")
+	}
+	if room.Status != domain.RoomStatusFinished || !room.IsFull() {
+		return false, errors.New("não há partida encerrada para revanche")
+	}
+
+	if !accept {
+		room.RematchRequests.Clear()
+		room.RematchDeadline = time.Time{}
+		return false, s.roomRepo.Update(roomID, room)
 	}
+	if room.WagerCards > 0 {
+		return false, ErrWagerRematch
+	}
+
+	if room.RematchRequests.Size() > 0 && time.Now().After(room.RematchDeadline) {
+		room.RematchRequests.Clear() // O pedido anterior expirou; este abre um novo prazo
+	}
+	if room.RematchRequests.Size() == 0 {
+		room.RematchDeadline = time.Now().Add(RematchWindow)
+	}
+	room.RematchRequests.Add(playerID)
 
+	started := room.RematchRequests.Size() == room.UserIDs.Size()
+	if started {
+		previous, err := s.gameRepo.Read(roomID)
+		if err != nil {
+			return false, err
+		}
+		if err := s.startMatch(&room, previous.Rematch()); err != nil {
+			return false, err
+		}
+		room.RematchRequests.Clear()
+		room.RematchDeadline = time.Time{}
+		room.Ready.Clear()
+		room.Status = domain.RoomStatusPlaying
+	}
+	return started, s.roomRepo.Update(roomID, room)
+}
+
+// PlayCard joga uma carta da mão do jogador, indicada pelo ID ou pelo tipo (a de mais estrelas
+// desse tipo). Quando a jogada completa a rodada, ela é resolvida e seu resultado é retornado;
+// caso contrário, o retorno é nil. Salas com compromisso e revelação recusam jogadas diretas.
+func (s *GameService) PlayCard(gameID string, playerID string, cardRef string) (*domain.RoundResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, game, ruleset, err := s.readTurn(gameID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if room.CommitReveal {
+		return nil, ErrCommitRequired
+	}
+	return s.play(room, game, ruleset, playerID, cardRef)
+}
+
+// Commit registra o compromisso da jogada de um jogador em uma sala com compromisso e
+// revelação. O retorno indica se os dois jogadores já se comprometeram e podem revelar.
+func (s *GameService) Commit(gameID string, playerID string, commitment string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, game, _, err := s.readTurn(gameID, playerID)
+	if err != nil {
+		return false, err
+	}
+	if !room.CommitReveal {
+		return false, errors.New("esta sala não usa jogadas com compromisso, use play")
+	}
+	if !domain.ValidCommitment(commitment) {
+		return false, errors.New("compromisso inválido: envie o SHA-256 em hexadecimal de <carta>:<nonce>")
+	}
+	if _, exists := game.Commitments.Get(playerID); exists {
+		return false, errors.New("jogador já enviou o compromisso desta rodada")
+	}
+
+	game.Commitments.Set(playerID, commitment)
+	return game.Commitments.Size() >= 2, s.gameRepo.Update(gameID, game)
+}
+
+// Reveal revela a carta e o nonce de um jogador depois que os dois se comprometeram. A carta só
+// é jogada se a revelação conferir com o compromisso e a carta estiver na mão; cada revelação
+// inválida conta uma tentativa e, após MaxRevealAttempts, o jogador perde a partida, caso em que
+// o resultado é retornado junto com ErrRevealForfeit.
+func (s *GameService) Reveal(gameID string, playerID string, cardRef string, nonce string) (*domain.RoundResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, game, ruleset, err := s.readTurn(gameID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if !room.CommitReveal {
+		return nil, errors.New("esta sala não usa jogadas com compromisso, use play")
+	}
+	commitment, committed := game.Commitments.Get(playerID)
+	if !committed {
+		return nil, errors.New("envie o compromisso antes de revelar")
+	}
+	if game.Commitments.Size() < 2 {
+		return nil, errors.New("aguarde o compromisso do oponente antes de revelar")
+	}
+
+	if len(nonce) >= domain.MinNonceLength && domain.MatchesCommitment(commitment, cardRef, nonce) {
+		result, err := s.play(room, game, ruleset, playerID, cardRef)
+		if !errors.Is(err, errNotInHand) {
+			return result, err
+		}
+	}
+
+	// A revelação não confere ou compromete uma carta fora da mão: conta como tentativa inválida
+	attempts, _ := game.FailedAttempts.Get(playerID)
+	attempts++
+	game.FailedAttempts.Set(playerID, attempts)
+	if attempts < domain.MaxRevealAttempts {
+		if err := s.gameRepo.Update(gameID, game); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("a revelação não confere com o compromisso ou a carta não está na sua mão (%d de %d tentativas)", attempts, domain.MaxRevealAttempts)
+	}
+
+	result := s.forfeitMatch(&game, playerID)
+	game.TurnDeadline = time.Time{}
+	room.Status = domain.RoomStatusFinished
+	if err := s.roomRepo.Update(room.ID, room); err != nil {
+		return nil, err
+	}
+	if err := s.gameRepo.Update(gameID, game); err != nil {
+		return nil, err
+	}
+	return &result, ErrRevealForfeit
+}
+
+// readTurn lê a sala, a partida e as regras de uma jogada, conferindo se o jogador pode jogar
+// na rodada atual.
+func (s *GameService) readTurn(gameID string, playerID string) (domain.Room, domain.Game, domain.Ruleset, error) {
 	room, err := s.roomRepo.Read(gameID)
 	if err != nil {
-		return err
+		return domain.Room{}, domain.Game{}, domain.Ruleset{}, err
 	}
 
+	if room.IsSpectator(playerID) {
+		return domain.Room{}, domain.Game{}, domain.Ruleset{}, errors.New("espectadores não podem jogar")
+	}
 	if !room.UserIDs.Contains(playerID) {
-		return errors.New("jogador não está na sala")
+		return domain.Room{}, domain.Game{}, domain.Ruleset{}, errors.New("jogador não está na sala")
+	}
+	if room.Status != domain.RoomStatusPlaying {
+		return domain.Room{}, domain.Game{}, domain.Ruleset{}, errors.New("a partida não está em andamento, use ready quando os dois jogadores estiverem na sala")
+	}
+	ruleset, err := readRuleset(s.rulesetRepo, room.RulesetID)
+	if err != nil {
+		return domain.Room{}, domain.Game{}, domain.Ruleset{}, err
+	}
+
+	game, err := s.gameRepo.Read(gameID)
+	if err != nil {
+		return domain.Room{}, domain.Game{}, domain.Ruleset{}, err
 	}
 
 	if _, exists := game.Plays.Get(playerID); exists {
-		return errors.New("jogador já jogou neste turno")
+		return domain.Room{}, domain.Game{}, domain.Ruleset{}, errors.New("jogador já jogou neste turno")
 	}
+	return room, game, ruleset, nil
+}
 
+// play tira a carta da mão do jogador e a registra na rodada, resolvendo-a quando as duas
+// jogadas estiverem feitas.
+func (s *GameService) play(room domain.Room, game domain.Game, ruleset domain.Ruleset, playerID string, cardRef string) (*domain.RoundResult, error) {
 	if game.Plays.Size() >= 2 {
-		return errors.New("o jogo já está cheio")
+		return nil, errors.New("o jogo já está cheio")
 	}
 
+	if _, dealt := game.Hands.Get(playerID); !dealt {
+		// Partidas restauradas de backups anteriores às mãos recebem as cartas na primeira jogada
+		game.Deal(playerID, matchDeck(s.userRepo, room, ruleset, playerID))
+	}
+	owned, inHand := game.TakeFromHand(playerID, cardRef)
+	if !inHand {
+		return nil, errNotInHand
+	}
+	card := owned.Card
+	if _, exists := ruleset.Ability(card.Ability); !exists {
+		card.Ability = "" // Habilidades fora das regras da sala não têm efeito
+	}
 	game.Plays.Set(playerID, card)
 
-	if game.Plays.Size() == 2 {
-		game.ResultsSeenBy.Clear()
+	if game.Plays.Size() < 2 {
+		return nil, s.gameRepo.Update(game.ID, game)
 	}
 
-	return s.gameRepo.Update(gameID, game)
+	result := s.resolveRound(&game, ruleset)
+	game.TurnDeadline = turnDeadline(room)
+	if result.MatchFinished {
+		game.TurnDeadline = time.Time{}
+		room.Status = domain.RoomStatusFinished
+		if err := s.roomRepo.Update(room.ID, room); err != nil {
+			return nil, err
+		}
+	}
+	return &result, s.gameRepo.Update(game.ID, game)
+}
+
+// resolveRound confronta as duas jogadas da rodada pelas regras da sala, aplicando as
+// habilidades das cartas, atualiza o placar, completa as mãos e prepara a próxima rodada. A
+// partida termina quando um jogador atinge as rodadas necessárias ou quando o limite de rodadas
+// é alcançado ou as cartas acabam, com vitória de quem tiver mais rodadas ou empate.
+func (s *GameService) resolveRound(game *domain.Game, ruleset domain.Ruleset) domain.RoundResult {
+	playerIDs := roundOrder(*game)
+	first, _ := game.Plays.Get(playerIDs[0])
+	second, _ := game.Plays.Get(playerIDs[1])
+
+	duel := ruleset.Duel([2]string{playerIDs[0], playerIDs[1]}, [2]domain.Card{first, second})
+	result := domain.RoundResult{
+		Round:     game.Round,
+		Plays:     map[string]domain.Card{playerIDs[0]: first, playerIDs[1]: second},
+		Effective: map[string]domain.Card{playerIDs[0]: duel.Cards[0], playerIDs[1]: duel.Cards[1]},
+		Effects:   duel.Effects,
+	}
+	if duel.Winner >= 0 {
+		result.WinnerID = playerIDs[duel.Winner]
+	}
+
+	if game.Scores == nil {
+		game.Scores = utils.NewMap[string, int]()
+	}
+	if game.Series == nil {
+		game.Series = utils.NewMap[string, int]()
+	}
+	if result.WinnerID != "" {
+		wins, _ := game.Scores.Get(result.WinnerID)
+		game.Scores.Set(result.WinnerID, wins+1)
+		if wins+1 >= ruleset.WinningRounds {
+			result.MatchFinished = true
+			result.MatchWinnerID = result.WinnerID
+		}
+	}
+	result.Scores = make(map[string]int, len(playerIDs))
+	for _, playerID := range playerIDs {
+		result.Scores[playerID], _ = game.Scores.Get(playerID)
+	}
+
+	for _, playerID := range playerIDs {
+		game.Draw(playerID)
+	}
+	outOfRounds := ruleset.MaxRounds > 0 && game.Round >= ruleset.MaxRounds
+	if !result.MatchFinished && (outOfRounds || game.OutOfCards()) {
+		result.MatchFinished = true
+		first, second := result.Scores[playerIDs[0]], result.Scores[playerIDs[1]]
+		switch {
+		case first > second:
+			result.MatchWinnerID = playerIDs[0]
+		case second > first:
+			result.MatchWinnerID = playerIDs[1]
+		}
+	}
+	if result.MatchFinished {
+		finishMatch(game, &result, playerIDs)
+	}
+
+	clearRound(game)
+	for _, playerID := range duel.Reveals {
+		game.Reveals.Add(playerID)
+	}
+	game.Round++
+	last := result
+	game.LastRound = &last
+	return result
+}
+
+// forfeitMatch encerra a partida com a derrota do jogador informado, sem resolver a rodada.
+func (s *GameService) forfeitMatch(game *domain.Game, loserID string) domain.RoundResult {
+	result := domain.RoundResult{
+		Round:         game.Round,
+		Plays:         map[string]domain.Card{},
+		Effective:     map[string]domain.Card{},
+		MatchFinished: true,
+		ForfeitedBy:   loserID,
+		Scores:        make(map[string]int, len(game.Seats)),
+	}
+	for _, playerID := range game.Seats {
+		if playerID != loserID {
+			result.MatchWinnerID = playerID
+		}
+		result.Scores[playerID], _ = game.Scores.Get(playerID)
+	}
+	if game.Series == nil {
+		game.Series = utils.NewMap[string, int]()
+	}
+	finishMatch(game, &result, game.Seats)
+	clearRound(game)
+	return result
+}
+
+// finishMatch registra o vencedor da partida e atualiza o placar da série no resultado.
+func finishMatch(game *domain.Game, result *domain.RoundResult, playerIDs []string) {
+	if result.MatchWinnerID != "" {
+		game.WinnerID = result.MatchWinnerID
+		matches, _ := game.Series.Get(result.MatchWinnerID)
+		game.Series.Set(result.MatchWinnerID, matches+1)
+	}
+	result.Series = make(map[string]int, len(playerIDs))
+	for _, playerID := range playerIDs {
+		result.Series[playerID], _ = game.Series.Get(playerID)
+	}
+}
+
+// clearRound descarta as jogadas, os compromissos e as tentativas da rodada encerrada.
+func clearRound(game *domain.Game) {
+	game.Plays.Clear()
+	game.ResultsSeenBy.Clear()
+	game.FailedAttempts = utils.NewMap[string, int]()
+	game.Commitments = utils.NewMap[string, string]()
+	game.Reveals = utils.NewSet[string]()
+}
+
+// roundOrder retorna os jogadores da rodada na ordem dos assentos, que desempata a aplicação
+// de habilidades com a mesma prioridade.
+func roundOrder(game domain.Game) []string {
+	playerIDs := make([]string, 0, game.Plays.Size())
+	for _, playerID := range game.Seats {
+		if _, played := game.Plays.Get(playerID); played {
+			playerIDs = append(playerIDs, playerID)
+		}
+	}
+	if len(playerIDs) == game.Plays.Size() {
+		return playerIDs
+	}
+	playerIDs = game.Plays.Keys() // Assentos que não batem com as jogadas caem na ordem alfabética
+	sort.Strings(playerIDs)
+	return playerIDs
 }
 
 // ResetRound redefine o estado de uma partida para o próximo turno.
@@ -119,6 +542,178 @@ func (s *GameService) ResetRound(gameID string) error {
 	game.Plays.Clear()
 	game.ResultsSeenBy.Clear()
 	game.FailedAttempts = utils.NewMap[string, int]()
+	game.Commitments = utils.NewMap[string, string]()
 
 	return s.gameRepo.Update(gameID, game)
-}
\ No newline at end of file
+}
+
+// turnDeadline retorna o prazo de uma nova rodada na sala, ou zero se a sala não é por correspondência.
+func turnDeadline(room domain.Room) time.Time {
+	if !room.Async {
+		return time.Time{}
+	}
+	return time.Now().UTC().Add(room.TurnTimeout())
+}
+
+// AsyncGames lista as partidas por correspondência em andamento de um jogador, primeiro as que
+// aguardam uma ação dele e depois pelo prazo da rodada.
+func (s *GameService) AsyncGames(userID string) ([]AsyncGame, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rooms, err := s.roomRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var games []AsyncGame
+	for _, room := range rooms {
+		if !room.Async || room.Status != domain.RoomStatusPlaying || !room.UserIDs.Contains(userID) {
+			continue
+		}
+		game, err := s.gameRepo.Read(room.ID)
+		if err != nil {
+			continue
+		}
+		games = append(games, AsyncGame{Room: room, Game: game, YourTurn: game.WaitingOn(userID, room.CommitReveal)})
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if games[i].YourTurn != games[j].YourTurn {
+			return games[i].YourTurn
+		}
+		return games[i].Game.TurnDeadline.Before(games[j].Game.TurnDeadline)
+	})
+	return games, nil
+}
+
+// ExpireTurns encerra as partidas por correspondência cuja rodada passou do prazo. Quem não agiu
+// a tempo perde a partida; se nenhum dos dois agiu, ela termina empatada. O retorno traz o
+// resultado de cada partida encerrada, pelo ID.
+func (s *GameService) ExpireTurns() (map[string]domain.RoundResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rooms, err := s.roomRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	expired := make(map[string]domain.RoundResult)
+	var problems []error
+	for _, room := range rooms {
+		if !room.Async || room.Status != domain.RoomStatusPlaying {
+			continue
+		}
+		game, err := s.gameRepo.Read(room.ID)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if game.TurnDeadline.IsZero() {
+			// Partidas de antes do prazo das rodadas ganham um prazo a partir de agora
+			game.TurnDeadline = turnDeadline(room)
+			if err := s.gameRepo.Update(game.ID, game); err != nil {
+				problems = append(problems, err)
+			}
+			continue
+		}
+		if time.Now().Before(game.TurnDeadline) {
+			continue
+		}
+
+		var late []string
+		for _, playerID := range game.Seats {
+			if game.WaitingOn(playerID, room.CommitReveal) {
+				late = append(late, playerID)
+			}
+		}
+		result := s.endMatch(&game, late)
+		result.TimedOut = true
+		room.Status = domain.RoomStatusFinished
+		if err := s.roomRepo.Update(room.ID, room); err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if err := s.gameRepo.Update(game.ID, game); err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		expired[room.ID] = result
+	}
+	return expired, errors.Join(problems...)
+}
+
+// ClaimSettlement marca o fim da partida da sala como acertado, sob o mesmo mutex que encerra as
+// partidas. Como vários caminhos podem encerrar a mesma partida ao mesmo tempo (revelações
+// inválidas, saída da sala, prazo da rodada, ausência na partida com aposta), só quem recebe true
+// paga os prêmios e registra o resultado; os demais apenas avisam os jogadores.
+func (s *GameService) ClaimSettlement(gameID string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, err := s.roomRepo.Read(gameID)
+	if err != nil {
+		return false, err
+	}
+	if room.Status == domain.RoomStatusPlaying {
+		return false, errors.New("a partida ainda está em andamento")
+	}
+	game, err := s.gameRepo.Read(gameID)
+	if err != nil {
+		return false, err
+	}
+	if game.Settled {
+		return false, nil
+	}
+	game.Settled = true
+	return true, s.gameRepo.Update(gameID, game)
+}
+
+// Forfeit encerra a partida em andamento da sala com a derrota do jogador informado ou, se
+// forem informados os dois, empatada, como quando os jogadores abandonam uma partida com aposta.
+func (s *GameService) Forfeit(roomID string, playerIDs []string) (domain.RoundResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	room, err := s.roomRepo.Read(roomID)
+	if err != nil {
+		return domain.RoundResult{}, err
+	}
+	if room.Status != domain.RoomStatusPlaying {
+		return domain.RoundResult{}, errors.New("não há partida em andamento na sala")
+	}
+	game, err := s.gameRepo.Read(roomID)
+	if err != nil {
+		return domain.RoundResult{}, err
+	}
+	result := s.endMatch(&game, playerIDs)
+	room.Status = domain.RoomStatusFinished
+	if err := s.roomRepo.Update(room.ID, room); err != nil {
+		return domain.RoundResult{}, err
+	}
+	return result, s.gameRepo.Update(game.ID, game)
+}
+
+// endMatch encerra a partida antes do fim: com a derrota do único jogador que a perdeu ou, se os
+// dois a perderam, empatada.
+func (s *GameService) endMatch(game *domain.Game, losers []string) domain.RoundResult {
+	game.TurnDeadline = time.Time{}
+	if len(losers) == 1 {
+		return s.forfeitMatch(game, losers[0])
+	}
+
+	result := domain.RoundResult{
+		Round:         game.Round,
+		Plays:         map[string]domain.Card{},
+		Effective:     map[string]domain.Card{},
+		MatchFinished: true,
+		Scores:        make(map[string]int, len(game.Seats)),
+	}
+	for _, playerID := range game.Seats {
+		result.Scores[playerID], _ = game.Scores.Get(playerID)
+	}
+	if game.Series == nil {
+		game.Series = utils.NewMap[string, int]()
+	}
+	finishMatch(game, &result, game.Seats)
+	clearRound(game)
+	return result
+}
@@ -0,0 +1,506 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClaimSettlement(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		settled     bool
+		wantErr     bool
+		wantGranted int
+	}{
+		{name: "partida encerrada é acertada uma vez", status: domain.RoomStatusFinished, wantGranted: 1},
+		{name: "sala de volta à espera", status: domain.RoomStatusWaiting, wantGranted: 1},
+		{name: "partida já acertada", status: domain.RoomStatusFinished, settled: true},
+		{name: "partida em andamento", status: domain.RoomStatusPlaying, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			roomRepo := data.NewInMemoryRepository[domain.Room]()
+			room := domain.NewRoom("7", "Sala 7", "alice")
+			room.Status = test.status
+			roomRepo.Create(room.ID, *room)
+			gameRepo := data.NewInMemoryRepository[domain.Game]()
+			gameRepo.Create("7", domain.Game{ID: "7", Settled: test.settled})
+			service := NewGameService(gameRepo, data.NewInMemoryRepository[domain.User](), roomRepo, data.NewInMemoryRepository[domain.Ruleset](), nil, &sync.Mutex{})
+
+			// Os handlers que veem o fim da partida correm juntos: só um deles pode acertá-la.
+			var granted, failed atomic.Int32
+			var wait sync.WaitGroup
+			for range 8 {
+				wait.Add(1)
+				go func() {
+					defer wait.Done()
+					claimed, err := service.ClaimSettlement("7")
+					if err != nil {
+						failed.Add(1)
+					}
+					if claimed {
+						granted.Add(1)
+					}
+				}()
+			}
+			wait.Wait()
+
+			if (failed.Load() > 0) != test.wantErr {
+				t.Errorf("%d pedidos falharam, esperado erro: %v", failed.Load(), test.wantErr)
+			}
+			if int(granted.Load()) != test.wantGranted {
+				t.Errorf("%d acertos concedidos, esperado %d", granted.Load(), test.wantGranted)
+			}
+		})
+	}
+}
+
+// newTestGameService cria os serviços de salas e de partidas sobre os mesmos repositórios, sem apostas.
+func newTestGameService(t *testing.T) (*RoomService, *GameService, *data.InMemoryRepository[domain.Game]) {
+	t.Helper()
+	roomMutex := &sync.Mutex{}
+	rooms := NewRoomService(data.NewInMemoryRepository[domain.Room](), newTestRulesets(t), roomMutex)
+	gameRepo := data.NewInMemoryRepository[domain.Game]()
+	games := NewGameService(gameRepo, data.NewInMemoryRepository[domain.User](), rooms.RoomRepo, rooms.RulesetRepo, nil, roomMutex)
+	return rooms, games, gameRepo
+}
+
+// newDuelRoom cria a sala 7 no estado informado, com alice e bob jogando e carol assistindo.
+func newDuelRoom(status string) domain.Room {
+	room := domain.NewRoom("7", "Sala 7", "alice")
+	room.Status = status
+	room.UserIDs.Add("alice")
+	room.UserIDs.Add("bob")
+	room.Spectators.Add("carol")
+	return *room
+}
+
+func TestSetReady(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		readyFirst  []string
+		playerID    string
+		ready       bool
+		wantErr     bool
+		wantStarted bool
+		wantStatus  string
+		wantReady   int
+	}{
+		{name: "primeiro jogador pronto", status: domain.RoomStatusReadyCheck, playerID: "alice", ready: true, wantStatus: domain.RoomStatusReadyCheck, wantReady: 1},
+		{name: "segundo jogador começa a partida", status: domain.RoomStatusReadyCheck, readyFirst: []string{"alice"}, playerID: "bob", ready: true, wantStarted: true, wantStatus: domain.RoomStatusPlaying},
+		{name: "desiste de estar pronto", status: domain.RoomStatusReadyCheck, readyFirst: []string{"alice"}, playerID: "alice", wantStatus: domain.RoomStatusReadyCheck},
+		{name: "nova série após a partida encerrada", status: domain.RoomStatusFinished, playerID: "alice", ready: true, wantStatus: domain.RoomStatusReadyCheck, wantReady: 1},
+		{name: "sala esperando jogadores", status: domain.RoomStatusWaiting, playerID: "alice", ready: true, wantErr: true, wantStatus: domain.RoomStatusWaiting},
+		{name: "partida em andamento", status: domain.RoomStatusPlaying, playerID: "alice", ready: true, wantErr: true, wantStatus: domain.RoomStatusPlaying},
+		{name: "espectador", status: domain.RoomStatusReadyCheck, playerID: "carol", ready: true, wantErr: true, wantStatus: domain.RoomStatusReadyCheck},
+		{name: "quem não está na sala", status: domain.RoomStatusReadyCheck, playerID: "dave", ready: true, wantErr: true, wantStatus: domain.RoomStatusReadyCheck},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rooms, games, gameRepo := newTestGameService(t)
+			room := newDuelRoom(test.status)
+			for _, playerID := range test.readyFirst {
+				room.Ready.Add(playerID)
+			}
+			rooms.RoomRepo.Create(room.ID, room)
+
+			started, err := games.SetReady("7", test.playerID, test.ready)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("erro = %v, esperado erro: %v", err, test.wantErr)
+			}
+			if started != test.wantStarted {
+				t.Errorf("partida iniciada: %v, esperado %v", started, test.wantStarted)
+			}
+			got, _ := rooms.GetRoom("7")
+			if got.Status != test.wantStatus {
+				t.Errorf("estado = %s, esperado %s", got.Status, test.wantStatus)
+			}
+			if !test.wantErr && got.Ready.Size() != test.wantReady {
+				t.Errorf("%d jogadores prontos, esperado %d", got.Ready.Size(), test.wantReady)
+			}
+			if _, err := gameRepo.Read("7"); (err == nil) != test.wantStarted {
+				t.Errorf("partida gravada: %v, esperado %v", err == nil, test.wantStarted)
+			}
+		})
+	}
+}
+
+func TestRematch(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		wagerCards  int
+		requested   []string
+		expired     bool
+		playerID    string
+		accept      bool
+		wantErr     error
+		wantStarted bool
+		wantPending int
+	}{
+		{name: "primeiro pedido", status: domain.RoomStatusFinished, playerID: "alice", accept: true, wantPending: 1},
+		{name: "segundo pedido começa a próxima partida", status: domain.RoomStatusFinished, requested: []string{"alice"}, playerID: "bob", accept: true, wantStarted: true},
+		{name: "pedido repetido", status: domain.RoomStatusFinished, requested: []string{"alice"}, playerID: "alice", accept: true, wantPending: 1},
+		{name: "pedido anterior expirado", status: domain.RoomStatusFinished, requested: []string{"alice"}, expired: true, playerID: "bob", accept: true, wantPending: 1},
+		{name: "recusa descarta o pedido", status: domain.RoomStatusFinished, requested: []string{"alice"}, playerID: "bob"},
+		{name: "partida em andamento", status: domain.RoomStatusPlaying, playerID: "alice", accept: true, wantErr: errors.New("não há partida encerrada para revanche")},
+		{name: "espectador", status: domain.RoomStatusFinished, playerID: "carol", accept: true, wantErr: errors.New("jogador não está na sala")},
+		{name: "sala que aposta cartas", status: domain.RoomStatusFinished, wagerCards: 1, playerID: "alice", accept: true, wantErr: ErrWagerRematch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rooms, games, gameRepo := newTestGameService(t)
+			room := newDuelRoom(test.status)
+			room.WagerCards = test.wagerCards
+			for _, playerID := range test.requested {
+				room.RematchRequests.Add(playerID)
+				room.RematchDeadline = time.Now().Add(RematchWindow)
+			}
+			if test.expired {
+				room.RematchDeadline = time.Now().Add(-time.Second)
+			}
+			rooms.RoomRepo.Create(room.ID, room)
+			previous := domain.NewGame("7", []string{"alice", "bob"})
+			previous.Series.Set("alice", 1)
+			gameRepo.Create(previous.ID, previous)
+
+			started, err := games.Rematch("7", test.playerID, test.accept)
+			if err != nil || test.wantErr != nil {
+				if err == nil || test.wantErr == nil || err.Error() != test.wantErr.Error() {
+					t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+				}
+				return
+			}
+			if started != test.wantStarted {
+				t.Errorf("partida iniciada: %v, esperado %v", started, test.wantStarted)
+			}
+			got, _ := rooms.GetRoom("7")
+			if got.RematchRequests.Size() != test.wantPending {
+				t.Errorf("%d pedidos pendentes, esperado %d", got.RematchRequests.Size(), test.wantPending)
+			}
+			if !test.wantStarted {
+				if got.Status != domain.RoomStatusFinished {
+					t.Errorf("estado = %s, esperado %s", got.Status, domain.RoomStatusFinished)
+				}
+				return
+			}
+			game, _ := gameRepo.Read("7")
+			// A próxima partida inverte os assentos e mantém o placar da série.
+			if got.Status != domain.RoomStatusPlaying || game.Number != 2 || !equalStrings(game.Seats, []string{"bob", "alice"}) {
+				t.Errorf("sala %s, partida %d com assentos %v", got.Status, game.Number, game.Seats)
+			}
+			if wins, _ := game.Series.Get("alice"); wins != 1 {
+				t.Errorf("série de alice = %d, esperado 1", wins)
+			}
+		})
+	}
+}
+
+func TestRoundEndsMatch(t *testing.T) {
+	rulesets := newTestRulesets(t)
+	tests := []struct {
+		name       string
+		ruleset    string
+		round      int
+		scores     [2]int
+		plays      [2]domain.Card
+		wantOver   bool
+		wantWinner string
+	}{
+		{name: "clássico na segunda vitória", ruleset: "classic", round: 2, scores: [2]int{1, 0}, plays: [2]domain.Card{{Type: "rock", Stars: 1}, {Type: "scissors", Stars: 1}}, wantOver: true, wantWinner: "alice"},
+		{name: "clássico sem limite de rodadas", ruleset: "classic", round: 20, plays: [2]domain.Card{{Type: "rock", Stars: 1}, {Type: "rock", Stars: 1}}},
+		{name: "rpsls exige a terceira vitória", ruleset: "rpsls", round: 3, scores: [2]int{0, 1}, plays: [2]domain.Card{{Type: "rock", Stars: 1}, {Type: "spock", Stars: 1}}},
+		{name: "rpsls na terceira vitória", ruleset: "rpsls", round: 4, scores: [2]int{0, 2}, plays: [2]domain.Card{{Type: "lizard", Stars: 1}, {Type: "scissors", Stars: 1}}, wantOver: true, wantWinner: "bob"},
+		{name: "azarão: menos estrelas vencem", ruleset: "underdog", round: 2, scores: [2]int{1, 0}, plays: [2]domain.Card{{Type: "paper", Stars: 1}, {Type: "paper", Stars: 3}}, wantOver: true, wantWinner: "alice"},
+		{name: "azarão empata no limite de rodadas", ruleset: "underdog", round: 5, scores: [2]int{1, 1}, plays: [2]domain.Card{{Type: "rock", Stars: 2}, {Type: "rock", Stars: 2}}, wantOver: true},
+		{name: "azarão decide pelo placar no limite", ruleset: "underdog", round: 5, scores: [2]int{1, 0}, plays: [2]domain.Card{{Type: "rock", Stars: 2}, {Type: "rock", Stars: 2}}, wantOver: true, wantWinner: "alice"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ruleset, err := rulesets.Read(test.ruleset)
+			if err != nil {
+				t.Fatalf("regras %s: %v", test.ruleset, err)
+			}
+			game := domain.NewGame("7", []string{"alice", "bob"})
+			for _, playerID := range game.Seats {
+				game.Deal(playerID, domain.BasicDeck(ruleset))
+			}
+			game.Round = test.round
+			game.Scores.Set("alice", test.scores[0])
+			game.Scores.Set("bob", test.scores[1])
+			game.Plays.Set("alice", test.plays[0])
+			game.Plays.Set("bob", test.plays[1])
+
+			result := (&GameService{}).resolveRound(&game, ruleset)
+			if result.MatchFinished != test.wantOver || result.MatchWinnerID != test.wantWinner {
+				t.Errorf("partida encerrada %v com vencedor %q, esperado %v e %q", result.MatchFinished, result.MatchWinnerID, test.wantOver, test.wantWinner)
+			}
+			if game.WinnerID != test.wantWinner {
+				t.Errorf("vencedor gravado = %q, esperado %q", game.WinnerID, test.wantWinner)
+			}
+		})
+	}
+}
+
+// revealNonce é o nonce usado nas jogadas com compromisso dos testes.
+const revealNonce = "0123456789abcdef"
+
+// newCommitGame cria a partida da sala 7 com compromisso e revelação em andamento, com uma
+// pedra na mão de alice e uma tesoura na de bob, e os compromissos já enviados.
+func newCommitGame(t *testing.T, commitReveal bool, commitments map[string]string) (*RoomService, *GameService, *data.InMemoryRepository[domain.Game]) {
+	t.Helper()
+	rooms, games, gameRepo := newTestGameService(t)
+	room := newDuelRoom(domain.RoomStatusPlaying)
+	room.CommitReveal = commitReveal
+	rooms.RoomRepo.Create(room.ID, room)
+	game := domain.NewGame("7", []string{"alice", "bob"})
+	game.Hands.Set("alice", []domain.OwnedCard{{ID: "a1", Card: domain.Card{Type: "rock", Stars: 1}}})
+	game.Hands.Set("bob", []domain.OwnedCard{{ID: "b1", Card: domain.Card{Type: "scissors", Stars: 1}}})
+	for playerID, commitment := range commitments {
+		game.Commitments.Set(playerID, commitment)
+	}
+	gameRepo.Create(game.ID, game)
+	return rooms, games, gameRepo
+}
+
+func TestCommit(t *testing.T) {
+	valid := domain.Commitment("a1", revealNonce)
+	tests := []struct {
+		name         string
+		commitReveal bool
+		commitments  map[string]string
+		commitment   string
+		wantErr      bool
+		wantBoth     bool
+	}{
+		{name: "primeiro compromisso", commitReveal: true, commitment: valid},
+		{name: "segundo compromisso libera a revelação", commitReveal: true, commitments: map[string]string{"bob": valid}, commitment: valid, wantBoth: true},
+		{name: "compromisso repetido", commitReveal: true, commitments: map[string]string{"alice": valid}, commitment: valid, wantErr: true},
+		{name: "formato inválido", commitReveal: true, commitment: "a1", wantErr: true},
+		{name: "sala sem compromisso", commitment: valid, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, games, gameRepo := newCommitGame(t, test.commitReveal, test.commitments)
+			both, err := games.Commit("7", "alice", test.commitment)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("erro = %v, esperado erro: %v", err, test.wantErr)
+			}
+			if both != test.wantBoth {
+				t.Errorf("os dois se comprometeram: %v, esperado %v", both, test.wantBoth)
+			}
+			game, _ := gameRepo.Read("7")
+			if got, _ := game.Commitments.Get("alice"); !test.wantErr && got != test.commitment {
+				t.Errorf("compromisso gravado = %q, esperado %q", got, test.commitment)
+			}
+		})
+	}
+}
+
+func TestPlayCardRequiresCommit(t *testing.T) {
+	_, games, _ := newCommitGame(t, true, nil)
+	if _, err := games.PlayCard("7", "alice", "a1"); !errors.Is(err, ErrCommitRequired) {
+		t.Errorf("erro = %v, esperado %v", err, ErrCommitRequired)
+	}
+}
+
+func TestReveal(t *testing.T) {
+	both := map[string]string{"alice": domain.Commitment("a1", revealNonce), "bob": domain.Commitment("b1", revealNonce)}
+	tests := []struct {
+		name         string
+		commitments  map[string]string
+		attempts     int
+		cardRef      string
+		nonce        string
+		wantErr      error
+		wantPlayed   bool
+		wantAttempts int
+		wantStatus   string
+	}{
+		{name: "revelação correta joga a carta", commitments: both, cardRef: "a1", nonce: revealNonce, wantPlayed: true, wantStatus: domain.RoomStatusPlaying},
+		{name: "nonce errado conta uma tentativa", commitments: both, cardRef: "a1", nonce: "fedcba9876543210", wantErr: errors.New("a revelação não confere com o compromisso ou a carta não está na sua mão (1 de 3 tentativas)"), wantAttempts: 1, wantStatus: domain.RoomStatusPlaying},
+		{
+			name:         "nonce curto conta uma tentativa",
+			commitments:  map[string]string{"alice": domain.Commitment("a1", "curto"), "bob": both["bob"]},
+			cardRef:      "a1",
+			nonce:        "curto",
+			wantErr:      errors.New("a revelação não confere com o compromisso ou a carta não está na sua mão (1 de 3 tentativas)"),
+			wantAttempts: 1,
+			wantStatus:   domain.RoomStatusPlaying,
+		},
+		{
+			name:         "carta fora da mão conta uma tentativa",
+			commitments:  map[string]string{"alice": domain.Commitment("b1", revealNonce), "bob": both["bob"]},
+			cardRef:      "b1",
+			nonce:        revealNonce,
+			wantErr:      errors.New("a revelação não confere com o compromisso ou a carta não está na sua mão (1 de 3 tentativas)"),
+			wantAttempts: 1,
+			wantStatus:   domain.RoomStatusPlaying,
+		},
+		// A derrota encerra a rodada, o que descarta as tentativas.
+		{name: "última tentativa perde a partida", commitments: both, attempts: domain.MaxRevealAttempts - 1, cardRef: "a1", nonce: "fedcba9876543210", wantErr: ErrRevealForfeit, wantStatus: domain.RoomStatusFinished},
+		{name: "oponente sem compromisso", commitments: map[string]string{"alice": both["alice"]}, cardRef: "a1", nonce: revealNonce, wantErr: errors.New("aguarde o compromisso do oponente antes de revelar"), wantStatus: domain.RoomStatusPlaying},
+		{name: "sem compromisso", cardRef: "a1", nonce: revealNonce, wantErr: errors.New("envie o compromisso antes de revelar"), wantStatus: domain.RoomStatusPlaying},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rooms, games, gameRepo := newCommitGame(t, true, test.commitments)
+			if test.attempts > 0 {
+				game, _ := gameRepo.Read("7")
+				game.FailedAttempts.Set("alice", test.attempts)
+				gameRepo.Update(game.ID, game)
+			}
+
+			result, err := games.Reveal("7", "alice", test.cardRef, test.nonce)
+			if err != nil || test.wantErr != nil {
+				if err == nil || test.wantErr == nil || err.Error() != test.wantErr.Error() {
+					t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+				}
+			}
+			game, _ := gameRepo.Read("7")
+			if _, played := game.Plays.Get("alice"); played != test.wantPlayed {
+				t.Errorf("carta jogada: %v, esperado %v", played, test.wantPlayed)
+			}
+			if attempts, _ := game.FailedAttempts.Get("alice"); attempts != test.wantAttempts {
+				t.Errorf("%d tentativas, esperado %d", attempts, test.wantAttempts)
+			}
+			if room, _ := rooms.GetRoom("7"); room.Status != test.wantStatus {
+				t.Errorf("estado = %s, esperado %s", room.Status, test.wantStatus)
+			}
+			if errors.Is(err, ErrRevealForfeit) && (result == nil || game.WinnerID != "bob") {
+				t.Errorf("partida perdida com resultado %v e vencedor %q", result, game.WinnerID)
+			}
+		})
+	}
+}
+
+func TestRevealResolvesRound(t *testing.T) {
+	_, games, _ := newCommitGame(t, true, map[string]string{"alice": domain.Commitment("rock", revealNonce), "bob": domain.Commitment("b1", revealNonce)})
+	if result, err := games.Reveal("7", "alice", "rock", revealNonce); err != nil || result != nil {
+		t.Fatalf("primeira revelação = %v, %v", result, err)
+	}
+	result, err := games.Reveal("7", "bob", "b1", revealNonce)
+	if err != nil {
+		t.Fatalf("Reveal: %v", err)
+	}
+	if result == nil || result.WinnerID != "alice" {
+		t.Errorf("resultado da rodada = %+v, esperado vitória de alice", result)
+	}
+}
+
+// newAsyncGame cria uma sala por correspondência em andamento entre alice e bob, com o prazo da
+// rodada a partir de agora e as jogadas já feitas.
+func newAsyncGame(rooms *RoomService, gameRepo *data.InMemoryRepository[domain.Game], roomID string, deadline time.Duration, played ...string) {
+	room := domain.NewRoom(roomID, "Sala "+roomID, "alice")
+	room.Status = domain.RoomStatusPlaying
+	room.Async = true
+	room.TurnHours = 24
+	room.UserIDs.Add("alice")
+	room.UserIDs.Add("bob")
+	rooms.RoomRepo.Create(room.ID, *room)
+	game := domain.NewGame(roomID, []string{"alice", "bob"})
+	if deadline != 0 {
+		game.TurnDeadline = time.Now().UTC().Add(deadline)
+	}
+	for _, playerID := range played {
+		game.Plays.Set(playerID, domain.Card{Type: "rock", Stars: 1})
+	}
+	gameRepo.Create(game.ID, game)
+}
+
+func TestAsyncGames(t *testing.T) {
+	rooms, games, gameRepo := newTestGameService(t)
+	newAsyncGame(rooms, gameRepo, "1", 2*time.Hour)
+	newAsyncGame(rooms, gameRepo, "2", time.Hour, "alice")
+	newAsyncGame(rooms, gameRepo, "3", 3*time.Hour, "bob")
+	newAsyncGame(rooms, gameRepo, "4", 4*time.Hour, "alice")
+	// Salas que não entram na lista: a partida ao vivo e a encerrada.
+	live := newDuelRoom(domain.RoomStatusPlaying)
+	rooms.RoomRepo.Create(live.ID, live)
+	gameRepo.Create(live.ID, domain.NewGame(live.ID, []string{"alice", "bob"}))
+	newAsyncGame(rooms, gameRepo, "5", time.Hour)
+	finished, _ := rooms.RoomRepo.Read("5")
+	finished.Status = domain.RoomStatusFinished
+	rooms.RoomRepo.Update("5", finished)
+
+	list, err := games.AsyncGames("alice")
+	if err != nil {
+		t.Fatalf("AsyncGames: %v", err)
+	}
+	var got []string
+	for _, game := range list {
+		got = append(got, fmt.Sprintf("%s:%v", game.Room.ID, game.YourTurn))
+	}
+	// Primeiro as que aguardam alice, depois pelo prazo da rodada.
+	want := []string{"1:true", "3:true", "2:false", "4:false"}
+	if !equalStrings(got, want) {
+		t.Errorf("partidas de alice = %v, esperado %v", got, want)
+	}
+	if list, _ := games.AsyncGames("carol"); len(list) != 0 {
+		t.Errorf("carol tem %d partidas, esperado nenhuma", len(list))
+	}
+}
+
+func TestExpireTurns(t *testing.T) {
+	tests := []struct {
+		name        string
+		deadline    time.Duration
+		played      []string
+		wantExpired bool
+		wantLoser   string
+		wantWinner  string
+	}{
+		{name: "rodada no prazo", deadline: time.Hour},
+		{name: "quem não jogou perde", deadline: -time.Minute, played: []string{"alice"}, wantExpired: true, wantLoser: "bob", wantWinner: "alice"},
+		{name: "ninguém jogou e a partida empata", deadline: -time.Minute, wantExpired: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rooms, games, gameRepo := newTestGameService(t)
+			newAsyncGame(rooms, gameRepo, "1", test.deadline, test.played...)
+
+			expired, err := games.ExpireTurns()
+			if err != nil {
+				t.Fatalf("ExpireTurns: %v", err)
+			}
+			result, ended := expired["1"]
+			if ended != test.wantExpired {
+				t.Fatalf("partida encerrada = %v, esperado %v", ended, test.wantExpired)
+			}
+			room, _ := rooms.RoomRepo.Read("1")
+			if !ended {
+				if room.Status != domain.RoomStatusPlaying {
+					t.Errorf("estado da sala = %s, esperado %s", room.Status, domain.RoomStatusPlaying)
+				}
+				return
+			}
+			if !result.TimedOut || !result.MatchFinished || result.ForfeitedBy != test.wantLoser || result.MatchWinnerID != test.wantWinner {
+				t.Errorf("resultado = %+v", result)
+			}
+			game, _ := gameRepo.Read("1")
+			if room.Status != domain.RoomStatusFinished || !game.TurnDeadline.IsZero() {
+				t.Errorf("sala %s com prazo %v", room.Status, game.TurnDeadline)
+			}
+		})
+	}
+
+	t.Run("partida sem prazo ganha um", func(t *testing.T) {
+		rooms, games, gameRepo := newTestGameService(t)
+		newAsyncGame(rooms, gameRepo, "1", 0)
+		if expired, err := games.ExpireTurns(); err != nil || len(expired) != 0 {
+			t.Fatalf("ExpireTurns = %v, %v", expired, err)
+		}
+		game, _ := gameRepo.Read("1")
+		if game.TurnDeadline.Before(time.Now().Add(23 * time.Hour)) {
+			t.Errorf("prazo = %v, esperado daqui a 24 horas", game.TurnDeadline)
+		}
+	})
+}
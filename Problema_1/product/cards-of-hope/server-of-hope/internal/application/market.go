@@ -0,0 +1,479 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Durações dos anúncios do mercado.
+const (
+	DefaultListingDuration = 24 * time.Hour     // validade de um anúncio de preço fixo sem duração informada
+	DefaultAuctionDuration = time.Hour          // duração de um leilão sem duração informada
+	MaxListingDuration     = 7 * 24 * time.Hour // duração máxima de um anúncio
+)
+
+// MarketSettleInterval define de quanto em quanto tempo os anúncios vencidos são fechados.
+const MarketSettleInterval = 5 * time.Second
+
+// Erros do mercado exibidos diretamente aos usuários.
+var (
+	ErrListingNotFound  = errors.New("Anúncio não encontrado")
+	ErrListingClosed    = errors.New("O anúncio não está mais aberto")
+	ErrListingEnded     = errors.New("O anúncio já terminou e está sendo fechado")
+	ErrListingKind      = errors.New("O anúncio deve ser de preço fixo ou leilão")
+	ErrListingCards     = fmt.Errorf("Um anúncio precisa de 1 a %d cartas", domain.MaxListingCards)
+	ErrListingPrice     = errors.New("O preço deve ser de ao menos 1 moeda")
+	ErrListingDuration  = errors.New("A duração do anúncio deve ser de 1 minuto a 7 dias")
+	ErrListingBot       = errors.New("Bots não usam o mercado")
+	ErrListingCardOwner = errors.New("A carta não está na coleção do vendedor")
+	ErrOwnListing       = errors.New("Não é possível comprar o próprio anúncio nem dar lances nele")
+	ErrNotFixedPrice    = errors.New("O anúncio é um leilão: dê um lance")
+	ErrNotAuction       = errors.New("O anúncio tem preço fixo: compre-o")
+	ErrBidTooLow        = errors.New("Lance abaixo do mínimo")
+	ErrNotListingSeller = errors.New("Apenas o vendedor pode retirar o anúncio")
+	ErrListingHasBids   = errors.New("Um leilão com lances não pode ser retirado")
+)
+
+// MarketServiceInterface descreve as operações do mercado de cartas entre usuários.
+//
+// Métodos:
+//   - List: busca os anúncios abertos.
+//   - Get: recupera um anúncio.
+//   - Sell: anuncia cartas da coleção.
+//   - Buy: compra um anúncio de preço fixo.
+//   - Bid: dá um lance em um leilão.
+//   - Cancel: retira um anúncio.
+//   - Settle: fecha os anúncios que terminaram.
+type MarketServiceInterface interface {
+	// List busca os anúncios abertos, dos que terminam antes aos que terminam depois.
+	//
+	// Parâmetros:
+	//   - filter: tipo de carta, faixa de estrelas e vendedor procurados.
+	//
+	// Retorno:
+	//   - []domain.Listing: anúncios encontrados.
+	//   - erro caso não seja possível listar os anúncios.
+	List(filter domain.ListingFilter) ([]domain.Listing, error)
+
+	// Get recupera um anúncio, em qualquer estado.
+	//
+	// Parâmetros:
+	//   - listingID: identificador do anúncio.
+	//
+	// Retorno:
+	//   - domain.Listing: anúncio encontrado.
+	//   - erro caso o anúncio não exista.
+	Get(listingID string) (domain.Listing, error)
+
+	// Sell anuncia cartas da coleção do vendedor, que ficam retidas no anúncio.
+	//
+	// Parâmetros:
+	//   - sellerID: usuário que anuncia.
+	//   - kind: tipo do anúncio (fixed, auction).
+	//   - cardIDs: IDs das cartas da coleção.
+	//   - price: preço pedido ou lance mínimo, em moedas.
+	//   - duration: duração do anúncio (zero usa a duração padrão do tipo).
+	//
+	// Retorno:
+	//   - domain.Listing: anúncio criado.
+	//   - erro caso o anúncio seja inválido ou alguma carta não esteja na coleção.
+	Sell(sellerID, kind string, cardIDs []string, price int, duration time.Duration) (domain.Listing, error)
+
+	// Buy compra um anúncio de preço fixo: as moedas vão para o vendedor e as cartas para a
+	// coleção do comprador.
+	//
+	// Parâmetros:
+	//   - listingID: identificador do anúncio.
+	//   - buyerID: usuário que compra.
+	//
+	// Retorno:
+	//   - domain.Listing: anúncio vendido.
+	//   - []domain.OwnedCard: cartas recebidas, com os IDs da coleção do comprador.
+	//   - erro caso o anúncio não esteja aberto ou o saldo não cubra o preço.
+	Buy(listingID, buyerID string) (domain.Listing, []domain.OwnedCard, error)
+
+	// Bid dá um lance em um leilão. As moedas do lance ficam retidas, e o lance superado é
+	// devolvido a quem o deu.
+	//
+	// Parâmetros:
+	//   - listingID: identificador do leilão.
+	//   - userID: usuário que dá o lance.
+	//   - amount: valor do lance, em moedas.
+	//
+	// Retorno:
+	//   - domain.Listing: leilão com o novo lance no fim de Bids.
+	//   - erro caso o leilão não esteja aberto, o lance seja baixo ou o saldo não o cubra.
+	Bid(listingID, userID string, amount int) (domain.Listing, error)
+
+	// Cancel retira um anúncio e devolve as cartas ao vendedor. Leilões com lances não podem
+	// ser retirados.
+	//
+	// Parâmetros:
+	//   - listingID: identificador do anúncio.
+	//   - sellerID: vendedor do anúncio.
+	//
+	// Retorno:
+	//   - domain.Listing: anúncio retirado.
+	//   - erro caso o anúncio não possa ser retirado.
+	Cancel(listingID, sellerID string) (domain.Listing, error)
+
+	// Settle fecha os anúncios abertos que passaram do prazo: o leilão com lances vai para o
+	// maior lance, e os demais anúncios devolvem as cartas ao vendedor.
+	//
+	// Retorno:
+	//   - []domain.Listing: anúncios fechados.
+	//   - erro agregando os anúncios que não puderam ser fechados, que ficam para a próxima vez.
+	Settle() ([]domain.Listing, error)
+}
+
+// MarketService implementa o mercado de cartas. As cartas anunciadas saem da coleção pelo
+// DeckService e as moedas dos lances são debitadas pelo WalletService, de modo que cartas e
+// moedas ficam retidas até o anúncio terminar.
+//
+// Campos:
+//   - listingRepo: repositório dos anúncios.
+//   - userRepo: repositório dos usuários.
+//   - deckService: serviço das coleções, de onde saem e para onde vão as cartas.
+//   - walletService: serviço das carteiras, que movimenta as moedas.
+//   - mutex: serializa as mudanças nos anúncios.
+type MarketService struct {
+	listingRepo   data.RepositoryInterface[domain.Listing]
+	userRepo      data.RepositoryInterface[domain.User]
+	deckService   DeckServiceInterface
+	walletService WalletServiceInterface
+	mutex         sync.Mutex
+}
+
+// NewMarketService cria uma nova instância de MarketService.
+//
+// Parâmetros:
+//   - listingRepo: repositório dos anúncios.
+//   - userRepo: repositório dos usuários.
+//   - deckService: serviço das coleções de cartas.
+//   - walletService: serviço das carteiras de moedas.
+//
+// Retorno:
+//   - ponteiro para MarketService.
+func NewMarketService(
+	listingRepo data.RepositoryInterface[domain.Listing],
+	userRepo data.RepositoryInterface[domain.User],
+	deckService DeckServiceInterface,
+	walletService WalletServiceInterface,
+) *MarketService {
+	return &MarketService{
+		listingRepo:   listingRepo,
+		userRepo:      userRepo,
+		deckService:   deckService,
+		walletService: walletService,
+	}
+}
+
+// List busca os anúncios abertos que ainda não terminaram.
+func (service *MarketService) List(filter domain.ListingFilter) ([]domain.Listing, error) {
+	listings, err := service.listingRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var found []domain.Listing
+	for _, listing := range listings {
+		if listing.Status == domain.ListingActive && !listing.Ended() && filter.Matches(listing) {
+			found = append(found, listing)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].EndsAt.Before(found[j].EndsAt)
+	})
+	return found, nil
+}
+
+// Get recupera um anúncio.
+func (service *MarketService) Get(listingID string) (domain.Listing, error) {
+	listing, err := service.listingRepo.Read(listingID)
+	if err != nil {
+		return domain.Listing{}, ErrListingNotFound
+	}
+	return listing, nil
+}
+
+// Sell anuncia cartas da coleção do vendedor, retirando-as da coleção.
+func (service *MarketService) Sell(sellerID, kind string, cardIDs []string, price int, duration time.Duration) (domain.Listing, error) {
+	if duration == 0 {
+		duration = DefaultListingDuration
+		if kind == domain.ListingAuction {
+			duration = DefaultAuctionDuration
+		}
+	}
+	switch {
+	case !domain.ValidListingKind(kind):
+		return domain.Listing{}, ErrListingKind
+	case len(cardIDs) == 0 || len(cardIDs) > domain.MaxListingCards:
+		return domain.Listing{}, ErrListingCards
+	case price < 1:
+		return domain.Listing{}, ErrListingPrice
+	case duration < time.Minute || duration > MaxListingDuration:
+		return domain.Listing{}, ErrListingDuration
+	}
+	user, err := service.userRepo.Read(sellerID)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	if user.Bot {
+		return domain.Listing{}, ErrListingBot
+	}
+
+	taken, err := service.deckService.TakeCards(sellerID, cardIDs)
+	if err != nil {
+		return domain.Listing{}, fmt.Errorf("%w (%v)", ErrListingCardOwner, err)
+	}
+	now := time.Now().UTC()
+	listing := domain.Listing{
+		ID:        utils.Count(),
+		SellerID:  sellerID,
+		Kind:      kind,
+		Cards:     plainCards(taken),
+		Price:     price,
+		Status:    domain.ListingActive,
+		CreatedAt: now,
+		EndsAt:    now.Add(duration),
+	}
+	if err := service.listingRepo.Create(listing.ID, listing); err != nil {
+		service.deckService.AddCards(sellerID, listing.Cards...)
+		return domain.Listing{}, err
+	}
+	return listing, nil
+}
+
+// Buy compra um anúncio de preço fixo. O anúncio é fechado antes de qualquer moeda ou carta
+// mudar de dono, para que não possa ser comprado de novo, e cada passo que falha desfaz os
+// anteriores e reabre o anúncio.
+func (service *MarketService) Buy(listingID, buyerID string) (domain.Listing, []domain.OwnedCard, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	listing, err := service.open(listingID, buyerID)
+	if err != nil {
+		return listing, nil, err
+	}
+	if listing.Kind != domain.ListingFixed {
+		return listing, nil, ErrNotFixedPrice
+	}
+
+	sold, err := service.close(listing, domain.ListingSold, buyerID)
+	if err != nil {
+		return listing, nil, err
+	}
+	if _, err := service.walletService.Debit(buyerID, listing.Price, domain.LedgerMarketBuy, listing.ID); err != nil {
+		return listing, nil, service.restore(listing, err)
+	}
+	received, err := service.deckService.AddCards(buyerID, listing.Cards...)
+	if err != nil {
+		err = service.refund(buyerID, listing.Price, domain.LedgerRefund, listing.ID, err)
+		return listing, nil, service.restore(listing, err)
+	}
+	if _, err := service.walletService.Credit(listing.SellerID, listing.Price, domain.LedgerSale, listing.ID); err != nil {
+		err = service.takeBack(buyerID, received, err)
+		err = service.refund(buyerID, listing.Price, domain.LedgerRefund, listing.ID, err)
+		return listing, nil, service.restore(listing, err)
+	}
+	return sold, received, nil
+}
+
+// Bid dá um lance em um leilão. Quem cobre o próprio lance paga só a diferença.
+func (service *MarketService) Bid(listingID, userID string, amount int) (domain.Listing, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	listing, err := service.open(listingID, userID)
+	if err != nil {
+		return listing, err
+	}
+	if listing.Kind != domain.ListingAuction {
+		return listing, ErrNotAuction
+	}
+	if minimum := listing.MinimumBid(); amount < minimum {
+		return listing, fmt.Errorf("%w: o lance mínimo é de %d moedas", ErrBidTooLow, minimum)
+	}
+
+	// O lance é registrado antes de as moedas serem retidas; se a retenção ou a devolução ao
+	// lance anterior falhar, o anúncio volta aos lances de antes.
+	previous, hasPrevious := listing.HighestBid()
+	// Os lances são recriados porque outras leituras do anúncio podem estar usando os atuais.
+	bids := make([]domain.Bid, 0, len(listing.Bids)+1)
+	bids = append(bids, listing.Bids...)
+	bid := listing
+	bid.Bids = append(bids, domain.Bid{UserID: userID, Amount: amount, CreatedAt: time.Now().UTC()})
+	if err := service.listingRepo.Update(bid.ID, bid); err != nil {
+		return listing, err
+	}
+
+	if hasPrevious && previous.UserID == userID {
+		if _, err := service.walletService.Debit(userID, amount-previous.Amount, domain.LedgerBid, listing.ID); err != nil {
+			return listing, service.restore(listing, err)
+		}
+		return bid, nil
+	}
+	if _, err := service.walletService.Debit(userID, amount, domain.LedgerBid, listing.ID); err != nil {
+		return listing, service.restore(listing, err)
+	}
+	if hasPrevious {
+		if _, err := service.walletService.Credit(previous.UserID, previous.Amount, domain.LedgerBidRefund, listing.ID); err != nil {
+			err = service.refund(userID, amount, domain.LedgerBidRefund, listing.ID, err)
+			return listing, service.restore(listing, err)
+		}
+	}
+	return bid, nil
+}
+
+// Cancel retira um anúncio e devolve as cartas ao vendedor.
+func (service *MarketService) Cancel(listingID, sellerID string) (domain.Listing, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	listing, err := service.listingRepo.Read(listingID)
+	if err != nil {
+		return domain.Listing{}, ErrListingNotFound
+	}
+	switch {
+	case listing.SellerID != sellerID:
+		return listing, ErrNotListingSeller
+	case listing.Status != domain.ListingActive:
+		return listing, ErrListingClosed
+	case len(listing.Bids) > 0:
+		return listing, ErrListingHasBids
+	}
+	cancelled, err := service.close(listing, domain.ListingCancelled, "")
+	if err != nil {
+		return listing, err
+	}
+	if _, err := service.deckService.AddCards(sellerID, listing.Cards...); err != nil {
+		return listing, service.restore(listing, err)
+	}
+	return cancelled, nil
+}
+
+// Settle fecha os anúncios abertos que passaram do prazo.
+func (service *MarketService) Settle() ([]domain.Listing, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	listings, err := service.listingRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var settled []domain.Listing
+	var problems []error
+	for _, listing := range listings {
+		if !listing.Ended() {
+			continue
+		}
+		closed, err := service.settle(listing)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("anúncio %s: %w", listing.ID, err))
+			continue
+		}
+		settled = append(settled, closed)
+	}
+	return settled, errors.Join(problems...)
+}
+
+// settle fecha um anúncio que passou do prazo. O anúncio é fechado antes da entrega das cartas e
+// do pagamento; se algum deles falhar, os passos anteriores são desfeitos e o anúncio volta a
+// ficar aberto, para ser fechado de novo na próxima rodada. Deve ser chamado com mutex travado.
+func (service *MarketService) settle(listing domain.Listing) (domain.Listing, error) {
+	bid, sold := listing.HighestBid()
+	if !sold {
+		expired, err := service.close(listing, domain.ListingExpired, "")
+		if err != nil {
+			return listing, err
+		}
+		if _, err := service.deckService.AddCards(listing.SellerID, listing.Cards...); err != nil {
+			return listing, service.restore(listing, err)
+		}
+		return expired, nil
+	}
+
+	// As moedas do lance já estão retidas: basta entregar as cartas e pagar o vendedor.
+	closed, err := service.close(listing, domain.ListingSold, bid.UserID)
+	if err != nil {
+		return listing, err
+	}
+	received, err := service.deckService.AddCards(bid.UserID, listing.Cards...)
+	if err != nil {
+		return listing, service.restore(listing, err)
+	}
+	if _, err := service.walletService.Credit(listing.SellerID, bid.Amount, domain.LedgerSale, listing.ID); err != nil {
+		return listing, service.restore(listing, service.takeBack(bid.UserID, received, err))
+	}
+	return closed, nil
+}
+
+// open lê um anúncio aberto em que o usuário pode comprar ou dar lances. Deve ser chamado com
+// mutex travado.
+func (service *MarketService) open(listingID, userID string) (domain.Listing, error) {
+	listing, err := service.listingRepo.Read(listingID)
+	if err != nil {
+		return domain.Listing{}, ErrListingNotFound
+	}
+	switch {
+	case listing.Status != domain.ListingActive:
+		return listing, ErrListingClosed
+	case listing.Ended():
+		return listing, ErrListingEnded
+	case listing.SellerID == userID:
+		return listing, ErrOwnListing
+	}
+	return listing, nil
+}
+
+// close encerra o anúncio com o estado e o comprador informados. Deve ser chamado com mutex
+// travado.
+func (service *MarketService) close(listing domain.Listing, status, buyerID string) (domain.Listing, error) {
+	listing.Status = status
+	listing.BuyerID = buyerID
+	listing.SettledAt = time.Now().UTC()
+	return listing, service.listingRepo.Update(listing.ID, listing)
+}
+
+// restore devolve o anúncio ao estado anterior a uma operação que falhou no meio. Deve ser
+// chamado com mutex travado.
+//
+// Retorno:
+//   - o erro que interrompeu a operação, junto com o erro da restauração, se houver.
+func (service *MarketService) restore(listing domain.Listing, cause error) error {
+	if err := service.listingRepo.Update(listing.ID, listing); err != nil {
+		return errors.Join(cause, fmt.Errorf("falha ao restaurar o anúncio %s: %w", listing.ID, err))
+	}
+	return cause
+}
+
+// refund devolve moedas debitadas por uma operação que falhou no meio.
+//
+// Retorno:
+//   - o erro que interrompeu a operação, junto com o erro da devolução, se houver.
+func (service *MarketService) refund(userID string, amount int, reason, reference string, cause error) error {
+	if _, err := service.walletService.Credit(userID, amount, reason, reference); err != nil {
+		return errors.Join(cause, fmt.Errorf("falha ao devolver %d moedas a %s: %w", amount, userID, err))
+	}
+	return cause
+}
+
+// takeBack retira da coleção as cartas entregues por uma operação que falhou no meio.
+//
+// Retorno:
+//   - o erro que interrompeu a operação, junto com o erro da retirada, se houver.
+func (service *MarketService) takeBack(userID string, received []domain.OwnedCard, cause error) error {
+	cardIDs := make([]string, 0, len(received))
+	for _, card := range received {
+		cardIDs = append(cardIDs, card.ID)
+	}
+	if _, err := service.deckService.TakeCards(userID, cardIDs); err != nil {
+		return errors.Join(cause, fmt.Errorf("falha ao retirar as cartas entregues a %s: %w", userID, err))
+	}
+	return cause
+}
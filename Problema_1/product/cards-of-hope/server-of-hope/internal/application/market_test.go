@@ -0,0 +1,353 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sync"
+	"testing"
+	"time"
+)
+
+// marketFixture reúne o mercado e os repositórios usados pelos testes. alice anuncia duas
+// cartas; bob e carol começam com uma carta cada.
+type marketFixture struct {
+	service     *MarketService
+	wallet      *WalletService
+	userRepo    *failingRepository[domain.User]
+	listingRepo *failingRepository[domain.Listing]
+}
+
+func newMarketFixture(t *testing.T) marketFixture {
+	t.Helper()
+	userRepo := newFailingRepository[domain.User]()
+	userRepo.Create("alice", collector("alice", domain.Card{Type: "rock", Stars: 1}, domain.Card{Type: "paper", Stars: 2}))
+	userRepo.Create("bob", collector("bob", domain.Card{Type: "scissors", Stars: 1}))
+	userRepo.Create("carol", collector("carol", domain.Card{Type: "rock", Stars: 3}))
+	userRepo.Create("bot", domain.User{ID: "bot", Bot: true})
+	listingRepo := newFailingRepository[domain.Listing]()
+	wallet := NewWalletService(data.NewInMemoryRepository[domain.LedgerEntry](), userRepo)
+	deckService := NewDeckService(userRepo, data.NewInMemoryRepository[domain.Room](), data.NewInMemoryRepository[domain.Ruleset](), &sync.Mutex{})
+	return marketFixture{
+		service:     NewMarketService(listingRepo, userRepo, deckService, wallet),
+		wallet:      wallet,
+		userRepo:    userRepo,
+		listingRepo: listingRepo,
+	}
+}
+
+// sell anuncia as duas cartas de alice.
+func (fixture marketFixture) sell(t *testing.T, kind string, price int) domain.Listing {
+	t.Helper()
+	listing, err := fixture.service.Sell("alice", kind, []string{"1", "2"}, price, 0)
+	if err != nil {
+		t.Fatalf("Sell: %v", err)
+	}
+	return listing
+}
+
+// end faz o anúncio passar do prazo.
+func (fixture marketFixture) end(t *testing.T, listingID string) {
+	t.Helper()
+	listing, err := fixture.listingRepo.Read(listingID)
+	if err != nil {
+		t.Fatalf("ler anúncio: %v", err)
+	}
+	listing.EndsAt = time.Now().Add(-time.Second)
+	fixture.listingRepo.Update(listingID, listing)
+}
+
+// check confere o estado do anúncio, os saldos e o tamanho das coleções.
+func (fixture marketFixture) check(t *testing.T, listingID, wantStatus string, wantBalances, wantCards map[string]int) {
+	t.Helper()
+	fixture.userRepo.failUpdate = func(string) bool { return false }
+	listing, err := fixture.listingRepo.Read(listingID)
+	if err != nil {
+		t.Fatalf("ler anúncio: %v", err)
+	}
+	if listing.Status != wantStatus {
+		t.Errorf("estado = %s, esperado %s", listing.Status, wantStatus)
+	}
+	checkHoldings(t, fixture.wallet, fixture.userRepo, wantBalances, wantCards)
+}
+
+func TestMarketBuy(t *testing.T) {
+	tests := []struct {
+		name         string
+		price        int
+		buyer        string
+		before       func(fixture marketFixture, listing *domain.Listing)
+		wantErr      error
+		wantStatus   string
+		wantBalances map[string]int
+		wantCards    map[string]int
+	}{
+		{
+			name:         "compra",
+			price:        30,
+			buyer:        "bob",
+			wantStatus:   domain.ListingSold,
+			wantBalances: map[string]int{"alice": StarterCoins + 30, "bob": StarterCoins - 30},
+			wantCards:    map[string]int{"alice": 0, "bob": 3},
+		},
+		{
+			name:         "saldo insuficiente",
+			price:        StarterCoins + 1,
+			buyer:        "bob",
+			wantErr:      ErrInsufficientCoins,
+			wantStatus:   domain.ListingActive,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins},
+			wantCards:    map[string]int{"alice": 0, "bob": 1},
+		},
+		{
+			name:         "próprio anúncio",
+			price:        30,
+			buyer:        "alice",
+			wantErr:      ErrOwnListing,
+			wantStatus:   domain.ListingActive,
+			wantBalances: map[string]int{"alice": StarterCoins},
+		},
+		{
+			name:  "falha ao entregar as cartas",
+			price: 30,
+			buyer: "bob",
+			before: func(fixture marketFixture, _ *domain.Listing) {
+				fixture.userRepo.failUpdate = func(id string) bool { return id == "bob" }
+			},
+			wantErr:      errWriteFailed,
+			wantStatus:   domain.ListingActive,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins},
+			wantCards:    map[string]int{"alice": 0, "bob": 1},
+		},
+		{
+			name:  "falha ao pagar o vendedor",
+			price: 30,
+			buyer: "bob",
+			before: func(fixture marketFixture, listing *domain.Listing) {
+				// Bots não têm carteira, então o crédito da venda falha.
+				listing.SellerID = "bot"
+				fixture.listingRepo.Update(listing.ID, *listing)
+			},
+			wantErr:      ErrBotWallet,
+			wantStatus:   domain.ListingActive,
+			wantBalances: map[string]int{"bob": StarterCoins},
+			wantCards:    map[string]int{"bob": 1},
+		},
+		{
+			name:  "anúncio já vendido",
+			price: 30,
+			buyer: "carol",
+			before: func(fixture marketFixture, listing *domain.Listing) {
+				if _, _, err := fixture.service.Buy(listing.ID, "bob"); err != nil {
+					t.Fatalf("Buy: %v", err)
+				}
+			},
+			wantErr:      ErrListingClosed,
+			wantStatus:   domain.ListingSold,
+			wantBalances: map[string]int{"alice": StarterCoins + 30, "bob": StarterCoins - 30, "carol": StarterCoins},
+			wantCards:    map[string]int{"bob": 3, "carol": 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newMarketFixture(t)
+			listing := fixture.sell(t, domain.ListingFixed, test.price)
+			if test.before != nil {
+				test.before(fixture, &listing)
+			}
+			_, _, err := fixture.service.Buy(listing.ID, test.buyer)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			fixture.check(t, listing.ID, test.wantStatus, test.wantBalances, test.wantCards)
+		})
+	}
+}
+
+func TestMarketBid(t *testing.T) {
+	type bid struct {
+		userID string
+		amount int
+	}
+	tests := []struct {
+		name         string
+		bids         []bid
+		before       func(fixture marketFixture)
+		wantErr      error
+		wantBids     int
+		wantBalances map[string]int
+	}{
+		{
+			name:         "primeiro lance",
+			bids:         []bid{{"bob", 20}},
+			wantBids:     1,
+			wantBalances: map[string]int{"bob": StarterCoins - 20},
+		},
+		{
+			name:         "lance coberto é devolvido",
+			bids:         []bid{{"bob", 20}, {"carol", 25}},
+			wantBids:     2,
+			wantBalances: map[string]int{"bob": StarterCoins, "carol": StarterCoins - 25},
+		},
+		{
+			name:         "cobrir o próprio lance paga a diferença",
+			bids:         []bid{{"bob", 20}, {"bob", 30}},
+			wantBids:     2,
+			wantBalances: map[string]int{"bob": StarterCoins - 30},
+		},
+		{
+			name:         "lance abaixo do mínimo",
+			bids:         []bid{{"bob", 5}},
+			wantErr:      ErrBidTooLow,
+			wantBalances: map[string]int{"bob": StarterCoins},
+		},
+		{
+			name:         "lance acima do saldo",
+			bids:         []bid{{"bob", 20}, {"carol", StarterCoins + 1}},
+			wantErr:      ErrInsufficientCoins,
+			wantBids:     1,
+			wantBalances: map[string]int{"bob": StarterCoins - 20, "carol": StarterCoins},
+		},
+		{
+			name: "falha ao registrar o lance",
+			bids: []bid{{"bob", 20}},
+			before: func(fixture marketFixture) {
+				fixture.listingRepo.failUpdate = func(string) bool { return true }
+			},
+			wantErr:      errWriteFailed,
+			wantBalances: map[string]int{"bob": StarterCoins},
+		},
+		{
+			name:         "próprio leilão",
+			bids:         []bid{{"alice", 20}},
+			wantErr:      ErrOwnListing,
+			wantBalances: map[string]int{"alice": StarterCoins},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newMarketFixture(t)
+			listing := fixture.sell(t, domain.ListingAuction, 10)
+			if test.before != nil {
+				test.before(fixture)
+			}
+			var err error
+			for _, next := range test.bids {
+				_, err = fixture.service.Bid(listing.ID, next.userID, next.amount)
+			}
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			fixture.listingRepo.failUpdate = func(string) bool { return false }
+			stored, _ := fixture.listingRepo.Read(listing.ID)
+			if len(stored.Bids) != test.wantBids {
+				t.Errorf("%d lances registrados, esperado %d", len(stored.Bids), test.wantBids)
+			}
+			fixture.check(t, listing.ID, domain.ListingActive, test.wantBalances, nil)
+		})
+	}
+}
+
+func TestMarketSettle(t *testing.T) {
+	tests := []struct {
+		name         string
+		bidders      []string
+		before       func(fixture marketFixture)
+		wantErr      bool
+		wantStatus   string
+		wantBalances map[string]int
+		wantCards    map[string]int
+	}{
+		{
+			name:         "sem lances devolve as cartas",
+			wantStatus:   domain.ListingExpired,
+			wantBalances: map[string]int{"alice": StarterCoins},
+			wantCards:    map[string]int{"alice": 2},
+		},
+		{
+			name:         "maior lance leva as cartas",
+			bidders:      []string{"bob", "carol"},
+			wantStatus:   domain.ListingSold,
+			wantBalances: map[string]int{"alice": StarterCoins + 25, "bob": StarterCoins, "carol": StarterCoins - 25},
+			wantCards:    map[string]int{"alice": 0, "bob": 1, "carol": 3},
+		},
+		{
+			name:    "falha ao entregar as cartas mantém o leilão aberto",
+			bidders: []string{"bob"},
+			before: func(fixture marketFixture) {
+				fixture.userRepo.failUpdate = func(id string) bool { return id == "bob" }
+			},
+			wantErr:      true,
+			wantStatus:   domain.ListingActive,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins - 20},
+			wantCards:    map[string]int{"alice": 0, "bob": 1},
+		},
+		{
+			name:    "falha ao pagar o vendedor retira as cartas entregues",
+			bidders: []string{"bob"},
+			before: func(fixture marketFixture) {
+				listings, _ := fixture.listingRepo.List()
+				listing := listings[0]
+				listing.SellerID = "bot"
+				fixture.listingRepo.Update(listing.ID, listing)
+			},
+			wantErr:      true,
+			wantStatus:   domain.ListingActive,
+			wantBalances: map[string]int{"bob": StarterCoins - 20},
+			wantCards:    map[string]int{"bob": 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newMarketFixture(t)
+			listing := fixture.sell(t, domain.ListingAuction, 10)
+			for index, bidder := range test.bidders {
+				if _, err := fixture.service.Bid(listing.ID, bidder, 20+5*index); err != nil {
+					t.Fatalf("Bid: %v", err)
+				}
+			}
+			fixture.end(t, listing.ID)
+			if test.before != nil {
+				test.before(fixture)
+			}
+			settled, err := fixture.service.Settle()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("erro = %v, esperado erro: %v", err, test.wantErr)
+			}
+			if !test.wantErr && len(settled) != 1 {
+				t.Errorf("%d anúncios fechados, esperado 1", len(settled))
+			}
+			fixture.check(t, listing.ID, test.wantStatus, test.wantBalances, test.wantCards)
+		})
+	}
+}
+
+func TestMarketCancel(t *testing.T) {
+	tests := []struct {
+		name       string
+		seller     string
+		bid        bool
+		wantErr    error
+		wantStatus string
+		wantCards  int
+	}{
+		{name: "devolve as cartas", seller: "alice", wantStatus: domain.ListingCancelled, wantCards: 2},
+		{name: "apenas o vendedor", seller: "bob", wantErr: ErrNotListingSeller, wantStatus: domain.ListingActive},
+		{name: "leilão com lances", seller: "alice", bid: true, wantErr: ErrListingHasBids, wantStatus: domain.ListingActive},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newMarketFixture(t)
+			listing := fixture.sell(t, domain.ListingAuction, 10)
+			if test.bid {
+				if _, err := fixture.service.Bid(listing.ID, "bob", 10); err != nil {
+					t.Fatalf("Bid: %v", err)
+				}
+			}
+			if _, err := fixture.service.Cancel(listing.ID, test.seller); !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			fixture.check(t, listing.ID, test.wantStatus, nil, map[string]int{"alice": test.wantCards})
+		})
+	}
+}
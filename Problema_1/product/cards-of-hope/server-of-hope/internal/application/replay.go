@@ -0,0 +1,259 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrReplayNotFound indica que o registro não existe ou o usuário não participou da partida.
+var ErrReplayNotFound = errors.New("registro de partida não encontrado")
+
+// ReplayServiceInterface descreve as operações sobre os registros das partidas.
+//
+// Métodos:
+//   - StartMatch: abre o registro de uma partida que começou.
+//   - Record: acrescenta um evento ao registro aberto de uma sala.
+//   - FinishMatch: fecha e guarda o registro de uma partida encerrada.
+//   - ListReplays: lista os registros das partidas de um usuário.
+//   - GetReplay: retorna um registro com todos os eventos.
+type ReplayServiceInterface interface {
+	// StartMatch abre o registro de uma partida que começou na sala.
+	//
+	// Parâmetros:
+	//   - room: sala da partida.
+	//   - game: partida iniciada.
+	StartMatch(room domain.Room, game domain.Game)
+
+	// Record acrescenta um evento ao registro aberto da sala; sem registro aberto, o evento é
+	// descartado. A saída de um jogador encerra o registro como abandonado.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - event: evento ocorrido.
+	Record(roomID string, event domain.ReplayEvent)
+
+	// FinishMatch fecha e guarda o registro da partida encerrada na sala.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - result: resultado da rodada que encerrou a partida.
+	//
+	// Retorno:
+	//   - string: ID do registro guardado.
+	//   - erro caso não haja registro aberto ou ele não possa ser guardado.
+	FinishMatch(roomID string, result domain.RoundResult) (string, error)
+
+	// ListReplays lista os registros das partidas que o usuário jogou ou assistiu, das mais
+	// recentes para as mais antigas.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - []domain.Replay: registros encontrados.
+	//   - erro caso não seja possível listar os registros.
+	ListReplays(userID string) ([]domain.Replay, error)
+
+	// GetReplay retorna um registro com todos os eventos, se o usuário participou da partida.
+	//
+	// Parâmetros:
+	//   - replayID: identificador do registro.
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - domain.Replay: registro encontrado.
+	//   - erro caso o registro não exista ou o usuário não tenha participado da partida.
+	GetReplay(replayID, userID string) (domain.Replay, error)
+}
+
+// ReplayService implementa os registros das partidas. Os registros abertos ficam em memória até
+// a partida terminar; os encerrados vão para o repositório e entram nos backups.
+//
+// Campos:
+//   - replayRepo: repositório dos registros encerrados.
+//   - live: registros abertos, indexados pela sala.
+//   - mutex: serializa as mudanças nos registros abertos.
+type ReplayService struct {
+	replayRepo data.RepositoryInterface[domain.Replay]
+	live       *utils.Map[string, domain.Replay]
+	mutex      sync.Mutex
+}
+
+// NewReplayService cria uma nova instância de ReplayService.
+//
+// Parâmetros:
+//   - replayRepo: repositório dos registros encerrados.
+//
+// Retorno:
+//   - ponteiro para ReplayService.
+func NewReplayService(replayRepo data.RepositoryInterface[domain.Replay]) *ReplayService {
+	return &ReplayService{
+		replayRepo: replayRepo,
+		live:       utils.NewMap[string, domain.Replay](),
+	}
+}
+
+// StartMatch abre o registro de uma partida que começou na sala. Um registro que ainda estava
+// aberto na sala é guardado como abandonado.
+//
+// Parâmetros:
+//   - room: sala da partida.
+//   - game: partida iniciada.
+func (service *ReplayService) StartMatch(room domain.Room, game domain.Game) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if replay, open := service.live.Get(room.ID); open {
+		service.close(replay, true)
+	}
+	now := time.Now().UTC()
+	spectators := room.Spectators.Items()
+	sort.Strings(spectators)
+	service.live.Set(room.ID, domain.Replay{
+		RoomID:     room.ID,
+		RoomName:   room.Name,
+		Number:     game.Number,
+		RulesetID:  room.RulesetID,
+		Players:    append([]string(nil), game.Seats...),
+		Spectators: spectators,
+		StartedAt:  now,
+		Events:     []domain.ReplayEvent{{At: now, Type: domain.ReplayMatchStarted, Round: 1}},
+	})
+}
+
+// Record acrescenta um evento ao registro aberto da sala, com o momento e a rodada atuais; sem
+// registro aberto, o evento é descartado, assim como uma segunda jogada do mesmo jogador na
+// rodada. Quem entra durante a partida passa a constar como espectador, e a saída de um
+// jogador encerra o registro como abandonado.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//   - event: evento ocorrido.
+func (service *ReplayService) Record(roomID string, event domain.ReplayEvent) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	replay, open := service.live.Get(roomID)
+	if !open || (event.Type == domain.ReplayPlay && playedThisRound(replay, event.UserID)) {
+		return
+	}
+	service.append(&replay, event)
+
+	switch event.Type {
+	case domain.ReplayJoined:
+		if !replay.Involves(event.UserID) {
+			replay.Spectators = append(replay.Spectators, event.UserID)
+		}
+	case domain.ReplayLeft, domain.ReplayKicked:
+		for _, playerID := range replay.Players {
+			if playerID == event.UserID {
+				service.close(replay, true)
+				return
+			}
+		}
+	}
+	service.live.Set(roomID, replay)
+}
+
+// playedThisRound informa se o registro já tem a jogada do usuário na rodada em andamento.
+func playedThisRound(replay domain.Replay, userID string) bool {
+	for index := len(replay.Events) - 1; index >= 0; index-- {
+		event := replay.Events[index]
+		if event.Type == domain.ReplayRoundResult {
+			return false
+		}
+		if event.Type == domain.ReplayPlay && event.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// append acrescenta o evento ao registro, preenchendo o momento e a rodada.
+func (service *ReplayService) append(replay *domain.Replay, event domain.ReplayEvent) {
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+	event.Round = replay.Rounds() + 1
+	replay.Events = append(replay.Events, event)
+}
+
+// close retira o registro dos abertos e o guarda no repositório. Deve ser chamado com mutex travado.
+func (service *ReplayService) close(replay domain.Replay, abandoned bool) (string, error) {
+	service.live.Delete(replay.RoomID)
+	replay.ID = utils.Count()
+	replay.FinishedAt = time.Now().UTC()
+	replay.Abandoned = abandoned
+	return replay.ID, service.replayRepo.Create(replay.ID, replay)
+}
+
+// FinishMatch fecha e guarda o registro da partida encerrada na sala.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//   - result: resultado da rodada que encerrou a partida.
+//
+// Retorno:
+//   - string: ID do registro guardado.
+//   - erro caso não haja registro aberto ou ele não possa ser guardado.
+func (service *ReplayService) FinishMatch(roomID string, result domain.RoundResult) (string, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	replay, open := service.live.Get(roomID)
+	if !open {
+		return "", ErrReplayNotFound
+	}
+	replay.WinnerID = result.MatchWinnerID
+	replay.ForfeitedBy = result.ForfeitedBy
+	service.append(&replay, domain.ReplayEvent{Type: domain.ReplayMatchFinished, UserID: result.MatchWinnerID})
+	return service.close(replay, false)
+}
+
+// ListReplays lista os registros das partidas que o usuário jogou ou assistiu, das mais
+// recentes para as mais antigas.
+//
+// Parâmetros:
+//   - userID: identificador do usuário.
+//
+// Retorno:
+//   - []domain.Replay: registros encontrados.
+//   - erro caso não seja possível listar os registros.
+func (service *ReplayService) ListReplays(userID string) ([]domain.Replay, error) {
+	replays, err := service.replayRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var found []domain.Replay
+	for _, replay := range replays {
+		if replay.Involves(userID) {
+			found = append(found, replay)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].FinishedAt.After(found[j].FinishedAt)
+	})
+	return found, nil
+}
+
+// GetReplay retorna um registro com todos os eventos, se o usuário participou da partida.
+//
+// Parâmetros:
+//   - replayID: identificador do registro.
+//   - userID: identificador do usuário.
+//
+// Retorno:
+//   - domain.Replay: registro encontrado.
+//   - erro caso o registro não exista ou o usuário não tenha participado da partida.
+func (service *ReplayService) GetReplay(replayID, userID string) (domain.Replay, error) {
+	replay, err := service.replayRepo.Read(replayID)
+	if err != nil || !replay.Involves(userID) {
+		return domain.Replay{}, ErrReplayNotFound
+	}
+	return replay, nil
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"testing"
+)
+
+// startReplay abre o registro da partida da sala 7, com alice e bob jogando e carol assistindo.
+func startReplay(t *testing.T) *ReplayService {
+	t.Helper()
+	service := NewReplayService(data.NewInMemoryRepository[domain.Replay]())
+	service.StartMatch(newDuelRoom(domain.RoomStatusPlaying), domain.NewGame("7", []string{"alice", "bob"}))
+	return service
+}
+
+// eventTypes lista os tipos dos eventos do registro, em ordem.
+func eventTypes(replay domain.Replay) []string {
+	types := make([]string, len(replay.Events))
+	for index, event := range replay.Events {
+		types[index] = event.Type
+	}
+	return types
+}
+
+func TestReplayRecordsMatch(t *testing.T) {
+	service := startReplay(t)
+	rock, scissors := domain.Card{Type: "rock", Stars: 1}, domain.Card{Type: "scissors", Stars: 1}
+	service.Record("7", domain.ReplayEvent{Type: domain.ReplayPlay, UserID: "alice", Card: &rock})
+	// Uma segunda jogada do mesmo jogador na rodada é descartada.
+	service.Record("7", domain.ReplayEvent{Type: domain.ReplayPlay, UserID: "alice", Card: &scissors})
+	service.Record("7", domain.ReplayEvent{Type: domain.ReplayPlay, UserID: "bob", Card: &scissors})
+	service.Record("7", domain.ReplayEvent{Type: domain.ReplayRoundResult, Result: &domain.ReplayRound{WinnerID: "alice"}})
+	service.Record("7", domain.ReplayEvent{Type: domain.ReplayJoined, UserID: "dave"})
+	service.Record("7", domain.ReplayEvent{Type: domain.ReplayPlay, UserID: "alice", Card: &rock})
+	service.Record("8", domain.ReplayEvent{Type: domain.ReplayChat, UserID: "erin", Text: "sala sem registro"})
+
+	replayID, err := service.FinishMatch("7", domain.RoundResult{MatchWinnerID: "alice"})
+	if err != nil {
+		t.Fatalf("FinishMatch: %v", err)
+	}
+	replay, err := service.GetReplay(replayID, "dave")
+	if err != nil {
+		t.Fatalf("GetReplay: %v", err)
+	}
+	want := []string{domain.ReplayMatchStarted, domain.ReplayPlay, domain.ReplayPlay, domain.ReplayRoundResult, domain.ReplayJoined, domain.ReplayPlay, domain.ReplayMatchFinished}
+	if !equalStrings(eventTypes(replay), want) {
+		t.Errorf("eventos = %v, esperado %v", eventTypes(replay), want)
+	}
+	if replay.Events[5].Round != 2 || replay.Rounds() != 1 {
+		t.Errorf("jogada na rodada %d com %d rodadas resolvidas", replay.Events[5].Round, replay.Rounds())
+	}
+	if replay.WinnerID != "alice" || replay.Abandoned || !equalStrings(replay.Spectators, []string{"carol", "dave"}) {
+		t.Errorf("vencedor %q, abandonada %v, espectadores %v", replay.WinnerID, replay.Abandoned, replay.Spectators)
+	}
+	if _, err := service.FinishMatch("7", domain.RoundResult{}); !errors.Is(err, ErrReplayNotFound) {
+		t.Errorf("registro fechado duas vezes: erro = %v", err)
+	}
+}
+
+func TestReplayAbandoned(t *testing.T) {
+	tests := []struct {
+		name          string
+		event         domain.ReplayEvent
+		wantAbandoned bool
+	}{
+		{name: "jogador sai", event: domain.ReplayEvent{Type: domain.ReplayLeft, UserID: "bob"}, wantAbandoned: true},
+		{name: "jogador expulso", event: domain.ReplayEvent{Type: domain.ReplayKicked, UserID: "alice"}, wantAbandoned: true},
+		{name: "espectador sai", event: domain.ReplayEvent{Type: domain.ReplayLeft, UserID: "carol"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := startReplay(t)
+			service.Record("7", test.event)
+			replays, _ := service.ListReplays("alice")
+			if closed := len(replays) == 1; closed != test.wantAbandoned {
+				t.Fatalf("registro fechado: %v, esperado %v", closed, test.wantAbandoned)
+			}
+			if test.wantAbandoned && !replays[0].Abandoned {
+				t.Error("registro fechado sem marcar o abandono")
+			}
+		})
+	}
+}
+
+func TestReplayAccess(t *testing.T) {
+	service := startReplay(t)
+	first, _ := service.FinishMatch("7", domain.RoundResult{MatchWinnerID: "bob"})
+	service.StartMatch(newDuelRoom(domain.RoomStatusPlaying), domain.NewGame("7", []string{"alice", "bob"}))
+	second, _ := service.FinishMatch("7", domain.RoundResult{MatchWinnerID: "alice"})
+
+	replays, err := service.ListReplays("carol")
+	if err != nil {
+		t.Fatalf("ListReplays: %v", err)
+	}
+	if len(replays) != 2 || replays[0].FinishedAt.Before(replays[1].FinishedAt) {
+		t.Fatalf("registros de carol = %+v, esperado %s e %s do mais recente ao mais antigo", replays, second, first)
+	}
+	if replays, _ := service.ListReplays("dave"); len(replays) != 0 {
+		t.Errorf("%d registros de quem não participou", len(replays))
+	}
+	tests := []struct {
+		name     string
+		replayID string
+		userID   string
+		wantErr  error
+	}{
+		{name: "jogador", replayID: first, userID: "bob"},
+		{name: "espectador", replayID: first, userID: "carol"},
+		{name: "quem não participou", replayID: first, userID: "dave", wantErr: ErrReplayNotFound},
+		{name: "registro inexistente", replayID: "nenhum", userID: "alice", wantErr: ErrReplayNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			replay, err := service.GetReplay(test.replayID, test.userID)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err == nil && replay.ID != test.replayID {
+				t.Errorf("registro = %s, esperado %s", replay.ID, test.replayID)
+			}
+		})
+	}
+}
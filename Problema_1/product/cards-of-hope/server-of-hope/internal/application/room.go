@@ -2,34 +2,136 @@ package application
 
 import (
 	"errors"
+	"fmt"
 	"server-of-hope/internal/data"
 	"server-of-hope/internal/domain"
 	"server-of-hope/internal/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
+// MaxRoomNameLength define o tamanho máximo do nome de uma sala.
+const MaxRoomNameLength = 32
+
+// InviteCodeLength define a quantidade de caracteres de um código de convite.
+const InviteCodeLength = 8
+
+// MaxInviteTTL define a validade máxima de um convite.
+const MaxInviteTTL = 24 * time.Hour
+
+// KickBanDuration define por quanto tempo um usuário expulso fica impedido de voltar à sala.
+const KickBanDuration = 10 * time.Minute
+
+// DefaultTurnHours define o prazo de cada rodada das partidas por correspondência quando nenhum
+// é informado.
+const DefaultTurnHours = 24
+
+// MaxTurnHours define o maior prazo de cada rodada das partidas por correspondência.
+const MaxTurnHours = 7 * 24
+
+// RoomOptions descreve as opções de criação de uma sala.
+//
+// Campos:
+//   - Name: nome de exibição da sala (vazio gera um nome padrão).
+//   - Private: restringe a entrada a quem tiver a senha ou um convite.
+//   - Password: senha da sala privada (vazio aceita apenas convites).
+//   - Ruleset: ID do conjunto de regras das partidas (vazio usa o conjunto padrão).
+//   - CommitReveal: as jogadas são feitas com compromisso e revelação.
+//   - TournamentID: torneio cuja partida será disputada na sala.
+//   - Async: as partidas são por correspondência, com um prazo longo para cada rodada.
+//   - TurnHours: prazo de cada rodada das partidas por correspondência (zero usa DefaultTurnHours).
+//   - WagerCoins: moedas que cada jogador aposta em cada partida.
+//   - WagerCards: quantidade de cartas que cada jogador aposta em cada partida.
+type RoomOptions struct {
+	Name         string
+	Private      bool
+	Password     string
+	Ruleset      string
+	CommitReveal bool
+	TournamentID string
+	Async        bool
+	TurnHours    int
+	WagerCoins   int
+	WagerCards   int
+}
+
+// JoinOptions descreve como um usuário entra em uma sala.
+//
+// Campos:
+//   - Password: senha da sala, exigida apenas em salas privadas.
+//   - InviteCode: código de convite; quando informado, define a sala e dispensa a senha.
+//   - Spectator: entra como espectador, sem ocupar uma vaga de jogador.
+type JoinOptions struct {
+	Password   string
+	InviteCode string
+	Spectator  bool
+}
+
+// RoomFilter descreve os filtros aplicáveis à listagem de salas.
+//
+// Campos:
+//   - Name: trecho que deve aparecer no nome da sala (sem diferenciar maiúsculas).
+//   - Status: estado exigido da sala (vazio aceita qualquer estado).
+//   - IncludeFull: inclui salas que já atingiram a capacidade.
+//   - OnlyPublic: omite as salas privadas.
+type RoomFilter struct {
+	Name        string
+	Status      string
+	IncludeFull bool
+	OnlyPublic  bool
+}
+
 // RoomServiceInterface descreve as operações para gerenciamento de salas.
 //
 // Métodos:
 //   - CreateRoom: cria uma nova sala.
-//   - JoinRoom: adiciona um usuário a uma sala.
+//   - JoinRoom: adiciona um jogador ou espectador a uma sala.
+//   - CreateInvite: gera um convite para uma sala privada.
 //   - LeaveRoom: remove um usuário de uma sala.
+//   - Kick: expulsa um usuário da sala.
+//   - SetLocked: tranca ou destranca a sala.
+//   - TransferHost: passa o papel de anfitrião a outro membro.
+//   - ReleaseHost: passa adiante o papel de anfitrião de um usuário que se desconectou.
+//   - GetRoom: retorna uma sala pelo ID.
+//   - ListRooms: lista as salas que atendem a um filtro.
 type RoomServiceInterface interface {
-	// CreateRoom cria uma nova sala e retorna seu ID.
+	// CreateRoom cria uma nova sala, adiciona o dono a ela e retorna seu ID.
+	//
+	// Parâmetros:
+	//   - ownerID: identificador do usuário que cria a sala.
+	//   - options: opções de criação da sala.
 	//
 	// Retorno:
 	//   - string: ID da sala criada.
 	//   - erro caso não seja possível criar a sala.
-	CreateRoom() (string, error)
+	CreateRoom(ownerID string, options RoomOptions) (string, error)
 
-	// JoinRoom adiciona um usuário a uma sala existente.
+	// JoinRoom adiciona um usuário a uma sala existente, como jogador ou espectador.
 	//
 	// Parâmetros:
-	//   - roomID: identificador da sala.
+	//   - roomID: identificador da sala (ignorado quando há código de convite).
 	//   - userID: identificador do usuário.
+	//   - options: senha, convite e papel do usuário na sala.
 	//
 	// Retorno:
+	//   - string: ID da sala em que o usuário entrou.
 	//   - erro caso não seja possível adicionar o usuário.
-	JoinRoom(roomID, userID string) error
+	JoinRoom(roomID, userID string, options JoinOptions) (string, error)
+
+	// CreateInvite gera um convite para uma sala privada. Apenas o anfitrião da sala pode convidar.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - userID: identificador do usuário que gera o convite.
+	//   - ttl: validade do convite.
+	//   - singleUse: consome o convite no primeiro uso.
+	//
+	// Retorno:
+	//   - domain.Invite: convite gerado.
+	//   - erro caso o usuário não possa convidar.
+	CreateInvite(roomID, userID string, ttl time.Duration, singleUse bool) (domain.Invite, error)
 
 	// LeaveRoom remove um usuário de uma sala existente.
 	//
@@ -40,69 +142,404 @@ type RoomServiceInterface interface {
 	// Retorno:
 	//   - erro caso não seja possível remover o usuário.
 	LeaveRoom(roomID, userID string) error
+
+	// Kick expulsa um membro da sala, impedindo seu retorno por KickBanDuration. Apenas o anfitrião pode expulsar.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - hostID: identificador do anfitrião.
+	//   - targetID: identificador do usuário expulso.
+	//
+	// Retorno:
+	//   - erro caso o usuário não possa ser expulso.
+	Kick(roomID, hostID, targetID string) error
+
+	// SetLocked tranca ou destranca a sala para novos membros. Apenas o anfitrião pode trancar.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - hostID: identificador do anfitrião.
+	//   - locked: novo estado da tranca.
+	//
+	// Retorno:
+	//   - erro caso o usuário não seja o anfitrião.
+	SetLocked(roomID, hostID string, locked bool) error
+
+	// TransferHost passa o papel de anfitrião a outro membro da sala.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - hostID: identificador do anfitrião atual.
+	//   - targetID: identificador do novo anfitrião.
+	//
+	// Retorno:
+	//   - erro caso a transferência não seja permitida.
+	TransferHost(roomID, hostID, targetID string) error
+
+	// ReleaseHost passa adiante o papel de anfitrião de todas as salas em que o usuário é anfitrião.
+	//
+	// Parâmetros:
+	//   - userID: identificador do anfitrião que deixou de estar disponível.
+	//   - eligible: informa se um membro pode assumir o papel (por exemplo, se está conectado).
+	//
+	// Retorno:
+	//   - slice das salas cujo anfitrião mudou.
+	//   - erro caso não seja possível atualizar as salas.
+	ReleaseHost(userID string, eligible func(userID string) bool) ([]domain.Room, error)
+
+	// GetRoom retorna a sala associada ao ID informado.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//
+	// Retorno:
+	//   - domain.Room: sala encontrada.
+	//   - erro caso a sala não exista.
+	GetRoom(roomID string) (domain.Room, error)
+
+	// ListRooms lista as salas que atendem ao filtro, das mais antigas para as mais novas.
+	//
+	// Parâmetros:
+	//   - filter: filtros da listagem.
+	//
+	// Retorno:
+	//   - slice de salas encontradas.
+	//   - erro caso não seja possível listar as salas.
+	ListRooms(filter RoomFilter) ([]domain.Room, error)
 }
 
 // RoomService implementa a lógica de gerenciamento de salas.
 //
 // Campos:
 //   - RoomRepo: repositório das salas.
+//   - RulesetRepo: repositório dos conjuntos de regras.
+//   - invites: convites ativos, indexados pelo código.
+//   - inviteMutex: garante que convites de uso único sejam consumidos uma única vez.
+//   - roomMutex: serializa as mudanças nas salas; é o mesmo mutex das partidas, dos baralhos e das apostas.
 type RoomService struct {
-	RoomRepo data.RepositoryInterface[domain.Room]
+	RoomRepo    data.RepositoryInterface[domain.Room]
+	RulesetRepo data.RepositoryInterface[domain.Ruleset]
+	invites     *utils.Map[string, domain.Invite]
+	inviteMutex sync.Mutex
+	roomMutex   *sync.Mutex
 }
 
+// Erros de sala exibidos diretamente aos usuários.
+var (
+	ErrRoomFull       = errors.New("A sala está cheia")
+	ErrRoomPrivate    = errors.New("A sala é privada, informe a senha ou um convite")
+	ErrWrongPassword  = errors.New("Senha incorreta")
+	ErrInvalidInvite  = errors.New("Convite inválido ou expirado")
+	ErrNotRoomHost    = errors.New("Apenas o anfitrião da sala pode fazer isso")
+	ErrRoomPublic     = errors.New("A sala é pública, não é preciso convite")
+	ErrInviteTTL      = errors.New("Validade do convite inválida")
+	ErrAlreadyInRoom  = errors.New("Você já está nesta sala com outro papel, saia antes de trocar")
+	ErrRoomLocked     = errors.New("A sala está trancada")
+	ErrBanned         = errors.New("Você foi expulso desta sala e ainda não pode voltar")
+	ErrNotInRoom      = errors.New("O usuário não está na sala")
+	ErrSelfTarget     = errors.New("Você não pode fazer isso consigo mesmo")
+	ErrTournamentRoom = errors.New("Ninguém pode ser expulso da sala de uma partida de torneio")
+	ErrTurnHours      = fmt.Errorf("O prazo de cada rodada deve ser de 1 a %d horas", MaxTurnHours)
+	ErrAsyncCommit    = errors.New("Partidas por correspondência não usam jogadas com compromisso e revelação")
+)
+
 // NewRoomService cria uma nova instância de RoomService.
 //
 // Parâmetros:
 //   - roomRepo: repositório das salas.
+//   - rulesetRepo: repositório dos conjuntos de regras que as salas podem escolher.
+//   - roomMutex: mutex compartilhado por todos os serviços que alteram as salas.
 //
 // Retorno:
 //   - ponteiro para RoomService.
-func NewRoomService(roomRepo data.RepositoryInterface[domain.Room]) *RoomService {
-	return &RoomService{RoomRepo: roomRepo}
+func NewRoomService(
+	roomRepo data.RepositoryInterface[domain.Room],
+	rulesetRepo data.RepositoryInterface[domain.Ruleset],
+	roomMutex *sync.Mutex,
+) *RoomService {
+	return &RoomService{
+		RoomRepo:    roomRepo,
+		RulesetRepo: rulesetRepo,
+		invites:     utils.NewMap[string, domain.Invite](),
+		roomMutex:   roomMutex,
+	}
 }
 
-// CreateRoom cria uma nova sala e retorna seu ID.
+// CreateRoom cria uma nova sala, adiciona o dono a ela e retorna seu ID.
+//
+// Parâmetros:
+//   - ownerID: identificador do usuário que cria a sala.
+//   - options: opções de criação da sala.
 //
 // Retorno:
 //   - string: ID da sala criada.
-//   - erro caso não seja possível criar a sala.
-func (service *RoomService) CreateRoom() (string, error) {
-	room := domain.NewRoom(utils.Count())
-	err := service.RoomRepo.Create(room.ID, *room)
+//   - erro caso o nome ou as regras sejam inválidos ou não seja possível criar a sala.
+func (service *RoomService) CreateRoom(ownerID string, options RoomOptions) (string, error) {
+	if options.Password != "" && !options.Private {
+		return "", errors.New("apenas salas privadas podem ter senha")
+	}
+	name := strings.TrimSpace(options.Name)
+	if len([]rune(name)) > MaxRoomNameLength {
+		return "", errors.New("nome da sala muito longo")
+	}
+	ruleset, err := readRuleset(service.RulesetRepo, options.Ruleset)
+	if err != nil {
+		return "", err
+	}
+	turnHours := options.TurnHours
+	if options.Async && turnHours == 0 {
+		turnHours = DefaultTurnHours
+	}
+	if (!options.Async && turnHours != 0) || turnHours < 0 || turnHours > MaxTurnHours {
+		return "", ErrTurnHours
+	}
+	if options.Async && options.CommitReveal {
+		return "", ErrAsyncCommit // A revelação depende do nonce guardado pelo cliente que se comprometeu
+	}
+	if options.WagerCoins < 0 || options.WagerCoins > MaxWagerCoins {
+		return "", ErrWagerCoins
+	}
+	if options.WagerCards < 0 || options.WagerCards > domain.MaxWagerCards {
+		return "", ErrWagerCards
+	}
+
+	id := utils.Count()
+	if name == "" {
+		name = "Sala " + id
+	}
+	room := domain.NewRoom(id, name, ownerID)
+	room.Private = options.Private
+	room.SetPassword(options.Password)
+	room.RulesetID = ruleset.ID
+	room.CommitReveal = options.CommitReveal
+	room.TournamentID = options.TournamentID
+	room.Async = options.Async
+	room.TurnHours = turnHours
+	room.WagerCoins = options.WagerCoins
+	room.WagerCards = options.WagerCards
+	room.UserIDs.Add(ownerID)
+	room.Messages.Set(ownerID, make(chan string, 1))
+
+	err = service.RoomRepo.Create(room.ID, *room)
 	if err != nil {
 		return "", err
 	}
 	return room.ID, nil
 }
 
-// JoinRoom adiciona um usuário a uma sala existente e cria um canal de mensagens para ele.
+// JoinRoom adiciona um usuário a uma sala existente, como jogador ou espectador, e cria um
+// canal de mensagens para ele. Salas privadas exigem a senha correta ou um convite válido,
+// tanto para jogadores quanto para espectadores.
 //
 // Parâmetros:
-//   - roomID: identificador da sala.
+//   - roomID: identificador da sala (ignorado quando há código de convite).
 //   - userID: identificador do usuário.
+//   - options: senha, convite e papel do usuário na sala.
 //
 // Retorno:
+//   - string: ID da sala em que o usuário entrou.
 //   - erro caso não seja possível adicionar o usuário.
-func (service *RoomService) JoinRoom(roomID, userID string) error {
+func (service *RoomService) JoinRoom(roomID, userID string, options JoinOptions) (string, error) {
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+
+	if options.InviteCode != "" {
+		return service.joinWithInvite(userID, options)
+	}
+
+	room, err := service.RoomRepo.Read(roomID)
+	if err != nil {
+		return "", err // Sala não encontrada
+	}
+
+	if room.IsMember(userID) {
+		return room.ID, service.checkRole(room, userID, options.Spectator)
+	}
+
+	if room.Private {
+		if options.Password == "" {
+			return "", ErrRoomPrivate
+		}
+		if !room.CheckPassword(options.Password) {
+			return "", ErrWrongPassword
+		}
+	}
+
+	return room.ID, service.addMember(room, userID, options.Spectator)
+}
+
+// joinWithInvite adiciona um usuário à sala de um convite válido, consumindo-o se for de uso único.
+// Deve ser chamado com roomMutex travado.
+func (service *RoomService) joinWithInvite(userID string, options JoinOptions) (string, error) {
+	service.inviteMutex.Lock()
+	defer service.inviteMutex.Unlock()
+
+	code := strings.ToUpper(strings.TrimSpace(options.InviteCode))
+	invite, exists := service.invites.Get(code)
+	if !exists || invite.Expired() {
+		service.invites.Delete(code)
+		return "", ErrInvalidInvite
+	}
+
+	room, err := service.RoomRepo.Read(invite.RoomID)
+	if err != nil {
+		service.invites.Delete(code)
+		return "", ErrInvalidInvite
+	}
+	if room.IsMember(userID) {
+		return room.ID, service.checkRole(room, userID, options.Spectator)
+	}
+
+	if err := service.addMember(room, userID, options.Spectator); err != nil {
+		return "", err
+	}
+	if invite.SingleUse {
+		service.invites.Delete(code)
+	}
+	return room.ID, nil
+}
+
+// checkRole confere se um membro que tenta entrar novamente na sala pediu o mesmo papel que já ocupa.
+func (service *RoomService) checkRole(room domain.Room, userID string, spectator bool) error {
+	if room.IsSpectator(userID) != spectator {
+		return ErrAlreadyInRoom
+	}
+	return nil
+}
+
+// CreateInvite gera um convite para uma sala privada. Apenas o anfitrião da sala pode convidar.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//   - userID: identificador do usuário que gera o convite.
+//   - ttl: validade do convite, limitada a MaxInviteTTL.
+//   - singleUse: consome o convite no primeiro uso.
+//
+// Retorno:
+//   - domain.Invite: convite gerado.
+//   - erro caso o usuário não possa convidar.
+func (service *RoomService) CreateInvite(roomID, userID string, ttl time.Duration, singleUse bool) (domain.Invite, error) {
 	room, err := service.RoomRepo.Read(roomID)
 	if err != nil {
-		return err // Sala não encontrada
+		return domain.Invite{}, err
+	}
+	if room.HostID != userID {
+		return domain.Invite{}, ErrNotRoomHost
+	}
+	if !room.Private {
+		return domain.Invite{}, ErrRoomPublic
+	}
+	if ttl <= 0 || ttl > MaxInviteTTL {
+		return domain.Invite{}, ErrInviteTTL
 	}
 
-	if room.UserIDs.Contains(userID) {
-		return nil // Usuário já está na sala
+	service.inviteMutex.Lock()
+	defer service.inviteMutex.Unlock()
+	service.purgeExpiredInvites()
+
+	code := utils.RandomCode(InviteCodeLength)
+	for _, exists := service.invites.Get(code); exists; _, exists = service.invites.Get(code) {
+		code = utils.RandomCode(InviteCodeLength)
+	}
+	invite := domain.Invite{
+		Code:      code,
+		RoomID:    roomID,
+		CreatedBy: userID,
+		ExpiresAt: time.Now().Add(ttl),
+		SingleUse: singleUse,
 	}
+	service.invites.Set(code, invite)
+	return invite, nil
+}
 
-	if room.UserIDs.Size() >= 2 {
-		return errors.New("A sala está cheia")
+// purgeExpiredInvites remove os convites expirados. Deve ser chamado com inviteMutex travado.
+func (service *RoomService) purgeExpiredInvites() {
+	for _, invite := range service.invites.Values() {
+		if invite.Expired() {
+			service.invites.Delete(invite.Code)
+		}
+	}
+}
+
+// addMember adiciona o usuário à sala como jogador, respeitando a capacidade, ou como
+// espectador, e persiste a alteração. Salas trancadas e usuários expulsos são recusados, e
+// um jogador que entra em uma sala sem anfitrião assume o papel. Deve ser chamado com roomMutex
+// travado, para que duas entradas simultâneas não passem juntas da conferência da capacidade.
+func (service *RoomService) addMember(room domain.Room, userID string, spectator bool) error {
+	if room.IsBanned(userID) {
+		return ErrBanned
+	}
+	if room.Locked {
+		return ErrRoomLocked
+	}
+	if spectator {
+		room.Spectators.Add(userID)
+		room.Messages.Set(userID, make(chan string, domain.SpectatorMessageBuffer))
+		return service.RoomRepo.Update(room.ID, room)
+	}
+	if room.IsFull() {
+		return ErrRoomFull
 	}
 
 	room.UserIDs.Add(userID)
 	room.Messages.Set(userID, make(chan string, 1))
-	return service.RoomRepo.Update(roomID, room)
+	if room.HostID == "" {
+		room.HostID = userID
+	}
+	if room.IsFull() {
+		room.Status = domain.RoomStatusReadyCheck
+		room.Ready.Clear()
+	}
+	return service.RoomRepo.Update(room.ID, room)
+}
+
+// removeMember retira o usuário da sala, atualiza o estado e passa adiante o papel de anfitrião se preciso.
+// A saída de um jogador volta a sala para a espera por jogadores, encerrando a partida em andamento.
+func removeMember(room *domain.Room, userID string) {
+	room.UserIDs.Remove(userID)
+	room.Spectators.Remove(userID)
+	room.Messages.Delete(userID)
+	room.Ready.Remove(userID)
+	room.DeckChoices.Delete(userID)
+	room.RematchRequests.Clear()
+	room.RematchDeadline = time.Time{}
+	if !room.IsFull() {
+		// Uma partida em andamento é abandonada quando um jogador sai.
+		room.Status = domain.RoomStatusWaiting
+	}
+	if room.HostID == userID {
+		room.HostID = nextHost(*room, userID, nil)
+	}
+}
+
+// nextHost escolhe o próximo anfitrião da sala: primeiro os jogadores, depois os espectadores,
+// em ordem alfabética. Retorna vazio se nenhum membro, além de excluded, for elegível.
+func nextHost(room domain.Room, excluded string, eligible func(userID string) bool) string {
+	players := room.UserIDs.Items()
+	sort.Strings(players)
+	spectators := room.Spectators.Items()
+	sort.Strings(spectators)
+	for _, userID := range append(players, spectators...) {
+		if userID != excluded && (eligible == nil || eligible(userID)) {
+			return userID
+		}
+	}
+	return ""
 }
 
-// LeaveRoom remove um usuário de uma sala e exclui seu canal de mensagens.
+// readAsHost lê a sala e confere se o usuário é seu anfitrião.
+func (service *RoomService) readAsHost(roomID, hostID string) (domain.Room, error) {
+	room, err := service.RoomRepo.Read(roomID)
+	if err != nil {
+		return domain.Room{}, err
+	}
+	if room.HostID != hostID {
+		return domain.Room{}, ErrNotRoomHost
+	}
+	return room, nil
+}
+
+// LeaveRoom remove um jogador ou espectador de uma sala e exclui seu canal de mensagens.
 //
 // Parâmetros:
 //   - roomID: identificador da sala.
@@ -111,11 +548,190 @@ func (service *RoomService) JoinRoom(roomID, userID string) error {
 // Retorno:
 //   - erro caso não seja possível remover o usuário.
 func (service *RoomService) LeaveRoom(roomID, userID string) error {
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+
 	room, err := service.RoomRepo.Read(roomID)
 	if err != nil {
 		return err
 	}
-	room.UserIDs.Remove(userID)
-	room.Messages.Delete(userID)
+	removeMember(&room, userID)
+	return service.RoomRepo.Update(roomID, room)
+}
+
+// Kick expulsa um membro da sala, impedindo seu retorno por KickBanDuration. Apenas o anfitrião pode expulsar.
+// Nas salas de torneio ninguém é expulso, porque a saída de um jogador decide a partida.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//   - hostID: identificador do anfitrião.
+//   - targetID: identificador do usuário expulso.
+//
+// Retorno:
+//   - erro caso o usuário não possa ser expulso.
+func (service *RoomService) Kick(roomID, hostID, targetID string) error {
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+
+	room, err := service.readAsHost(roomID, hostID)
+	if err != nil {
+		return err
+	}
+	if room.TournamentID != "" {
+		return ErrTournamentRoom
+	}
+	if targetID == hostID {
+		return ErrSelfTarget
+	}
+	if !room.IsMember(targetID) {
+		return ErrNotInRoom
+	}
+	if room.Status == domain.RoomStatusPlaying && room.WagerID != "" && room.UserIDs.Contains(targetID) {
+		return ErrWagerKick // Expulsar o oponente não pode livrar o anfitrião de perder a aposta
+	}
+
+	removeMember(&room, targetID)
+	for _, userID := range room.Bans.Keys() {
+		if !room.IsBanned(userID) {
+			room.Bans.Delete(userID)
+		}
+	}
+	room.Bans.Set(targetID, time.Now().Add(KickBanDuration))
 	return service.RoomRepo.Update(roomID, room)
 }
+
+// SetLocked tranca ou destranca a sala para novos membros. Apenas o anfitrião pode trancar.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//   - hostID: identificador do anfitrião.
+//   - locked: novo estado da tranca.
+//
+// Retorno:
+//   - erro caso o usuário não seja o anfitrião.
+func (service *RoomService) SetLocked(roomID, hostID string, locked bool) error {
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+
+	room, err := service.readAsHost(roomID, hostID)
+	if err != nil {
+		return err
+	}
+	room.Locked = locked
+	return service.RoomRepo.Update(roomID, room)
+}
+
+// TransferHost passa o papel de anfitrião a outro membro da sala.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//   - hostID: identificador do anfitrião atual.
+//   - targetID: identificador do novo anfitrião.
+//
+// Retorno:
+//   - erro caso a transferência não seja permitida.
+func (service *RoomService) TransferHost(roomID, hostID, targetID string) error {
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+
+	room, err := service.readAsHost(roomID, hostID)
+	if err != nil {
+		return err
+	}
+	if targetID == hostID {
+		return ErrSelfTarget
+	}
+	if !room.IsMember(targetID) {
+		return ErrNotInRoom
+	}
+	room.HostID = targetID
+	return service.RoomRepo.Update(roomID, room)
+}
+
+// ReleaseHost passa adiante o papel de anfitrião de todas as salas em que o usuário é anfitrião.
+// Salas sem outro membro elegível mantêm o anfitrião atual.
+//
+// Parâmetros:
+//   - userID: identificador do anfitrião que deixou de estar disponível.
+//   - eligible: informa se um membro pode assumir o papel (por exemplo, se está conectado).
+//
+// Retorno:
+//   - slice das salas cujo anfitrião mudou.
+//   - erro caso não seja possível atualizar as salas.
+func (service *RoomService) ReleaseHost(userID string, eligible func(userID string) bool) ([]domain.Room, error) {
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+
+	rooms, err := service.RoomRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []domain.Room
+	for _, room := range rooms {
+		if room.HostID != userID {
+			continue
+		}
+		next := nextHost(room, userID, eligible)
+		if next == "" {
+			continue
+		}
+		room.HostID = next
+		if err := service.RoomRepo.Update(room.ID, room); err != nil {
+			return changed, err
+		}
+		changed = append(changed, room)
+	}
+	return changed, nil
+}
+
+// GetRoom retorna a sala associada ao ID informado.
+//
+// Parâmetros:
+//   - roomID: identificador da sala.
+//
+// Retorno:
+//   - domain.Room: sala encontrada.
+//   - erro caso a sala não exista.
+func (service *RoomService) GetRoom(roomID string) (domain.Room, error) {
+	return service.RoomRepo.Read(roomID)
+}
+
+// ListRooms lista as salas que atendem ao filtro, das mais antigas para as mais novas.
+// Por padrão, apenas salas com vagas são listadas.
+//
+// Parâmetros:
+//   - filter: filtros da listagem.
+//
+// Retorno:
+//   - slice de salas encontradas.
+//   - erro caso não seja possível listar as salas.
+func (service *RoomService) ListRooms(filter RoomFilter) ([]domain.Room, error) {
+	rooms, err := service.RoomRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToLower(strings.TrimSpace(filter.Name))
+	result := make([]domain.Room, 0, len(rooms))
+	for _, room := range rooms {
+		if !filter.IncludeFull && room.IsFull() {
+			continue
+		}
+		if filter.OnlyPublic && room.Private {
+			continue
+		}
+		if filter.Status != "" && room.Status != filter.Status {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(room.Name), name) {
+			continue
+		}
+		result = append(result, room)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
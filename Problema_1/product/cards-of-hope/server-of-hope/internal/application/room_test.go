@@ -0,0 +1,632 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRulesets cria um repositório com os conjuntos de regras embutidos no servidor.
+func newTestRulesets(t *testing.T) *data.InMemoryRepository[domain.Ruleset] {
+	t.Helper()
+	rulesets, err := data.LoadRulesets()
+	if err != nil {
+		t.Fatalf("LoadRulesets: %v", err)
+	}
+	repo := data.NewInMemoryRepository[domain.Ruleset]()
+	for _, ruleset := range rulesets {
+		repo.Create(ruleset.ID, ruleset)
+	}
+	return repo
+}
+
+// newTestRoomService cria um serviço de salas com um repositório vazio.
+func newTestRoomService(t *testing.T) *RoomService {
+	t.Helper()
+	return NewRoomService(data.NewInMemoryRepository[domain.Room](), newTestRulesets(t), &sync.Mutex{})
+}
+
+func TestCreateRoom(t *testing.T) {
+	tests := []struct {
+		name    string
+		options RoomOptions
+		wantErr error
+		check   func(t *testing.T, room domain.Room)
+	}{
+		{
+			name: "sem opções",
+			check: func(t *testing.T, room domain.Room) {
+				if room.Name != "Sala "+room.ID || room.RulesetID != domain.DefaultRulesetID || room.Private {
+					t.Errorf("sala %q com regras %q, privada %v", room.Name, room.RulesetID, room.Private)
+				}
+				if room.OwnerID != "alice" || room.HostID != "alice" || !room.UserIDs.Contains("alice") {
+					t.Errorf("dono %q, anfitrião %q, jogadores %v", room.OwnerID, room.HostID, room.UserIDs.Items())
+				}
+				if _, exists := room.Messages.Get("alice"); !exists {
+					t.Error("o dono ficou sem canal de mensagens")
+				}
+			},
+		},
+		{
+			name:    "nome com espaços e regras escolhidas",
+			options: RoomOptions{Name: "  Treino  ", Ruleset: "rpsls", CommitReveal: true},
+			check: func(t *testing.T, room domain.Room) {
+				if room.Name != "Treino" || room.RulesetID != "rpsls" || !room.CommitReveal {
+					t.Errorf("sala %q com regras %q, jogadas fechadas %v", room.Name, room.RulesetID, room.CommitReveal)
+				}
+			},
+		},
+		{
+			name:    "privada com senha",
+			options: RoomOptions{Private: true, Password: "segredo"},
+			check: func(t *testing.T, room domain.Room) {
+				if !room.Private || !room.CheckPassword("segredo") || room.CheckPassword("outra") {
+					t.Errorf("sala privada %v com a senha errada", room.Private)
+				}
+			},
+		},
+		{
+			name:    "por correspondência usa o prazo padrão",
+			options: RoomOptions{Async: true},
+			check: func(t *testing.T, room domain.Room) {
+				if !room.Async || room.TurnHours != DefaultTurnHours {
+					t.Errorf("sala por correspondência %v com prazo de %d horas", room.Async, room.TurnHours)
+				}
+			},
+		},
+		{
+			name:    "com aposta",
+			options: RoomOptions{WagerCoins: MaxWagerCoins, WagerCards: domain.MaxWagerCards},
+			check: func(t *testing.T, room domain.Room) {
+				if room.WagerCoins != MaxWagerCoins || room.WagerCards != domain.MaxWagerCards {
+					t.Errorf("aposta de %d moedas e %d cartas", room.WagerCoins, room.WagerCards)
+				}
+			},
+		},
+		{name: "nome muito longo", options: RoomOptions{Name: strings.Repeat("a", MaxRoomNameLength+1)}, wantErr: errors.New("nome da sala muito longo")},
+		{name: "senha em sala pública", options: RoomOptions{Password: "segredo"}, wantErr: errors.New("apenas salas privadas podem ter senha")},
+		{name: "regras desconhecidas", options: RoomOptions{Ruleset: "xadrez"}, wantErr: ErrUnknownRuleset},
+		{name: "prazo sem correspondência", options: RoomOptions{TurnHours: 2}, wantErr: ErrTurnHours},
+		{name: "prazo longo demais", options: RoomOptions{Async: true, TurnHours: MaxTurnHours + 1}, wantErr: ErrTurnHours},
+		{name: "correspondência com compromisso", options: RoomOptions{Async: true, CommitReveal: true}, wantErr: ErrAsyncCommit},
+		{name: "aposta negativa", options: RoomOptions{WagerCoins: -1}, wantErr: ErrWagerCoins},
+		{name: "cartas demais na aposta", options: RoomOptions{WagerCards: domain.MaxWagerCards + 1}, wantErr: ErrWagerCards},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newTestRoomService(t)
+			roomID, err := service.CreateRoom("alice", test.options)
+			if test.wantErr != nil {
+				if err == nil || err.Error() != test.wantErr.Error() {
+					t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+				}
+				if rooms, _ := service.RoomRepo.List(); len(rooms) != 0 {
+					t.Errorf("%d salas criadas apesar do erro", len(rooms))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateRoom: %v", err)
+			}
+			room, err := service.GetRoom(roomID)
+			if err != nil {
+				t.Fatalf("GetRoom: %v", err)
+			}
+			test.check(t, room)
+		})
+	}
+}
+
+func TestListRooms(t *testing.T) {
+	service := newTestRoomService(t)
+	create := func(name string, options RoomOptions) string {
+		t.Helper()
+		options.Name = name
+		roomID, err := service.CreateRoom("owner-"+name, options)
+		if err != nil {
+			t.Fatalf("CreateRoom(%s): %v", name, err)
+		}
+		return roomID
+	}
+	open := create("Aberta", RoomOptions{})
+	secret := create("Secreta", RoomOptions{Private: true, Password: "segredo"})
+	full := create("Cheia", RoomOptions{})
+	if _, err := service.JoinRoom(full, "bob", JoinOptions{}); err != nil {
+		t.Fatalf("JoinRoom: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter RoomFilter
+		want   []string
+	}{
+		{name: "padrão omite as cheias", filter: RoomFilter{}, want: []string{open, secret}},
+		{name: "com as cheias", filter: RoomFilter{IncludeFull: true}, want: []string{open, secret, full}},
+		{name: "apenas públicas", filter: RoomFilter{OnlyPublic: true, IncludeFull: true}, want: []string{open, full}},
+		{name: "por nome sem diferenciar maiúsculas", filter: RoomFilter{Name: " SECR "}, want: []string{secret}},
+		{name: "por estado", filter: RoomFilter{Status: domain.RoomStatusReadyCheck, IncludeFull: true}, want: []string{full}},
+		{name: "nenhuma", filter: RoomFilter{Name: "torneio"}, want: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rooms, err := service.ListRooms(test.filter)
+			if err != nil {
+				t.Fatalf("ListRooms: %v", err)
+			}
+			got := make([]string, 0, len(rooms))
+			for _, room := range rooms {
+				got = append(got, room.ID)
+			}
+			if !equalStrings(got, test.want) {
+				t.Errorf("salas = %v, esperado %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCreateInvite(t *testing.T) {
+	tests := []struct {
+		name    string
+		private bool
+		userID  string
+		ttl     time.Duration
+		wantErr error
+	}{
+		{name: "anfitrião da sala privada", private: true, userID: "alice", ttl: time.Minute},
+		{name: "validade máxima", private: true, userID: "alice", ttl: MaxInviteTTL},
+		{name: "quem não é anfitrião", private: true, userID: "bob", ttl: time.Minute, wantErr: ErrNotRoomHost},
+		{name: "sala pública", userID: "alice", ttl: time.Minute, wantErr: ErrRoomPublic},
+		{name: "validade zero", private: true, userID: "alice", wantErr: ErrInviteTTL},
+		{name: "validade longa demais", private: true, userID: "alice", ttl: MaxInviteTTL + time.Second, wantErr: ErrInviteTTL},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newTestRoomService(t)
+			roomID, err := service.CreateRoom("alice", RoomOptions{Private: test.private})
+			if err != nil {
+				t.Fatalf("CreateRoom: %v", err)
+			}
+			invite, err := service.CreateInvite(roomID, test.userID, test.ttl, true)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err == nil && (invite.RoomID != roomID || invite.CreatedBy != "alice" || len(invite.Code) != InviteCodeLength) {
+				t.Errorf("convite = %+v", invite)
+			}
+		})
+	}
+}
+
+func TestJoinPrivateRoom(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  func(invite domain.Invite) JoinOptions
+		expired  bool
+		wantErr  error
+		wantKept bool
+	}{
+		{name: "senha correta", options: func(domain.Invite) JoinOptions { return JoinOptions{Password: "segredo"} }, wantKept: true},
+		{name: "sem senha", options: func(domain.Invite) JoinOptions { return JoinOptions{} }, wantErr: ErrRoomPrivate, wantKept: true},
+		{name: "senha errada", options: func(domain.Invite) JoinOptions { return JoinOptions{Password: "outra"} }, wantErr: ErrWrongPassword, wantKept: true},
+		{name: "convite em minúsculas", options: func(invite domain.Invite) JoinOptions { return JoinOptions{InviteCode: strings.ToLower(invite.Code)} }},
+		{name: "convite de espectador", options: func(invite domain.Invite) JoinOptions { return JoinOptions{InviteCode: invite.Code, Spectator: true} }},
+		{name: "convite desconhecido", options: func(domain.Invite) JoinOptions { return JoinOptions{InviteCode: "XXXXXXXX"} }, wantErr: ErrInvalidInvite, wantKept: true},
+		{name: "convite expirado", options: func(invite domain.Invite) JoinOptions { return JoinOptions{InviteCode: invite.Code} }, expired: true, wantErr: ErrInvalidInvite},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newTestRoomService(t)
+			roomID, err := service.CreateRoom("alice", RoomOptions{Private: true, Password: "segredo"})
+			if err != nil {
+				t.Fatalf("CreateRoom: %v", err)
+			}
+			invite, err := service.CreateInvite(roomID, "alice", time.Minute, true)
+			if err != nil {
+				t.Fatalf("CreateInvite: %v", err)
+			}
+			if test.expired {
+				invite.ExpiresAt = time.Now().Add(-time.Second)
+				service.invites.Set(invite.Code, invite)
+			}
+
+			joined, err := service.JoinRoom(roomID, "bob", test.options(invite))
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			room, _ := service.GetRoom(roomID)
+			if member := room.IsMember("bob"); member != (err == nil) {
+				t.Errorf("bob na sala: %v, erro: %v", member, err)
+			}
+			if err == nil && joined != roomID {
+				t.Errorf("entrou na sala %s, esperado %s", joined, roomID)
+			}
+			// O convite de uso único só é gasto quando leva alguém para dentro da sala.
+			if _, kept := service.invites.Get(invite.Code); kept != test.wantKept {
+				t.Errorf("convite guardado: %v, esperado %v", kept, test.wantKept)
+			}
+		})
+	}
+}
+
+func TestInviteReuse(t *testing.T) {
+	tests := []struct {
+		name      string
+		singleUse bool
+		wantErr   error
+	}{
+		{name: "uso único", singleUse: true, wantErr: ErrInvalidInvite},
+		{name: "vários usos", singleUse: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newTestRoomService(t)
+			roomID, err := service.CreateRoom("alice", RoomOptions{Private: true})
+			if err != nil {
+				t.Fatalf("CreateRoom: %v", err)
+			}
+			invite, err := service.CreateInvite(roomID, "alice", time.Minute, test.singleUse)
+			if err != nil {
+				t.Fatalf("CreateInvite: %v", err)
+			}
+			if _, err := service.JoinRoom("", "bob", JoinOptions{InviteCode: invite.Code}); err != nil {
+				t.Fatalf("primeiro uso: %v", err)
+			}
+			if _, err := service.JoinRoom("", "carol", JoinOptions{InviteCode: invite.Code, Spectator: true}); !errors.Is(err, test.wantErr) {
+				t.Errorf("segundo uso: erro = %v, esperado %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestJoinRoles(t *testing.T) {
+	tests := []struct {
+		name       string
+		full       bool
+		private    bool
+		first      *JoinOptions
+		options    JoinOptions
+		wantErr    error
+		wantStatus string
+	}{
+		{name: "jogador completa a sala", options: JoinOptions{}, wantStatus: domain.RoomStatusReadyCheck},
+		{name: "espectador não ocupa vaga", options: JoinOptions{Spectator: true}, wantStatus: domain.RoomStatusWaiting},
+		{name: "espectador em sala cheia", full: true, options: JoinOptions{Spectator: true}, wantStatus: domain.RoomStatusReadyCheck},
+		{name: "jogador em sala cheia", full: true, options: JoinOptions{}, wantErr: ErrRoomFull, wantStatus: domain.RoomStatusReadyCheck},
+		{name: "espectador de sala privada sem senha", private: true, options: JoinOptions{Spectator: true}, wantErr: ErrRoomPrivate, wantStatus: domain.RoomStatusWaiting},
+		{name: "mesmo papel outra vez", first: &JoinOptions{Spectator: true}, options: JoinOptions{Spectator: true}, wantStatus: domain.RoomStatusWaiting},
+		{name: "espectador tenta jogar", first: &JoinOptions{Spectator: true}, options: JoinOptions{}, wantErr: ErrAlreadyInRoom, wantStatus: domain.RoomStatusWaiting},
+		{name: "jogador tenta assistir", first: &JoinOptions{}, options: JoinOptions{Spectator: true}, wantErr: ErrAlreadyInRoom, wantStatus: domain.RoomStatusReadyCheck},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newTestRoomService(t)
+			options := RoomOptions{Private: test.private}
+			if test.private {
+				options.Password = "segredo"
+			}
+			roomID, err := service.CreateRoom("alice", options)
+			if err != nil {
+				t.Fatalf("CreateRoom: %v", err)
+			}
+			if test.full {
+				if _, err := service.JoinRoom(roomID, "bob", JoinOptions{}); err != nil {
+					t.Fatalf("JoinRoom(bob): %v", err)
+				}
+			}
+			if test.first != nil {
+				if _, err := service.JoinRoom(roomID, "carol", *test.first); err != nil {
+					t.Fatalf("primeira entrada: %v", err)
+				}
+			}
+
+			_, err = service.JoinRoom(roomID, "carol", test.options)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			room, _ := service.GetRoom(roomID)
+			if room.Status != test.wantStatus {
+				t.Errorf("estado = %s, esperado %s", room.Status, test.wantStatus)
+			}
+			if test.wantErr == nil && room.IsSpectator("carol") != test.options.Spectator {
+				t.Errorf("carol espectadora: %v, esperado %v", room.IsSpectator("carol"), test.options.Spectator)
+			}
+		})
+	}
+}
+
+// newHostedRoom cria uma sala com alice como anfitriã, bob como oponente e carol como espectadora.
+func newHostedRoom(t *testing.T) (*RoomService, string) {
+	t.Helper()
+	service := newTestRoomService(t)
+	roomID, err := service.CreateRoom("alice", RoomOptions{})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	if _, err := service.JoinRoom(roomID, "bob", JoinOptions{}); err != nil {
+		t.Fatalf("JoinRoom(bob): %v", err)
+	}
+	if _, err := service.JoinRoom(roomID, "carol", JoinOptions{Spectator: true}); err != nil {
+		t.Fatalf("JoinRoom(carol): %v", err)
+	}
+	return service, roomID
+}
+
+func TestKick(t *testing.T) {
+	tests := []struct {
+		name       string
+		hostID     string
+		targetID   string
+		prepare    func(room *domain.Room)
+		wantErr    error
+		wantStatus string
+	}{
+		{name: "expulsa o oponente", hostID: "alice", targetID: "bob", wantStatus: domain.RoomStatusWaiting},
+		{name: "expulsa o espectador", hostID: "alice", targetID: "carol", wantStatus: domain.RoomStatusReadyCheck},
+		{name: "quem não é anfitrião", hostID: "bob", targetID: "carol", wantErr: ErrNotRoomHost},
+		{name: "a si mesmo", hostID: "alice", targetID: "alice", wantErr: ErrSelfTarget},
+		{name: "quem não está na sala", hostID: "alice", targetID: "dave", wantErr: ErrNotInRoom},
+		{
+			name:     "sala de torneio",
+			hostID:   "alice",
+			targetID: "bob",
+			prepare:  func(room *domain.Room) { room.TournamentID = "1" },
+			wantErr:  ErrTournamentRoom,
+		},
+		{
+			name:     "oponente de partida apostada",
+			hostID:   "alice",
+			targetID: "bob",
+			prepare: func(room *domain.Room) {
+				room.Status = domain.RoomStatusPlaying
+				room.WagerID = "1"
+			},
+			wantErr: ErrWagerKick,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, roomID := newHostedRoom(t)
+			if test.prepare != nil {
+				room, _ := service.GetRoom(roomID)
+				test.prepare(&room)
+				service.RoomRepo.Update(roomID, room)
+			}
+
+			err := service.Kick(roomID, test.hostID, test.targetID)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			room, _ := service.GetRoom(roomID)
+			if room.IsMember(test.targetID) || !room.IsBanned(test.targetID) {
+				t.Errorf("%s na sala: %v, banido: %v", test.targetID, room.IsMember(test.targetID), room.IsBanned(test.targetID))
+			}
+			if room.Status != test.wantStatus {
+				t.Errorf("estado = %s, esperado %s", room.Status, test.wantStatus)
+			}
+			// O expulso não pode voltar, nem com outro papel, até o banimento vencer.
+			if _, err := service.JoinRoom(roomID, test.targetID, JoinOptions{Spectator: true}); !errors.Is(err, ErrBanned) {
+				t.Errorf("retorno do expulso: erro = %v, esperado %v", err, ErrBanned)
+			}
+		})
+	}
+}
+
+func TestKickBanExpires(t *testing.T) {
+	service, roomID := newHostedRoom(t)
+	if err := service.Kick(roomID, "alice", "bob"); err != nil {
+		t.Fatalf("Kick: %v", err)
+	}
+	room, _ := service.GetRoom(roomID)
+	room.Bans.Set("bob", time.Now().Add(-time.Second))
+	service.RoomRepo.Update(roomID, room)
+
+	if _, err := service.JoinRoom(roomID, "bob", JoinOptions{}); err != nil {
+		t.Errorf("retorno após o banimento: %v", err)
+	}
+	// Banimentos vencidos são descartados na expulsão seguinte.
+	if err := service.Kick(roomID, "alice", "carol"); err != nil {
+		t.Fatalf("Kick: %v", err)
+	}
+	room, _ = service.GetRoom(roomID)
+	if _, exists := room.Bans.Get("bob"); exists {
+		t.Error("o banimento vencido continuou guardado")
+	}
+}
+
+func TestSetLocked(t *testing.T) {
+	tests := []struct {
+		name    string
+		hostID  string
+		locked  bool
+		wantErr error
+		joinErr error
+	}{
+		{name: "anfitrião tranca", hostID: "alice", locked: true, joinErr: ErrRoomLocked},
+		{name: "anfitrião destranca", hostID: "alice", locked: false},
+		{name: "quem não é anfitrião", hostID: "bob", locked: true, wantErr: ErrNotRoomHost},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, roomID := newHostedRoom(t)
+			if err := service.SetLocked(roomID, test.hostID, test.locked); !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if _, err := service.JoinRoom(roomID, "dave", JoinOptions{Spectator: true}); !errors.Is(err, test.joinErr) {
+				t.Errorf("entrada de espectador: erro = %v, esperado %v", err, test.joinErr)
+			}
+			// Quem já está na sala continua podendo confirmar seu papel.
+			if _, err := service.JoinRoom(roomID, "bob", JoinOptions{}); err != nil {
+				t.Errorf("membro barrado pela tranca: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransferHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostID   string
+		targetID string
+		wantErr  error
+		wantHost string
+	}{
+		{name: "para o oponente", hostID: "alice", targetID: "bob", wantHost: "bob"},
+		{name: "para o espectador", hostID: "alice", targetID: "carol", wantHost: "carol"},
+		{name: "quem não é anfitrião", hostID: "bob", targetID: "bob", wantErr: ErrNotRoomHost, wantHost: "alice"},
+		{name: "para si mesmo", hostID: "alice", targetID: "alice", wantErr: ErrSelfTarget, wantHost: "alice"},
+		{name: "para quem não está na sala", hostID: "alice", targetID: "dave", wantErr: ErrNotInRoom, wantHost: "alice"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, roomID := newHostedRoom(t)
+			if err := service.TransferHost(roomID, test.hostID, test.targetID); !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if room, _ := service.GetRoom(roomID); room.HostID != test.wantHost || room.OwnerID != "alice" {
+				t.Errorf("anfitrião %q e dono %q, esperado anfitrião %q", room.HostID, room.OwnerID, test.wantHost)
+			}
+		})
+	}
+}
+
+func TestHostSuccession(t *testing.T) {
+	tests := []struct {
+		name     string
+		online   []string
+		leave    bool
+		wantHost string
+	}{
+		{name: "saída passa ao jogador", leave: true, wantHost: "bob"},
+		{name: "queda passa ao jogador conectado", online: []string{"bob", "carol"}, wantHost: "bob"},
+		{name: "queda passa ao espectador conectado", online: []string{"carol"}, wantHost: "carol"},
+		{name: "queda sem ninguém conectado", online: nil, wantHost: "alice"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, roomID := newHostedRoom(t)
+			if test.leave {
+				if err := service.LeaveRoom(roomID, "alice"); err != nil {
+					t.Fatalf("LeaveRoom: %v", err)
+				}
+			} else {
+				online := func(userID string) bool { return slices.Contains(test.online, userID) }
+				changed, err := service.ReleaseHost("alice", online)
+				if err != nil {
+					t.Fatalf("ReleaseHost: %v", err)
+				}
+				if (len(changed) == 1) != (test.wantHost != "alice") {
+					t.Errorf("%d salas mudaram de anfitrião", len(changed))
+				}
+			}
+			if room, _ := service.GetRoom(roomID); room.HostID != test.wantHost {
+				t.Errorf("anfitrião = %q, esperado %q", room.HostID, test.wantHost)
+			}
+		})
+	}
+}
+
+// slowRepository é um repositório em memória cujas leituras demoram, para que as mudanças
+// concorrentes nas salas se intercalem entre a leitura e a escrita.
+type slowRepository[T any] struct {
+	*data.InMemoryRepository[T]
+}
+
+func (r slowRepository[T]) Read(id string) (T, error) {
+	item, err := r.InMemoryRepository.Read(id)
+	time.Sleep(time.Millisecond)
+	return item, err
+}
+
+func TestConcurrentJoins(t *testing.T) {
+	service := NewRoomService(slowRepository[domain.Room]{data.NewInMemoryRepository[domain.Room]()}, newTestRulesets(t), &sync.Mutex{})
+	roomID, err := service.CreateRoom("alice", RoomOptions{})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+
+	// Jogadores e espectadores entram ao mesmo tempo: só uma vaga de jogador resta, e nenhuma
+	// entrada de espectador pode ser apagada por outra.
+	const joiners = 16
+	var players, full atomic.Int32
+	var wait sync.WaitGroup
+	for index := range joiners {
+		wait.Add(2)
+		go func() {
+			defer wait.Done()
+			_, err := service.JoinRoom(roomID, fmt.Sprintf("player-%d", index), JoinOptions{})
+			switch {
+			case err == nil:
+				players.Add(1)
+			case errors.Is(err, ErrRoomFull):
+				full.Add(1)
+			default:
+				t.Errorf("JoinRoom: %v", err)
+			}
+		}()
+		go func() {
+			defer wait.Done()
+			if _, err := service.JoinRoom(roomID, fmt.Sprintf("spectator-%d", index), JoinOptions{Spectator: true}); err != nil {
+				t.Errorf("JoinRoom como espectador: %v", err)
+			}
+		}()
+	}
+	wait.Wait()
+
+	room, _ := service.GetRoom(roomID)
+	if players.Load() != 1 || full.Load() != joiners-1 {
+		t.Errorf("%d jogadores entraram e %d encontraram a sala cheia", players.Load(), full.Load())
+	}
+	if room.UserIDs.Size() != room.Capacity || room.Status != domain.RoomStatusReadyCheck {
+		t.Errorf("%d jogadores na sala de capacidade %d, estado %s", room.UserIDs.Size(), room.Capacity, room.Status)
+	}
+	if room.Spectators.Size() != joiners {
+		t.Errorf("%d espectadores na sala, esperado %d", room.Spectators.Size(), joiners)
+	}
+}
+
+func TestRoomChangesShareTheLock(t *testing.T) {
+	roomMutex := &sync.Mutex{}
+	roomRepo := slowRepository[domain.Room]{data.NewInMemoryRepository[domain.Room]()}
+	rulesetRepo := newTestRulesets(t)
+	rooms := NewRoomService(roomRepo, rulesetRepo, roomMutex)
+	games := NewGameService(data.NewInMemoryRepository[domain.Game](), data.NewInMemoryRepository[domain.User](), roomRepo, rulesetRepo, nil, roomMutex)
+	roomID, err := rooms.CreateRoom("alice", RoomOptions{})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	if _, err := rooms.JoinRoom(roomID, "bob", JoinOptions{}); err != nil {
+		t.Fatalf("JoinRoom: %v", err)
+	}
+
+	// A tranca e a prontidão mudam a mesma sala ao mesmo tempo; nenhuma pode desfazer a outra.
+	var wait sync.WaitGroup
+	for _, change := range []func() error{
+		func() error { return rooms.SetLocked(roomID, "alice", true) },
+		func() error { _, err := games.SetReady(roomID, "alice", true); return err },
+		func() error { _, err := games.SetReady(roomID, "bob", true); return err },
+	} {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			if err := change(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wait.Wait()
+
+	room, _ := rooms.GetRoom(roomID)
+	if !room.Locked || room.Status != domain.RoomStatusPlaying {
+		t.Errorf("sala trancada %v, estado %s", room.Locked, room.Status)
+	}
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sort"
+)
+
+// ErrUnknownRuleset indica que o conjunto de regras pedido não existe no servidor.
+var ErrUnknownRuleset = errors.New("Conjunto de regras desconhecido")
+
+// RulesetServiceInterface descreve as operações de consulta aos conjuntos de regras.
+//
+// Métodos:
+//   - GetRuleset: retorna um conjunto de regras pelo ID.
+//   - ListRulesets: lista os conjuntos de regras disponíveis.
+type RulesetServiceInterface interface {
+	// GetRuleset retorna um conjunto de regras pelo ID; o ID vazio retorna o conjunto padrão.
+	//
+	// Parâmetros:
+	//   - rulesetID: identificador do conjunto de regras.
+	//
+	// Retorno:
+	//   - domain.Ruleset: conjunto de regras encontrado.
+	//   - ErrUnknownRuleset caso não exista.
+	GetRuleset(rulesetID string) (domain.Ruleset, error)
+
+	// ListRulesets lista os conjuntos de regras disponíveis, ordenados pelo ID.
+	//
+	// Retorno:
+	//   - slice com os conjuntos de regras.
+	ListRulesets() []domain.Ruleset
+}
+
+// RulesetService implementa a consulta aos conjuntos de regras carregados no servidor.
+type RulesetService struct {
+	RulesetRepo data.RepositoryInterface[domain.Ruleset]
+}
+
+// NewRulesetService cria uma nova instância de RulesetService.
+//
+// Parâmetros:
+//   - rulesetRepo: repositório dos conjuntos de regras.
+//
+// Retorno:
+//   - ponteiro para RulesetService.
+func NewRulesetService(rulesetRepo data.RepositoryInterface[domain.Ruleset]) *RulesetService {
+	return &RulesetService{RulesetRepo: rulesetRepo}
+}
+
+// GetRuleset retorna um conjunto de regras pelo ID; o ID vazio retorna o conjunto padrão.
+//
+// Parâmetros:
+//   - rulesetID: identificador do conjunto de regras.
+//
+// Retorno:
+//   - domain.Ruleset: conjunto de regras encontrado.
+//   - ErrUnknownRuleset caso não exista.
+func (service *RulesetService) GetRuleset(rulesetID string) (domain.Ruleset, error) {
+	return readRuleset(service.RulesetRepo, rulesetID)
+}
+
+// ListRulesets lista os conjuntos de regras disponíveis, ordenados pelo ID.
+//
+// Retorno:
+//   - slice com os conjuntos de regras.
+func (service *RulesetService) ListRulesets() []domain.Ruleset {
+	rulesets, err := service.RulesetRepo.List()
+	if err != nil {
+		return nil
+	}
+	sort.Slice(rulesets, func(i, j int) bool { return rulesets[i].ID < rulesets[j].ID })
+	return rulesets
+}
+
+// readRuleset lê um conjunto de regras do repositório, usando o padrão quando o ID é vazio.
+func readRuleset(rulesetRepo data.RepositoryInterface[domain.Ruleset], rulesetID string) (domain.Ruleset, error) {
+	if rulesetID == "" {
+		rulesetID = domain.DefaultRulesetID
+	}
+	ruleset, err := rulesetRepo.Read(rulesetID)
+	if err != nil {
+		return domain.Ruleset{}, ErrUnknownRuleset
+	}
+	return ruleset, nil
+}
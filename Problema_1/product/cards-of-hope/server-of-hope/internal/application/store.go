@@ -1,12 +1,48 @@
 package application
 
-import "server-of-hope/internal/domain"
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ServerSeedSize define quantos bytes aleatórios tem cada semente do servidor.
+const ServerSeedSize = 32
+
+// PackHistorySize define quantos pacotes abertos o histórico de um usuário mostra.
+const PackHistorySize = 20
+
+// Erros da loja e do sorteio verificável dos pacotes.
+var (
+	ErrPackNotFound      = errors.New("Tipo de pacote não encontrado")
+	ErrInvalidClientSeed = errors.New("A semente do cliente deve ter de 1 a 64 caracteres")
+	ErrOpeningNotFound   = errors.New("Pacote aberto não encontrado")
+	ErrSeedNotRevealed   = errors.New("A semente deste pacote ainda está em uso; troque a semente para revelá-la e verificar o pacote")
+	ErrPackTablesChanged = errors.New("As tabelas de sorteio deste tipo de pacote mudaram desde a abertura, ou não foram registradas nela; o pacote não pode ser recalculado")
+	ErrOpeningNotLatest  = errors.New("Apenas o último pacote aberto com a semente em uso pode ser desfeito")
+)
 
 // StoreServiceInterface descreve as operações para manipulação de pacotes de cartas na loja.
 //
 // Métodos:
 //   - AddPackage: adiciona um novo pacote de cartas.
 //   - GetPackage: recupera um pacote de cartas.
+//   - Stock: lista os pacotes disponíveis sem consumi-los.
+//   - Catalog: lista os tipos de pacote à venda.
+//   - PackType: recupera um tipo de pacote.
+//   - OpenPackage: sorteia as cartas de um pacote para um usuário.
+//   - CancelOpening: desfaz a abertura de um pacote que não chegou à coleção.
+//   - PityProgress: informa o andamento das garantias de raridade de um usuário.
+//   - Seed: recupera a semente em uso nos sorteios de um usuário.
+//   - SetClientSeed: troca a semente do usuário na semente em uso.
+//   - RotateSeed: revela a semente em uso e cria outra.
+//   - Openings: lista os pacotes abertos por um usuário.
+//   - VerifyOpening: recalcula um pacote aberto com a semente revelada.
 type StoreServiceInterface interface {
 	// AddPackage adiciona um novo pacote de cartas à loja.
 	//
@@ -19,39 +55,514 @@ type StoreServiceInterface interface {
 	// Retorno:
 	//   - CardPackage: pacote de cartas recuperado.
 	GetPackage() domain.CardPackage
+
+	// Stock lista os pacotes de cartas disponíveis na loja sem consumi-los.
+	//
+	// Retorno:
+	//   - slice com os pacotes disponíveis.
+	Stock() []domain.CardPackage
+
+	// Catalog lista os tipos de pacote à venda, do mais barato ao mais caro.
+	//
+	// Retorno:
+	//   - []domain.PackType: tipos de pacote, com as tabelas de sorteio.
+	//   - erro caso não seja possível listar os tipos de pacote.
+	Catalog() ([]domain.PackType, error)
+
+	// PackType recupera um tipo de pacote à venda.
+	//
+	// Parâmetros:
+	//   - packID: identificador do tipo de pacote.
+	//
+	// Retorno:
+	//   - domain.PackType: tipo de pacote encontrado.
+	//   - erro caso a loja não venda o tipo de pacote.
+	PackType(packID string) (domain.PackType, error)
+
+	// OpenPackage sorteia as cartas de um pacote pela tabela do seu tipo, com a semente em uso
+	// do usuário e o próximo nonce, cumprindo a garantia de raridade quando o usuário chega ao
+	// último pacote dela.
+	//
+	// Parâmetros:
+	//   - userID: usuário que abre o pacote.
+	//   - packID: identificador do tipo de pacote.
+	//
+	// Retorno:
+	//   - domain.PackOpening: registro da abertura, com as cartas sorteadas.
+	//   - erro caso a loja não venda o tipo de pacote.
+	OpenPackage(userID, packID string) (domain.PackOpening, error)
+
+	// CancelOpening desfaz a abertura de um pacote cujas cartas não chegaram à coleção: apaga o
+	// registro e devolve o nonce da semente e o contador da garantia aos valores de antes dela.
+	//
+	// Parâmetros:
+	//   - opening: abertura a desfazer, como retornada por OpenPackage.
+	//
+	// Retorno:
+	//   - erro caso a abertura não seja a última da semente em uso ou não possa ser desfeita.
+	CancelOpening(opening domain.PackOpening) error
+
+	// PityProgress informa quantos pacotes de cada tipo o usuário abriu desde a última carta da
+	// raridade garantida.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - map[string]int: pacotes abertos sem a raridade garantida, por tipo de pacote.
+	//   - erro caso não seja possível ler os contadores.
+	PityProgress(userID string) (map[string]int, error)
+
+	// Seed recupera a semente em uso nos sorteios do usuário, criando-a no primeiro uso.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - domain.PackSeed: semente em uso, cuja semente do servidor não deve ser exibida.
+	//   - erro caso não seja possível ler ou criar a semente.
+	Seed(userID string) (domain.PackSeed, error)
+
+	// SetClientSeed troca a semente do usuário; os próximos pacotes da semente em uso a usam.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - clientSeed: nova semente do usuário.
+	//
+	// Retorno:
+	//   - domain.PackSeed: semente em uso atualizada.
+	//   - erro caso a semente do usuário seja inválida.
+	SetClientSeed(userID, clientSeed string) (domain.PackSeed, error)
+
+	// RotateSeed revela a semente do servidor em uso e cria outra, com o nonce zerado.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - clientSeed: semente do usuário para a nova semente (vazio mantém a atual).
+	//
+	// Retorno:
+	//   - domain.PackSeed: semente revelada.
+	//   - domain.PackSeed: nova semente em uso.
+	//   - erro caso a semente do usuário seja inválida.
+	RotateSeed(userID, clientSeed string) (domain.PackSeed, domain.PackSeed, error)
+
+	// Openings lista os pacotes abertos pelo usuário, do mais novo ao mais antigo.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - []domain.PackOpening: pacotes abertos.
+	//   - erro caso não seja possível listar os pacotes.
+	Openings(userID string) ([]domain.PackOpening, error)
+
+	// VerifyOpening recalcula as cartas de um pacote aberto pelo usuário com a semente revelada,
+	// pelas mesmas tabelas de sorteio e o mesmo contador da garantia usados na abertura.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - openingID: identificador do pacote aberto.
+	//
+	// Retorno:
+	//   - domain.PackOpening: registro da abertura.
+	//   - domain.PackSeed: semente revelada usada no sorteio.
+	//   - domain.CardPackage: cartas recalculadas.
+	//   - erro caso o pacote não exista, a semente ainda não tenha sido revelada ou as tabelas do
+	//     tipo de pacote tenham mudado desde a abertura.
+	VerifyOpening(userID, openingID string) (domain.PackOpening, domain.PackSeed, domain.CardPackage, error)
 }
 
-// StoreService implementa a lógica de armazenamento de pacotes de cartas usando um canal.
+// StoreService implementa a lógica de armazenamento de pacotes de cartas e dos sorteios da loja.
 //
 // Campos:
-//   - packages: canal responsável pelo armazenamento dos pacotes de cartas.
+//   - packages: estoque dos pacotes de cartas, do mais antigo ao mais novo.
+//   - stockMutex: protege o estoque de pacotes.
+//   - stocked: acorda quem espera por um pacote quando o estoque é reabastecido.
+//   - packRepo: repositório dos tipos de pacote à venda.
+//   - pityRepo: repositório dos contadores das garantias de raridade.
+//   - rulesetRepo: repositório dos conjuntos de regras, de onde vêm as habilidades das cartas.
+//   - seedRepo: repositório das sementes dos sorteios, em uso e reveladas.
+//   - openingRepo: repositório dos pacotes abertos.
+//   - mutex: serializa as mudanças nos contadores das garantias e nas sementes.
 type StoreService struct {
-	// packages é o canal responsável pelo armazenamento dos pacotes de cartas.
-	packages chan domain.CardPackage
+	packages    []domain.CardPackage
+	stockMutex  sync.Mutex
+	stocked     *sync.Cond
+	packRepo    data.RepositoryInterface[domain.PackType]
+	pityRepo    data.RepositoryInterface[domain.PackPity]
+	rulesetRepo data.RepositoryInterface[domain.Ruleset]
+	seedRepo    data.RepositoryInterface[domain.PackSeed]
+	openingRepo data.RepositoryInterface[domain.PackOpening]
+	mutex       sync.Mutex
 }
 
 // NewStoreService cria uma nova instância de StoreService.
 //
+// Parâmetros:
+//   - packRepo: repositório dos tipos de pacote à venda.
+//   - pityRepo: repositório dos contadores das garantias de raridade.
+//   - rulesetRepo: repositório dos conjuntos de regras.
+//   - seedRepo: repositório das sementes dos sorteios.
+//   - openingRepo: repositório dos pacotes abertos.
+//
 // Retorno:
 //   - ponteiro para StoreService.
-func NewStoreService() *StoreService {
-	return &StoreService{
-		packages: make(chan domain.CardPackage, 10),
+func NewStoreService(
+	packRepo data.RepositoryInterface[domain.PackType],
+	pityRepo data.RepositoryInterface[domain.PackPity],
+	rulesetRepo data.RepositoryInterface[domain.Ruleset],
+	seedRepo data.RepositoryInterface[domain.PackSeed],
+	openingRepo data.RepositoryInterface[domain.PackOpening],
+) *StoreService {
+	storeService := &StoreService{
+		packRepo:    packRepo,
+		pityRepo:    pityRepo,
+		rulesetRepo: rulesetRepo,
+		seedRepo:    seedRepo,
+		openingRepo: openingRepo,
 	}
+	storeService.stocked = sync.NewCond(&storeService.stockMutex)
+	return storeService
 }
 
-// AddPackage adiciona um novo pacote de cartas ao canal de armazenamento.
+// AddPackage adiciona um novo pacote de cartas ao estoque e acorda quem espera por um.
 //
 // Parâmetros:
 //   - cardPackage: pacote de cartas a ser adicionado.
 func (s *StoreService) AddPackage(cardPackage domain.CardPackage) {
-	s.packages <- cardPackage
+	s.stockMutex.Lock()
+	defer s.stockMutex.Unlock()
+	s.packages = append(s.packages, cardPackage)
+	s.stocked.Signal()
 }
 
-// GetPackage recupera um pacote de cartas do canal de armazenamento.
+// GetPackage retira o pacote mais antigo do estoque, esperando enquanto ele estiver vazio.
 //
 // Retorno:
 //   - CardPackage: pacote de cartas recuperado.
 func (s *StoreService) GetPackage() domain.CardPackage {
-	return <-s.packages
+	s.stockMutex.Lock()
+	defer s.stockMutex.Unlock()
+	for len(s.packages) == 0 {
+		s.stocked.Wait()
+	}
+	cardPackage := s.packages[0]
+	s.packages = s.packages[1:]
+	return cardPackage
+}
+
+// Stock lista os pacotes disponíveis no estoque sem consumi-los.
+//
+// Retorno:
+//   - slice com os pacotes disponíveis, do mais antigo ao mais novo.
+func (s *StoreService) Stock() []domain.CardPackage {
+	s.stockMutex.Lock()
+	defer s.stockMutex.Unlock()
+	return append([]domain.CardPackage(nil), s.packages...)
+}
+
+// Catalog lista os tipos de pacote à venda, do mais barato ao mais caro.
+func (s *StoreService) Catalog() ([]domain.PackType, error) {
+	packs, err := s.packRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(packs, func(i, j int) bool {
+		if packs[i].Price != packs[j].Price {
+			return packs[i].Price < packs[j].Price
+		}
+		return packs[i].ID < packs[j].ID
+	})
+	return packs, nil
+}
+
+// PackType recupera um tipo de pacote à venda.
+func (s *StoreService) PackType(packID string) (domain.PackType, error) {
+	pack, err := s.packRepo.Read(packID)
+	if err != nil {
+		return domain.PackType{}, ErrPackNotFound
+	}
+	return pack, nil
+}
+
+// OpenPackage sorteia as cartas de um pacote com a semente em uso do usuário, registra a abertura
+// e atualiza o nonce da semente e o contador da garantia de raridade: o contador volta a zero
+// quando o pacote traz a raridade garantida.
+func (s *StoreService) OpenPackage(userID, packID string) (domain.PackOpening, error) {
+	pack, err := s.PackType(packID)
+	if err != nil {
+		return domain.PackOpening{}, err
+	}
+	ruleset, err := readRuleset(s.rulesetRepo, domain.DefaultRulesetID)
+	if err != nil {
+		return domain.PackOpening{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	seed, err := s.activeSeed(userID)
+	if err != nil {
+		return domain.PackOpening{}, err
+	}
+	key := domain.PackPityKey(userID, packID)
+	pity, err := s.pityRepo.Read(key)
+	save := s.pityRepo.Update
+	if err != nil {
+		pity = domain.PackPity{UserID: userID, PackID: packID}
+		save = s.pityRepo.Create
+	}
+	guarantee := pack.Guarantees(pity.Opened)
+	draw := domain.FairDraw(seed.ServerSeed, seed.ClientSeed, seed.Nonce)
+	opening := domain.PackOpening{
+		ID:         utils.Count(),
+		UserID:     userID,
+		PackID:     packID,
+		SeedID:     seed.ID,
+		ClientSeed: seed.ClientSeed,
+		Nonce:      seed.Nonce,
+		Guarantee:  guarantee,
+		PityOpened: pity.Opened,
+		TableHash:  pack.TableHash(ruleset.Abilities),
+		Cards:      pack.Roll(draw, ruleset.Abilities, guarantee),
+		CreatedAt:  time.Now(),
+	}
+
+	// A abertura é gravada antes de o nonce avançar, e cada passo que falha desfaz os anteriores:
+	// assim nenhum nonce é gasto sem um registro que permita verificar o sorteio.
+	if err := s.openingRepo.Create(opening.ID, opening); err != nil {
+		return domain.PackOpening{}, err
+	}
+	advanced := seed
+	advanced.Nonce++
+	if err := s.seedRepo.Update(seed.ID, advanced); err != nil {
+		return domain.PackOpening{}, s.undoOpening(opening, err)
+	}
+	pity.Opened++
+	if pack.Pity.Packs > 0 && pack.MeetsPity(opening.Cards) {
+		pity.Opened = 0
+	}
+	if err := save(key, pity); err != nil {
+		if restoreErr := s.seedRepo.Update(seed.ID, seed); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("falha ao restaurar o nonce da semente %s: %w", seed.ID, restoreErr))
+		}
+		return domain.PackOpening{}, s.undoOpening(opening, err)
+	}
+	return opening, nil
+}
+
+// undoOpening apaga o registro de uma abertura que não pôde ser concluída. Deve ser chamada com o
+// mutex travado.
+//
+// Retorno:
+//   - o erro que interrompeu a abertura, junto com o erro da remoção, se houver.
+func (s *StoreService) undoOpening(opening domain.PackOpening, cause error) error {
+	if err := s.openingRepo.Delete(opening.ID); err != nil {
+		return errors.Join(cause, fmt.Errorf("falha ao apagar o pacote aberto %s: %w", opening.ID, err))
+	}
+	return cause
+}
+
+// CancelOpening desfaz a abertura de um pacote cujas cartas não chegaram à coleção. Apenas a
+// última abertura da semente em uso pode ser desfeita: com outra aberta depois, devolver o nonce
+// faria o mesmo sorteio sair duas vezes. Como em OpenPackage, cada passo que falha refaz os
+// anteriores, para que nenhum nonce fique gasto sem o registro da abertura.
+func (s *StoreService) CancelOpening(opening domain.PackOpening) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	seed, err := s.seedRepo.Read(opening.SeedID)
+	if err != nil {
+		return err
+	}
+	if seed.Revealed() || seed.Nonce != opening.Nonce+1 {
+		return ErrOpeningNotLatest
+	}
+	key := domain.PackPityKey(opening.UserID, opening.PackID)
+	pity, err := s.pityRepo.Read(key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.openingRepo.Delete(opening.ID); err != nil {
+		return err
+	}
+	restored := seed
+	restored.Nonce = opening.Nonce
+	if err := s.seedRepo.Update(seed.ID, restored); err != nil {
+		return s.redoOpening(opening, err)
+	}
+	reverted := pity
+	reverted.Opened = opening.PityOpened
+	if err := s.pityRepo.Update(key, reverted); err != nil {
+		if restoreErr := s.seedRepo.Update(seed.ID, seed); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("falha ao avançar de novo o nonce da semente %s: %w", seed.ID, restoreErr))
+		}
+		return s.redoOpening(opening, err)
+	}
+	return nil
+}
+
+// redoOpening grava de novo o registro de uma abertura que não pôde ser desfeita. Deve ser
+// chamada com o mutex travado.
+//
+// Retorno:
+//   - o erro que interrompeu o cancelamento, junto com o erro da gravação, se houver.
+func (s *StoreService) redoOpening(opening domain.PackOpening, cause error) error {
+	if err := s.openingRepo.Create(opening.ID, opening); err != nil {
+		return errors.Join(cause, fmt.Errorf("falha ao regravar o pacote aberto %s: %w", opening.ID, err))
+	}
+	return cause
+}
+
+// PityProgress informa quantos pacotes de cada tipo o usuário abriu sem a raridade garantida.
+func (s *StoreService) PityProgress(userID string) (map[string]int, error) {
+	counters, err := s.pityRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	progress := map[string]int{}
+	for _, counter := range counters {
+		if counter.UserID == userID {
+			progress[counter.PackID] = counter.Opened
+		}
+	}
+	return progress, nil
+}
+
+// Seed recupera a semente em uso nos sorteios do usuário, criando-a no primeiro uso.
+func (s *StoreService) Seed(userID string) (domain.PackSeed, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.activeSeed(userID)
+}
+
+// SetClientSeed troca a semente do usuário na semente em uso. A semente do servidor não muda,
+// então o hash publicado continua valendo.
+func (s *StoreService) SetClientSeed(userID, clientSeed string) (domain.PackSeed, error) {
+	if !domain.ValidClientSeed(clientSeed) {
+		return domain.PackSeed{}, ErrInvalidClientSeed
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	seed, err := s.activeSeed(userID)
+	if err != nil {
+		return domain.PackSeed{}, err
+	}
+	seed.ClientSeed = clientSeed
+	if err := s.seedRepo.Update(seed.ID, seed); err != nil {
+		return domain.PackSeed{}, err
+	}
+	return seed, nil
+}
+
+// RotateSeed revela a semente em uso e cria outra para os próximos pacotes, mantendo a semente
+// do usuário quando nenhuma nova é informada.
+func (s *StoreService) RotateSeed(userID, clientSeed string) (domain.PackSeed, domain.PackSeed, error) {
+	if clientSeed != "" && !domain.ValidClientSeed(clientSeed) {
+		return domain.PackSeed{}, domain.PackSeed{}, ErrInvalidClientSeed
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	revealed, err := s.activeSeed(userID)
+	if err != nil {
+		return domain.PackSeed{}, domain.PackSeed{}, err
+	}
+	now := time.Now()
+	revealed.RevealedAt = &now
+	if err := s.seedRepo.Update(revealed.ID, revealed); err != nil {
+		return domain.PackSeed{}, domain.PackSeed{}, err
+	}
+	if clientSeed == "" {
+		clientSeed = revealed.ClientSeed
+	}
+	active, err := s.createSeed(userID, clientSeed)
+	if err != nil {
+		return domain.PackSeed{}, domain.PackSeed{}, err
+	}
+	return revealed, active, nil
+}
+
+// Openings lista os pacotes abertos pelo usuário, do mais novo ao mais antigo.
+func (s *StoreService) Openings(userID string) ([]domain.PackOpening, error) {
+	all, err := s.openingRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var openings []domain.PackOpening
+	for _, opening := range all {
+		if opening.UserID == userID {
+			openings = append(openings, opening)
+		}
+	}
+	sort.Slice(openings, func(i, j int) bool {
+		return openings[i].CreatedAt.After(openings[j].CreatedAt)
+	})
+	return openings, nil
+}
+
+// VerifyOpening recalcula as cartas de um pacote aberto pelo usuário com a semente revelada, a
+// semente do usuário e o nonce registrados, pelas tabelas atuais do tipo de pacote.
+func (s *StoreService) VerifyOpening(userID, openingID string) (domain.PackOpening, domain.PackSeed, domain.CardPackage, error) {
+	opening, err := s.openingRepo.Read(openingID)
+	if err != nil || opening.UserID != userID {
+		return domain.PackOpening{}, domain.PackSeed{}, domain.CardPackage{}, ErrOpeningNotFound
+	}
+	seed, err := s.seedRepo.Read(opening.SeedID)
+	if err != nil {
+		return domain.PackOpening{}, domain.PackSeed{}, domain.CardPackage{}, err
+	}
+	if !seed.Revealed() {
+		return opening, domain.PackSeed{}, domain.CardPackage{}, ErrSeedNotRevealed
+	}
+	pack, err := s.PackType(opening.PackID)
+	if err != nil {
+		return domain.PackOpening{}, domain.PackSeed{}, domain.CardPackage{}, err
+	}
+	ruleset, err := readRuleset(s.rulesetRepo, domain.DefaultRulesetID)
+	if err != nil {
+		return domain.PackOpening{}, domain.PackSeed{}, domain.CardPackage{}, err
+	}
+	if pack.TableHash(ruleset.Abilities) != opening.TableHash {
+		return opening, seed, domain.CardPackage{}, ErrPackTablesChanged
+	}
+	// A garantia é recalculada do contador registrado, sem confiar na marcação da abertura.
+	draw := domain.FairDraw(seed.ServerSeed, opening.ClientSeed, opening.Nonce)
+	return opening, seed, pack.Roll(draw, ruleset.Abilities, pack.Guarantees(opening.PityOpened)), nil
+}
+
+// activeSeed retorna a semente em uso do usuário, criando uma com a semente do usuário aleatória
+// no primeiro uso. Deve ser chamada com o mutex travado.
+func (s *StoreService) activeSeed(userID string) (domain.PackSeed, error) {
+	seeds, err := s.seedRepo.List()
+	if err != nil {
+		return domain.PackSeed{}, err
+	}
+	for _, seed := range seeds {
+		if seed.UserID == userID && !seed.Revealed() {
+			return seed, nil
+		}
+	}
+	return s.createSeed(userID, utils.RandomHex(8))
+}
+
+// createSeed cria uma semente do servidor para o usuário, publicando só o seu hash.
+func (s *StoreService) createSeed(userID, clientSeed string) (domain.PackSeed, error) {
+	serverSeed := utils.RandomHex(ServerSeedSize)
+	seed := domain.PackSeed{
+		ID:         utils.Count(),
+		UserID:     userID,
+		ServerSeed: serverSeed,
+		Hash:       domain.SeedHash(serverSeed),
+		ClientSeed: clientSeed,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.seedRepo.Create(seed.ID, seed); err != nil {
+		return domain.PackSeed{}, err
+	}
+	return seed, nil
 }
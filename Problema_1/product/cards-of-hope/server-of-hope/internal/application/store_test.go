@@ -0,0 +1,104 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"testing"
+)
+
+// newTestStoreService cria uma loja que vende só o pacote "test": cartas quase sempre comuns e a
+// garantia de uma épica a cada três pacotes. Ligar failPity faz o contador da garantia falhar.
+func newTestStoreService(t *testing.T, failPity *bool) (*StoreService, *data.InMemoryRepository[domain.PackSeed], *data.InMemoryRepository[domain.PackOpening]) {
+	t.Helper()
+	packRepo := data.NewInMemoryRepository[domain.PackType]()
+	packRepo.Create("test", domain.PackType{
+		ID:    "test",
+		Price: 10,
+		Types: []domain.TypeWeight{{Type: "rock", Weight: 1}},
+		Rarities: []domain.RarityTier{
+			{Rarity: domain.RarityCommon, Weight: 1 << 30, Stars: []domain.StarWeight{{Stars: 1, Weight: 1}}},
+			{Rarity: domain.RarityEpic, Weight: 1, Stars: []domain.StarWeight{{Stars: 5, Weight: 1}}},
+		},
+		Pity: domain.Pity{Rarity: domain.RarityEpic, Packs: 3},
+	})
+	if failPity == nil {
+		failPity = new(bool)
+	}
+	seedRepo := data.NewInMemoryRepository[domain.PackSeed]()
+	openingRepo := data.NewInMemoryRepository[domain.PackOpening]()
+	pityRepo := newFailingRepository[domain.PackPity]()
+	pityRepo.failUpdate = func(string) bool { return *failPity }
+	return NewStoreService(packRepo, pityRepo, newTestRulesets(t), seedRepo, openingRepo), seedRepo, openingRepo
+}
+
+func TestOpenPackagePity(t *testing.T) {
+	store, seedRepo, _ := newTestStoreService(t, nil)
+	for index, want := range []struct {
+		guarantee bool
+		opened    int
+	}{{false, 1}, {false, 2}, {true, 0}, {false, 1}} {
+		opening, err := store.OpenPackage("alice", "test")
+		if err != nil {
+			t.Fatalf("OpenPackage: %v", err)
+		}
+		if opening.Guarantee != want.guarantee || opening.Nonce != index || (opening.Cards[2].Rarity == domain.RarityEpic) != want.guarantee {
+			t.Errorf("pacote %d com garantia %v, nonce %d e última carta %s", index, opening.Guarantee, opening.Nonce, opening.Cards[2].Rarity)
+		}
+		if progress, _ := store.PityProgress("alice"); progress["test"] != want.opened {
+			t.Errorf("depois do pacote %d, contador = %d, esperado %d", index, progress["test"], want.opened)
+		}
+	}
+	seed, _ := store.Seed("alice")
+	if saved, _ := seedRepo.Read(seed.ID); saved.Nonce != 4 {
+		t.Errorf("nonce = %d, esperado 4", saved.Nonce)
+	}
+	if _, err := store.OpenPackage("alice", "nenhum"); !errors.Is(err, ErrPackNotFound) {
+		t.Errorf("pacote desconhecido: erro = %v", err)
+	}
+}
+
+func TestCancelOpening(t *testing.T) {
+	failPity := false
+	store, seedRepo, openingRepo := newTestStoreService(t, &failPity)
+	store.OpenPackage("alice", "test")
+	first, _ := store.OpenPackage("alice", "test")
+	if _, err := store.OpenPackage("bob", "test"); err != nil {
+		t.Fatalf("OpenPackage de bob: %v", err)
+	}
+	second, _ := store.OpenPackage("alice", "test")
+
+	// Com outro pacote aberto depois, devolver o nonce repetiria um sorteio.
+	if err := store.CancelOpening(first); !errors.Is(err, ErrOpeningNotLatest) {
+		t.Fatalf("abertura antiga: erro = %v, esperado %v", err, ErrOpeningNotLatest)
+	}
+
+	// Se o contador não puder voltar, a abertura continua inteira: registro, nonce e contador.
+	failPity = true
+	if err := store.CancelOpening(second); err == nil {
+		t.Fatal("cancelamento aceito com o contador da garantia falhando")
+	}
+	failPity = false
+	seed, _ := seedRepo.Read(second.SeedID)
+	if _, err := openingRepo.Read(second.ID); err != nil || seed.Nonce != second.Nonce+1 {
+		t.Fatalf("abertura parcialmente desfeita: registro %v, nonce %d", err, seed.Nonce)
+	}
+
+	if err := store.CancelOpening(second); err != nil {
+		t.Fatalf("CancelOpening: %v", err)
+	}
+	if _, err := openingRepo.Read(second.ID); err == nil {
+		t.Error("o registro da abertura desfeita continua gravado")
+	}
+	if progress, _ := store.PityProgress("alice"); progress["test"] != second.PityOpened {
+		t.Errorf("contador = %d, esperado %d", progress["test"], second.PityOpened)
+	}
+	// O próximo pacote repete o sorteio desfeito, com o mesmo nonce e a mesma garantia.
+	again, err := store.OpenPackage("alice", "test")
+	if err != nil {
+		t.Fatalf("OpenPackage: %v", err)
+	}
+	if again.Nonce != second.Nonce || again.Cards != second.Cards || again.Guarantee != second.Guarantee {
+		t.Errorf("pacote reaberto = %+v, esperado as cartas de %+v", again, second)
+	}
+}
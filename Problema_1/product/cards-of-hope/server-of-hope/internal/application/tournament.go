@@ -0,0 +1,587 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limites do tamanho de um torneio.
+const (
+	MinTournamentSize = 2
+	MaxTournamentSize = 32
+)
+
+// TournamentOptions descreve as opções de criação de um torneio.
+//
+// Campos:
+//   - Name: nome de exibição do torneio (vazio gera um nome padrão).
+//   - Format: formato do torneio (vazio usa eliminação simples).
+//   - Size: quantidade máxima de jogadores.
+//   - Ruleset: ID do conjunto de regras das partidas (vazio usa o conjunto padrão).
+type TournamentOptions struct {
+	Name    string
+	Format  string
+	Size    int
+	Ruleset string
+}
+
+// Erros de torneio exibidos diretamente aos usuários.
+var (
+	ErrTournamentNotFound   = errors.New("Torneio não encontrado")
+	ErrTournamentFormat     = errors.New("Formato de torneio desconhecido: use single_elimination ou round_robin")
+	ErrTournamentSize       = errors.New("O torneio deve ter entre 2 e 32 jogadores")
+	ErrTournamentClosed     = errors.New("O torneio não aceita mais inscrições")
+	ErrTournamentFull       = errors.New("O torneio está lotado")
+	ErrAlreadyRegistered    = errors.New("Você já está inscrito neste torneio")
+	ErrNotRegistered        = errors.New("Você não está inscrito neste torneio")
+	ErrNotTournamentOwner   = errors.New("Apenas quem criou o torneio pode iniciá-lo")
+	ErrTournamentTooFew     = errors.New("O torneio precisa de ao menos 2 jogadores para começar")
+	ErrTournamentBotPlayers = errors.New("Bots não participam de torneios")
+	ErrMatchRoomUnavailable = errors.New("Não foi possível abrir a sala de uma partida do torneio; ela será aberta quando o torneio avançar de novo")
+)
+
+// errMatchDecided indica que a partida do torneio já tinha resultado, como em uma revanche
+// disputada na mesma sala depois da partida valendo.
+var errMatchDecided = errors.New("a partida do torneio já foi decidida")
+
+// TournamentServiceInterface descreve as operações dos torneios.
+//
+// Métodos:
+//   - CreateTournament: cria um torneio com inscrições abertas.
+//   - Register: inscreve um jogador.
+//   - Unregister: cancela a inscrição de um jogador.
+//   - Start: monta a chave e abre as salas das primeiras partidas.
+//   - RecordResult: registra o resultado da partida disputada em uma sala do torneio.
+//   - Forfeit: dá a vitória ao oponente de quem saiu da sala de uma partida.
+//   - GetTournament: retorna um torneio pelo ID.
+//   - ListTournaments: lista os torneios.
+type TournamentServiceInterface interface {
+	// CreateTournament cria um torneio com inscrições abertas. Quem cria não é inscrito.
+	//
+	// Parâmetros:
+	//   - ownerID: identificador do usuário que cria o torneio.
+	//   - options: opções de criação do torneio.
+	//
+	// Retorno:
+	//   - domain.Tournament: torneio criado.
+	//   - erro caso as opções sejam inválidas.
+	CreateTournament(ownerID string, options TournamentOptions) (domain.Tournament, error)
+
+	// Register inscreve um jogador no torneio. A inscrição que lota o torneio o inicia.
+	//
+	// Parâmetros:
+	//   - tournamentID: identificador do torneio.
+	//   - userID: identificador do jogador.
+	//
+	// Retorno:
+	//   - domain.Tournament: torneio atualizado.
+	//   - []domain.TournamentMatch: partidas cujas salas foram abertas.
+	//   - erro caso a inscrição não seja aceita.
+	Register(tournamentID, userID string) (domain.Tournament, []domain.TournamentMatch, error)
+
+	// Unregister cancela a inscrição de um jogador enquanto o torneio não começou.
+	//
+	// Parâmetros:
+	//   - tournamentID: identificador do torneio.
+	//   - userID: identificador do jogador.
+	//
+	// Retorno:
+	//   - domain.Tournament: torneio atualizado.
+	//   - erro caso o jogador não esteja inscrito ou o torneio já tenha começado.
+	Unregister(tournamentID, userID string) (domain.Tournament, error)
+
+	// Start monta a chave com os inscritos e abre as salas das primeiras partidas, sem esperar
+	// o torneio lotar. Apenas quem criou o torneio pode iniciá-lo.
+	//
+	// Parâmetros:
+	//   - tournamentID: identificador do torneio.
+	//   - userID: identificador de quem inicia.
+	//
+	// Retorno:
+	//   - domain.Tournament: torneio atualizado.
+	//   - []domain.TournamentMatch: partidas cujas salas foram abertas.
+	//   - erro caso o torneio não possa começar.
+	Start(tournamentID, userID string) (domain.Tournament, []domain.TournamentMatch, error)
+
+	// RecordResult registra o resultado da partida disputada na sala e abre as salas das
+	// partidas que ficaram prontas. Na eliminação simples, um empate não decide a partida.
+	//
+	// Parâmetros:
+	//   - tournamentID: identificador do torneio.
+	//   - roomID: sala da partida.
+	//   - winnerID: vencedor da partida (vazio em empate).
+	//
+	// Retorno:
+	//   - domain.Tournament: torneio atualizado.
+	//   - []domain.TournamentMatch: partidas cujas salas foram abertas.
+	//   - erro caso a sala não tenha partida do torneio em andamento.
+	RecordResult(tournamentID, roomID, winnerID string) (domain.Tournament, []domain.TournamentMatch, error)
+
+	// Forfeit dá a vitória ao oponente do jogador que saiu da sala de uma partida em andamento.
+	//
+	// Parâmetros:
+	//   - tournamentID: identificador do torneio.
+	//   - roomID: sala da partida.
+	//   - userID: jogador que saiu.
+	//
+	// Retorno:
+	//   - domain.Tournament: torneio atualizado.
+	//   - []domain.TournamentMatch: partidas cujas salas foram abertas.
+	//   - erro caso o usuário não jogue uma partida em andamento na sala.
+	Forfeit(tournamentID, roomID, userID string) (domain.Tournament, []domain.TournamentMatch, error)
+
+	// GetTournament retorna o torneio associado ao ID informado.
+	//
+	// Parâmetros:
+	//   - tournamentID: identificador do torneio.
+	//
+	// Retorno:
+	//   - domain.Tournament: torneio encontrado.
+	//   - erro caso o torneio não exista.
+	GetTournament(tournamentID string) (domain.Tournament, error)
+
+	// ListTournaments lista os torneios, dos mais antigos para os mais novos.
+	//
+	// Retorno:
+	//   - []domain.Tournament: torneios encontrados.
+	//   - erro caso não seja possível listar os torneios.
+	ListTournaments() ([]domain.Tournament, error)
+}
+
+// TournamentService implementa os torneios. As partidas são disputadas em salas comuns,
+// criadas pelo RoomService e marcadas com o ID do torneio.
+//
+// Campos:
+//   - tournamentRepo: repositório dos torneios.
+//   - userRepo: repositório dos usuários, para validar as inscrições.
+//   - rulesetRepo: repositório das regras que os torneios podem escolher.
+//   - roomService: serviço usado para abrir as salas das partidas.
+//   - mutex: serializa as mudanças nos torneios.
+type TournamentService struct {
+	tournamentRepo data.RepositoryInterface[domain.Tournament]
+	userRepo       data.RepositoryInterface[domain.User]
+	rulesetRepo    data.RepositoryInterface[domain.Ruleset]
+	roomService    RoomServiceInterface
+	mutex          sync.Mutex
+}
+
+// NewTournamentService cria uma nova instância de TournamentService.
+//
+// Parâmetros:
+//   - tournamentRepo: repositório dos torneios.
+//   - userRepo: repositório dos usuários.
+//   - rulesetRepo: repositório das regras.
+//   - roomService: serviço das salas.
+//
+// Retorno:
+//   - ponteiro para TournamentService.
+func NewTournamentService(
+	tournamentRepo data.RepositoryInterface[domain.Tournament],
+	userRepo data.RepositoryInterface[domain.User],
+	rulesetRepo data.RepositoryInterface[domain.Ruleset],
+	roomService RoomServiceInterface,
+) *TournamentService {
+	return &TournamentService{
+		tournamentRepo: tournamentRepo,
+		userRepo:       userRepo,
+		rulesetRepo:    rulesetRepo,
+		roomService:    roomService,
+	}
+}
+
+// CreateTournament cria um torneio com inscrições abertas. Quem cria não é inscrito.
+//
+// Parâmetros:
+//   - ownerID: identificador do usuário que cria o torneio.
+//   - options: opções de criação do torneio.
+//
+// Retorno:
+//   - domain.Tournament: torneio criado.
+//   - erro caso o nome, o formato, o tamanho ou as regras sejam inválidos.
+func (service *TournamentService) CreateTournament(ownerID string, options TournamentOptions) (domain.Tournament, error) {
+	name := strings.TrimSpace(options.Name)
+	if len([]rune(name)) > MaxRoomNameLength {
+		return domain.Tournament{}, errors.New("nome do torneio muito longo")
+	}
+	format := options.Format
+	if format == "" {
+		format = domain.TournamentSingleElimination
+	}
+	if !domain.ValidTournamentFormat(format) {
+		return domain.Tournament{}, ErrTournamentFormat
+	}
+	if options.Size < MinTournamentSize || options.Size > MaxTournamentSize {
+		return domain.Tournament{}, ErrTournamentSize
+	}
+	ruleset, err := readRuleset(service.rulesetRepo, options.Ruleset)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+
+	id := utils.Count()
+	if name == "" {
+		name = "Torneio " + id
+	}
+	tournament := domain.Tournament{
+		ID:        id,
+		Name:      name,
+		OwnerID:   ownerID,
+		Format:    format,
+		Size:      options.Size,
+		RulesetID: ruleset.ID,
+		Status:    domain.TournamentStatusRegistering,
+		Players:   []string{},
+		CreatedAt: time.Now(),
+	}
+	return tournament, service.tournamentRepo.Create(id, tournament)
+}
+
+// Register inscreve um jogador no torneio. A inscrição que lota o torneio o inicia.
+//
+// Parâmetros:
+//   - tournamentID: identificador do torneio.
+//   - userID: identificador do jogador.
+//
+// Retorno:
+//   - domain.Tournament: torneio atualizado.
+//   - []domain.TournamentMatch: partidas cujas salas foram abertas.
+//   - erro caso a inscrição não seja aceita.
+func (service *TournamentService) Register(tournamentID, userID string) (domain.Tournament, []domain.TournamentMatch, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	tournament, err := service.read(tournamentID)
+	if err != nil {
+		return domain.Tournament{}, nil, err
+	}
+	user, err := service.userRepo.Read(userID)
+	if err != nil {
+		return domain.Tournament{}, nil, err
+	}
+	switch {
+	case user.Bot:
+		return domain.Tournament{}, nil, ErrTournamentBotPlayers
+	case tournament.Status != domain.TournamentStatusRegistering:
+		return domain.Tournament{}, nil, ErrTournamentClosed
+	case tournament.IsRegistered(userID):
+		return domain.Tournament{}, nil, ErrAlreadyRegistered
+	case len(tournament.Players) >= tournament.Size:
+		return domain.Tournament{}, nil, ErrTournamentFull
+	}
+
+	tournament.Players = append(tournament.Players, userID)
+	if len(tournament.Players) < tournament.Size {
+		return tournament, nil, service.tournamentRepo.Update(tournament.ID, tournament)
+	}
+	return service.start(tournament)
+}
+
+// Unregister cancela a inscrição de um jogador enquanto o torneio não começou.
+//
+// Parâmetros:
+//   - tournamentID: identificador do torneio.
+//   - userID: identificador do jogador.
+//
+// Retorno:
+//   - domain.Tournament: torneio atualizado.
+//   - erro caso o jogador não esteja inscrito ou o torneio já tenha começado.
+func (service *TournamentService) Unregister(tournamentID, userID string) (domain.Tournament, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	tournament, err := service.read(tournamentID)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	if tournament.Status != domain.TournamentStatusRegistering {
+		return domain.Tournament{}, ErrTournamentClosed
+	}
+	if !tournament.IsRegistered(userID) {
+		return domain.Tournament{}, ErrNotRegistered
+	}
+
+	players := make([]string, 0, len(tournament.Players))
+	for _, playerID := range tournament.Players {
+		if playerID != userID {
+			players = append(players, playerID)
+		}
+	}
+	tournament.Players = players
+	return tournament, service.tournamentRepo.Update(tournament.ID, tournament)
+}
+
+// Start monta a chave com os inscritos e abre as salas das primeiras partidas, sem esperar o
+// torneio lotar. Apenas quem criou o torneio pode iniciá-lo.
+//
+// Parâmetros:
+//   - tournamentID: identificador do torneio.
+//   - userID: identificador de quem inicia.
+//
+// Retorno:
+//   - domain.Tournament: torneio atualizado.
+//   - []domain.TournamentMatch: partidas cujas salas foram abertas.
+//   - erro caso o torneio não possa começar.
+func (service *TournamentService) Start(tournamentID, userID string) (domain.Tournament, []domain.TournamentMatch, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	tournament, err := service.read(tournamentID)
+	if err != nil {
+		return domain.Tournament{}, nil, err
+	}
+	switch {
+	case tournament.OwnerID != userID:
+		return domain.Tournament{}, nil, ErrNotTournamentOwner
+	case tournament.Status != domain.TournamentStatusRegistering:
+		return domain.Tournament{}, nil, ErrTournamentClosed
+	case len(tournament.Players) < MinTournamentSize:
+		return domain.Tournament{}, nil, ErrTournamentTooFew
+	}
+	return service.start(tournament)
+}
+
+// start monta a chave do torneio e abre as primeiras partidas. Deve ser chamado com mutex travado.
+func (service *TournamentService) start(tournament domain.Tournament) (domain.Tournament, []domain.TournamentMatch, error) {
+	if tournament.Format == domain.TournamentRoundRobin {
+		tournament.Matches = domain.NewRoundRobinSchedule(tournament.Players)
+	} else {
+		tournament.Matches = domain.NewSingleEliminationBracket(tournament.Players)
+	}
+	tournament.Status = domain.TournamentStatusRunning
+	tournament.StartedAt = time.Now()
+	return service.advance(tournament)
+}
+
+// RecordResult registra o resultado da partida disputada na sala e abre as salas das
+// partidas que ficaram prontas. Na eliminação simples, um empate não decide a partida: os
+// jogadores disputam outra na mesma sala.
+//
+// Parâmetros:
+//   - tournamentID: identificador do torneio.
+//   - roomID: sala da partida.
+//   - winnerID: vencedor da partida (vazio em empate).
+//
+// Retorno:
+//   - domain.Tournament: torneio atualizado.
+//   - []domain.TournamentMatch: partidas cujas salas foram abertas.
+//   - erro caso a sala não tenha partida do torneio em andamento.
+func (service *TournamentService) RecordResult(tournamentID, roomID, winnerID string) (domain.Tournament, []domain.TournamentMatch, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	tournament, index, err := service.readMatch(tournamentID, roomID)
+	if err != nil {
+		return domain.Tournament{}, nil, err
+	}
+	if winnerID == "" && tournament.Format == domain.TournamentSingleElimination {
+		return tournament, nil, nil
+	}
+	tournament.Matches[index].Status = domain.TournamentMatchFinished
+	tournament.Matches[index].WinnerID = winnerID
+	return service.advance(tournament)
+}
+
+// Forfeit dá a vitória ao oponente do jogador que saiu da sala de uma partida em andamento.
+//
+// Parâmetros:
+//   - tournamentID: identificador do torneio.
+//   - roomID: sala da partida.
+//   - userID: jogador que saiu.
+//
+// Retorno:
+//   - domain.Tournament: torneio atualizado.
+//   - []domain.TournamentMatch: partidas cujas salas foram abertas.
+//   - erro caso o usuário não jogue uma partida em andamento na sala.
+func (service *TournamentService) Forfeit(tournamentID, roomID, userID string) (domain.Tournament, []domain.TournamentMatch, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	tournament, index, err := service.readMatch(tournamentID, roomID)
+	if err != nil {
+		return domain.Tournament{}, nil, err
+	}
+	match := tournament.Matches[index]
+	if !match.Has(userID) {
+		return domain.Tournament{}, nil, ErrNotInRoom
+	}
+	tournament.Matches[index].Status = domain.TournamentMatchFinished
+	tournament.Matches[index].WinnerID = match.Opponent(userID)
+	tournament.Matches[index].Walkover = true
+	return service.advance(tournament)
+}
+
+// readMatch lê o torneio e localiza a partida em andamento na sala.
+func (service *TournamentService) readMatch(tournamentID, roomID string) (domain.Tournament, int, error) {
+	tournament, err := service.read(tournamentID)
+	if err != nil {
+		return domain.Tournament{}, 0, err
+	}
+	index, found := tournament.MatchIndex(roomID)
+	if !found {
+		return domain.Tournament{}, 0, ErrTournamentNotFound
+	}
+	if tournament.Matches[index].Status != domain.TournamentMatchPlaying {
+		return domain.Tournament{}, 0, errMatchDecided
+	}
+	return tournament, index, nil
+}
+
+// advance leva os vencedores às partidas seguintes, abre as salas das partidas prontas e
+// encerra o torneio quando a última partida termina. Deve ser chamado com mutex travado.
+//
+// Se uma sala não puder ser aberta, o progresso até ali é gravado mesmo assim, para que as
+// salas já abertas e o resultado registrado não se percam; a partida fica pendente, é aberta
+// na próxima vez que o torneio avançar, e o erro retornado é ErrMatchRoomUnavailable.
+func (service *TournamentService) advance(tournament domain.Tournament) (domain.Tournament, []domain.TournamentMatch, error) {
+	if tournament.Format == domain.TournamentSingleElimination {
+		fillBracket(tournament.Matches)
+	}
+
+	var opened []domain.TournamentMatch
+	var openErr error
+	for index, match := range tournament.Matches {
+		if !matchReady(tournament, match) {
+			continue
+		}
+		roomID, err := service.openRoom(tournament, match)
+		if err != nil {
+			openErr = ErrMatchRoomUnavailable
+			break
+		}
+		tournament.Matches[index].RoomID = roomID
+		tournament.Matches[index].Status = domain.TournamentMatchPlaying
+		opened = append(opened, tournament.Matches[index])
+	}
+
+	if winnerID, finished := tournamentWinner(tournament); finished {
+		tournament.Status = domain.TournamentStatusFinished
+		tournament.WinnerID = winnerID
+		tournament.FinishedAt = time.Now()
+	}
+	if err := service.tournamentRepo.Update(tournament.ID, tournament); err != nil {
+		return tournament, opened, err
+	}
+	return tournament, opened, openErr
+}
+
+// fillBracket coloca nas partidas da eliminação simples os vencedores das partidas que as alimentam.
+func fillBracket(matches []domain.TournamentMatch) {
+	positions := make(map[[2]int]int, len(matches))
+	for index, match := range matches {
+		positions[[2]int{match.Round, match.Slot}] = index
+	}
+	for index, match := range matches {
+		if match.Round == 1 || match.Status != domain.TournamentMatchPending {
+			continue
+		}
+		for side := range match.Players {
+			feeder := matches[positions[[2]int{match.Round - 1, 2*match.Slot + side}]]
+			if feeder.Decided() {
+				matches[index].Players[side] = feeder.WinnerID
+			}
+		}
+	}
+}
+
+// matchReady informa se a partida pode ganhar uma sala: está pendente, tem os dois jogadores
+// e, no todos contra todos, pertence à primeira rodada ainda não concluída.
+func matchReady(tournament domain.Tournament, match domain.TournamentMatch) bool {
+	if match.Status != domain.TournamentMatchPending || match.Players[0] == "" || match.Players[1] == "" {
+		return false
+	}
+	if tournament.Format != domain.TournamentRoundRobin {
+		return true
+	}
+	for _, other := range tournament.Matches {
+		if other.Round < match.Round && !other.Decided() {
+			return false
+		}
+	}
+	return true
+}
+
+// tournamentWinner informa se todas as partidas terminaram e quem venceu o torneio: o vencedor
+// da final ou, no todos contra todos, o líder isolado da classificação.
+func tournamentWinner(tournament domain.Tournament) (string, bool) {
+	for _, match := range tournament.Matches {
+		if !match.Decided() {
+			return "", false
+		}
+	}
+	if tournament.Format == domain.TournamentSingleElimination {
+		return tournament.Matches[len(tournament.Matches)-1].WinnerID, true
+	}
+	standings := tournament.Standings()
+	if len(standings) > 1 && standings[0].Points == standings[1].Points {
+		return "", true
+	}
+	return standings[0].UserID, true
+}
+
+// openRoom cria a sala pública da partida com os dois jogadores já sentados.
+func (service *TournamentService) openRoom(tournament domain.Tournament, match domain.TournamentMatch) (string, error) {
+	suffix := " " + match.ID
+	name := []rune(tournament.Name)
+	if len(name)+len(suffix) > MaxRoomNameLength {
+		name = name[:MaxRoomNameLength-len(suffix)]
+	}
+	roomID, err := service.roomService.CreateRoom(match.Players[0], RoomOptions{
+		Name:         strings.TrimSpace(string(name)) + suffix,
+		Ruleset:      tournament.RulesetID,
+		TournamentID: tournament.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := service.roomService.JoinRoom(roomID, match.Players[1], JoinOptions{}); err != nil {
+		service.roomService.LeaveRoom(roomID, match.Players[0]) // A sala sem o oponente não serve à partida
+		return "", err
+	}
+	return roomID, nil
+}
+
+// read lê o torneio, traduzindo a ausência em ErrTournamentNotFound. As listas são copiadas
+// para que as mudanças só cheguem ao repositório no Update.
+func (service *TournamentService) read(tournamentID string) (domain.Tournament, error) {
+	tournament, err := service.tournamentRepo.Read(tournamentID)
+	if err != nil {
+		return domain.Tournament{}, ErrTournamentNotFound
+	}
+	tournament.Players = append([]string(nil), tournament.Players...)
+	tournament.Matches = append([]domain.TournamentMatch(nil), tournament.Matches...)
+	return tournament, nil
+}
+
+// GetTournament retorna o torneio associado ao ID informado.
+//
+// Parâmetros:
+//   - tournamentID: identificador do torneio.
+//
+// Retorno:
+//   - domain.Tournament: torneio encontrado.
+//   - erro caso o torneio não exista.
+func (service *TournamentService) GetTournament(tournamentID string) (domain.Tournament, error) {
+	return service.read(tournamentID)
+}
+
+// ListTournaments lista os torneios, dos mais antigos para os mais novos.
+//
+// Retorno:
+//   - []domain.Tournament: torneios encontrados.
+//   - erro caso não seja possível listar os torneios.
+func (service *TournamentService) ListTournaments() ([]domain.Tournament, error) {
+	tournaments, err := service.tournamentRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tournaments, func(i, j int) bool {
+		return tournaments[i].CreatedAt.Before(tournaments[j].CreatedAt)
+	})
+	return tournaments, nil
+}
@@ -0,0 +1,255 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"strings"
+	"testing"
+)
+
+// failingRooms abre salas normalmente até esgotar as permitidas e recusa as seguintes.
+type failingRooms struct {
+	*RoomService
+	allowed int
+}
+
+func (rooms *failingRooms) CreateRoom(ownerID string, options RoomOptions) (string, error) {
+	if rooms.allowed == 0 {
+		return "", errors.New("falha ao criar a sala")
+	}
+	rooms.allowed--
+	return rooms.RoomService.CreateRoom(ownerID, options)
+}
+
+// newTestTournamentService cria um serviço de torneios com os usuários informados, sobre o
+// serviço de salas dado (nil cria um novo).
+func newTestTournamentService(t *testing.T, rooms RoomServiceInterface, userIDs ...string) *TournamentService {
+	t.Helper()
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	for _, userID := range userIDs {
+		userRepo.Create(userID, domain.User{ID: userID, Bot: domain.IsBotID(userID)})
+	}
+	if rooms == nil {
+		rooms = newTestRoomService(t)
+	}
+	return NewTournamentService(data.NewInMemoryRepository[domain.Tournament](), userRepo, newTestRulesets(t), rooms)
+}
+
+// registerAll inscreve os jogadores no torneio, falhando o teste em qualquer erro.
+func registerAll(t *testing.T, service *TournamentService, tournamentID string, userIDs ...string) (domain.Tournament, []domain.TournamentMatch) {
+	t.Helper()
+	var tournament domain.Tournament
+	var opened []domain.TournamentMatch
+	for _, userID := range userIDs {
+		var err error
+		tournament, opened, err = service.Register(tournamentID, userID)
+		if err != nil {
+			t.Fatalf("Register(%s): %v", userID, err)
+		}
+	}
+	return tournament, opened
+}
+
+func TestCreateTournament(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    TournamentOptions
+		wantErr    error
+		wantFormat string
+	}{
+		{name: "formato padrão", options: TournamentOptions{Size: 4}, wantFormat: domain.TournamentSingleElimination},
+		{name: "todos contra todos", options: TournamentOptions{Size: 3, Format: domain.TournamentRoundRobin, Ruleset: "rpsls"}, wantFormat: domain.TournamentRoundRobin},
+		{name: "formato desconhecido", options: TournamentOptions{Size: 4, Format: "swiss"}, wantErr: ErrTournamentFormat},
+		{name: "pequeno demais", options: TournamentOptions{Size: MinTournamentSize - 1}, wantErr: ErrTournamentSize},
+		{name: "grande demais", options: TournamentOptions{Size: MaxTournamentSize + 1}, wantErr: ErrTournamentSize},
+		{name: "regras desconhecidas", options: TournamentOptions{Size: 4, Ruleset: "xadrez"}, wantErr: ErrUnknownRuleset},
+		{name: "nome muito longo", options: TournamentOptions{Size: 4, Name: strings.Repeat("a", MaxRoomNameLength+1)}, wantErr: errors.New("nome do torneio muito longo")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newTestTournamentService(t, nil)
+			tournament, err := service.CreateTournament("owner", test.options)
+			if err != nil || test.wantErr != nil {
+				if err == nil || test.wantErr == nil || err.Error() != test.wantErr.Error() {
+					t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+				}
+				return
+			}
+			if tournament.Format != test.wantFormat || tournament.Status != domain.TournamentStatusRegistering || tournament.Name != "Torneio "+tournament.ID {
+				t.Errorf("torneio %q no formato %s e estado %s", tournament.Name, tournament.Format, tournament.Status)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name       string
+		registered []string
+		status     string
+		userID     string
+		wantErr    error
+	}{
+		{name: "inscrição", userID: "alice"},
+		{name: "bot", userID: "bot:easy", wantErr: ErrTournamentBotPlayers},
+		{name: "inscrição repetida", registered: []string{"alice"}, userID: "alice", wantErr: ErrAlreadyRegistered},
+		{name: "torneio lotado", registered: []string{"bob", "carol", "dave", "erin"}, userID: "alice", wantErr: ErrTournamentFull},
+		{name: "torneio em andamento", status: domain.TournamentStatusRunning, userID: "alice", wantErr: ErrTournamentClosed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newTestTournamentService(t, nil, "alice", "bob", "carol", "dave", "bot:easy")
+			tournament, _ := service.CreateTournament("owner", TournamentOptions{Size: 4})
+			tournament.Players = test.registered
+			if test.status != "" {
+				tournament.Status = test.status
+			}
+			service.tournamentRepo.Update(tournament.ID, tournament)
+
+			got, opened, err := service.Register(tournament.ID, test.userID)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err == nil && (!got.IsRegistered(test.userID) || len(opened) != 0) {
+				t.Errorf("inscritos %v, %d partidas abertas", got.Players, len(opened))
+			}
+		})
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	service := newTestTournamentService(t, nil, "alice", "bob", "carol")
+	tournament, _ := service.CreateTournament("owner", TournamentOptions{Size: 3})
+	registerAll(t, service, tournament.ID, "alice", "bob")
+
+	if _, err := service.Unregister(tournament.ID, "carol"); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("quem não está inscrito: erro = %v", err)
+	}
+	got, err := service.Unregister(tournament.ID, "alice")
+	if err != nil || !equalStrings(got.Players, []string{"bob"}) {
+		t.Fatalf("Unregister = %v, %v", got.Players, err)
+	}
+	registerAll(t, service, tournament.ID, "alice", "carol")
+	if _, err := service.Unregister(tournament.ID, "alice"); !errors.Is(err, ErrTournamentClosed) {
+		t.Errorf("depois do início: erro = %v", err)
+	}
+}
+
+func TestStartTournament(t *testing.T) {
+	tests := []struct {
+		name        string
+		registered  []string
+		userID      string
+		wantErr     error
+		wantMatches int
+	}{
+		{name: "três inscritos, um avança direto", registered: []string{"alice", "bob", "carol"}, userID: "owner", wantMatches: 1},
+		{name: "quem não criou o torneio", registered: []string{"alice", "bob"}, userID: "alice", wantErr: ErrNotTournamentOwner},
+		{name: "poucos inscritos", registered: []string{"alice"}, userID: "owner", wantErr: ErrTournamentTooFew},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rooms := newTestRoomService(t)
+			service := newTestTournamentService(t, rooms, "alice", "bob", "carol")
+			tournament, _ := service.CreateTournament("owner", TournamentOptions{Size: 8})
+			registerAll(t, service, tournament.ID, test.registered...)
+
+			got, opened, err := service.Start(tournament.ID, test.userID)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Status != domain.TournamentStatusRunning || len(opened) != test.wantMatches {
+				t.Fatalf("estado %s com %d partidas abertas, esperado %d", got.Status, len(opened), test.wantMatches)
+			}
+			room, err := rooms.GetRoom(opened[0].RoomID)
+			if err != nil || room.TournamentID != tournament.ID || room.Status != domain.RoomStatusReadyCheck {
+				t.Errorf("sala da partida = %+v, %v", room, err)
+			}
+		})
+	}
+}
+
+func TestSingleEliminationRuns(t *testing.T) {
+	service := newTestTournamentService(t, nil, "alice", "bob", "carol", "dave")
+	tournament, _ := service.CreateTournament("owner", TournamentOptions{Size: 4})
+	// A inscrição que lota o torneio abre as salas da primeira rodada.
+	tournament, opened := registerAll(t, service, tournament.ID, "alice", "bob", "carol", "dave")
+	if len(opened) != 2 {
+		t.Fatalf("%d partidas abertas na primeira rodada, esperado 2", len(opened))
+	}
+
+	// Na eliminação simples, o empate não decide a partida.
+	if tournament, _, _ = service.RecordResult(tournament.ID, opened[0].RoomID, ""); tournament.Matches[0].Status != domain.TournamentMatchPlaying {
+		t.Errorf("partida empatada com estado %s", tournament.Matches[0].Status)
+	}
+	if _, final, err := service.RecordResult(tournament.ID, opened[0].RoomID, "alice"); err != nil || len(final) != 0 {
+		t.Fatalf("primeira semifinal = %v, %v", final, err)
+	}
+	// Quem sai da sala perde a partida por W.O.
+	_, final, err := service.Forfeit(tournament.ID, opened[1].RoomID, "carol")
+	if err != nil || len(final) != 1 || final[0].Players != [2]string{"alice", "dave"} {
+		t.Fatalf("final aberta = %+v, %v", final, err)
+	}
+	if _, _, err := service.RecordResult(tournament.ID, opened[1].RoomID, "carol"); !errors.Is(err, errMatchDecided) {
+		t.Errorf("revanche após o W.O.: erro = %v", err)
+	}
+
+	tournament, _, err = service.RecordResult(tournament.ID, final[0].RoomID, "dave")
+	if err != nil {
+		t.Fatalf("final: %v", err)
+	}
+	if tournament.Status != domain.TournamentStatusFinished || tournament.WinnerID != "dave" || !tournament.Matches[1].Walkover {
+		t.Errorf("torneio %s com vencedor %q, W.O. %v", tournament.Status, tournament.WinnerID, tournament.Matches[1].Walkover)
+	}
+}
+
+func TestRoundRobinRuns(t *testing.T) {
+	service := newTestTournamentService(t, nil, "alice", "bob", "carol")
+	tournament, _ := service.CreateTournament("owner", TournamentOptions{Size: 3, Format: domain.TournamentRoundRobin})
+	tournament, opened := registerAll(t, service, tournament.ID, "alice", "bob", "carol")
+
+	// Cada rodada só abre quando a anterior termina; todos vencem uma vez e o torneio empata.
+	for round := 1; len(opened) > 0; round++ {
+		if len(opened) != 1 || opened[0].Round != round {
+			t.Fatalf("rodada %d abriu %+v", round, opened)
+		}
+		var err error
+		tournament, opened, err = service.RecordResult(tournament.ID, opened[0].RoomID, opened[0].Players[round%2])
+		if err != nil {
+			t.Fatalf("RecordResult: %v", err)
+		}
+	}
+	if tournament.Status != domain.TournamentStatusFinished || tournament.WinnerID != "" {
+		t.Errorf("torneio %s com vencedor %q", tournament.Status, tournament.WinnerID)
+	}
+}
+
+func TestAdvanceKeepsProgressWhenRoomFails(t *testing.T) {
+	rooms := &failingRooms{RoomService: newTestRoomService(t), allowed: 1}
+	service := newTestTournamentService(t, rooms, "alice", "bob", "carol", "dave")
+	tournament, _ := service.CreateTournament("owner", TournamentOptions{Size: 4})
+	registerAll(t, service, tournament.ID, "alice", "bob", "carol")
+
+	got, opened, err := service.Register(tournament.ID, "dave")
+	if !errors.Is(err, ErrMatchRoomUnavailable) {
+		t.Fatalf("erro = %v, esperado %v", err, ErrMatchRoomUnavailable)
+	}
+	if len(opened) != 1 || got.Status != domain.TournamentStatusRunning {
+		t.Fatalf("estado %s com %d partidas abertas", got.Status, len(opened))
+	}
+	// A sala aberta e a inscrição ficam gravadas; a partida sem sala espera o próximo avanço.
+	saved, _ := service.GetTournament(tournament.ID)
+	if !saved.IsRegistered("dave") || saved.Matches[0].RoomID != opened[0].RoomID || saved.Matches[1].Status != domain.TournamentMatchPending {
+		t.Fatalf("torneio gravado = %+v", saved.Matches)
+	}
+
+	rooms.allowed = 1
+	_, retried, err := service.RecordResult(tournament.ID, opened[0].RoomID, "alice")
+	if err != nil || len(retried) != 1 || retried[0].ID != saved.Matches[1].ID {
+		t.Errorf("partida reaberta = %+v, %v", retried, err)
+	}
+}
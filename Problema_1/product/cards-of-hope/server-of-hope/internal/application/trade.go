@@ -0,0 +1,323 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTradeTTL define a validade de uma proposta de troca quando nenhuma é informada.
+const DefaultTradeTTL = 15 * time.Minute
+
+// MaxTradeTTL define a validade máxima de uma proposta de troca.
+const MaxTradeTTL = 24 * time.Hour
+
+// Erros de troca exibidos diretamente aos usuários.
+var (
+	ErrTradeNotFound     = errors.New("Proposta de troca não encontrada")
+	ErrTradeClosed       = errors.New("A proposta de troca não está mais pendente")
+	ErrTradeExpired      = errors.New("A proposta de troca expirou")
+	ErrTradeEmpty        = errors.New("A troca precisa de ao menos uma carta")
+	ErrTradeTooLarge     = fmt.Errorf("Cada lado da troca pode ter no máximo %d cartas", domain.MaxTradeCards)
+	ErrTradeTTL          = errors.New("A validade da proposta deve ser de 1 minuto a 24 horas")
+	ErrTradeSelf         = errors.New("Não é possível trocar cartas consigo mesmo")
+	ErrTradeBot          = errors.New("Bots não trocam cartas")
+	ErrTradeUnknownUser  = errors.New("Usuário não encontrado")
+	ErrNotTradeRecipient = errors.New("Apenas quem recebeu a proposta pode aceitá-la ou recusá-la")
+	ErrNotTradeOwner     = errors.New("Apenas quem fez a proposta pode cancelá-la")
+	ErrTradeCardNotOwned = errors.New("A carta não está na coleção do dono")
+	ErrTradeFailed       = errors.New("A troca não pôde ser feita porque alguma carta saiu da coleção do dono")
+)
+
+// TradeServiceInterface descreve as operações das trocas de cartas entre usuários.
+//
+// Métodos:
+//   - Offer: propõe uma troca a outro usuário.
+//   - Accept: aceita uma proposta, trocando as cartas.
+//   - Decline: recusa uma proposta.
+//   - Cancel: cancela uma proposta feita.
+//   - ListTrades: lista as propostas pendentes de um usuário.
+type TradeServiceInterface interface {
+	// Offer propõe uma troca: cartas da coleção de quem propõe por cartas da coleção do
+	// destinatário. Um dos lados pode ficar vazio, como em um presente.
+	//
+	// Parâmetros:
+	//   - fromID: usuário que faz a proposta.
+	//   - toID: usuário que recebe a proposta.
+	//   - offered: IDs das cartas oferecidas, da coleção de fromID.
+	//   - requested: IDs das cartas pedidas, da coleção de toID.
+	//   - ttl: validade da proposta (zero usa DefaultTradeTTL).
+	//
+	// Retorno:
+	//   - domain.Trade: proposta registrada.
+	//   - erro caso a proposta seja inválida ou alguma carta não esteja na coleção do dono.
+	Offer(fromID, toID string, offered, requested []string, ttl time.Duration) (domain.Trade, error)
+
+	// Accept aceita uma proposta pendente e troca as cartas de uma só vez. Se alguma carta já
+	// saiu da coleção do dono, nenhuma muda de dono e a proposta termina como failed.
+	//
+	// Parâmetros:
+	//   - tradeID: identificador da proposta.
+	//   - userID: destinatário da proposta.
+	//
+	// Retorno:
+	//   - domain.Trade: proposta atualizada.
+	//   - map[string][]domain.OwnedCard: cartas recebidas por cada usuário, com os novos IDs.
+	//   - erro caso a proposta não possa ser aceita.
+	Accept(tradeID, userID string) (domain.Trade, map[string][]domain.OwnedCard, error)
+
+	// Decline recusa uma proposta pendente.
+	//
+	// Parâmetros:
+	//   - tradeID: identificador da proposta.
+	//   - userID: destinatário da proposta.
+	//
+	// Retorno:
+	//   - domain.Trade: proposta atualizada.
+	//   - erro caso a proposta não possa ser recusada.
+	Decline(tradeID, userID string) (domain.Trade, error)
+
+	// Cancel cancela uma proposta pendente.
+	//
+	// Parâmetros:
+	//   - tradeID: identificador da proposta.
+	//   - userID: usuário que fez a proposta.
+	//
+	// Retorno:
+	//   - domain.Trade: proposta atualizada.
+	//   - erro caso a proposta não possa ser cancelada.
+	Cancel(tradeID, userID string) (domain.Trade, error)
+
+	// ListTrades lista as propostas pendentes feitas ou recebidas por um usuário, das mais
+	// recentes para as mais antigas. As propostas vencidas são encerradas como expired.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - []domain.Trade: propostas pendentes.
+	//   - erro caso não seja possível listar as propostas.
+	ListTrades(userID string) ([]domain.Trade, error)
+}
+
+// TradeService implementa as trocas de cartas. As cartas não ficam reservadas enquanto a
+// proposta está pendente: o aceite confere a posse e troca as cartas pelo DeckService, que
+// serializa todas as mudanças nas coleções.
+//
+// Campos:
+//   - tradeRepo: repositório das propostas.
+//   - userRepo: repositório dos usuários.
+//   - deckService: serviço das coleções, que faz a troca.
+//   - mutex: serializa as mudanças de estado das propostas.
+type TradeService struct {
+	tradeRepo   data.RepositoryInterface[domain.Trade]
+	userRepo    data.RepositoryInterface[domain.User]
+	deckService DeckServiceInterface
+	mutex       sync.Mutex
+}
+
+// NewTradeService cria uma nova instância de TradeService.
+//
+// Parâmetros:
+//   - tradeRepo: repositório das propostas de troca.
+//   - userRepo: repositório dos usuários.
+//   - deckService: serviço das coleções de cartas.
+//
+// Retorno:
+//   - ponteiro para TradeService.
+func NewTradeService(
+	tradeRepo data.RepositoryInterface[domain.Trade],
+	userRepo data.RepositoryInterface[domain.User],
+	deckService DeckServiceInterface,
+) *TradeService {
+	return &TradeService{
+		tradeRepo:   tradeRepo,
+		userRepo:    userRepo,
+		deckService: deckService,
+	}
+}
+
+// Offer propõe uma troca a outro usuário, guardando as cartas como estão nas coleções.
+func (service *TradeService) Offer(fromID, toID string, offered, requested []string, ttl time.Duration) (domain.Trade, error) {
+	if ttl == 0 {
+		ttl = DefaultTradeTTL
+	}
+	switch {
+	case fromID == toID:
+		return domain.Trade{}, ErrTradeSelf
+	case len(offered) == 0 && len(requested) == 0:
+		return domain.Trade{}, ErrTradeEmpty
+	case len(offered) > domain.MaxTradeCards || len(requested) > domain.MaxTradeCards:
+		return domain.Trade{}, ErrTradeTooLarge
+	case ttl < time.Minute || ttl > MaxTradeTTL:
+		return domain.Trade{}, ErrTradeTTL
+	}
+
+	offeredCards, err := service.ownedCards(fromID, offered)
+	if err != nil {
+		return domain.Trade{}, err
+	}
+	requestedCards, err := service.ownedCards(toID, requested)
+	if err != nil {
+		return domain.Trade{}, err
+	}
+
+	now := time.Now().UTC()
+	trade := domain.Trade{
+		ID:        utils.Count(),
+		FromID:    fromID,
+		ToID:      toID,
+		Offered:   offeredCards,
+		Requested: requestedCards,
+		Status:    domain.TradePending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	return trade, service.tradeRepo.Create(trade.ID, trade)
+}
+
+// ownedCards resolve as cartas da coleção de um usuário que pode trocar cartas.
+func (service *TradeService) ownedCards(userID string, cardIDs []string) ([]domain.OwnedCard, error) {
+	user, err := service.userRepo.Read(userID)
+	if err != nil {
+		return nil, ErrTradeUnknownUser
+	}
+	if user.Bot {
+		return nil, ErrTradeBot
+	}
+	collection, err := service.deckService.Collection(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Collection = collection
+	cards := make([]domain.OwnedCard, 0, len(cardIDs))
+	seen := make(map[string]bool, len(cardIDs))
+	for _, cardID := range cardIDs {
+		card, owned := user.FindCard(cardID)
+		if !owned || seen[cardID] {
+			return nil, fmt.Errorf("%w: carta %s de %s", ErrTradeCardNotOwned, cardID, userID)
+		}
+		seen[cardID] = true
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// Accept aceita uma proposta pendente e troca as cartas de uma só vez.
+func (service *TradeService) Accept(tradeID, userID string) (domain.Trade, map[string][]domain.OwnedCard, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	trade, err := service.pending(tradeID)
+	if err != nil {
+		return trade, nil, err
+	}
+	if trade.ToID != userID {
+		return trade, nil, ErrNotTradeRecipient
+	}
+
+	receivedByFrom, receivedByTo, err := service.deckService.Swap(trade.FromID, domain.CardIDs(trade.Offered), trade.ToID, domain.CardIDs(trade.Requested))
+	if err != nil {
+		trade, resolveErr := service.resolve(trade, domain.TradeFailed)
+		if resolveErr != nil {
+			return trade, nil, resolveErr
+		}
+		return trade, nil, fmt.Errorf("%w (%v)", ErrTradeFailed, err)
+	}
+	trade, err = service.resolve(trade, domain.TradeAccepted)
+	received := map[string][]domain.OwnedCard{
+		trade.FromID: receivedByFrom,
+		trade.ToID:   receivedByTo,
+	}
+	return trade, received, err
+}
+
+// Decline recusa uma proposta pendente.
+func (service *TradeService) Decline(tradeID, userID string) (domain.Trade, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	trade, err := service.pending(tradeID)
+	if err != nil {
+		return trade, err
+	}
+	if trade.ToID != userID {
+		return trade, ErrNotTradeRecipient
+	}
+	return service.resolve(trade, domain.TradeDeclined)
+}
+
+// Cancel cancela uma proposta pendente.
+func (service *TradeService) Cancel(tradeID, userID string) (domain.Trade, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	trade, err := service.pending(tradeID)
+	if err != nil {
+		return trade, err
+	}
+	if trade.FromID != userID {
+		return trade, ErrNotTradeOwner
+	}
+	return service.resolve(trade, domain.TradeCancelled)
+}
+
+// ListTrades lista as propostas pendentes feitas ou recebidas por um usuário.
+func (service *TradeService) ListTrades(userID string) ([]domain.Trade, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	trades, err := service.tradeRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var pending []domain.Trade
+	for _, trade := range trades {
+		if !trade.Involves(userID) || trade.Status != domain.TradePending {
+			continue
+		}
+		if trade.Expired() {
+			if _, err := service.resolve(trade, domain.TradeExpired); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		pending = append(pending, trade)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.After(pending[j].CreatedAt)
+	})
+	return pending, nil
+}
+
+// pending lê uma proposta que ainda aguarda resposta, encerrando-a se já venceu. Deve ser
+// chamado com mutex travado.
+func (service *TradeService) pending(tradeID string) (domain.Trade, error) {
+	trade, err := service.tradeRepo.Read(tradeID)
+	if err != nil {
+		return domain.Trade{}, ErrTradeNotFound
+	}
+	if trade.Expired() {
+		trade, err = service.resolve(trade, domain.TradeExpired)
+		if err != nil {
+			return trade, err
+		}
+		return trade, ErrTradeExpired
+	}
+	if trade.Status != domain.TradePending {
+		return trade, ErrTradeClosed
+	}
+	return trade, nil
+}
+
+// resolve encerra a proposta com o estado informado. Deve ser chamado com mutex travado.
+func (service *TradeService) resolve(trade domain.Trade, status string) (domain.Trade, error) {
+	trade.Status = status
+	trade.ResolvedAt = time.Now().UTC()
+	return trade, service.tradeRepo.Update(trade.ID, trade)
+}
@@ -0,0 +1,244 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sync"
+	"testing"
+)
+
+// errWriteFailed é o erro devolvido pelas escritas recusadas de failingRepository.
+var errWriteFailed = errors.New("falha de escrita")
+
+// failingRepository é um repositório em memória que recusa as atualizações escolhidas pelo teste.
+type failingRepository[T any] struct {
+	*data.InMemoryRepository[T]
+	failUpdate func(id string) bool
+}
+
+func newFailingRepository[T any]() *failingRepository[T] {
+	return &failingRepository[T]{
+		InMemoryRepository: data.NewInMemoryRepository[T](),
+		failUpdate:         func(string) bool { return false },
+	}
+}
+
+func (r *failingRepository[T]) Update(id string, item T) error {
+	if r.failUpdate(id) {
+		return errWriteFailed
+	}
+	return r.InMemoryRepository.Update(id, item)
+}
+
+// collector cria um usuário cuja coleção já tem as cartas informadas, com IDs de 1 em diante.
+func collector(id string, cards ...domain.Card) domain.User {
+	user := domain.User{ID: id, Collection: []domain.OwnedCard{}, Decks: map[string][]string{}}
+	user.AddCards(cards...)
+	return user
+}
+
+// collectionTypes lista os tipos das cartas da coleção de um usuário do repositório.
+func collectionTypes(t *testing.T, userRepo data.RepositoryInterface[domain.User], userID string) []string {
+	t.Helper()
+	user, err := userRepo.Read(userID)
+	if err != nil {
+		t.Fatalf("ler %s: %v", userID, err)
+	}
+	types := make([]string, 0, len(user.Collection))
+	for _, card := range user.Collection {
+		types = append(types, card.Type)
+	}
+	return types
+}
+
+// checkHoldings confere o saldo de moedas e o tamanho da coleção de cada usuário informado.
+func checkHoldings(t *testing.T, wallet *WalletService, userRepo data.RepositoryInterface[domain.User], wantBalances, wantCards map[string]int) {
+	t.Helper()
+	for userID, want := range wantBalances {
+		balance, _, err := wallet.Balance(userID)
+		if err != nil {
+			t.Fatalf("Balance(%s): %v", userID, err)
+		}
+		if balance != want {
+			t.Errorf("saldo de %s = %d, esperado %d", userID, balance, want)
+		}
+	}
+	for userID, want := range wantCards {
+		user, err := userRepo.Read(userID)
+		if err != nil {
+			t.Fatalf("ler %s: %v", userID, err)
+		}
+		if len(user.Collection) != want {
+			t.Errorf("%s tem %d cartas, esperado %d", userID, len(user.Collection), want)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if a[index] != b[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTradeAccept(t *testing.T) {
+	rock := domain.Card{Type: "rock", Stars: 1}
+	paper := domain.Card{Type: "paper", Stars: 1}
+	scissors := domain.Card{Type: "scissors", Stars: 1}
+
+	tests := []struct {
+		name       string
+		offered    []string
+		requested  []string
+		before     func(userRepo *failingRepository[domain.User])
+		accepter   string
+		wantErr    error
+		wantStatus string
+		wantAlice  []string
+		wantBob    []string
+	}{
+		{
+			name:       "troca aceita",
+			offered:    []string{"1"},
+			requested:  []string{"2"},
+			accepter:   "bob",
+			wantStatus: domain.TradeAccepted,
+			wantAlice:  []string{"paper", "scissors"},
+			wantBob:    []string{"paper", "rock"},
+		},
+		{
+			name:       "presente sem contrapartida",
+			offered:    []string{"1", "2"},
+			accepter:   "bob",
+			wantStatus: domain.TradeAccepted,
+			wantAlice:  []string{},
+			wantBob:    []string{"paper", "scissors", "rock", "paper"},
+		},
+		{
+			name:       "apenas o destinatário aceita",
+			offered:    []string{"1"},
+			accepter:   "alice",
+			wantErr:    ErrNotTradeRecipient,
+			wantStatus: domain.TradePending,
+			wantAlice:  []string{"rock", "paper"},
+			wantBob:    []string{"paper", "scissors"},
+		},
+		{
+			name:      "carta oferecida saiu da coleção",
+			offered:   []string{"1"},
+			requested: []string{"2"},
+			before: func(userRepo *failingRepository[domain.User]) {
+				alice, _ := userRepo.Read("alice")
+				alice.TakeCards([]string{"1"})
+				userRepo.Update("alice", alice)
+			},
+			accepter:   "bob",
+			wantErr:    ErrTradeFailed,
+			wantStatus: domain.TradeFailed,
+			wantAlice:  []string{"paper"},
+			wantBob:    []string{"paper", "scissors"},
+		},
+		{
+			name:      "carta pedida saiu da coleção",
+			offered:   []string{"1"},
+			requested: []string{"2"},
+			before: func(userRepo *failingRepository[domain.User]) {
+				bob, _ := userRepo.Read("bob")
+				bob.TakeCards([]string{"2"})
+				userRepo.Update("bob", bob)
+			},
+			accepter:   "bob",
+			wantErr:    ErrTradeFailed,
+			wantStatus: domain.TradeFailed,
+			wantAlice:  []string{"rock", "paper"},
+			wantBob:    []string{"paper"},
+		},
+		{
+			name:      "falha ao gravar a segunda coleção",
+			offered:   []string{"1"},
+			requested: []string{"2"},
+			before: func(userRepo *failingRepository[domain.User]) {
+				userRepo.failUpdate = func(id string) bool { return id == "bob" }
+			},
+			accepter:   "bob",
+			wantErr:    ErrTradeFailed,
+			wantStatus: domain.TradeFailed,
+			wantAlice:  []string{"rock", "paper"},
+			wantBob:    []string{"paper", "scissors"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userRepo := newFailingRepository[domain.User]()
+			userRepo.Create("alice", collector("alice", rock, paper))
+			userRepo.Create("bob", collector("bob", paper, scissors))
+			tradeRepo := data.NewInMemoryRepository[domain.Trade]()
+			deckService := NewDeckService(userRepo, data.NewInMemoryRepository[domain.Room](), data.NewInMemoryRepository[domain.Ruleset](), &sync.Mutex{})
+			service := NewTradeService(tradeRepo, userRepo, deckService)
+
+			trade, err := service.Offer("alice", "bob", test.offered, test.requested, 0)
+			if err != nil {
+				t.Fatalf("Offer: %v", err)
+			}
+			if test.before != nil {
+				test.before(userRepo)
+			}
+			_, _, err = service.Accept(trade.ID, test.accepter)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			userRepo.failUpdate = func(string) bool { return false }
+
+			stored, err := tradeRepo.Read(trade.ID)
+			if err != nil {
+				t.Fatalf("ler proposta: %v", err)
+			}
+			if stored.Status != test.wantStatus {
+				t.Errorf("estado = %s, esperado %s", stored.Status, test.wantStatus)
+			}
+			if got := collectionTypes(t, userRepo, "alice"); !equalStrings(got, test.wantAlice) {
+				t.Errorf("coleção de alice = %v, esperado %v", got, test.wantAlice)
+			}
+			if got := collectionTypes(t, userRepo, "bob"); !equalStrings(got, test.wantBob) {
+				t.Errorf("coleção de bob = %v, esperado %v", got, test.wantBob)
+			}
+		})
+	}
+}
+
+func TestTradeOfferValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  string
+		offered   []string
+		requested []string
+		wantErr   error
+	}{
+		{name: "consigo mesmo", from: "alice", to: "alice", offered: []string{"1"}, wantErr: ErrTradeSelf},
+		{name: "sem cartas", from: "alice", to: "bob", wantErr: ErrTradeEmpty},
+		{name: "carta de outro dono", from: "alice", to: "bob", offered: []string{"9"}, wantErr: ErrTradeCardNotOwned},
+		{name: "carta repetida", from: "alice", to: "bob", offered: []string{"1", "1"}, wantErr: ErrTradeCardNotOwned},
+		{name: "usuário desconhecido", from: "alice", to: "carol", offered: []string{"1"}, wantErr: ErrTradeUnknownUser},
+		{name: "bot", from: "alice", to: "bot", offered: []string{"1"}, wantErr: ErrTradeBot},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userRepo := data.NewInMemoryRepository[domain.User]()
+			userRepo.Create("alice", collector("alice", domain.Card{Type: "rock", Stars: 1}))
+			userRepo.Create("bob", collector("bob", domain.Card{Type: "paper", Stars: 1}))
+			userRepo.Create("bot", domain.User{ID: "bot", Bot: true})
+			deckService := NewDeckService(userRepo, data.NewInMemoryRepository[domain.Room](), data.NewInMemoryRepository[domain.Ruleset](), &sync.Mutex{})
+			service := NewTradeService(data.NewInMemoryRepository[domain.Trade](), userRepo, deckService)
+
+			if _, err := service.Offer(test.from, test.to, test.offered, test.requested, 0); !errors.Is(err, test.wantErr) {
+				t.Errorf("erro = %v, esperado %v", err, test.wantErr)
+			}
+		})
+	}
+}
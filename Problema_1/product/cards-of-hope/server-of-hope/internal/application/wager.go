@@ -0,0 +1,446 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxWagerCoins define quantas moedas cada jogador pode apostar em uma partida.
+const MaxWagerCoins = 1000
+
+// WagerSweepInterval define de quanto em quanto tempo os jogadores desconectados das partidas
+// com aposta são conferidos.
+const WagerSweepInterval = 5 * time.Second
+
+// WagerReconnectWindow define quanto tempo um jogador desconectado tem para voltar a uma partida
+// com aposta antes de perdê-la.
+const WagerReconnectWindow = 2 * time.Minute
+
+// Erros das apostas exibidos diretamente aos usuários.
+var (
+	ErrWagerCoins     = fmt.Errorf("A aposta em moedas deve ser de 0 a %d", MaxWagerCoins)
+	ErrWagerCards     = fmt.Errorf("A aposta em cartas deve ser de 0 a %d cartas", domain.MaxWagerCards)
+	ErrWagerBot       = errors.New("Bots não jogam partidas com aposta")
+	ErrWagerStake     = errors.New("Escolha as cartas da sua aposta")
+	ErrWagerCardOwner = errors.New("A carta não está na sua coleção")
+	ErrWagerNoCards   = errors.New("A sala não aposta cartas")
+	ErrWagerHold      = errors.New("Não foi possível reter as apostas")
+	ErrWagerRematch   = errors.New("Salas com aposta de cartas não têm revanche: fiquem prontos de novo com as cartas da aposta")
+	ErrWagerNotFound  = errors.New("Aposta não encontrada")
+	ErrWagerSettled   = errors.New("A aposta já foi paga ou devolvida")
+	ErrWagerKick      = errors.New("Não é possível expulsar um jogador durante uma partida com aposta")
+)
+
+// WagerServiceInterface descreve as operações das apostas das partidas.
+//
+// Métodos:
+//   - ChooseStake: escolhe as cartas apostadas na próxima partida da sala.
+//   - Hold: retém as apostas dos jogadores no início de uma partida.
+//   - Settle: paga as apostas ao vencedor ou as devolve.
+//   - Get: recupera uma aposta.
+//   - Absentees: aponta os jogadores desconectados há muito tempo das partidas com aposta.
+//   - Reconcile: resolve as apostas retidas de partidas que não estão mais em andamento.
+type WagerServiceInterface interface {
+	// ChooseStake escolhe as cartas da coleção que o jogador aposta na próxima partida da sala.
+	//
+	// Parâmetros:
+	//   - roomID: identificador da sala.
+	//   - userID: jogador da sala.
+	//   - cardIDs: IDs das cartas da coleção, tantas quanto a aposta da sala pede.
+	//
+	// Retorno:
+	//   - erro caso a sala não aposte cartas, a quantidade não confira ou alguma carta não esteja
+	//     na coleção.
+	ChooseStake(roomID, userID string, cardIDs []string) error
+
+	// Hold retém as apostas de todos os jogadores da sala: as moedas são debitadas e as cartas
+	// escolhidas saem das coleções. Ou todas as apostas são retidas, ou nenhuma.
+	//
+	// Parâmetros:
+	//   - room: sala que vai começar a partida.
+	//   - match: número da partida na série da sala.
+	//
+	// Retorno:
+	//   - domain.Wager: aposta retida.
+	//   - erro caso algum jogador não tenha as moedas ou as cartas da aposta.
+	Hold(room domain.Room, match int) (domain.Wager, error)
+
+	// Settle encerra uma aposta retida: o vencedor recebe todas as moedas e cartas e, sem
+	// vencedor, cada jogador recebe a sua aposta de volta.
+	//
+	// Parâmetros:
+	//   - wagerID: identificador da aposta.
+	//   - winnerID: vencedor da partida (vazio no empate ou na partida interrompida).
+	//
+	// Retorno:
+	//   - domain.Wager: aposta encerrada.
+	//   - map[string][]domain.OwnedCard: cartas recebidas por cada jogador, com os novos IDs.
+	//   - erro caso a aposta não exista ou já tenha sido encerrada.
+	Settle(wagerID, winnerID string) (domain.Wager, map[string][]domain.OwnedCard, error)
+
+	// Get recupera uma aposta.
+	//
+	// Parâmetros:
+	//   - wagerID: identificador da aposta.
+	//
+	// Retorno:
+	//   - domain.Wager: aposta encontrada.
+	//   - erro caso a aposta não exista.
+	Get(wagerID string) (domain.Wager, error)
+
+	// Absentees aponta, nas partidas com aposta em andamento, os jogadores desconectados há mais
+	// de WagerReconnectWindow. As partidas por correspondência são ignoradas, porque nelas os
+	// jogadores podem ficar desconectados.
+	//
+	// Parâmetros:
+	//   - online: informa se um usuário está conectado.
+	//
+	// Retorno:
+	//   - map[string][]string: jogadores ausentes de cada sala, pelo ID da sala.
+	//   - erro caso as apostas não possam ser listadas.
+	Absentees(online func(userID string) bool) (map[string][]string, error)
+
+	// Reconcile resolve as apostas retidas cujas partidas não estão mais em andamento, como
+	// depois de uma queda do servidor: as partidas encerradas pagam o vencedor e as demais
+	// devolvem as apostas.
+	//
+	// Retorno:
+	//   - []domain.Wager: apostas resolvidas.
+	//   - erro agregando as apostas que não puderam ser resolvidas.
+	Reconcile() ([]domain.Wager, error)
+}
+
+// WagerService implementa as apostas das partidas. As moedas passam pelo WalletService e as
+// cartas pelo DeckService, de modo que ficam retidas na aposta até o fim da partida.
+//
+// Campos:
+//   - wagerRepo: repositório das apostas.
+//   - userRepo: repositório dos usuários.
+//   - roomRepo: repositório das salas.
+//   - gameRepo: repositório das partidas.
+//   - deckService: serviço das coleções, de onde saem e para onde vão as cartas.
+//   - walletService: serviço das carteiras, que movimenta as moedas.
+//   - absentSince: momento em que cada jogador de uma aposta retida foi visto desconectado.
+//   - mutex: serializa as mudanças nas apostas.
+//   - roomMutex: serializa as mudanças nas salas, junto com RoomService.
+type WagerService struct {
+	wagerRepo     data.RepositoryInterface[domain.Wager]
+	userRepo      data.RepositoryInterface[domain.User]
+	roomRepo      data.RepositoryInterface[domain.Room]
+	gameRepo      data.RepositoryInterface[domain.Game]
+	deckService   DeckServiceInterface
+	walletService WalletServiceInterface
+	absentSince   *utils.Map[string, time.Time]
+	mutex         sync.Mutex
+	roomMutex     *sync.Mutex
+}
+
+// NewWagerService cria uma nova instância de WagerService.
+//
+// Parâmetros:
+//   - wagerRepo: repositório das apostas.
+//   - userRepo: repositório dos usuários.
+//   - roomRepo: repositório das salas.
+//   - gameRepo: repositório das partidas.
+//   - deckService: serviço das coleções de cartas.
+//   - walletService: serviço das carteiras de moedas.
+//   - roomMutex: mutex compartilhado por todos os serviços que alteram as salas.
+//
+// Retorno:
+//   - ponteiro para WagerService.
+func NewWagerService(
+	wagerRepo data.RepositoryInterface[domain.Wager],
+	userRepo data.RepositoryInterface[domain.User],
+	roomRepo data.RepositoryInterface[domain.Room],
+	gameRepo data.RepositoryInterface[domain.Game],
+	deckService DeckServiceInterface,
+	walletService WalletServiceInterface,
+	roomMutex *sync.Mutex,
+) *WagerService {
+	return &WagerService{
+		wagerRepo:     wagerRepo,
+		userRepo:      userRepo,
+		roomRepo:      roomRepo,
+		gameRepo:      gameRepo,
+		deckService:   deckService,
+		walletService: walletService,
+		absentSince:   utils.NewMap[string, time.Time](),
+		roomMutex:     roomMutex,
+	}
+}
+
+// ChooseStake escolhe as cartas apostadas pelo jogador na próxima partida da sala.
+func (service *WagerService) ChooseStake(roomID, userID string, cardIDs []string) error {
+	service.roomMutex.Lock()
+	defer service.roomMutex.Unlock()
+
+	room, err := service.roomRepo.Read(roomID)
+	if err != nil {
+		return err
+	}
+	if !room.UserIDs.Contains(userID) {
+		return errors.New("apenas jogadores da sala podem apostar")
+	}
+	if room.WagerCards == 0 {
+		return ErrWagerNoCards
+	}
+	if len(cardIDs) != room.WagerCards {
+		return fmt.Errorf("%w: a sala aposta %d cartas", ErrWagerStake, room.WagerCards)
+	}
+	user, err := service.userRepo.Read(userID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(cardIDs))
+	for _, cardID := range cardIDs {
+		if _, owned := user.FindCard(cardID); !owned || seen[cardID] {
+			return fmt.Errorf("%w: %s", ErrWagerCardOwner, cardID)
+		}
+		seen[cardID] = true
+	}
+	room.Stakes.Set(userID, append([]string(nil), cardIDs...))
+	return service.roomRepo.Update(roomID, room)
+}
+
+// Hold retém as apostas dos jogadores da sala, desfazendo as já retidas se alguma falhar. As
+// cartas escolhidas são consumidas: a próxima partida pede uma nova escolha.
+func (service *WagerService) Hold(room domain.Room, match int) (domain.Wager, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	players := room.UserIDs.Items()
+	sort.Strings(players)
+	wager := domain.Wager{
+		ID:        utils.Count(),
+		RoomID:    room.ID,
+		Match:     match,
+		Stakes:    make(map[string]domain.Stake, len(players)),
+		Status:    domain.WagerHeld,
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, playerID := range players {
+		stake, err := service.take(room, wager.ID, playerID)
+		if err != nil {
+			service.giveBack(wager)
+			return domain.Wager{}, fmt.Errorf("%w de %s: %v", ErrWagerHold, playerID, err)
+		}
+		wager.Stakes[playerID] = stake
+	}
+	if err := service.wagerRepo.Create(wager.ID, wager); err != nil {
+		service.giveBack(wager)
+		return domain.Wager{}, err
+	}
+	return wager, nil
+}
+
+// take retém a aposta de um jogador: as moedas da sala e as cartas que ele escolheu.
+func (service *WagerService) take(room domain.Room, wagerID, playerID string) (domain.Stake, error) {
+	user, err := service.userRepo.Read(playerID)
+	if err != nil {
+		return domain.Stake{}, err
+	}
+	if user.Bot {
+		return domain.Stake{}, ErrWagerBot
+	}
+	var cardIDs []string
+	if room.WagerCards > 0 {
+		chosen, ok := room.Stakes.Get(playerID)
+		if !ok || len(chosen) != room.WagerCards {
+			return domain.Stake{}, ErrWagerStake
+		}
+		cardIDs = chosen
+	}
+
+	stake := domain.Stake{Coins: room.WagerCoins}
+	if stake.Coins > 0 {
+		if _, err := service.walletService.Debit(playerID, stake.Coins, domain.LedgerWager, wagerID); err != nil {
+			return domain.Stake{}, err
+		}
+	}
+	if len(cardIDs) > 0 {
+		taken, err := service.deckService.TakeCards(playerID, cardIDs)
+		if err != nil {
+			if stake.Coins > 0 {
+				service.walletService.Credit(playerID, stake.Coins, domain.LedgerWagerBack, wagerID)
+			}
+			return domain.Stake{}, err
+		}
+		stake.Cards = plainCards(taken)
+	}
+	return stake, nil
+}
+
+// giveBack devolve a cada jogador a sua aposta. Deve ser chamado com mutex travado.
+func (service *WagerService) giveBack(wager domain.Wager) (map[string][]domain.OwnedCard, error) {
+	received := make(map[string][]domain.OwnedCard, len(wager.Stakes))
+	var problems []error
+	for playerID, stake := range wager.Stakes {
+		if stake.Coins > 0 {
+			if _, err := service.walletService.Credit(playerID, stake.Coins, domain.LedgerWagerBack, wager.ID); err != nil {
+				problems = append(problems, err)
+			}
+		}
+		if len(stake.Cards) > 0 {
+			cards, err := service.deckService.AddCards(playerID, stake.Cards...)
+			if err != nil {
+				problems = append(problems, err)
+			}
+			received[playerID] = cards
+		}
+	}
+	return received, errors.Join(problems...)
+}
+
+// Settle paga as apostas ao vencedor ou, sem vencedor, devolve a aposta de cada jogador.
+func (service *WagerService) Settle(wagerID, winnerID string) (domain.Wager, map[string][]domain.OwnedCard, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	wager, err := service.wagerRepo.Read(wagerID)
+	if err != nil {
+		return domain.Wager{}, nil, ErrWagerNotFound
+	}
+	if wager.Status != domain.WagerHeld {
+		return wager, nil, ErrWagerSettled
+	}
+	return service.settle(wager, winnerID)
+}
+
+// settle encerra uma aposta retida. Deve ser chamado com mutex travado.
+func (service *WagerService) settle(wager domain.Wager, winnerID string) (domain.Wager, map[string][]domain.OwnedCard, error) {
+	for playerID := range wager.Stakes {
+		service.absentSince.Delete(absenceKey(wager.ID, playerID))
+	}
+
+	var received map[string][]domain.OwnedCard
+	var err error
+	if !wager.Involves(winnerID) {
+		wager.Status = domain.WagerRefunded
+		received, err = service.giveBack(wager)
+	} else {
+		wager.Status = domain.WagerPaid
+		wager.WinnerID = winnerID
+		received, err = service.pay(wager, winnerID)
+	}
+	wager.SettledAt = time.Now().UTC()
+	if updateErr := service.wagerRepo.Update(wager.ID, wager); updateErr != nil {
+		return wager, received, errors.Join(err, updateErr)
+	}
+	return wager, received, err
+}
+
+// pay entrega ao vencedor todas as moedas e cartas apostadas. Deve ser chamado com mutex travado.
+func (service *WagerService) pay(wager domain.Wager, winnerID string) (map[string][]domain.OwnedCard, error) {
+	coins, cards := wager.Pot()
+	received := make(map[string][]domain.OwnedCard, 1)
+	var problems []error
+	if coins > 0 {
+		if _, err := service.walletService.Credit(winnerID, coins, domain.LedgerWagerWin, wager.ID); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if len(cards) > 0 {
+		added, err := service.deckService.AddCards(winnerID, cards...)
+		if err != nil {
+			problems = append(problems, err)
+		}
+		received[winnerID] = added
+	}
+	return received, errors.Join(problems...)
+}
+
+// Get recupera uma aposta.
+func (service *WagerService) Get(wagerID string) (domain.Wager, error) {
+	wager, err := service.wagerRepo.Read(wagerID)
+	if err != nil {
+		return domain.Wager{}, ErrWagerNotFound
+	}
+	return wager, nil
+}
+
+// Absentees aponta os jogadores desconectados há mais de WagerReconnectWindow das partidas com
+// aposta em andamento, lembrando desde quando cada um foi visto desconectado.
+func (service *WagerService) Absentees(online func(userID string) bool) (map[string][]string, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	wagers, err := service.wagerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	absentees := make(map[string][]string)
+	for _, wager := range wagers {
+		if wager.Status != domain.WagerHeld {
+			continue
+		}
+		room, err := service.roomRepo.Read(wager.RoomID)
+		if err != nil || room.Async || room.Status != domain.RoomStatusPlaying || room.WagerID != wager.ID {
+			continue
+		}
+		for playerID := range wager.Stakes {
+			key := absenceKey(wager.ID, playerID)
+			if online(playerID) {
+				service.absentSince.Delete(key)
+				continue
+			}
+			since, seen := service.absentSince.Get(key)
+			if !seen {
+				service.absentSince.Set(key, now)
+				continue
+			}
+			if now.Sub(since) >= WagerReconnectWindow {
+				absentees[room.ID] = append(absentees[room.ID], playerID)
+			}
+		}
+		sort.Strings(absentees[room.ID])
+	}
+	return absentees, nil
+}
+
+// Reconcile resolve as apostas retidas de partidas que não estão mais em andamento.
+func (service *WagerService) Reconcile() ([]domain.Wager, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	wagers, err := service.wagerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var resolved []domain.Wager
+	var problems []error
+	for _, wager := range wagers {
+		if wager.Status != domain.WagerHeld {
+			continue
+		}
+		room, roomErr := service.roomRepo.Read(wager.RoomID)
+		if roomErr == nil && room.WagerID == wager.ID && room.Status == domain.RoomStatusPlaying {
+			continue
+		}
+
+		winnerID := ""
+		if roomErr == nil && room.WagerID == wager.ID && room.Status == domain.RoomStatusFinished {
+			if game, err := service.gameRepo.Read(room.ID); err == nil && game.Number == wager.Match {
+				winnerID = game.WinnerID
+			}
+		}
+		settled, _, err := service.settle(wager, winnerID)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("aposta %s: %w", wager.ID, err))
+			continue
+		}
+		resolved = append(resolved, settled)
+	}
+	return resolved, errors.Join(problems...)
+}
+
+// absenceKey identifica a ausência de um jogador em uma aposta.
+func absenceKey(wagerID, playerID string) string {
+	return wagerID + "/" + playerID
+}
@@ -0,0 +1,315 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sync"
+	"testing"
+	"time"
+)
+
+// wagerFixture reúne as apostas de uma sala de alice e bob, cada um com duas cartas.
+type wagerFixture struct {
+	service  *WagerService
+	wallet   *WalletService
+	userRepo *data.InMemoryRepository[domain.User]
+	roomRepo *data.InMemoryRepository[domain.Room]
+	gameRepo *data.InMemoryRepository[domain.Game]
+}
+
+func newWagerFixture(t *testing.T, coins, cards int) wagerFixture {
+	t.Helper()
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	userRepo.Create("alice", collector("alice", domain.Card{Type: "rock", Stars: 1}, domain.Card{Type: "paper", Stars: 1}))
+	userRepo.Create("bob", collector("bob", domain.Card{Type: "scissors", Stars: 1}, domain.Card{Type: "rock", Stars: 2}))
+	userRepo.Create("bot", domain.User{ID: "bot", Bot: true})
+	roomRepo := data.NewInMemoryRepository[domain.Room]()
+	room := domain.NewRoom("7", "Sala 7", "alice")
+	room.UserIDs.Add("alice")
+	room.UserIDs.Add("bob")
+	room.WagerCoins = coins
+	room.WagerCards = cards
+	roomRepo.Create(room.ID, *room)
+	gameRepo := data.NewInMemoryRepository[domain.Game]()
+	wallet := NewWalletService(data.NewInMemoryRepository[domain.LedgerEntry](), userRepo)
+	deckService := NewDeckService(userRepo, roomRepo, data.NewInMemoryRepository[domain.Ruleset](), &sync.Mutex{})
+	return wagerFixture{
+		service:  NewWagerService(data.NewInMemoryRepository[domain.Wager](), userRepo, roomRepo, gameRepo, deckService, wallet, &sync.Mutex{}),
+		wallet:   wallet,
+		userRepo: userRepo,
+		roomRepo: roomRepo,
+		gameRepo: gameRepo,
+	}
+}
+
+// room lê a sala da aposta.
+func (fixture wagerFixture) room(t *testing.T) domain.Room {
+	t.Helper()
+	room, err := fixture.roomRepo.Read("7")
+	if err != nil {
+		t.Fatalf("ler sala: %v", err)
+	}
+	return room
+}
+
+// hold escolhe a primeira carta de cada jogador, se a sala apostar cartas, e retém as apostas.
+func (fixture wagerFixture) hold(t *testing.T) domain.Wager {
+	t.Helper()
+	if fixture.room(t).WagerCards > 0 {
+		for _, playerID := range []string{"alice", "bob"} {
+			if err := fixture.service.ChooseStake("7", playerID, []string{"1"}); err != nil {
+				t.Fatalf("ChooseStake(%s): %v", playerID, err)
+			}
+		}
+	}
+	wager, err := fixture.service.Hold(fixture.room(t), 1)
+	if err != nil {
+		t.Fatalf("Hold: %v", err)
+	}
+	return wager
+}
+
+func TestWagerHold(t *testing.T) {
+	tests := []struct {
+		name         string
+		coins, cards int
+		before       func(t *testing.T, fixture wagerFixture)
+		wantErr      error
+		wantBalances map[string]int
+		wantCards    map[string]int
+	}{
+		{
+			name:         "retém moedas e cartas",
+			coins:        10,
+			cards:        1,
+			wantBalances: map[string]int{"alice": StarterCoins - 10, "bob": StarterCoins - 10},
+			wantCards:    map[string]int{"alice": 1, "bob": 1},
+		},
+		{
+			name:         "só moedas",
+			coins:        10,
+			wantBalances: map[string]int{"alice": StarterCoins - 10, "bob": StarterCoins - 10},
+			wantCards:    map[string]int{"alice": 2, "bob": 2},
+		},
+		{
+			name:  "saldo insuficiente devolve a aposta já retida",
+			coins: 10,
+			cards: 1,
+			before: func(t *testing.T, fixture wagerFixture) {
+				if _, err := fixture.wallet.Debit("bob", StarterCoins-5, domain.LedgerPurchase, ""); err != nil {
+					t.Fatalf("Debit: %v", err)
+				}
+			},
+			wantErr:      ErrWagerHold,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": 5},
+			wantCards:    map[string]int{"alice": 2, "bob": 2},
+		},
+		{
+			name:  "cartas não escolhidas devolvem a aposta já retida",
+			coins: 10,
+			cards: 1,
+			before: func(t *testing.T, fixture wagerFixture) {
+				room := fixture.room(t)
+				room.Stakes.Delete("bob")
+				fixture.roomRepo.Update(room.ID, room)
+			},
+			wantErr:      ErrWagerHold,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins},
+			wantCards:    map[string]int{"alice": 2, "bob": 2},
+		},
+		{
+			name:  "bots não apostam",
+			coins: 10,
+			before: func(t *testing.T, fixture wagerFixture) {
+				room := fixture.room(t)
+				room.UserIDs.Remove("bob")
+				room.UserIDs.Add("bot")
+				fixture.roomRepo.Update(room.ID, room)
+			},
+			wantErr:      ErrWagerHold,
+			wantBalances: map[string]int{"alice": StarterCoins},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newWagerFixture(t, test.coins, test.cards)
+			if test.cards > 0 {
+				for _, playerID := range []string{"alice", "bob"} {
+					if err := fixture.service.ChooseStake("7", playerID, []string{"1"}); err != nil {
+						t.Fatalf("ChooseStake(%s): %v", playerID, err)
+					}
+				}
+			}
+			if test.before != nil {
+				test.before(t, fixture)
+			}
+			wager, err := fixture.service.Hold(fixture.room(t), 1)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			if err == nil && wager.Status != domain.WagerHeld {
+				t.Errorf("estado = %s, esperado %s", wager.Status, domain.WagerHeld)
+			}
+			checkHoldings(t, fixture.wallet, fixture.userRepo, test.wantBalances, test.wantCards)
+		})
+	}
+}
+
+func TestWagerSettle(t *testing.T) {
+	tests := []struct {
+		name         string
+		winnerID     string
+		wantStatus   string
+		wantBalances map[string]int
+		wantCards    map[string]int
+	}{
+		{
+			name:         "vencedor leva o pote",
+			winnerID:     "alice",
+			wantStatus:   domain.WagerPaid,
+			wantBalances: map[string]int{"alice": StarterCoins + 10, "bob": StarterCoins - 10},
+			wantCards:    map[string]int{"alice": 3, "bob": 1},
+		},
+		{
+			name:         "empate devolve as apostas",
+			wantStatus:   domain.WagerRefunded,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins},
+			wantCards:    map[string]int{"alice": 2, "bob": 2},
+		},
+		{
+			name:         "vencedor fora da aposta devolve as apostas",
+			winnerID:     "carol",
+			wantStatus:   domain.WagerRefunded,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins},
+			wantCards:    map[string]int{"alice": 2, "bob": 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newWagerFixture(t, 10, 1)
+			wager := fixture.hold(t)
+
+			settled, _, err := fixture.service.Settle(wager.ID, test.winnerID)
+			if err != nil {
+				t.Fatalf("Settle: %v", err)
+			}
+			if settled.Status != test.wantStatus {
+				t.Errorf("estado = %s, esperado %s", settled.Status, test.wantStatus)
+			}
+			// Uma aposta encerrada não pode ser paga de novo.
+			if _, _, err := fixture.service.Settle(wager.ID, test.winnerID); !errors.Is(err, ErrWagerSettled) {
+				t.Errorf("segundo acerto: erro = %v, esperado %v", err, ErrWagerSettled)
+			}
+			checkHoldings(t, fixture.wallet, fixture.userRepo, test.wantBalances, test.wantCards)
+		})
+	}
+}
+
+func TestWagerForfeitAbsentees(t *testing.T) {
+	fixture := newWagerFixture(t, 10, 0)
+	wager := fixture.hold(t)
+	room := fixture.room(t)
+	room.Status = domain.RoomStatusPlaying
+	room.WagerID = wager.ID
+	fixture.roomRepo.Update(room.ID, room)
+	online := func(userID string) bool { return userID == "alice" }
+
+	absentees, err := fixture.service.Absentees(online)
+	if err != nil {
+		t.Fatalf("Absentees: %v", err)
+	}
+	if len(absentees) != 0 {
+		t.Fatalf("ausentes antes da janela = %v", absentees)
+	}
+	// bob foi visto desconectado há mais tempo do que a janela de reconexão.
+	fixture.service.absentSince.Set(absenceKey(wager.ID, "bob"), time.Now().Add(-WagerReconnectWindow))
+	absentees, err = fixture.service.Absentees(online)
+	if err != nil {
+		t.Fatalf("Absentees: %v", err)
+	}
+	if players := absentees["7"]; len(players) != 1 || players[0] != "bob" {
+		t.Fatalf("ausentes = %v, esperado bob na sala 7", absentees)
+	}
+
+	// A desistência dá a partida, e o pote, ao oponente.
+	if _, _, err := fixture.service.Settle(wager.ID, "alice"); err != nil {
+		t.Fatalf("Settle: %v", err)
+	}
+	checkHoldings(t, fixture.wallet, fixture.userRepo, map[string]int{"alice": StarterCoins + 10, "bob": StarterCoins - 10}, nil)
+	if absentees, _ := fixture.service.Absentees(online); len(absentees) != 0 {
+		t.Errorf("ausentes depois do acerto = %v", absentees)
+	}
+}
+
+func TestWagerReconcile(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		game         *domain.Game
+		deleteRoom   bool
+		wantStatus   string
+		wantBalances map[string]int
+	}{
+		{
+			name:         "partida em andamento continua retida",
+			status:       domain.RoomStatusPlaying,
+			wantStatus:   domain.WagerHeld,
+			wantBalances: map[string]int{"alice": StarterCoins - 10, "bob": StarterCoins - 10},
+		},
+		{
+			name:         "partida encerrada paga o vencedor",
+			status:       domain.RoomStatusFinished,
+			game:         &domain.Game{ID: "7", Number: 1, WinnerID: "bob"},
+			wantStatus:   domain.WagerPaid,
+			wantBalances: map[string]int{"alice": StarterCoins - 10, "bob": StarterCoins + 10},
+		},
+		{
+			name:         "partida de outra revanche devolve as apostas",
+			status:       domain.RoomStatusFinished,
+			game:         &domain.Game{ID: "7", Number: 2, WinnerID: "bob"},
+			wantStatus:   domain.WagerRefunded,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins},
+		},
+		{
+			name:         "sala de volta à espera devolve as apostas",
+			status:       domain.RoomStatusWaiting,
+			wantStatus:   domain.WagerRefunded,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins},
+		},
+		{
+			name:         "sala apagada devolve as apostas",
+			deleteRoom:   true,
+			wantStatus:   domain.WagerRefunded,
+			wantBalances: map[string]int{"alice": StarterCoins, "bob": StarterCoins},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newWagerFixture(t, 10, 0)
+			wager := fixture.hold(t)
+			room := fixture.room(t)
+			room.Status = test.status
+			room.WagerID = wager.ID
+			fixture.roomRepo.Update(room.ID, room)
+			if test.game != nil {
+				fixture.gameRepo.Create(test.game.ID, *test.game)
+			}
+			if test.deleteRoom {
+				fixture.roomRepo.Delete(room.ID)
+			}
+
+			if _, err := fixture.service.Reconcile(); err != nil {
+				t.Fatalf("Reconcile: %v", err)
+			}
+			stored, err := fixture.service.Get(wager.ID)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if stored.Status != test.wantStatus {
+				t.Errorf("estado = %s, esperado %s", stored.Status, test.wantStatus)
+			}
+			checkHoldings(t, fixture.wallet, fixture.userRepo, test.wantBalances, nil)
+		})
+	}
+}
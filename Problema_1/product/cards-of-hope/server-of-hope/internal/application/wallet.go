@@ -0,0 +1,363 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Valores da economia de moedas.
+const (
+	StarterCoins  = 100 // saldo com que cada carteira começa
+	RoundWinCoins = 5   // prêmio por rodada vencida
+	MatchWinCoins = 20  // prêmio por partida vencida
+)
+
+// LedgerHistorySize define quantos lançamentos recentes acompanham o saldo.
+const LedgerHistorySize = 10
+
+// ErrInvalidAmount indica um lançamento sem valor ou, em créditos e débitos, com valor negativo.
+var ErrInvalidAmount = errors.New("valor de lançamento inválido")
+
+// ErrInsufficientCoins indica que o saldo não cobre o débito pedido.
+var ErrInsufficientCoins = errors.New("moedas insuficientes")
+
+// ErrInsufficientDust indica que o saldo de pó de criação não cobre o débito pedido.
+var ErrInsufficientDust = errors.New("pó de criação insuficiente")
+
+// ErrBotWallet indica uma operação de carteira para um bot, que não ganha nem gasta moedas.
+var ErrBotWallet = errors.New("bots não têm carteira")
+
+// WalletServiceInterface descreve as operações sobre as carteiras de moedas dos usuários.
+//
+// Métodos:
+//   - Balance: retorna o saldo e os lançamentos recentes de um usuário.
+//   - Credit: credita moedas na carteira de um usuário.
+//   - Debit: debita moedas da carteira de um usuário.
+//   - Grant: ajusta o saldo de um usuário por decisão de um administrador.
+//   - Dust: retorna o saldo de pó de criação de um usuário.
+//   - CreditDust: credita pó de criação na carteira de um usuário.
+//   - DebitDust: debita pó de criação da carteira de um usuário.
+type WalletServiceInterface interface {
+	// Balance retorna o saldo e os lançamentos mais recentes de um usuário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - int: saldo atual de moedas.
+	//   - []domain.LedgerEntry: até LedgerHistorySize lançamentos, de moedas e de pó, do mais
+	//     recente ao mais antigo.
+	//   - erro caso o usuário não exista ou seja um bot.
+	Balance(userID string) (int, []domain.LedgerEntry, error)
+
+	// Credit credita moedas na carteira de um usuário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - amount: quantidade de moedas (positiva).
+	//   - reason: motivo do lançamento.
+	//   - reference: sala ou observação do lançamento.
+	//
+	// Retorno:
+	//   - domain.LedgerEntry: lançamento registrado.
+	//   - erro caso o valor seja inválido, o usuário não exista ou seja um bot.
+	Credit(userID string, amount int, reason, reference string) (domain.LedgerEntry, error)
+
+	// Debit debita moedas da carteira de um usuário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - amount: quantidade de moedas (positiva).
+	//   - reason: motivo do lançamento.
+	//   - reference: sala ou observação do lançamento.
+	//
+	// Retorno:
+	//   - domain.LedgerEntry: lançamento registrado.
+	//   - erro caso o saldo não cubra o valor, o valor seja inválido ou o usuário não exista.
+	Debit(userID string, amount int, reason, reference string) (domain.LedgerEntry, error)
+
+	// Grant ajusta o saldo de um usuário por decisão de um administrador. O valor pode ser
+	// negativo, desde que o saldo não fique abaixo de zero.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - amount: moedas a creditar (ou debitar, se negativo).
+	//   - note: motivo do ajuste.
+	//
+	// Retorno:
+	//   - domain.LedgerEntry: lançamento registrado.
+	//   - erro caso o ajuste deixe o saldo negativo, o valor seja zero ou o usuário não exista.
+	Grant(userID string, amount int, note string) (domain.LedgerEntry, error)
+
+	// Dust retorna o saldo de pó de criação de um usuário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//
+	// Retorno:
+	//   - int: saldo atual de pó.
+	//   - erro caso o usuário não exista ou seja um bot.
+	Dust(userID string) (int, error)
+
+	// CreditDust credita pó de criação na carteira de um usuário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - amount: quantidade de pó (positiva).
+	//   - reason: motivo do lançamento.
+	//   - reference: cartas ou observação do lançamento.
+	//
+	// Retorno:
+	//   - domain.LedgerEntry: lançamento registrado.
+	//   - erro caso o valor seja inválido, o usuário não exista ou seja um bot.
+	CreditDust(userID string, amount int, reason, reference string) (domain.LedgerEntry, error)
+
+	// DebitDust debita pó de criação da carteira de um usuário.
+	//
+	// Parâmetros:
+	//   - userID: identificador do usuário.
+	//   - amount: quantidade de pó (positiva).
+	//   - reason: motivo do lançamento.
+	//   - reference: cartas ou observação do lançamento.
+	//
+	// Retorno:
+	//   - domain.LedgerEntry: lançamento registrado.
+	//   - erro caso o saldo de pó não cubra o valor, o valor seja inválido ou o usuário não exista.
+	DebitDust(userID string, amount int, reason, reference string) (domain.LedgerEntry, error)
+}
+
+// WalletService implementa as carteiras de moedas e de pó de criação sobre um livro-caixa só de
+// acréscimos. Os saldos são calculados a partir dos lançamentos na primeira operação, o que inclui
+// os lançamentos restaurados de um backup.
+//
+// Campos:
+//   - ledgerRepo: repositório dos lançamentos, indexado pela posição no livro-caixa.
+//   - userRepo: repositório dos usuários, donos das carteiras.
+//   - balances: saldo de cada carteira aberta, por usuário e moeda (veja domain.WalletKey).
+//   - seq: posição do último lançamento.
+//   - loaded: indica se os saldos já foram calculados a partir do repositório.
+//   - mutex: serializa os lançamentos.
+type WalletService struct {
+	ledgerRepo data.RepositoryInterface[domain.LedgerEntry]
+	userRepo   data.RepositoryInterface[domain.User]
+	balances   map[domain.WalletKey]int
+	seq        int
+	loaded     bool
+	mutex      sync.Mutex
+}
+
+// NewWalletService cria uma nova instância de WalletService.
+//
+// Parâmetros:
+//   - ledgerRepo: repositório dos lançamentos do livro-caixa.
+//   - userRepo: repositório dos usuários.
+//
+// Retorno:
+//   - ponteiro para WalletService.
+func NewWalletService(
+	ledgerRepo data.RepositoryInterface[domain.LedgerEntry],
+	userRepo data.RepositoryInterface[domain.User],
+) *WalletService {
+	return &WalletService{
+		ledgerRepo: ledgerRepo,
+		userRepo:   userRepo,
+		balances:   map[domain.WalletKey]int{},
+	}
+}
+
+// Balance retorna o saldo e os lançamentos mais recentes de um usuário, abrindo a carteira com
+// o saldo inicial se necessário.
+func (service *WalletService) Balance(userID string) (int, []domain.LedgerEntry, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	balance, err := service.open(userID)
+	if err != nil {
+		return 0, nil, err
+	}
+	entries, err := service.entries()
+	if err != nil {
+		return 0, nil, err
+	}
+	var history []domain.LedgerEntry
+	for index := len(entries) - 1; index >= 0 && len(history) < LedgerHistorySize; index-- {
+		if entries[index].UserID == userID {
+			history = append(history, entries[index])
+		}
+	}
+	return balance, history, nil
+}
+
+// Credit credita moedas na carteira de um usuário.
+func (service *WalletService) Credit(userID string, amount int, reason, reference string) (domain.LedgerEntry, error) {
+	if amount <= 0 {
+		return domain.LedgerEntry{}, ErrInvalidAmount
+	}
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if _, err := service.open(userID); err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	return service.append(userID, "", amount, reason, reference)
+}
+
+// Debit debita moedas da carteira de um usuário, recusando débitos maiores que o saldo.
+func (service *WalletService) Debit(userID string, amount int, reason, reference string) (domain.LedgerEntry, error) {
+	if amount <= 0 {
+		return domain.LedgerEntry{}, ErrInvalidAmount
+	}
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	balance, err := service.open(userID)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	if balance < amount {
+		return domain.LedgerEntry{}, fmt.Errorf("%w: o saldo é %d e o valor é %d", ErrInsufficientCoins, balance, amount)
+	}
+	return service.append(userID, "", -amount, reason, reference)
+}
+
+// Grant ajusta o saldo de um usuário por decisão de um administrador.
+func (service *WalletService) Grant(userID string, amount int, note string) (domain.LedgerEntry, error) {
+	if amount == 0 {
+		return domain.LedgerEntry{}, ErrInvalidAmount
+	}
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	balance, err := service.open(userID)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	if balance+amount < 0 {
+		return domain.LedgerEntry{}, fmt.Errorf("%w: o saldo é %d e o ajuste é %d", ErrInsufficientCoins, balance, amount)
+	}
+	return service.append(userID, "", amount, domain.LedgerGrant, note)
+}
+
+// Dust retorna o saldo de pó de criação de um usuário, que começa em zero.
+func (service *WalletService) Dust(userID string) (int, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if _, err := service.open(userID); err != nil {
+		return 0, err
+	}
+	return service.balances[domain.WalletKey{UserID: userID, Currency: domain.CurrencyDust}], nil
+}
+
+// CreditDust credita pó de criação na carteira de um usuário.
+func (service *WalletService) CreditDust(userID string, amount int, reason, reference string) (domain.LedgerEntry, error) {
+	if amount <= 0 {
+		return domain.LedgerEntry{}, ErrInvalidAmount
+	}
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if _, err := service.open(userID); err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	return service.append(userID, domain.CurrencyDust, amount, reason, reference)
+}
+
+// DebitDust debita pó de criação da carteira de um usuário, recusando débitos maiores que o saldo.
+func (service *WalletService) DebitDust(userID string, amount int, reason, reference string) (domain.LedgerEntry, error) {
+	if amount <= 0 {
+		return domain.LedgerEntry{}, ErrInvalidAmount
+	}
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if _, err := service.open(userID); err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	balance := service.balances[domain.WalletKey{UserID: userID, Currency: domain.CurrencyDust}]
+	if balance < amount {
+		return domain.LedgerEntry{}, fmt.Errorf("%w: o saldo é %d e o valor é %d", ErrInsufficientDust, balance, amount)
+	}
+	return service.append(userID, domain.CurrencyDust, -amount, reason, reference)
+}
+
+// open retorna o saldo da carteira do usuário, creditando o saldo inicial na primeira vez.
+// Deve ser chamado com mutex travado.
+func (service *WalletService) open(userID string) (int, error) {
+	if err := service.load(); err != nil {
+		return 0, err
+	}
+	user, err := service.userRepo.Read(userID)
+	if err != nil {
+		return 0, err
+	}
+	if user.Bot {
+		return 0, ErrBotWallet
+	}
+	if balance, opened := service.balances[domain.WalletKey{UserID: userID}]; opened {
+		return balance, nil
+	}
+	entry, err := service.append(userID, "", StarterCoins, domain.LedgerStarter, "")
+	if err != nil {
+		return 0, err
+	}
+	return entry.Balance, nil
+}
+
+// load calcula os saldos a partir dos lançamentos do repositório, uma única vez. Deve ser
+// chamado com mutex travado.
+func (service *WalletService) load() error {
+	if service.loaded {
+		return nil
+	}
+	entries, err := service.entries()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		service.balances[entry.WalletKey()] += entry.Amount
+		service.seq = max(service.seq, entry.Seq)
+	}
+	service.loaded = true
+	return nil
+}
+
+// entries lista os lançamentos do repositório na ordem do livro-caixa.
+func (service *WalletService) entries() ([]domain.LedgerEntry, error) {
+	entries, err := service.ledgerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Seq < entries[j].Seq
+	})
+	return entries, nil
+}
+
+// append registra um lançamento no livro-caixa e atualiza o saldo da moeda. Deve ser chamado com
+// mutex travado, depois de validado o valor.
+func (service *WalletService) append(userID, currency string, amount int, reason, reference string) (domain.LedgerEntry, error) {
+	key := domain.WalletKey{UserID: userID, Currency: currency}
+	entry := domain.LedgerEntry{
+		Seq:       service.seq + 1,
+		UserID:    userID,
+		Currency:  currency,
+		Amount:    amount,
+		Balance:   service.balances[key] + amount,
+		Reason:    reason,
+		Reference: reference,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := service.ledgerRepo.Create(strconv.Itoa(entry.Seq), entry); err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	service.seq = entry.Seq
+	service.balances[key] = entry.Balance
+	return entry, nil
+}
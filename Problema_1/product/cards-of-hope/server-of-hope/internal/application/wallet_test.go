@@ -0,0 +1,160 @@
+package application
+
+import (
+	"errors"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"testing"
+)
+
+// newTestWallet cria uma carteira em memória com os usuários informados.
+func newTestWallet(t *testing.T, users ...domain.User) *WalletService {
+	t.Helper()
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	for _, user := range users {
+		if err := userRepo.Create(user.ID, user); err != nil {
+			t.Fatalf("criar usuário %s: %v", user.ID, err)
+		}
+	}
+	return NewWalletService(data.NewInMemoryRepository[domain.LedgerEntry](), userRepo)
+}
+
+func TestWalletBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		credits []int
+		debits  []int
+		want    int
+		wantErr error
+	}{
+		{name: "saldo inicial", want: StarterCoins},
+		{name: "crédito", credits: []int{25}, want: StarterCoins + 25},
+		{name: "débito", debits: []int{40}, want: StarterCoins - 40},
+		{name: "débito de todo o saldo", debits: []int{StarterCoins}, want: 0},
+		{name: "crédito e débito", credits: []int{30}, debits: []int{StarterCoins + 30}, want: 0},
+		{name: "débito acima do saldo", debits: []int{StarterCoins + 1}, want: StarterCoins, wantErr: ErrInsufficientCoins},
+		{name: "débito depois de zerar", debits: []int{StarterCoins, 1}, want: 0, wantErr: ErrInsufficientCoins},
+		{name: "débito sem valor", debits: []int{0}, want: StarterCoins, wantErr: ErrInvalidAmount},
+		{name: "crédito negativo", credits: []int{-5}, want: StarterCoins, wantErr: ErrInvalidAmount},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wallet := newTestWallet(t, domain.User{ID: "alice"})
+			var err error
+			for _, amount := range test.credits {
+				if _, creditErr := wallet.Credit("alice", amount, domain.LedgerGrant, ""); creditErr != nil {
+					err = creditErr
+				}
+			}
+			for _, amount := range test.debits {
+				if _, debitErr := wallet.Debit("alice", amount, domain.LedgerPurchase, ""); debitErr != nil {
+					err = debitErr
+				}
+			}
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			balance, _, err := wallet.Balance("alice")
+			if err != nil {
+				t.Fatalf("Balance: %v", err)
+			}
+			if balance != test.want {
+				t.Errorf("saldo = %d, esperado %d", balance, test.want)
+			}
+		})
+	}
+}
+
+func TestWalletDust(t *testing.T) {
+	tests := []struct {
+		name     string
+		credit   int
+		debit    int
+		wantDust int
+		wantErr  error
+	}{
+		{name: "começa em zero"},
+		{name: "crédito", credit: 50, wantDust: 50},
+		{name: "débito", credit: 50, debit: 20, wantDust: 30},
+		{name: "débito acima do saldo", credit: 10, debit: 11, wantDust: 10, wantErr: ErrInsufficientDust},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wallet := newTestWallet(t, domain.User{ID: "alice"})
+			var err error
+			if test.credit > 0 {
+				_, err = wallet.CreditDust("alice", test.credit, domain.LedgerDisenchant, "")
+			}
+			if err == nil && test.debit > 0 {
+				_, err = wallet.DebitDust("alice", test.debit, domain.LedgerCraft, "")
+			}
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, test.wantErr)
+			}
+			dust, err := wallet.Dust("alice")
+			if err != nil {
+				t.Fatalf("Dust: %v", err)
+			}
+			if dust != test.wantDust {
+				t.Errorf("pó = %d, esperado %d", dust, test.wantDust)
+			}
+			balance, _, err := wallet.Balance("alice")
+			if err != nil {
+				t.Fatalf("Balance: %v", err)
+			}
+			if balance != StarterCoins {
+				t.Errorf("o pó alterou as moedas: saldo = %d, esperado %d", balance, StarterCoins)
+			}
+		})
+	}
+}
+
+func TestWalletKeysDoNotCollide(t *testing.T) {
+	// Um usuário chamado "alice/dust" não pode compartilhar a carteira de pó de "alice".
+	wallet := newTestWallet(t, domain.User{ID: "alice"}, domain.User{ID: "alice/dust"})
+	if _, err := wallet.CreditDust("alice", 70, domain.LedgerDisenchant, ""); err != nil {
+		t.Fatalf("CreditDust: %v", err)
+	}
+	balance, _, err := wallet.Balance("alice/dust")
+	if err != nil {
+		t.Fatalf("Balance: %v", err)
+	}
+	if balance != StarterCoins {
+		t.Errorf("saldo de alice/dust = %d, esperado %d", balance, StarterCoins)
+	}
+}
+
+func TestWalletReloadsFromLedger(t *testing.T) {
+	ledgerRepo := data.NewInMemoryRepository[domain.LedgerEntry]()
+	userRepo := data.NewInMemoryRepository[domain.User]()
+	if err := userRepo.Create("alice", domain.User{ID: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	first := NewWalletService(ledgerRepo, userRepo)
+	if _, err := first.Debit("alice", 30, domain.LedgerPurchase, ""); err != nil {
+		t.Fatalf("Debit: %v", err)
+	}
+	if _, err := first.CreditDust("alice", 15, domain.LedgerDisenchant, ""); err != nil {
+		t.Fatalf("CreditDust: %v", err)
+	}
+
+	second := NewWalletService(ledgerRepo, userRepo)
+	balance, _, err := second.Balance("alice")
+	if err != nil {
+		t.Fatalf("Balance: %v", err)
+	}
+	dust, err := second.Dust("alice")
+	if err != nil {
+		t.Fatalf("Dust: %v", err)
+	}
+	if balance != StarterCoins-30 || dust != 15 {
+		t.Errorf("saldo = %d e pó = %d, esperado %d e 15", balance, dust, StarterCoins-30)
+	}
+}
+
+func TestWalletRejectsBots(t *testing.T) {
+	wallet := newTestWallet(t, domain.User{ID: "bot", Bot: true})
+	if _, _, err := wallet.Balance("bot"); !errors.Is(err, ErrBotWallet) {
+		t.Errorf("erro = %v, esperado %v", err, ErrBotWallet)
+	}
+}
@@ -0,0 +1,845 @@
+package data
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"time"
+)
+
+// SchemaVersion é a versão atual do formato do arquivo de backup.
+// Deve ser incrementada sempre que ArchiveData mudar de forma incompatível,
+// acompanhada de uma migração registrada em migrations.
+const SchemaVersion = 7
+
+// Archive representa o envelope versionado de um backup do servidor.
+//
+// Campos:
+//   - SchemaVersion: versão do formato dos dados.
+//   - CreatedAt: momento em que o backup foi gerado.
+//   - Checksum: hash SHA-256 dos dados, usado na verificação de integridade.
+//   - Data: dados serializados no formato da versão informada.
+type Archive struct {
+	SchemaVersion int             `json:"schema_version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Checksum      string          `json:"checksum"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// ArchiveData contém o estado completo do servidor no formato da versão atual.
+//
+// Campos:
+//   - Users: usuários cadastrados.
+//   - Rooms: salas existentes.
+//   - Games: partidas em andamento.
+//   - Stock: pacotes de cartas disponíveis no estoque da loja.
+//   - Replays: registros das partidas encerradas.
+//   - Tournaments: torneios e suas chaves.
+//   - Ledger: lançamentos do livro-caixa de moedas, na ordem em que foram feitos.
+//   - Trades: propostas de troca de cartas.
+//   - Listings: anúncios do mercado de cartas, com as cartas retidas.
+//   - PackPity: contadores das garantias de raridade dos pacotes da loja.
+//   - PackSeeds: sementes dos sorteios verificáveis dos pacotes, em uso e reveladas.
+//   - PackOpenings: pacotes abertos, com a semente e o nonce de cada sorteio.
+//   - Wagers: apostas das partidas com aposta, com as moedas e as cartas retidas.
+//   - DirectMessages: mensagens diretas entre usuários, entregues ou na caixa de entrada.
+type ArchiveData struct {
+	Users          []domain.User          `json:"users"`
+	Rooms          []domain.Room          `json:"rooms"`
+	Games          []domain.Game          `json:"games"`
+	Stock          []domain.CardPackage   `json:"stock"`
+	Replays        []domain.Replay        `json:"replays"`
+	Tournaments    []domain.Tournament    `json:"tournaments"`
+	Ledger         []domain.LedgerEntry   `json:"ledger"`
+	Trades         []domain.Trade         `json:"trades"`
+	Listings       []domain.Listing       `json:"listings"`
+	PackPity       []domain.PackPity      `json:"pack_pity"`
+	PackSeeds      []domain.PackSeed      `json:"pack_seeds"`
+	PackOpenings   []domain.PackOpening   `json:"pack_openings"`
+	Wagers         []domain.Wager         `json:"wagers"`
+	DirectMessages []domain.DirectMessage `json:"direct_messages"`
+}
+
+// migration converte os dados genéricos de uma versão para a seguinte.
+type migration func(data map[string]any) error
+
+// migrations mapeia a versão de origem para a função que a converte na versão seguinte.
+var migrations = map[int]migration{
+	1: migrateRoomMetadata,
+	2: migrateRoomHost,
+	3: migrateReadyCheck,
+	4: migrateRoomSettings,
+	5: migratePackOpenings,
+	6: migrateSettledMatches,
+}
+
+// WriteArchive serializa os dados em um envelope da versão atual.
+//
+// Parâmetros:
+//   - writer: destino do backup.
+//   - archiveData: dados a serem gravados.
+//
+// Retorno:
+//   - erro caso não seja possível serializar ou gravar os dados.
+func WriteArchive(writer io.Writer, archiveData ArchiveData) error {
+	raw, err := json.Marshal(archiveData)
+	if err != nil {
+		return err
+	}
+	archive := Archive{
+		SchemaVersion: SchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Checksum:      checksum(raw),
+		Data:          raw,
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archive)
+}
+
+// ReadArchive lê um backup, confere o checksum e migra os dados até a versão atual.
+//
+// Parâmetros:
+//   - reader: origem do backup.
+//
+// Retorno:
+//   - ArchiveData: dados no formato da versão atual.
+//   - int: versão original do backup lido.
+//   - erro caso o backup esteja corrompido ou em uma versão não suportada.
+func ReadArchive(reader io.Reader) (ArchiveData, int, error) {
+	var archive Archive
+	if err := json.NewDecoder(reader).Decode(&archive); err != nil {
+		return ArchiveData{}, 0, fmt.Errorf("backup inválido: %w", err)
+	}
+	if archive.SchemaVersion < 1 || archive.SchemaVersion > SchemaVersion {
+		return ArchiveData{}, archive.SchemaVersion, fmt.Errorf("versão de esquema não suportada: %d", archive.SchemaVersion)
+	}
+	if checksum(archive.Data) != archive.Checksum {
+		return ArchiveData{}, archive.SchemaVersion, errors.New("checksum não confere, o backup está corrompido")
+	}
+
+	var generic map[string]any
+	if err := json.Unmarshal(archive.Data, &generic); err != nil {
+		return ArchiveData{}, archive.SchemaVersion, fmt.Errorf("dados inválidos: %w", err)
+	}
+	for version := archive.SchemaVersion; version < SchemaVersion; version++ {
+		migrate, exists := migrations[version]
+		if !exists {
+			return ArchiveData{}, archive.SchemaVersion, fmt.Errorf("migração da versão %d não encontrada", version)
+		}
+		if err := migrate(generic); err != nil {
+			return ArchiveData{}, archive.SchemaVersion, fmt.Errorf("falha ao migrar da versão %d: %w", version, err)
+		}
+	}
+
+	raw, err := json.Marshal(generic)
+	if err != nil {
+		return ArchiveData{}, archive.SchemaVersion, err
+	}
+	var archiveData ArchiveData
+	if err := json.Unmarshal(raw, &archiveData); err != nil {
+		return ArchiveData{}, archive.SchemaVersion, fmt.Errorf("dados inválidos: %w", err)
+	}
+	return archiveData, archive.SchemaVersion, nil
+}
+
+// Verify confere a consistência interna dos dados do backup.
+//
+// Retorno:
+//   - erro agregando todas as inconsistências encontradas, ou nil.
+func (archiveData ArchiveData) Verify() error {
+	var problems []error
+
+	rulesets := make(map[string]domain.Ruleset)
+	builtin, err := LoadRulesets()
+	if err != nil {
+		problems = append(problems, err)
+	}
+	for _, ruleset := range builtin {
+		rulesets[ruleset.ID] = ruleset
+	}
+
+	users := make(map[string]bool, len(archiveData.Users))
+	for _, user := range archiveData.Users {
+		if user.ID == "" {
+			problems = append(problems, errors.New("usuário sem ID"))
+			continue
+		}
+		if users[user.ID] {
+			problems = append(problems, fmt.Errorf("usuário duplicado: %s", user.ID))
+		}
+		users[user.ID] = true
+		problems = append(problems, verifyCollection(user, rulesets[domain.DefaultRulesetID])...)
+	}
+
+	rooms := make(map[string]domain.Room, len(archiveData.Rooms))
+	for _, room := range archiveData.Rooms {
+		if room.ID == "" {
+			problems = append(problems, errors.New("sala sem ID"))
+			continue
+		}
+		if _, exists := rooms[room.ID]; exists {
+			problems = append(problems, fmt.Errorf("sala duplicada: %s", room.ID))
+		}
+		rooms[room.ID] = room
+		if !domain.ValidRoomStatus(room.Status) {
+			problems = append(problems, fmt.Errorf("sala %s com estado inválido: %q", room.ID, room.Status))
+		}
+		if _, exists := rulesets[roomRuleset(room)]; !exists {
+			problems = append(problems, fmt.Errorf("sala %s com conjunto de regras desconhecido: %q", room.ID, room.RulesetID))
+		}
+		if room.TurnHours < 0 || (!room.Async && room.TurnHours != 0) || (room.Async && room.TurnHours == 0) {
+			problems = append(problems, fmt.Errorf("sala %s com prazo de rodada inválido: %d horas", room.ID, room.TurnHours))
+		}
+		if room.Spectators != nil {
+			for _, userID := range room.Spectators.Items() {
+				if !users[userID] {
+					problems = append(problems, fmt.Errorf("sala %s referencia espectador inexistente: %s", room.ID, userID))
+				}
+				if room.UserIDs != nil && room.UserIDs.Contains(userID) {
+					problems = append(problems, fmt.Errorf("sala %s tem %s como jogador e espectador", room.ID, userID))
+				}
+			}
+		}
+		if room.UserIDs == nil {
+			continue
+		}
+		if room.HostID != "" && !room.IsMember(room.HostID) {
+			problems = append(problems, fmt.Errorf("sala %s tem anfitrião fora da sala: %s", room.ID, room.HostID))
+		}
+		if room.RematchRequests != nil {
+			for _, userID := range room.RematchRequests.Items() {
+				if !room.UserIDs.Contains(userID) {
+					problems = append(problems, fmt.Errorf("sala %s tem pedido de revanche de jogador fora da sala: %s", room.ID, userID))
+				}
+			}
+		}
+		if room.Ready != nil {
+			for _, userID := range room.Ready.Items() {
+				if !room.UserIDs.Contains(userID) {
+					problems = append(problems, fmt.Errorf("sala %s tem jogador pronto fora da sala: %s", room.ID, userID))
+				}
+			}
+		}
+		if room.DeckChoices != nil {
+			for _, userID := range room.DeckChoices.Keys() {
+				if !room.UserIDs.Contains(userID) {
+					problems = append(problems, fmt.Errorf("sala %s tem baralho escolhido por jogador fora da sala: %s", room.ID, userID))
+				}
+			}
+		}
+		if room.UserIDs.Size() > room.Capacity {
+			problems = append(problems, fmt.Errorf("sala %s excede a capacidade de %d jogadores", room.ID, room.Capacity))
+		}
+		for _, userID := range room.UserIDs.Items() {
+			if !users[userID] {
+				problems = append(problems, fmt.Errorf("sala %s referencia usuário inexistente: %s", room.ID, userID))
+			}
+		}
+	}
+
+	for _, game := range archiveData.Games {
+		room, exists := rooms[game.ID]
+		if !exists {
+			problems = append(problems, fmt.Errorf("partida %s sem sala correspondente", game.ID))
+			continue
+		}
+		ruleset := rulesets[roomRuleset(room)]
+		// Fora de uma partida em andamento, a partida foi abandonada por quem saiu da sala e
+		// pode manter jogadas e compromissos dele até a próxima começar.
+		seated := func(userID string) bool {
+			return room.Status != domain.RoomStatusPlaying || (room.UserIDs != nil && room.UserIDs.Contains(userID))
+		}
+		for _, piles := range []*utils.Map[string, []domain.OwnedCard]{game.Hands, game.DrawPiles} {
+			if piles == nil {
+				continue
+			}
+			piles.ForEach(func(userID string, cards []domain.OwnedCard) {
+				for _, card := range cards {
+					// Habilidades fora das regras da sala são ignoradas na jogada
+					plain := card.Card
+					plain.Ability = ""
+					if err := verifyCard(plain, ruleset); err != nil {
+						problems = append(problems, fmt.Errorf("partida %s, cartas de %s: %w", game.ID, userID, err))
+					}
+				}
+			})
+		}
+		if game.Commitments != nil {
+			game.Commitments.ForEach(func(userID string, commitment string) {
+				if !seated(userID) {
+					problems = append(problems, fmt.Errorf("partida %s tem compromisso de usuário fora da sala: %s", game.ID, userID))
+				}
+				if !domain.ValidCommitment(commitment) {
+					problems = append(problems, fmt.Errorf("partida %s tem compromisso inválido de %s", game.ID, userID))
+				}
+			})
+		}
+		if game.Plays == nil {
+			continue
+		}
+		game.Plays.ForEach(func(userID string, card domain.Card) {
+			if !seated(userID) {
+				problems = append(problems, fmt.Errorf("partida %s tem jogada de usuário fora da sala: %s", game.ID, userID))
+			}
+			if err := verifyCard(card, ruleset); err != nil {
+				problems = append(problems, fmt.Errorf("partida %s: %w", game.ID, err))
+			}
+		})
+	}
+
+	replays := make(map[string]bool, len(archiveData.Replays))
+	for _, replay := range archiveData.Replays {
+		if replay.ID == "" {
+			problems = append(problems, errors.New("registro de partida sem ID"))
+			continue
+		}
+		if replays[replay.ID] {
+			problems = append(problems, fmt.Errorf("registro de partida duplicado: %s", replay.ID))
+		}
+		replays[replay.ID] = true
+		if len(replay.Players) == 0 {
+			problems = append(problems, fmt.Errorf("registro de partida %s sem jogadores", replay.ID))
+		}
+		for _, userID := range append(append([]string(nil), replay.Players...), replay.Spectators...) {
+			if !users[userID] {
+				problems = append(problems, fmt.Errorf("registro de partida %s referencia usuário inexistente: %s", replay.ID, userID))
+			}
+		}
+	}
+
+	tournaments := make(map[string]bool, len(archiveData.Tournaments))
+	for _, tournament := range archiveData.Tournaments {
+		if tournament.ID == "" {
+			problems = append(problems, errors.New("torneio sem ID"))
+			continue
+		}
+		if tournaments[tournament.ID] {
+			problems = append(problems, fmt.Errorf("torneio duplicado: %s", tournament.ID))
+		}
+		tournaments[tournament.ID] = true
+		if !domain.ValidTournamentFormat(tournament.Format) {
+			problems = append(problems, fmt.Errorf("torneio %s com formato desconhecido: %q", tournament.ID, tournament.Format))
+		}
+		if _, ok := rulesets[tournament.RulesetID]; !ok {
+			problems = append(problems, fmt.Errorf("torneio %s com regras desconhecidas: %q", tournament.ID, tournament.RulesetID))
+		}
+		for _, userID := range append([]string{tournament.OwnerID}, tournament.Players...) {
+			if !users[userID] {
+				problems = append(problems, fmt.Errorf("torneio %s referencia usuário inexistente: %s", tournament.ID, userID))
+			}
+		}
+		for _, match := range tournament.Matches {
+			for _, userID := range match.Players {
+				if userID != "" && !tournament.IsRegistered(userID) {
+					problems = append(problems, fmt.Errorf("torneio %s tem partida %s com jogador não inscrito: %s", tournament.ID, match.ID, userID))
+				}
+			}
+		}
+	}
+
+	problems = append(problems, verifyLedger(archiveData.Ledger, users)...)
+
+	trades := make(map[string]bool, len(archiveData.Trades))
+	for _, trade := range archiveData.Trades {
+		if trade.ID == "" {
+			problems = append(problems, errors.New("proposta de troca sem ID"))
+			continue
+		}
+		if trades[trade.ID] {
+			problems = append(problems, fmt.Errorf("proposta de troca duplicada: %s", trade.ID))
+		}
+		trades[trade.ID] = true
+		if !domain.ValidTradeStatus(trade.Status) {
+			problems = append(problems, fmt.Errorf("proposta de troca %s com estado inválido: %q", trade.ID, trade.Status))
+		}
+		for _, userID := range []string{trade.FromID, trade.ToID} {
+			if !users[userID] {
+				problems = append(problems, fmt.Errorf("proposta de troca %s referencia usuário inexistente: %s", trade.ID, userID))
+			}
+		}
+	}
+
+	problems = append(problems, verifyListings(archiveData.Listings, users, rulesets[domain.DefaultRulesetID])...)
+
+	packs := make(map[string]bool)
+	loaded, err := LoadPackTypes(rulesets[domain.DefaultRulesetID])
+	if err != nil {
+		problems = append(problems, err)
+	}
+	for _, pack := range loaded {
+		packs[pack.ID] = true
+	}
+	problems = append(problems, verifyPackPity(archiveData.PackPity, users, packs)...)
+	problems = append(problems, verifyPackSeeds(archiveData.PackSeeds, archiveData.PackOpenings, users, packs, rulesets[domain.DefaultRulesetID])...)
+	problems = append(problems, verifyWagers(archiveData.Wagers, users, rulesets[domain.DefaultRulesetID])...)
+	problems = append(problems, verifyDirectMessages(archiveData.DirectMessages, users)...)
+
+	stockRuleset := rulesets[domain.DefaultRulesetID]
+	for index, cardPackage := range archiveData.Stock {
+		for _, card := range cardPackage {
+			if err := verifyCard(card, stockRuleset); err != nil {
+				problems = append(problems, fmt.Errorf("pacote %d do estoque: %w", index, err))
+			}
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// verifyLedger confere se os lançamentos têm posições únicas, pertencem a usuários existentes e
+// se o saldo de cada lançamento é a soma dos anteriores do mesmo usuário, sem nunca ficar negativo.
+func verifyLedger(ledger []domain.LedgerEntry, users map[string]bool) []error {
+	var problems []error
+	entries := append([]domain.LedgerEntry(nil), ledger...)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Seq < entries[j].Seq
+	})
+	balances := make(map[domain.WalletKey]int)
+	for index, entry := range entries {
+		if entry.Seq <= 0 {
+			problems = append(problems, fmt.Errorf("lançamento com posição inválida: %d", entry.Seq))
+		}
+		if index > 0 && entries[index-1].Seq == entry.Seq {
+			problems = append(problems, fmt.Errorf("lançamento duplicado: %d", entry.Seq))
+		}
+		if !users[entry.UserID] {
+			problems = append(problems, fmt.Errorf("lançamento %d referencia usuário inexistente: %s", entry.Seq, entry.UserID))
+		}
+		if entry.Amount == 0 {
+			problems = append(problems, fmt.Errorf("lançamento %d sem valor", entry.Seq))
+		}
+		if entry.Currency != "" && entry.Currency != domain.CurrencyDust {
+			problems = append(problems, fmt.Errorf("lançamento %d com moeda inválida: %q", entry.Seq, entry.Currency))
+		}
+		key := entry.WalletKey()
+		balances[key] += entry.Amount
+		if entry.Balance != balances[key] {
+			problems = append(problems, fmt.Errorf("lançamento %d com saldo %d, mas a soma dos lançamentos de %s é %d", entry.Seq, entry.Balance, key, balances[key]))
+			balances[key] = entry.Balance // Confere os seguintes a partir do saldo registrado
+		}
+		if entry.Balance < 0 {
+			problems = append(problems, fmt.Errorf("lançamento %d deixa o saldo de %s negativo", entry.Seq, entry.UserID))
+		}
+	}
+	return problems
+}
+
+// verifyWagers confere se as apostas têm IDs únicos, estado conhecido, jogadores existentes,
+// moedas não negativas, cartas válidas e, quando pagas, um vencedor entre os jogadores.
+func verifyWagers(wagers []domain.Wager, users map[string]bool, ruleset domain.Ruleset) []error {
+	var problems []error
+	seen := make(map[string]bool, len(wagers))
+	for _, wager := range wagers {
+		if wager.ID == "" {
+			problems = append(problems, errors.New("aposta sem ID"))
+			continue
+		}
+		if seen[wager.ID] {
+			problems = append(problems, fmt.Errorf("aposta duplicada: %s", wager.ID))
+		}
+		seen[wager.ID] = true
+		if !domain.ValidWagerStatus(wager.Status) {
+			problems = append(problems, fmt.Errorf("aposta %s com estado inválido: %q", wager.ID, wager.Status))
+		}
+		if wager.Status == domain.WagerPaid && !wager.Involves(wager.WinnerID) {
+			problems = append(problems, fmt.Errorf("aposta %s paga a quem não apostou: %q", wager.ID, wager.WinnerID))
+		}
+		for userID, stake := range wager.Stakes {
+			if !users[userID] {
+				problems = append(problems, fmt.Errorf("aposta %s referencia usuário inexistente: %s", wager.ID, userID))
+			}
+			if stake.Coins < 0 {
+				problems = append(problems, fmt.Errorf("aposta %s com %d moedas de %s", wager.ID, stake.Coins, userID))
+			}
+			if len(stake.Cards) > domain.MaxWagerCards {
+				problems = append(problems, fmt.Errorf("aposta %s com %d cartas de %s", wager.ID, len(stake.Cards), userID))
+			}
+			for _, card := range stake.Cards {
+				if err := verifyCard(card, ruleset); err != nil {
+					problems = append(problems, fmt.Errorf("aposta %s: %w", wager.ID, err))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// verifyDirectMessages confere se as mensagens diretas têm IDs únicos, remetente e destinatário
+// existentes e distintos e um texto dentro do tamanho permitido.
+func verifyDirectMessages(messages []domain.DirectMessage, users map[string]bool) []error {
+	var problems []error
+	seen := make(map[string]bool, len(messages))
+	for _, message := range messages {
+		if message.ID == "" {
+			problems = append(problems, errors.New("mensagem direta sem ID"))
+			continue
+		}
+		if seen[message.ID] {
+			problems = append(problems, fmt.Errorf("mensagem direta duplicada: %s", message.ID))
+		}
+		seen[message.ID] = true
+		for _, userID := range []string{message.FromID, message.ToID} {
+			if !users[userID] {
+				problems = append(problems, fmt.Errorf("mensagem direta %s referencia usuário inexistente: %s", message.ID, userID))
+			}
+		}
+		if message.FromID == message.ToID {
+			problems = append(problems, fmt.Errorf("mensagem direta %s enviada para o próprio remetente", message.ID))
+		}
+		if length := len([]rune(message.Text)); length == 0 || length > domain.MaxDirectMessageLength {
+			problems = append(problems, fmt.Errorf("mensagem direta %s com %d caracteres", message.ID, length))
+		}
+	}
+	return problems
+}
+
+// verifyListings confere se os anúncios têm IDs únicos, tipo e estado conhecidos, usuários
+// existentes, cartas válidas e lances crescentes a partir do lance mínimo.
+func verifyListings(listings []domain.Listing, users map[string]bool, ruleset domain.Ruleset) []error {
+	var problems []error
+	seen := make(map[string]bool, len(listings))
+	for _, listing := range listings {
+		if listing.ID == "" {
+			problems = append(problems, errors.New("anúncio sem ID"))
+			continue
+		}
+		if seen[listing.ID] {
+			problems = append(problems, fmt.Errorf("anúncio duplicado: %s", listing.ID))
+		}
+		seen[listing.ID] = true
+		if !domain.ValidListingKind(listing.Kind) {
+			problems = append(problems, fmt.Errorf("anúncio %s com tipo inválido: %q", listing.ID, listing.Kind))
+		}
+		if !domain.ValidListingStatus(listing.Status) {
+			problems = append(problems, fmt.Errorf("anúncio %s com estado inválido: %q", listing.ID, listing.Status))
+		}
+		if len(listing.Cards) == 0 || len(listing.Cards) > domain.MaxListingCards {
+			problems = append(problems, fmt.Errorf("anúncio %s com %d cartas", listing.ID, len(listing.Cards)))
+		}
+		for _, card := range listing.Cards {
+			if err := verifyCard(card, ruleset); err != nil {
+				problems = append(problems, fmt.Errorf("anúncio %s: %w", listing.ID, err))
+			}
+		}
+		userIDs := []string{listing.SellerID}
+		if listing.BuyerID != "" {
+			userIDs = append(userIDs, listing.BuyerID)
+		}
+		minimum := listing.Price
+		for _, bid := range listing.Bids {
+			userIDs = append(userIDs, bid.UserID)
+			if bid.Amount < minimum {
+				problems = append(problems, fmt.Errorf("anúncio %s com lance de %d abaixo do mínimo de %d", listing.ID, bid.Amount, minimum))
+			}
+			minimum = bid.Amount + 1
+		}
+		for _, userID := range userIDs {
+			if !users[userID] {
+				problems = append(problems, fmt.Errorf("anúncio %s referencia usuário inexistente: %s", listing.ID, userID))
+			}
+		}
+	}
+	return problems
+}
+
+// verifyPackPity confere se os contadores das garantias são únicos, de usuários existentes e de
+// tipos de pacote vendidos pela loja.
+func verifyPackPity(counters []domain.PackPity, users map[string]bool, packs map[string]bool) []error {
+	var problems []error
+	seen := make(map[string]bool, len(counters))
+	for _, counter := range counters {
+		key := domain.PackPityKey(counter.UserID, counter.PackID)
+		if seen[key] {
+			problems = append(problems, fmt.Errorf("garantia duplicada de %s no pacote %s", counter.UserID, counter.PackID))
+		}
+		seen[key] = true
+		if !users[counter.UserID] {
+			problems = append(problems, fmt.Errorf("garantia de pacote referencia usuário inexistente: %s", counter.UserID))
+		}
+		if !packs[counter.PackID] {
+			problems = append(problems, fmt.Errorf("garantia de %s em tipo de pacote desconhecido: %q", counter.UserID, counter.PackID))
+		}
+		if counter.Opened < 0 {
+			problems = append(problems, fmt.Errorf("garantia de %s no pacote %s com contador negativo", counter.UserID, counter.PackID))
+		}
+	}
+	return problems
+}
+
+// verifyPackSeeds confere se as sementes dos sorteios conferem com os hashes publicados, se cada
+// usuário tem no máximo uma semente em uso e se os pacotes abertos referenciam sementes do mesmo
+// usuário, com nonces já usados, tipos de pacote conhecidos e cartas válidas.
+func verifyPackSeeds(seeds []domain.PackSeed, openings []domain.PackOpening, users map[string]bool, packs map[string]bool, ruleset domain.Ruleset) []error {
+	var problems []error
+	byID := make(map[string]domain.PackSeed, len(seeds))
+	active := make(map[string]bool)
+	for _, seed := range seeds {
+		if seed.ID == "" {
+			problems = append(problems, errors.New("semente de pacotes sem ID"))
+			continue
+		}
+		if _, exists := byID[seed.ID]; exists {
+			problems = append(problems, fmt.Errorf("semente de pacotes duplicada: %s", seed.ID))
+		}
+		byID[seed.ID] = seed
+		if !users[seed.UserID] {
+			problems = append(problems, fmt.Errorf("semente de pacotes %s referencia usuário inexistente: %s", seed.ID, seed.UserID))
+		}
+		if seed.Hash != domain.SeedHash(seed.ServerSeed) {
+			problems = append(problems, fmt.Errorf("semente de pacotes %s não confere com o hash publicado", seed.ID))
+		}
+		if !domain.ValidClientSeed(seed.ClientSeed) || seed.Nonce < 0 {
+			problems = append(problems, fmt.Errorf("semente de pacotes %s com semente do cliente ou nonce inválidos", seed.ID))
+		}
+		if !seed.Revealed() {
+			if active[seed.UserID] {
+				problems = append(problems, fmt.Errorf("usuário %s com mais de uma semente de pacotes em uso", seed.UserID))
+			}
+			active[seed.UserID] = true
+		}
+	}
+
+	seen := make(map[string]bool, len(openings))
+	for _, opening := range openings {
+		if opening.ID == "" {
+			problems = append(problems, errors.New("pacote aberto sem ID"))
+			continue
+		}
+		if seen[opening.ID] {
+			problems = append(problems, fmt.Errorf("pacote aberto duplicado: %s", opening.ID))
+		}
+		seen[opening.ID] = true
+		seed, exists := byID[opening.SeedID]
+		if !exists || seed.UserID != opening.UserID {
+			problems = append(problems, fmt.Errorf("pacote aberto %s referencia semente inexistente ou de outro usuário: %s", opening.ID, opening.SeedID))
+		} else if opening.Nonce < 0 || opening.Nonce >= seed.Nonce {
+			problems = append(problems, fmt.Errorf("pacote aberto %s com nonce %d fora dos usados pela semente %s", opening.ID, opening.Nonce, seed.ID))
+		}
+		if !packs[opening.PackID] {
+			problems = append(problems, fmt.Errorf("pacote aberto %s de tipo desconhecido: %q", opening.ID, opening.PackID))
+		}
+		if opening.PityOpened < 0 {
+			problems = append(problems, fmt.Errorf("pacote aberto %s com contador da garantia negativo: %d", opening.ID, opening.PityOpened))
+		}
+		// O hash das tabelas tem o mesmo formato de um compromisso: SHA-256 em hexadecimal
+		if opening.TableHash != "" && !domain.ValidCommitment(opening.TableHash) {
+			problems = append(problems, fmt.Errorf("pacote aberto %s com hash das tabelas inválido", opening.ID))
+		}
+		for _, card := range opening.Cards {
+			if err := verifyCard(card, ruleset); err != nil {
+				problems = append(problems, fmt.Errorf("pacote aberto %s: %w", opening.ID, err))
+			}
+		}
+	}
+	return problems
+}
+
+// migrateRoomMetadata (v1 → v2) preenche nome, dono, capacidade, estado e data de criação das salas.
+func migrateRoomMetadata(data map[string]any) error {
+	rooms, _ := data["rooms"].([]any)
+	for _, item := range rooms {
+		room, ok := item.(map[string]any)
+		if !ok {
+			return errors.New("sala em formato inválido")
+		}
+		id, _ := room["id"].(string)
+		members, _ := room["user_ids"].([]any)
+		room["name"] = "Sala " + id
+		room["owner_id"] = ""
+		if len(members) > 0 {
+			room["owner_id"] = members[0]
+		}
+		room["capacity"] = domain.RoomCapacity
+		room["status"] = domain.RoomStatusWaiting
+		if len(members) >= domain.RoomCapacity {
+			room["status"] = domain.RoomStatusPlaying
+		}
+		room["created_at"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	return nil
+}
+
+// migrateRoomHost (v2 → v3) torna o dono de cada sala seu anfitrião, ou o primeiro jogador
+// caso o dono não esteja mais nela, e inicia as salas destrancadas e sem expulsões.
+func migrateRoomHost(data map[string]any) error {
+	rooms, _ := data["rooms"].([]any)
+	for _, item := range rooms {
+		room, ok := item.(map[string]any)
+		if !ok {
+			return errors.New("sala em formato inválido")
+		}
+		owner, _ := room["owner_id"].(string)
+		members, _ := room["user_ids"].([]any)
+		room["host_id"] = ""
+		for _, member := range members {
+			if member == owner {
+				room["host_id"] = owner
+				break
+			}
+		}
+		if room["host_id"] == "" && len(members) > 0 {
+			room["host_id"] = members[0]
+		}
+		room["locked"] = false
+		room["bans"] = map[string]any{}
+	}
+	return nil
+}
+
+// migrateReadyCheck (v3 → v4) leva as salas cheias ao ready check, já que antes as partidas
+// começavam sem confirmação e não tinham placar a ser retomado.
+func migrateReadyCheck(data map[string]any) error {
+	rooms, _ := data["rooms"].([]any)
+	for _, item := range rooms {
+		room, ok := item.(map[string]any)
+		if !ok {
+			return errors.New("sala em formato inválido")
+		}
+		if room["status"] == domain.RoomStatusPlaying {
+			room["status"] = domain.RoomStatusReadyCheck
+		}
+		room["ready"] = []any{}
+	}
+	return nil
+}
+
+// migrateRoomSettings (v4 → v5) completa as salas com as opções que chegaram sem mudança de
+// versão: salas públicas e sem espectadores, regras clássicas, sem baralhos escolhidos, sem
+// pedidos de revanche, sem apostas e com jogadas abertas.
+func migrateRoomSettings(data map[string]any) error {
+	rooms, _ := data["rooms"].([]any)
+	defaults := map[string]func() any{
+		"private":          func() any { return false },
+		"spectators":       func() any { return []any{} },
+		"rematch_requests": func() any { return []any{} },
+		"ruleset_id":       func() any { return domain.DefaultRulesetID },
+		"deck_choices":     func() any { return map[string]any{} },
+		"commit_reveal":    func() any { return false },
+		"stakes":           func() any { return map[string]any{} },
+	}
+	for _, item := range rooms {
+		room, ok := item.(map[string]any)
+		if !ok {
+			return errors.New("sala em formato inválido")
+		}
+		for field, value := range defaults {
+			if current, exists := room[field]; !exists || current == nil || current == "" {
+				room[field] = value()
+			}
+		}
+	}
+	return nil
+}
+
+// migratePackOpenings (v5 → v6) registra que os pacotes abertos antes da v6 não guardaram as
+// tabelas de sorteio nem o contador da garantia: o hash fica vazio, e esses pacotes deixam de ser
+// recalculados, já que as tabelas atuais podem não ser as do sorteio.
+func migratePackOpenings(data map[string]any) error {
+	openings, _ := data["pack_openings"].([]any)
+	for _, item := range openings {
+		opening, ok := item.(map[string]any)
+		if !ok {
+			return errors.New("pacote aberto em formato inválido")
+		}
+		if _, exists := opening["table_hash"]; !exists {
+			opening["table_hash"] = ""
+		}
+		if _, exists := opening["pity_opened"]; !exists {
+			opening["pity_opened"] = 0
+		}
+	}
+	return nil
+}
+
+// migrateSettledMatches (v6 → v7) marca como acertadas as partidas que já não estavam em
+// andamento, cujos prêmios e apostas foram pagos antes de o acerto ser registrado.
+func migrateSettledMatches(data map[string]any) error {
+	status := make(map[string]any)
+	rooms, _ := data["rooms"].([]any)
+	for _, item := range rooms {
+		if room, ok := item.(map[string]any); ok {
+			if id, ok := room["id"].(string); ok {
+				status[id] = room["status"]
+			}
+		}
+	}
+	games, _ := data["games"].([]any)
+	for _, item := range games {
+		game, ok := item.(map[string]any)
+		if !ok {
+			return errors.New("partida em formato inválido")
+		}
+		id, _ := game["id"].(string)
+		game["settled"] = status[id] != domain.RoomStatusPlaying
+	}
+	return nil
+}
+
+// roomRuleset retorna o conjunto de regras da sala, considerando o padrão para salas
+// anteriores às regras configuráveis.
+func roomRuleset(room domain.Room) string {
+	if room.RulesetID == "" {
+		return domain.DefaultRulesetID
+	}
+	return room.RulesetID
+}
+
+// verifyCard confere se a carta possui tipo, estrelas e habilidade válidos nas regras informadas.
+func verifyCard(card domain.Card, ruleset domain.Ruleset) error {
+	if !ruleset.HasCard(card.Type) {
+		return fmt.Errorf("tipo de carta inválido: %q", card.Type)
+	}
+	if _, exists := ruleset.Ability(card.Ability); card.Ability != "" && !exists {
+		return fmt.Errorf("habilidade de carta inválida: %q", card.Ability)
+	}
+	if card.Stars < 1 || card.Stars > domain.MaxStars {
+		return fmt.Errorf("quantidade de estrelas inválida: %d", card.Stars)
+	}
+	if !domain.ValidRarity(card.Rarity) {
+		return fmt.Errorf("raridade de carta inválida: %q", card.Rarity)
+	}
+	return nil
+}
+
+// verifyCollection confere as cartas da coleção de um usuário e se os baralhos salvos usam
+// apenas cartas da coleção.
+func verifyCollection(user domain.User, ruleset domain.Ruleset) []error {
+	var problems []error
+	owned := make(map[string]bool, len(user.Collection))
+	for _, card := range user.Collection {
+		if card.ID == "" || owned[card.ID] {
+			problems = append(problems, fmt.Errorf("usuário %s com carta sem ID ou repetida: %q", user.ID, card.ID))
+		}
+		owned[card.ID] = true
+		if err := verifyCard(card.Card, ruleset); err != nil {
+			problems = append(problems, fmt.Errorf("coleção de %s: %w", user.ID, err))
+		}
+	}
+	for name, cardIDs := range user.Decks {
+		for _, cardID := range cardIDs {
+			if !owned[cardID] {
+				problems = append(problems, fmt.Errorf("baralho %s de %s usa carta fora da coleção: %s", name, user.ID, cardID))
+			}
+		}
+	}
+	return problems
+}
+
+// checksum calcula o hash SHA-256 da forma compacta do JSON informado.
+func checksum(raw []byte) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		compact.Reset()
+		compact.Write(raw)
+	}
+	sum := sha256.Sum256(compact.Bytes())
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,214 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"server-of-hope/internal/domain"
+	"strings"
+	"testing"
+)
+
+// envelope monta um backup da versão informada com o checksum correto.
+func envelope(t *testing.T, version int, data string) *bytes.Buffer {
+	t.Helper()
+	raw, err := json.Marshal(Archive{SchemaVersion: version, Checksum: checksum([]byte(data)), Data: json.RawMessage(data)})
+	if err != nil {
+		t.Fatalf("montar backup: %v", err)
+	}
+	return bytes.NewBuffer(raw)
+}
+
+// findRoom retorna a sala com o ID informado.
+func findRoom(t *testing.T, archiveData ArchiveData, id string) domain.Room {
+	t.Helper()
+	for _, room := range archiveData.Rooms {
+		if room.ID == id {
+			return room
+		}
+	}
+	t.Fatalf("sala %s não encontrada", id)
+	return domain.Room{}
+}
+
+func TestReadArchiveMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		data    string
+		check   func(t *testing.T, archiveData ArchiveData)
+	}{
+		{
+			name:    "v1 completa as salas e acerta as partidas",
+			version: 1,
+			data: `{
+				"rooms": [{"id": "7", "user_ids": ["alice", "bob"]}, {"id": "8", "user_ids": ["carol"]}],
+				"games": [{"id": "7"}],
+				"pack_openings": [{"id": "1", "user_id": "alice"}]
+			}`,
+			check: func(t *testing.T, archiveData ArchiveData) {
+				full := findRoom(t, archiveData, "7")
+				if full.Name != "Sala 7" || full.OwnerID != "alice" || full.HostID != "alice" {
+					t.Errorf("sala cheia = %q, dono %q, anfitrião %q", full.Name, full.OwnerID, full.HostID)
+				}
+				// A partida começava sem confirmação, então a sala cheia volta ao ready check.
+				if full.Status != domain.RoomStatusReadyCheck || full.Capacity != domain.RoomCapacity {
+					t.Errorf("sala cheia com estado %s e capacidade %d", full.Status, full.Capacity)
+				}
+				if full.RulesetID != domain.DefaultRulesetID || full.Private || full.CommitReveal {
+					t.Errorf("sala cheia com regras %q, privada %v, jogadas fechadas %v", full.RulesetID, full.Private, full.CommitReveal)
+				}
+				if full.Ready == nil || full.Spectators == nil || full.Bans == nil || full.DeckChoices == nil || full.Stakes == nil || full.RematchRequests == nil {
+					t.Error("sala cheia com coleções vazias não criadas")
+				}
+				if waiting := findRoom(t, archiveData, "8"); waiting.Status != domain.RoomStatusWaiting || waiting.HostID != "carol" {
+					t.Errorf("sala incompleta com estado %s e anfitrião %q", waiting.Status, waiting.HostID)
+				}
+				if !archiveData.Games[0].Settled {
+					t.Error("partida de sala fora de jogo não foi marcada como acertada")
+				}
+				if opening := archiveData.PackOpenings[0]; opening.TableHash != "" || opening.PityOpened != 0 {
+					t.Errorf("pacote aberto com hash %q e contador %d", opening.TableHash, opening.PityOpened)
+				}
+			},
+		},
+		{
+			name:    "v3 mantém o anfitrião e leva a sala cheia ao ready check",
+			version: 3,
+			data:    `{"rooms": [{"id": "7", "owner_id": "alice", "host_id": "bob", "status": "playing", "user_ids": ["alice", "bob"]}]}`,
+			check: func(t *testing.T, archiveData ArchiveData) {
+				room := findRoom(t, archiveData, "7")
+				if room.HostID != "bob" || room.Status != domain.RoomStatusReadyCheck {
+					t.Errorf("sala com anfitrião %q e estado %s", room.HostID, room.Status)
+				}
+			},
+		},
+		{
+			name:    "v4 preenche as opções sem apagar as gravadas",
+			version: 4,
+			data:    `{"rooms": [{"id": "7", "status": "waiting", "private": true, "ruleset_id": "", "commit_reveal": true, "user_ids": ["alice"]}]}`,
+			check: func(t *testing.T, archiveData ArchiveData) {
+				room := findRoom(t, archiveData, "7")
+				if !room.Private || !room.CommitReveal || room.RulesetID != domain.DefaultRulesetID {
+					t.Errorf("sala privada %v, jogadas fechadas %v, regras %q", room.Private, room.CommitReveal, room.RulesetID)
+				}
+				if room.Spectators == nil || room.Stakes == nil {
+					t.Error("opções ausentes não foram preenchidas")
+				}
+			},
+		},
+		{
+			name:    "v5 mantém o hash já gravado",
+			version: 5,
+			data:    `{"pack_openings": [{"id": "1", "table_hash": "abc", "pity_opened": 3}, {"id": "2"}]}`,
+			check: func(t *testing.T, archiveData ArchiveData) {
+				if first := archiveData.PackOpenings[0]; first.TableHash != "abc" || first.PityOpened != 3 {
+					t.Errorf("pacote gravado com hash %q e contador %d", first.TableHash, first.PityOpened)
+				}
+				if second := archiveData.PackOpenings[1]; second.TableHash != "" || second.PityOpened != 0 {
+					t.Errorf("pacote antigo com hash %q e contador %d", second.TableHash, second.PityOpened)
+				}
+			},
+		},
+		{
+			name:    "v6 deixa em aberto o acerto das partidas em andamento",
+			version: 6,
+			data: `{
+				"rooms": [{"id": "7", "status": "playing"}, {"id": "8", "status": "finished"}],
+				"games": [{"id": "7"}, {"id": "8"}]
+			}`,
+			check: func(t *testing.T, archiveData ArchiveData) {
+				if archiveData.Games[0].Settled || !archiveData.Games[1].Settled {
+					t.Errorf("partidas acertadas: em andamento %v, encerrada %v", archiveData.Games[0].Settled, archiveData.Games[1].Settled)
+				}
+			},
+		},
+		{
+			name:    "versão atual não é migrada",
+			version: SchemaVersion,
+			data:    `{"games": [{"id": "7", "settled": false}]}`,
+			check: func(t *testing.T, archiveData ArchiveData) {
+				if archiveData.Games[0].Settled {
+					t.Error("partida da versão atual foi alterada")
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			archiveData, version, err := ReadArchive(envelope(t, test.version, test.data))
+			if err != nil {
+				t.Fatalf("ReadArchive: %v", err)
+			}
+			if version != test.version {
+				t.Errorf("versão lida = %d, esperado %d", version, test.version)
+			}
+			test.check(t, archiveData)
+		})
+	}
+}
+
+func TestReadArchiveRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive func(t *testing.T) *bytes.Buffer
+		wantErr string
+	}{
+		{
+			name: "checksum não confere",
+			archive: func(t *testing.T) *bytes.Buffer {
+				raw, _ := json.Marshal(Archive{SchemaVersion: SchemaVersion, Checksum: checksum([]byte(`{"users":[]}`)), Data: json.RawMessage(`{"users":[{"id":"alice"}]}`)})
+				return bytes.NewBuffer(raw)
+			},
+			wantErr: "checksum não confere",
+		},
+		{
+			name:    "versão zero",
+			archive: func(t *testing.T) *bytes.Buffer { return envelope(t, 0, `{}`) },
+			wantErr: "versão de esquema não suportada",
+		},
+		{
+			name:    "versão futura",
+			archive: func(t *testing.T) *bytes.Buffer { return envelope(t, SchemaVersion+1, `{}`) },
+			wantErr: "versão de esquema não suportada",
+		},
+		{
+			name:    "sala em formato inválido",
+			archive: func(t *testing.T) *bytes.Buffer { return envelope(t, 1, `{"rooms": ["7"]}`) },
+			wantErr: "falha ao migrar da versão 1",
+		},
+		{
+			name:    "envelope inválido",
+			archive: func(t *testing.T) *bytes.Buffer { return bytes.NewBufferString("backup") },
+			wantErr: "backup inválido",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := ReadArchive(test.archive(t))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("erro = %v, esperado %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteArchiveRoundTrip(t *testing.T) {
+	written := ArchiveData{
+		Users:  []domain.User{{ID: "alice", Username: "alice"}},
+		Ledger: []domain.LedgerEntry{{Seq: 1, UserID: "alice", Amount: 100, Balance: 100, Reason: domain.LedgerStarter}},
+	}
+	var buffer bytes.Buffer
+	if err := WriteArchive(&buffer, written); err != nil {
+		t.Fatalf("WriteArchive: %v", err)
+	}
+	read, version, err := ReadArchive(&buffer)
+	if err != nil {
+		t.Fatalf("ReadArchive: %v", err)
+	}
+	if version != SchemaVersion {
+		t.Errorf("versão = %d, esperado %d", version, SchemaVersion)
+	}
+	if len(read.Users) != 1 || read.Users[0].ID != "alice" || len(read.Ledger) != 1 || read.Ledger[0].Balance != 100 {
+		t.Errorf("dados lidos = %+v", read)
+	}
+}
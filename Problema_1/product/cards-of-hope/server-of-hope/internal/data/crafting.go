@@ -0,0 +1,29 @@
+package data
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"server-of-hope/internal/domain"
+)
+
+// craftingFile contém as receitas de criação e os valores em pó embutidos no servidor.
+//
+//go:embed crafting.json
+var craftingFile []byte
+
+// LoadCraftingRules lê e valida as receitas de criação embutidas no servidor.
+//
+// Retorno:
+//   - domain.CraftingRules: receitas e valores em pó das cartas.
+//   - erro caso o arquivo seja inválido.
+func LoadCraftingRules() (domain.CraftingRules, error) {
+	var rules domain.CraftingRules
+	if err := json.Unmarshal(craftingFile, &rules); err != nil {
+		return domain.CraftingRules{}, fmt.Errorf("crafting.json: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return domain.CraftingRules{}, fmt.Errorf("crafting.json: %w", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,57 @@
+package data
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"server-of-hope/internal/domain"
+	"sort"
+)
+
+// packFiles contém os tipos de pacote da loja embutidos no servidor, um arquivo JSON por tipo.
+//
+//go:embed packs/*.json
+var packFiles embed.FS
+
+// LoadPackTypes lê e valida os tipos de pacote embutidos no servidor.
+//
+// Parâmetros:
+//   - ruleset: regras padrão, de onde vêm os tipos de carta e as habilidades dos pacotes.
+//
+// Retorno:
+//   - []domain.PackType: tipos de pacote ordenados pelo preço.
+//   - erro caso algum arquivo seja inválido ou o pacote padrão não exista.
+func LoadPackTypes(ruleset domain.Ruleset) ([]domain.PackType, error) {
+	files, err := packFiles.ReadDir("packs")
+	if err != nil {
+		return nil, err
+	}
+
+	packs := make([]domain.PackType, 0, len(files))
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		raw, err := packFiles.ReadFile(path.Join("packs", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var pack domain.PackType
+		if err := json.Unmarshal(raw, &pack); err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		if err := pack.Validate(ruleset); err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		if seen[pack.ID] {
+			return nil, fmt.Errorf("%s: tipo de pacote duplicado: %s", file.Name(), pack.ID)
+		}
+		seen[pack.ID] = true
+		packs = append(packs, pack)
+	}
+	if !seen[domain.DefaultPackID] {
+		return nil, fmt.Errorf("tipo de pacote padrão não encontrado: %s", domain.DefaultPackID)
+	}
+
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Price < packs[j].Price })
+	return packs, nil
+}
@@ -0,0 +1,30 @@
+package data
+
+import (
+	"server-of-hope/internal/domain"
+	"testing"
+)
+
+func TestLoadPackTypes(t *testing.T) {
+	rulesets, err := LoadRulesets()
+	if err != nil {
+		t.Fatalf("LoadRulesets: %v", err)
+	}
+	packs, err := LoadPackTypes(rulesets[0])
+	if err != nil {
+		t.Fatalf("LoadPackTypes: %v", err)
+	}
+	if len(packs) != 2 || packs[0].ID != domain.DefaultPackID || packs[1].ID != "premium" || packs[0].Price >= packs[1].Price {
+		t.Fatalf("pacotes = %+v, esperado basic e premium do mais barato ao mais caro", packs)
+	}
+	if pity := packs[1].Pity; pity.Rarity != domain.RarityLegendary || pity.Packs != 20 {
+		t.Errorf("garantia do premium = %+v", pity)
+	}
+
+	// Os pacotes usam as habilidades das regras padrão; sem elas, as tabelas não são aceitas.
+	withoutAbilities := rulesets[0]
+	withoutAbilities.Abilities = nil
+	if _, err := LoadPackTypes(withoutAbilities); err == nil {
+		t.Error("pacotes com habilidades aceitos por regras sem habilidades")
+	}
+}
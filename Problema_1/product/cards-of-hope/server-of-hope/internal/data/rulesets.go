@@ -0,0 +1,54 @@
+package data
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"server-of-hope/internal/domain"
+	"sort"
+)
+
+// rulesetFiles contém os conjuntos de regras embutidos no servidor, um arquivo JSON por conjunto.
+//
+//go:embed rulesets/*.json
+var rulesetFiles embed.FS
+
+// LoadRulesets lê e valida os conjuntos de regras embutidos no servidor.
+//
+// Retorno:
+//   - []domain.Ruleset: conjuntos de regras ordenados pelo ID.
+//   - erro caso algum arquivo seja inválido ou o conjunto padrão não exista.
+func LoadRulesets() ([]domain.Ruleset, error) {
+	files, err := rulesetFiles.ReadDir("rulesets")
+	if err != nil {
+		return nil, err
+	}
+
+	rulesets := make([]domain.Ruleset, 0, len(files))
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		raw, err := rulesetFiles.ReadFile(path.Join("rulesets", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var ruleset domain.Ruleset
+		if err := json.Unmarshal(raw, &ruleset); err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		if err := ruleset.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		if seen[ruleset.ID] {
+			return nil, fmt.Errorf("%s: conjunto de regras duplicado: %s", file.Name(), ruleset.ID)
+		}
+		seen[ruleset.ID] = true
+		rulesets = append(rulesets, ruleset)
+	}
+	if !seen[domain.DefaultRulesetID] {
+		return nil, fmt.Errorf("conjunto de regras padrão não encontrado: %s", domain.DefaultRulesetID)
+	}
+
+	sort.Slice(rulesets, func(i, j int) bool { return rulesets[i].ID < rulesets[j].ID })
+	return rulesets, nil
+}
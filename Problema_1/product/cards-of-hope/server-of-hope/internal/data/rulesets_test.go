@@ -0,0 +1,51 @@
+package data
+
+import (
+	"server-of-hope/internal/domain"
+	"testing"
+)
+
+func TestLoadRulesets(t *testing.T) {
+	rulesets, err := LoadRulesets()
+	if err != nil {
+		t.Fatalf("LoadRulesets: %v", err)
+	}
+	want := []struct {
+		id            string
+		cards         int
+		starTieBreak  string
+		winningRounds int
+		maxRounds     int
+	}{
+		{id: domain.DefaultRulesetID, cards: 3, starTieBreak: domain.StarTieBreakHigher, winningRounds: 2},
+		{id: "rpsls", cards: 5, starTieBreak: domain.StarTieBreakHigher, winningRounds: 3, maxRounds: 9},
+		{id: "underdog", cards: 3, starTieBreak: domain.StarTieBreakLower, winningRounds: 2, maxRounds: 5},
+	}
+	if len(rulesets) != len(want) {
+		t.Fatalf("%d conjuntos de regras, esperado %d", len(rulesets), len(want))
+	}
+	for index, ruleset := range rulesets {
+		expected := want[index]
+		if ruleset.ID != expected.id || len(ruleset.Cards) != expected.cards || ruleset.StarTieBreak != expected.starTieBreak {
+			t.Errorf("regras %d = %q com %d tipos e desempate %q", index, ruleset.ID, len(ruleset.Cards), ruleset.StarTieBreak)
+		}
+		if ruleset.WinningRounds != expected.winningRounds || ruleset.MaxRounds != expected.maxRounds {
+			t.Errorf("regras %q vencem com %d de %d rodadas", ruleset.ID, ruleset.WinningRounds, ruleset.MaxRounds)
+		}
+		// Em cada variante, todo tipo vence e perde para a mesma quantidade de tipos.
+		for _, cardType := range ruleset.Cards {
+			beaten, beatenBy := 0, 0
+			for _, other := range ruleset.Cards {
+				if ruleset.Defeats(cardType, other) {
+					beaten++
+				}
+				if ruleset.Defeats(other, cardType) {
+					beatenBy++
+				}
+			}
+			if beaten != beatenBy {
+				t.Errorf("regras %q: %s vence %d tipos e perde para %d", ruleset.ID, cardType, beaten, beatenBy)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Efeitos que uma habilidade pode ter. Os efeitos são implementados pelo servidor; os conjuntos
+// de regras declaram quais habilidades existem, com nome, prioridade e parâmetros.
+const (
+	EffectSwapStars  = "swap_stars"  // troca as estrelas da carta com as da carta do oponente
+	EffectBoostStars = "boost_stars" // soma Amount estrelas à própria carta
+	EffectWinTies    = "win_ties"    // vence a rodada caso ela termine empatada
+	EffectRevealNext = "reveal_next" // revela a carta do oponente assim que ele jogar na próxima rodada
+)
+
+// Fases da resolução de uma rodada, na ordem em que acontecem: efeitos que alteram as cartas
+// antes da comparação, efeitos que decidem empates e efeitos que valem para a rodada seguinte.
+const (
+	PhaseBeforeCompare = iota
+	PhaseTie
+	PhaseAfterRound
+)
+
+// effectPhases associa cada efeito à fase da rodada em que ele é aplicado.
+var effectPhases = map[string]int{
+	EffectSwapStars:  PhaseBeforeCompare,
+	EffectBoostStars: PhaseBeforeCompare,
+	EffectWinTies:    PhaseTie,
+	EffectRevealNext: PhaseAfterRound,
+}
+
+// Ability descreve uma habilidade especial que uma carta pode carregar.
+//
+// Campos:
+//   - ID: identificador da habilidade, referenciado pelas cartas.
+//   - Name: nome de exibição.
+//   - Description: explicação da habilidade para os jogadores.
+//   - Effect: efeito aplicado (swap_stars, boost_stars, win_ties ou reveal_next).
+//   - Priority: ordem de aplicação dentro da mesma fase; valores menores são aplicados antes.
+//   - Amount: intensidade do efeito, usada por boost_stars.
+type Ability struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Effect      string `json:"effect"`
+	Priority    int    `json:"priority"`
+	Amount      int    `json:"amount,omitempty"`
+}
+
+// Validate confere se a habilidade tem ID e um efeito conhecido com parâmetros válidos.
+func (ability Ability) Validate() error {
+	if ability.ID == "" {
+		return errors.New("habilidade sem ID")
+	}
+	if _, exists := effectPhases[ability.Effect]; !exists {
+		return fmt.Errorf("habilidade %q com efeito desconhecido: %q", ability.ID, ability.Effect)
+	}
+	if ability.Effect == EffectBoostStars && ability.Amount < 1 {
+		return fmt.Errorf("habilidade %q precisa de amount positivo", ability.ID)
+	}
+	return nil
+}
+
+// AppliedEffect registra uma habilidade que teve efeito na rodada.
+//
+// Campos:
+//   - UserID: jogador dono da carta com a habilidade.
+//   - Ability: ID da habilidade.
+//   - Effect: efeito aplicado.
+//   - Stars: estrelas das cartas de cada jogador logo após o efeito.
+type AppliedEffect struct {
+	UserID  string         `json:"user_id"`
+	Ability string         `json:"ability"`
+	Effect  string         `json:"effect"`
+	Stars   map[string]int `json:"stars"`
+}
+
+// Duel descreve o confronto das duas cartas de uma rodada depois de aplicadas as habilidades.
+//
+// Campos:
+//   - PlayerIDs: jogadores na ordem em que as cartas foram informadas.
+//   - Cards: cartas de cada jogador após os efeitos que alteram estrelas.
+//   - Winner: índice do vencedor em PlayerIDs, ou -1 em caso de empate.
+//   - Effects: habilidades aplicadas, na ordem de aplicação.
+//   - Reveals: jogadores que verão a carta do oponente na próxima rodada.
+type Duel struct {
+	PlayerIDs [2]string
+	Cards     [2]Card
+	Winner    int
+	Effects   []AppliedEffect
+	Reveals   []string
+}
+
+// pendingAbility é uma habilidade aguardando aplicação, com o índice do dono da carta.
+type pendingAbility struct {
+	owner   int
+	ability Ability
+}
+
+// Duel resolve o confronto entre duas cartas: as habilidades são aplicadas por fase, depois
+// por prioridade e, em caso de empate de prioridade, pela ordem dos jogadores.
+//
+// Parâmetros:
+//   - playerIDs: jogadores na ordem das cartas.
+//   - cards: cartas jogadas por cada jogador.
+//
+// Retorno:
+//   - Duel: cartas após os efeitos, vencedor e habilidades aplicadas.
+func (ruleset Ruleset) Duel(playerIDs [2]string, cards [2]Card) Duel {
+	duel := Duel{PlayerIDs: playerIDs, Cards: cards, Winner: -1}
+
+	var pending []pendingAbility
+	for owner, card := range cards {
+		if ability, exists := ruleset.Ability(card.Ability); exists {
+			pending = append(pending, pendingAbility{owner: owner, ability: ability})
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		first, second := pending[i].ability, pending[j].ability
+		if effectPhases[first.Effect] != effectPhases[second.Effect] {
+			return effectPhases[first.Effect] < effectPhases[second.Effect]
+		}
+		return first.Priority < second.Priority
+	})
+
+	for _, item := range pending {
+		if effectPhases[item.ability.Effect] != PhaseBeforeCompare {
+			continue
+		}
+		switch item.ability.Effect {
+		case EffectSwapStars:
+			duel.Cards[0].Stars, duel.Cards[1].Stars = duel.Cards[1].Stars, duel.Cards[0].Stars
+		case EffectBoostStars:
+			duel.Cards[item.owner].Stars += item.ability.Amount
+		}
+		duel.Effects = append(duel.Effects, duel.applied(item))
+	}
+
+	switch ruleset.Compare(duel.Cards[0], duel.Cards[1]) {
+	case 1:
+		duel.Winner = 0
+	case -1:
+		duel.Winner = 1
+	}
+
+	// Um empate só é desfeito se apenas um dos jogadores tiver uma habilidade de desempate.
+	var tieBreakers []pendingAbility
+	owners := [2]bool{}
+	for _, item := range pending {
+		if effectPhases[item.ability.Effect] == PhaseTie && !owners[item.owner] {
+			tieBreakers = append(tieBreakers, item)
+			owners[item.owner] = true
+		}
+	}
+	if duel.Winner == -1 && len(tieBreakers) == 1 {
+		duel.Winner = tieBreakers[0].owner
+		duel.Effects = append(duel.Effects, duel.applied(tieBreakers[0]))
+	}
+
+	for _, item := range pending {
+		if effectPhases[item.ability.Effect] == PhaseAfterRound {
+			duel.Reveals = append(duel.Reveals, playerIDs[item.owner])
+			duel.Effects = append(duel.Effects, duel.applied(item))
+		}
+	}
+	return duel
+}
+
+// applied registra a habilidade aplicada com as estrelas atuais das duas cartas.
+func (duel Duel) applied(item pendingAbility) AppliedEffect {
+	return AppliedEffect{
+		UserID:  duel.PlayerIDs[item.owner],
+		Ability: item.ability.ID,
+		Effect:  item.ability.Effect,
+		Stars: map[string]int{
+			duel.PlayerIDs[0]: duel.Cards[0].Stars,
+			duel.PlayerIDs[1]: duel.Cards[1].Stars,
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package domain
+
+import "testing"
+
+func TestDuel(t *testing.T) {
+	ruleset := classicRuleset(StarTieBreakHigher)
+	ruleset.Abilities = []Ability{
+		{ID: "mirror", Effect: EffectSwapStars, Priority: 10},
+		{ID: "rally", Effect: EffectBoostStars, Priority: 20, Amount: 2},
+		{ID: "stubborn", Effect: EffectWinTies},
+		{ID: "scout", Effect: EffectRevealNext},
+	}
+	tests := []struct {
+		name        string
+		cards       [2]Card
+		wantWinner  int
+		wantStars   [2]int
+		wantEffects []string
+		wantReveals []string
+	}{
+		{
+			name:       "sem habilidades",
+			cards:      [2]Card{{Type: "rock", Stars: 1}, {Type: "rock", Stars: 2}},
+			wantWinner: 1,
+			wantStars:  [2]int{1, 2},
+		},
+		{
+			name:        "espelho troca as estrelas",
+			cards:       [2]Card{{Type: "rock", Stars: 3, Ability: "mirror"}, {Type: "rock", Stars: 1}},
+			wantWinner:  1,
+			wantStars:   [2]int{1, 3},
+			wantEffects: []string{"alice:mirror"},
+		},
+		{
+			name:        "reforço soma estrelas",
+			cards:       [2]Card{{Type: "rock", Stars: 1, Ability: "rally"}, {Type: "rock", Stars: 2}},
+			wantWinner:  0,
+			wantStars:   [2]int{3, 2},
+			wantEffects: []string{"alice:rally"},
+		},
+		{
+			name:        "a troca vem antes do reforço",
+			cards:       [2]Card{{Type: "rock", Stars: 1, Ability: "rally"}, {Type: "rock", Stars: 4, Ability: "mirror"}},
+			wantWinner:  0,
+			wantStars:   [2]int{6, 1},
+			wantEffects: []string{"bob:mirror", "alice:rally"},
+		},
+		{
+			name:        "o tipo ainda decide antes das estrelas",
+			cards:       [2]Card{{Type: "scissors", Stars: 1, Ability: "rally"}, {Type: "rock", Stars: 1}},
+			wantWinner:  1,
+			wantStars:   [2]int{3, 1},
+			wantEffects: []string{"alice:rally"},
+		},
+		{
+			name:        "teimosia desfaz o empate",
+			cards:       [2]Card{{Type: "paper", Stars: 2}, {Type: "paper", Stars: 2, Ability: "stubborn"}},
+			wantWinner:  1,
+			wantStars:   [2]int{2, 2},
+			wantEffects: []string{"bob:stubborn"},
+		},
+		{
+			name:       "teimosia dos dois mantém o empate",
+			cards:      [2]Card{{Type: "paper", Stars: 2, Ability: "stubborn"}, {Type: "paper", Stars: 2, Ability: "stubborn"}},
+			wantWinner: -1,
+			wantStars:  [2]int{2, 2},
+		},
+		{
+			name:       "teimosia não vale fora do empate",
+			cards:      [2]Card{{Type: "paper", Stars: 1, Ability: "stubborn"}, {Type: "scissors", Stars: 1}},
+			wantWinner: 1,
+			wantStars:  [2]int{1, 1},
+		},
+		{
+			name:        "espião revela a próxima carta",
+			cards:       [2]Card{{Type: "rock", Stars: 1, Ability: "scout"}, {Type: "scissors", Stars: 1}},
+			wantWinner:  0,
+			wantStars:   [2]int{1, 1},
+			wantEffects: []string{"alice:scout"},
+			wantReveals: []string{"alice"},
+		},
+		{
+			name:       "habilidade fora das regras é ignorada",
+			cards:      [2]Card{{Type: "rock", Stars: 1, Ability: "fireball"}, {Type: "rock", Stars: 2}},
+			wantWinner: 1,
+			wantStars:  [2]int{1, 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			duel := ruleset.Duel([2]string{"alice", "bob"}, test.cards)
+			if duel.Winner != test.wantWinner {
+				t.Errorf("vencedor = %d, esperado %d", duel.Winner, test.wantWinner)
+			}
+			if stars := [2]int{duel.Cards[0].Stars, duel.Cards[1].Stars}; stars != test.wantStars {
+				t.Errorf("estrelas = %v, esperado %v", stars, test.wantStars)
+			}
+			effects := make([]string, 0, len(duel.Effects))
+			for _, effect := range duel.Effects {
+				effects = append(effects, effect.UserID+":"+effect.Ability)
+			}
+			if len(effects) != len(test.wantEffects) {
+				t.Fatalf("efeitos = %v, esperado %v", effects, test.wantEffects)
+			}
+			for index := range effects {
+				if effects[index] != test.wantEffects[index] {
+					t.Errorf("efeitos = %v, esperado %v", effects, test.wantEffects)
+				}
+			}
+			if len(duel.Reveals) != len(test.wantReveals) || (len(duel.Reveals) > 0 && duel.Reveals[0] != test.wantReveals[0]) {
+				t.Errorf("revelações = %v, esperado %v", duel.Reveals, test.wantReveals)
+			}
+		})
+	}
+}
+
+func TestAbilityValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ability Ability
+		wantErr bool
+	}{
+		{name: "troca de estrelas", ability: Ability{ID: "mirror", Effect: EffectSwapStars}},
+		{name: "reforço com quantidade", ability: Ability{ID: "rally", Effect: EffectBoostStars, Amount: 1}},
+		{name: "reforço sem quantidade", ability: Ability{ID: "rally", Effect: EffectBoostStars}, wantErr: true},
+		{name: "sem ID", ability: Ability{Effect: EffectWinTies}, wantErr: true},
+		{name: "efeito desconhecido", ability: Ability{ID: "fireball", Effect: "burn"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.ability.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate = %v, esperado erro: %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// BotIDPrefix é o prefixo reservado dos IDs dos bots; nenhum usuário pode se registrar com ele.
+const BotIDPrefix = "bot:"
+
+// Dificuldades dos bots. Cada uma usa uma estratégia de escolha de cartas.
+const (
+	BotEasy   = "easy"
+	BotMedium = "medium"
+	BotHard   = "hard"
+)
+
+// BotRotationBias define a chance de a estratégia de rotação seguir a previsão em vez de jogar ao acaso.
+const BotRotationBias = 0.75
+
+// BotStrategy escolhe a carta que um bot joga a cada rodada.
+type BotStrategy interface {
+	// Choose escolhe uma carta da mão.
+	//
+	// Parâmetros:
+	//   - hand: cartas na mão do bot (nunca vazia).
+	//   - history: tipos das cartas jogadas pelo oponente nas rodadas anteriores, da mais antiga para a mais nova.
+	//   - ruleset: regras da sala.
+	//
+	// Retorno:
+	//   - OwnedCard: carta escolhida.
+	Choose(hand []OwnedCard, history []string, ruleset Ruleset) OwnedCard
+}
+
+// RandomStrategy joga uma carta qualquer da mão.
+type RandomStrategy struct{}
+
+// FrequencyStrategy prevê que o oponente repetirá o tipo que mais jogou e responde com uma carta que o vence.
+type FrequencyStrategy struct{}
+
+// RotationStrategy prevê que o oponente passará ao tipo seguinte ao último que jogou, na ordem
+// das cartas das regras, e responde com uma carta que o vence. Para não ficar previsível, segue
+// a previsão com chance BotRotationBias e joga ao acaso nas demais vezes.
+type RotationStrategy struct{}
+
+// BotID retorna o ID do bot de uma dificuldade.
+func BotID(difficulty string) string {
+	return BotIDPrefix + difficulty
+}
+
+// IsBotID informa se o ID pertence a um bot.
+func IsBotID(userID string) bool {
+	return strings.HasPrefix(userID, BotIDPrefix)
+}
+
+// NewBotStrategy retorna a estratégia usada pelos bots de uma dificuldade.
+//
+// Parâmetros:
+//   - difficulty: dificuldade do bot (easy, medium ou hard).
+//
+// Retorno:
+//   - BotStrategy: estratégia da dificuldade.
+//   - bool: false se a dificuldade não existe.
+func NewBotStrategy(difficulty string) (BotStrategy, bool) {
+	switch difficulty {
+	case BotEasy:
+		return RandomStrategy{}, true
+	case BotMedium:
+		return RotationStrategy{}, true
+	case BotHard:
+		return FrequencyStrategy{}, true
+	}
+	return nil, false
+}
+
+// Choose escolhe uma carta ao acaso.
+func (RandomStrategy) Choose(hand []OwnedCard, history []string, ruleset Ruleset) OwnedCard {
+	return hand[rand.Intn(len(hand))]
+}
+
+// Choose responde ao tipo mais jogado pelo oponente; sem histórico, joga ao acaso.
+func (FrequencyStrategy) Choose(hand []OwnedCard, history []string, ruleset Ruleset) OwnedCard {
+	counts := make(map[string]int, len(ruleset.Cards))
+	predicted := ""
+	for _, cardType := range history {
+		counts[cardType]++
+		if predicted == "" || counts[cardType] > counts[predicted] {
+			predicted = cardType
+		}
+	}
+	if predicted == "" {
+		return RandomStrategy{}.Choose(hand, history, ruleset)
+	}
+	return counterCard(hand, predicted, ruleset)
+}
+
+// Choose responde ao tipo seguinte ao último jogado pelo oponente; sem histórico, joga ao acaso.
+func (RotationStrategy) Choose(hand []OwnedCard, history []string, ruleset Ruleset) OwnedCard {
+	if len(history) == 0 || len(ruleset.Cards) == 0 || rand.Float64() >= BotRotationBias {
+		return RandomStrategy{}.Choose(hand, history, ruleset)
+	}
+	last := history[len(history)-1]
+	predicted := ruleset.Cards[0]
+	for index, cardType := range ruleset.Cards {
+		if cardType == last {
+			predicted = ruleset.Cards[(index+1)%len(ruleset.Cards)]
+			break
+		}
+	}
+	return counterCard(hand, predicted, ruleset)
+}
+
+// counterCard escolhe a carta da mão com melhor resposta ao tipo previsto: primeiro as que o
+// vencem, depois as que não perdem para ele, sempre preferindo mais estrelas.
+func counterCard(hand []OwnedCard, predicted string, ruleset Ruleset) OwnedCard {
+	score := func(card OwnedCard) int {
+		switch {
+		case ruleset.Defeats(card.Type, predicted):
+			return 2
+		case !ruleset.Defeats(predicted, card.Type):
+			return 1
+		}
+		return 0
+	}
+	best := hand[0]
+	for _, card := range hand[1:] {
+		if score(card) > score(best) || (score(card) == score(best) && card.Stars > best.Stars) {
+			best = card
+		}
+	}
+	return best
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "testing"
+
+// hand monta uma mão com as cartas informadas, usando o tipo e as estrelas como ID.
+func hand(cards ...Card) []OwnedCard {
+	owned := make([]OwnedCard, len(cards))
+	for index, card := range cards {
+		owned[index] = OwnedCard{ID: card.Type + string(rune('0'+card.Stars)), Card: card}
+	}
+	return owned
+}
+
+func TestNewBotStrategy(t *testing.T) {
+	tests := []struct {
+		difficulty string
+		want       BotStrategy
+	}{
+		{difficulty: BotEasy, want: RandomStrategy{}},
+		{difficulty: BotMedium, want: RotationStrategy{}},
+		{difficulty: BotHard, want: FrequencyStrategy{}},
+		{difficulty: "impossible"},
+	}
+	for _, test := range tests {
+		t.Run(test.difficulty, func(t *testing.T) {
+			strategy, ok := NewBotStrategy(test.difficulty)
+			if strategy != test.want || ok != (test.want != nil) {
+				t.Errorf("NewBotStrategy(%q) = %T, %v", test.difficulty, strategy, ok)
+			}
+		})
+	}
+}
+
+func TestFrequencyStrategy(t *testing.T) {
+	ruleset := classicRuleset("")
+	tests := []struct {
+		name    string
+		hand    []OwnedCard
+		history []string
+		want    string
+	}{
+		{name: "vence o tipo mais jogado", hand: hand(Card{Type: "rock", Stars: 1}, Card{Type: "paper", Stars: 1}), history: []string{"rock", "scissors", "rock"}, want: "paper1"},
+		{name: "empate no histórico fica com o primeiro", hand: hand(Card{Type: "scissors", Stars: 1}, Card{Type: "paper", Stars: 1}), history: []string{"rock", "paper"}, want: "paper1"},
+		{name: "prefere mais estrelas", hand: hand(Card{Type: "paper", Stars: 1}, Card{Type: "paper", Stars: 3}), history: []string{"rock"}, want: "paper3"},
+		{name: "sem vitória, evita a derrota", hand: hand(Card{Type: "scissors", Stars: 3}, Card{Type: "rock", Stars: 1}), history: []string{"rock"}, want: "rock1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := (FrequencyStrategy{}).Choose(test.hand, test.history, ruleset); got.ID != test.want {
+				t.Errorf("Choose = %s, esperado %s", got.ID, test.want)
+			}
+		})
+	}
+}
+
+func TestStrategiesChooseFromHand(t *testing.T) {
+	ruleset := classicRuleset("")
+	cards := hand(Card{Type: "rock", Stars: 1}, Card{Type: "scissors", Stars: 2})
+	for _, strategy := range []BotStrategy{RandomStrategy{}, RotationStrategy{}, FrequencyStrategy{}} {
+		for _, history := range [][]string{nil, {"paper"}, {"rock", "lizard"}} {
+			for range 20 {
+				choice := strategy.Choose(cards, history, ruleset)
+				if choice != cards[0] && choice != cards[1] {
+					t.Fatalf("%T escolheu %+v, fora da mão", strategy, choice)
+				}
+			}
+		}
+	}
+}
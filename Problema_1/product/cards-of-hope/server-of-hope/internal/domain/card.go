@@ -3,11 +3,15 @@ package domain
 // Card representa uma carta do jogo.
 //
 // Campos:
-//   - Type: tipo da carta (ex: pedra, papel, tesoura).
+//   - Type: tipo da carta, definido pelo conjunto de regras da sala (ex: pedra, papel, tesoura).
 //   - Stars: quantidade de estrelas da carta.
+//   - Ability: ID da habilidade especial da carta, declarada nas regras da sala (vazio se não tiver).
+//   - Rarity: raridade da carta (common, rare, epic, legendary; vazio conta como comum).
 type Card struct {
-	Type  string `json:"type"`
-	Stars int    `json:"stars"`
+	Type    string `json:"type"`
+	Stars   int    `json:"stars"`
+	Ability string `json:"ability,omitempty"`
+	Rarity  string `json:"rarity,omitempty"`
 }
 
 // CardPackage representa um pacote de três cartas.
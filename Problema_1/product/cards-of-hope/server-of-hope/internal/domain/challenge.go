@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Estados de um desafio. O desafio aguarda a resposta do desafiado (pending) e termina aceito
+// (accepted), recusado (declined), cancelado por quem desafiou (cancelled) ou vencido (expired).
+const (
+	ChallengePending   = "pending"
+	ChallengeAccepted  = "accepted"
+	ChallengeDeclined  = "declined"
+	ChallengeCancelled = "cancelled"
+	ChallengeExpired   = "expired"
+)
+
+// Challenge representa o desafio de um usuário a outro para um duelo em uma sala privada.
+//
+// Campos:
+//   - ID: identificador do desafio.
+//   - FromID: usuário que desafiou.
+//   - ToID: usuário desafiado.
+//   - RulesetID: conjunto de regras da sala do duelo.
+//   - CommitReveal: as jogadas do duelo são feitas com compromisso e revelação.
+//   - Status: estado do desafio (pending, accepted, declined, cancelled, expired).
+//   - RoomID: sala criada para o duelo, preenchida no aceite.
+//   - CreatedAt: momento do desafio.
+//   - ExpiresAt: momento em que o desafio deixa de valer.
+//   - ResolvedAt: momento em que o desafio saiu de pending.
+type Challenge struct {
+	ID           string    `json:"id"`
+	FromID       string    `json:"from_id"`
+	ToID         string    `json:"to_id"`
+	RulesetID    string    `json:"ruleset_id"`
+	CommitReveal bool      `json:"commit_reveal"`
+	Status       string    `json:"status"`
+	RoomID       string    `json:"room_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	ResolvedAt   time.Time `json:"resolved_at"`
+}
+
+// Expired informa se o desafio pendente já passou do prazo.
+func (challenge Challenge) Expired() bool {
+	return challenge.Status == ChallengePending && time.Now().After(challenge.ExpiresAt)
+}
+
+// Involves informa se o usuário desafiou ou foi desafiado.
+func (challenge Challenge) Involves(userID string) bool {
+	return challenge.FromID == userID || challenge.ToID == userID
+}
+
+// Opponent retorna o outro usuário do desafio.
+func (challenge Challenge) Opponent(userID string) string {
+	if challenge.FromID == userID {
+		return challenge.ToID
+	}
+	return challenge.FromID
+}
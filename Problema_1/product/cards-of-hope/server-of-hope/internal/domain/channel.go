@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+// LobbyChannel é o canal global em que todo usuário entra ao fazer login.
+const LobbyChannel = "lobby"
+
+// MaxChannelNameLength define o tamanho máximo do nome de um canal de chat.
+const MaxChannelNameLength = 24
+
+// Channel representa um canal de chat fora das salas: o saguão global ou um canal de assunto
+// criado por um usuário. Os canais ficam só na memória, e os de assunto deixam de existir
+// quando o último membro sai.
+//
+// Campos:
+//   - Name: nome do canal, que também o identifica.
+//   - OwnerID: usuário que criou o canal (vazio no saguão).
+//   - Members: usuários conectados que estão no canal.
+//   - CreatedAt: momento em que o canal foi criado.
+type Channel struct {
+	Name      string             `json:"name"`
+	OwnerID   string             `json:"owner_id,omitempty"`
+	Members   *utils.Set[string] `json:"members"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// NewChannel cria um canal vazio.
+//
+// Parâmetros:
+//   - name: nome do canal.
+//   - ownerID: usuário que criou o canal (vazio no saguão).
+//
+// Retorno:
+//   - ponteiro para o novo canal.
+func NewChannel(name, ownerID string) *Channel {
+	return &Channel{
+		Name:      name,
+		OwnerID:   ownerID,
+		Members:   utils.NewSet[string](),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Permanent informa se o canal continua existindo mesmo sem membros.
+func (channel Channel) Permanent() bool {
+	return channel.Name == LobbyChannel
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// MinNonceLength define o tamanho mínimo do nonce de uma revelação. Com poucas cartas possíveis,
+// um nonce curto permitiria descobrir a carta testando todas as combinações contra o compromisso.
+const MinNonceLength = 16
+
+// MaxRevealAttempts define quantas revelações que não conferem com o compromisso um jogador pode
+// enviar na mesma rodada antes de perder a partida.
+const MaxRevealAttempts = 3
+
+// Commitment calcula o compromisso de uma jogada: o hash SHA-256, em hexadecimal, da referência
+// da carta e do nonce separados por dois-pontos. O cliente envia o compromisso antes de conhecer
+// a jogada do oponente e só revela a carta e o nonce depois que os dois se comprometeram.
+//
+// Parâmetros:
+//   - cardRef: ID ou tipo da carta, exatamente como será revelado.
+//   - nonce: valor aleatório escolhido pelo jogador.
+//
+// Retorno:
+//   - string: compromisso em hexadecimal.
+func Commitment(cardRef, nonce string) string {
+	sum := sha256.Sum256([]byte(cardRef + ":" + nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidCommitment informa se o valor tem o formato de um compromisso: 64 dígitos hexadecimais minúsculos.
+func ValidCommitment(commitment string) bool {
+	if len(commitment) != 2*sha256.Size {
+		return false
+	}
+	for _, digit := range commitment {
+		if (digit < '0' || digit > '9') && (digit < 'a' || digit > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesCommitment informa se a carta e o nonce revelados conferem com o compromisso.
+//
+// Parâmetros:
+//   - commitment: compromisso enviado antes da revelação.
+//   - cardRef: referência da carta revelada.
+//   - nonce: nonce revelado.
+//
+// Retorno:
+//   - bool: true se o hash da revelação é igual ao compromisso.
+func MatchesCommitment(commitment, cardRef, nonce string) bool {
+	expected := Commitment(cardRef, nonce)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(commitment)) == 1
+}
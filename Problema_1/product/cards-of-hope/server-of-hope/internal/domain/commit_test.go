@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommitment(t *testing.T) {
+	// sha256("rock:0123456789abcdef"), calculado fora do servidor.
+	const want = "ab2dbd40f10bb399d8e413680c0544f845e505ba1b62402bdad4b4505db92942"
+	if got := Commitment("rock", "0123456789abcdef"); got != want {
+		t.Errorf("Commitment = %s, esperado %s", got, want)
+	}
+}
+
+func TestValidCommitment(t *testing.T) {
+	valid := Commitment("rock", "0123456789abcdef")
+	tests := []struct {
+		name       string
+		commitment string
+		want       bool
+	}{
+		{name: "hash em hexadecimal", commitment: valid, want: true},
+		{name: "maiúsculas", commitment: strings.ToUpper(valid)},
+		{name: "curto", commitment: valid[:63]},
+		{name: "longo", commitment: valid + "0"},
+		{name: "fora do hexadecimal", commitment: "g" + valid[1:]},
+		{name: "vazio"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ValidCommitment(test.commitment); got != test.want {
+				t.Errorf("ValidCommitment(%q) = %v, esperado %v", test.commitment, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchesCommitment(t *testing.T) {
+	commitment := Commitment("rock", "0123456789abcdef")
+	tests := []struct {
+		name    string
+		cardRef string
+		nonce   string
+		want    bool
+	}{
+		{name: "revelação correta", cardRef: "rock", nonce: "0123456789abcdef", want: true},
+		{name: "outra carta", cardRef: "paper", nonce: "0123456789abcdef"},
+		{name: "outro nonce", cardRef: "rock", nonce: "0123456789abcdee"},
+		{name: "nonce dividido com a carta", cardRef: "rock:0123", nonce: "456789abcdef"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := MatchesCommitment(commitment, test.cardRef, test.nonce); got != test.want {
+				t.Errorf("MatchesCommitment(%q, %q) = %v, esperado %v", test.cardRef, test.nonce, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxStars define a maior quantidade de estrelas de uma carta.
+const MaxStars = 5
+
+// CraftRecipe define uma receita de criação: Cards cartas do mesmo tipo com Stars estrelas, mais
+// Dust de pó, viram uma carta desse tipo com ResultStars estrelas.
+//
+// Campos:
+//   - Stars: estrelas das cartas consumidas.
+//   - Cards: quantidade de cartas consumidas.
+//   - ResultStars: estrelas da carta criada.
+//   - Dust: pó de criação gasto na receita.
+type CraftRecipe struct {
+	Stars       int `json:"stars"`
+	Cards       int `json:"cards"`
+	ResultStars int `json:"result_stars"`
+	Dust        int `json:"dust"`
+}
+
+// CraftingRules reúne as receitas de criação e o valor em pó das cartas desfeitas, definidos
+// como dados no servidor.
+//
+// Campos:
+//   - Recipes: receitas de criação, no máximo uma por quantidade de estrelas consumida.
+//   - DustPerStar: pó recebido por estrela de uma carta desfeita.
+//   - AbilityDust: pó extra por carta desfeita com habilidade.
+//   - RarityDust: pó extra por carta desfeita, por raridade.
+type CraftingRules struct {
+	Recipes     []CraftRecipe  `json:"recipes"`
+	DustPerStar int            `json:"dust_per_star"`
+	AbilityDust int            `json:"ability_dust"`
+	RarityDust  map[string]int `json:"rarity_dust"`
+}
+
+// Validate confere se as receitas consomem cartas, aumentam as estrelas e não se repetem, e se os
+// valores em pó são positivos.
+//
+// Retorno:
+//   - erro agregando todos os problemas encontrados, ou nil.
+func (rules CraftingRules) Validate() error {
+	var problems []error
+	seen := make(map[int]bool, len(rules.Recipes))
+	for _, recipe := range rules.Recipes {
+		if recipe.Stars < 1 || recipe.ResultStars <= recipe.Stars || recipe.ResultStars > MaxStars {
+			problems = append(problems, fmt.Errorf("receita de %d para %d estrelas inválida", recipe.Stars, recipe.ResultStars))
+		}
+		if recipe.Cards < 2 {
+			problems = append(problems, fmt.Errorf("a receita de %d estrelas precisa consumir ao menos 2 cartas", recipe.Stars))
+		}
+		if recipe.Dust < 0 {
+			problems = append(problems, fmt.Errorf("a receita de %d estrelas tem custo negativo", recipe.Stars))
+		}
+		if seen[recipe.Stars] {
+			problems = append(problems, fmt.Errorf("receita repetida para %d estrelas", recipe.Stars))
+		}
+		seen[recipe.Stars] = true
+	}
+	if rules.DustPerStar < 1 {
+		problems = append(problems, fmt.Errorf("pó por estrela inválido: %d", rules.DustPerStar))
+	}
+	if rules.AbilityDust < 0 {
+		problems = append(problems, fmt.Errorf("pó por habilidade inválido: %d", rules.AbilityDust))
+	}
+	for rarity, dust := range rules.RarityDust {
+		if rarity == "" || !ValidRarity(rarity) || dust < 0 {
+			problems = append(problems, fmt.Errorf("pó da raridade %q inválido: %d", rarity, dust))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// Recipe retorna a receita que consome cartas com a quantidade de estrelas informada.
+func (rules CraftingRules) Recipe(stars int) (CraftRecipe, bool) {
+	for _, recipe := range rules.Recipes {
+		if recipe.Stars == stars {
+			return recipe, true
+		}
+	}
+	return CraftRecipe{}, false
+}
+
+// DustValue calcula o pó recebido ao desfazer uma carta: o pó por estrela, mais o extra da
+// habilidade e da raridade. Cartas sem raridade valem como comuns.
+func (rules CraftingRules) DustValue(card Card) int {
+	rarity := card.Rarity
+	if rarity == "" {
+		rarity = RarityCommon
+	}
+	dust := card.Stars*rules.DustPerStar + rules.RarityDust[rarity]
+	if card.Ability != "" {
+		dust += rules.AbilityDust
+	}
+	return dust
+}
+
+// Craft aplica a receita das cartas informadas e retorna a carta criada. Todas as cartas precisam
+// ter o mesmo tipo e as estrelas da receita, na quantidade pedida por ela. A carta criada mantém a
+// raridade mais alta e a primeira habilidade entre as cartas consumidas.
+//
+// Parâmetros:
+//   - cards: cartas consumidas.
+//
+// Retorno:
+//   - Card: carta criada.
+//   - CraftRecipe: receita aplicada.
+//   - erro caso não haja receita para as cartas ou elas não a cumpram.
+func (rules CraftingRules) Craft(cards []Card) (Card, CraftRecipe, error) {
+	if len(cards) == 0 {
+		return Card{}, CraftRecipe{}, errors.New("nenhuma carta informada")
+	}
+	recipe, ok := rules.Recipe(cards[0].Stars)
+	if !ok {
+		return Card{}, CraftRecipe{}, fmt.Errorf("não há receita para cartas de %d estrelas", cards[0].Stars)
+	}
+	if len(cards) != recipe.Cards {
+		return Card{}, CraftRecipe{}, fmt.Errorf("a receita de %d estrelas usa %d cartas, não %d", recipe.Stars, recipe.Cards, len(cards))
+	}
+
+	crafted := Card{Type: cards[0].Type, Stars: recipe.ResultStars, Rarity: cards[0].Rarity}
+	for _, card := range cards {
+		if card.Type != crafted.Type || card.Stars != recipe.Stars {
+			return Card{}, CraftRecipe{}, fmt.Errorf("todas as cartas precisam ser %s de %d estrelas", crafted.Type, recipe.Stars)
+		}
+		if RarityRank(card.Rarity) > RarityRank(crafted.Rarity) {
+			crafted.Rarity = card.Rarity
+		}
+		if crafted.Ability == "" {
+			crafted.Ability = card.Ability
+		}
+	}
+	return crafted, recipe, nil
+}
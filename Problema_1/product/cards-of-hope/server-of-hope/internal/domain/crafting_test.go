@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+// testCraftingRules cria receitas de 3 cartas de 1 estrela em 1 de 2 estrelas, por 5 de pó, e de
+// 4 cartas de 2 estrelas em 1 de 4, de graça.
+func testCraftingRules() CraftingRules {
+	return CraftingRules{
+		Recipes: []CraftRecipe{
+			{Stars: 1, Cards: 3, ResultStars: 2, Dust: 5},
+			{Stars: 2, Cards: 4, ResultStars: 4},
+		},
+		DustPerStar: 2,
+		AbilityDust: 5,
+		RarityDust:  map[string]int{RarityCommon: 0, RarityEpic: 15},
+	}
+}
+
+func TestCraft(t *testing.T) {
+	rock := Card{Type: "rock", Stars: 1}
+	tests := []struct {
+		name     string
+		cards    []Card
+		want     Card
+		wantDust int
+		wantErr  string
+	}{
+		{name: "receita de 1 estrela", cards: []Card{rock, rock, rock}, want: Card{Type: "rock", Stars: 2}, wantDust: 5},
+		{
+			name:     "herda a maior raridade e a primeira habilidade",
+			cards:    []Card{rock, {Type: "rock", Stars: 1, Rarity: RarityEpic, Ability: "rally"}, {Type: "rock", Stars: 1, Ability: "mirror"}},
+			want:     Card{Type: "rock", Stars: 2, Rarity: RarityEpic, Ability: "rally"},
+			wantDust: 5,
+		},
+		{name: "receita de 2 estrelas", cards: []Card{{Type: "paper", Stars: 2}, {Type: "paper", Stars: 2}, {Type: "paper", Stars: 2}, {Type: "paper", Stars: 2}}, want: Card{Type: "paper", Stars: 4}},
+		{name: "cartas de menos", cards: []Card{rock, rock}, wantErr: "usa 3 cartas, não 2"},
+		{name: "tipos misturados", cards: []Card{rock, rock, {Type: "paper", Stars: 1}}, wantErr: "todas as cartas precisam ser rock de 1 estrelas"},
+		{name: "estrelas misturadas", cards: []Card{rock, rock, {Type: "rock", Stars: 2}}, wantErr: "todas as cartas precisam ser rock de 1 estrelas"},
+		{name: "sem receita", cards: []Card{{Type: "rock", Stars: 5}}, wantErr: "não há receita para cartas de 5 estrelas"},
+		{name: "nenhuma carta", wantErr: "nenhuma carta informada"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			crafted, recipe, err := testCraftingRules().Craft(test.cards)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("erro = %v, esperado %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Craft: %v", err)
+			}
+			if crafted != test.want || recipe.Dust != test.wantDust {
+				t.Errorf("carta = %+v por %d de pó, esperado %+v por %d", crafted, recipe.Dust, test.want, test.wantDust)
+			}
+		})
+	}
+}
+
+func TestDustValue(t *testing.T) {
+	tests := []struct {
+		name string
+		card Card
+		want int
+	}{
+		{name: "sem raridade conta como comum", card: Card{Type: "rock", Stars: 3}, want: 6},
+		{name: "épica", card: Card{Type: "rock", Stars: 5, Rarity: RarityEpic}, want: 25},
+		{name: "com habilidade", card: Card{Type: "rock", Stars: 1, Ability: "rally"}, want: 7},
+		{name: "raridade sem pó definido", card: Card{Type: "rock", Stars: 1, Rarity: RarityRare}, want: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := testCraftingRules().DustValue(test.card); got != test.want {
+				t.Errorf("DustValue = %d, esperado %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCraftingRulesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(rules *CraftingRules)
+		wantErr string
+	}{
+		{name: "regras válidas", change: func(rules *CraftingRules) {}},
+		{name: "resultado sem mais estrelas", change: func(rules *CraftingRules) { rules.Recipes[0].ResultStars = 1 }, wantErr: "receita de 1 para 1 estrelas inválida"},
+		{name: "resultado acima do máximo", change: func(rules *CraftingRules) { rules.Recipes[1].ResultStars = MaxStars + 1 }, wantErr: "receita de 2 para 6 estrelas inválida"},
+		{name: "uma carta só", change: func(rules *CraftingRules) { rules.Recipes[0].Cards = 1 }, wantErr: "ao menos 2 cartas"},
+		{name: "receita repetida", change: func(rules *CraftingRules) { rules.Recipes[1].Stars = 1 }, wantErr: "receita repetida"},
+		{name: "pó por estrela zerado", change: func(rules *CraftingRules) { rules.DustPerStar = 0 }, wantErr: "pó por estrela inválido"},
+		{name: "raridade desconhecida", change: func(rules *CraftingRules) { rules.RarityDust["mythic"] = 1 }, wantErr: "pó da raridade \"mythic\" inválido"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules := testCraftingRules()
+			test.change(&rules)
+			err := rules.Validate()
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("erro = %v, esperado %q", err, test.wantErr)
+			}
+		})
+	}
+}
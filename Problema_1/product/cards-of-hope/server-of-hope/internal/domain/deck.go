@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DeckSize define quantas cartas um baralho tem.
+const DeckSize = 8
+
+// HandSize define quantas cartas um jogador tem na mão durante a partida.
+const HandSize = 3
+
+// MaxDecks define quantos baralhos salvos um usuário pode ter.
+const MaxDecks = 10
+
+// MaxDeckNameLength define o tamanho máximo do nome de um baralho.
+const MaxDeckNameLength = 24
+
+// StarterDeckName é o nome do baralho inicial criado junto com a coleção do usuário.
+const StarterDeckName = "starter"
+
+// OwnedCard representa uma carta da coleção de um usuário, identificada para ser usada em baralhos.
+//
+// Campos:
+//   - ID: identificador da carta na coleção do usuário.
+//   - Card: tipo, estrelas e habilidade da carta.
+type OwnedCard struct {
+	ID string `json:"id"`
+	Card
+}
+
+// Deck representa um baralho com as cartas resolvidas a partir da coleção.
+//
+// Campos:
+//   - Name: nome do baralho.
+//   - Cards: cartas do baralho.
+type Deck struct {
+	Name  string      `json:"name"`
+	Cards []OwnedCard `json:"cards"`
+}
+
+// AddCards adiciona cartas à coleção do usuário, atribuindo um ID sequencial a cada uma.
+//
+// Parâmetros:
+//   - cards: cartas a serem adicionadas.
+//
+// Retorno:
+//   - []OwnedCard: cartas adicionadas, com seus IDs.
+func (user *User) AddCards(cards ...Card) []OwnedCard {
+	added := make([]OwnedCard, 0, len(cards))
+	for _, card := range cards {
+		user.CardSeq++
+		owned := OwnedCard{ID: strconv.Itoa(user.CardSeq), Card: card}
+		user.Collection = append(user.Collection, owned)
+		added = append(added, owned)
+	}
+	return added
+}
+
+// FindCard retorna a carta da coleção com o ID informado.
+func (user User) FindCard(cardID string) (OwnedCard, bool) {
+	for _, card := range user.Collection {
+		if card.ID == cardID {
+			return card, true
+		}
+	}
+	return OwnedCard{}, false
+}
+
+// TakeCards retira cartas da coleção do usuário e apaga os baralhos que as usavam. Nenhuma
+// carta é retirada se alguma não estiver na coleção ou aparecer repetida.
+//
+// Parâmetros:
+//   - cardIDs: IDs das cartas da coleção.
+//
+// Retorno:
+//   - []OwnedCard: cartas retiradas, na ordem dos IDs.
+//   - erro caso alguma carta não esteja na coleção.
+func (user *User) TakeCards(cardIDs []string) ([]OwnedCard, error) {
+	taken := make(map[string]bool, len(cardIDs))
+	cards := make([]OwnedCard, 0, len(cardIDs))
+	for _, cardID := range cardIDs {
+		card, owned := user.FindCard(cardID)
+		if !owned || taken[cardID] {
+			return nil, fmt.Errorf("a carta %s não está na coleção de %s", cardID, user.ID)
+		}
+		taken[cardID] = true
+		cards = append(cards, card)
+	}
+
+	// A coleção e os baralhos são recriados porque outras leituras do usuário podem estar usando os atuais.
+	collection := make([]OwnedCard, 0, len(user.Collection))
+	for _, card := range user.Collection {
+		if !taken[card.ID] {
+			collection = append(collection, card)
+		}
+	}
+	user.Collection = collection
+
+	decks := make(map[string][]string, len(user.Decks))
+	for name, deckCards := range user.Decks {
+		usesTaken := false
+		for _, cardID := range deckCards {
+			usesTaken = usesTaken || taken[cardID]
+		}
+		if !usesTaken {
+			decks[name] = deckCards
+		}
+	}
+	user.Decks = decks
+	return cards, nil
+}
+
+// Deck resolve um baralho salvo do usuário com as cartas atuais da coleção.
+//
+// Parâmetros:
+//   - name: nome do baralho.
+//
+// Retorno:
+//   - Deck: baralho encontrado.
+//   - erro caso o baralho não exista ou referencie cartas que não estão mais na coleção.
+func (user User) Deck(name string) (Deck, error) {
+	cardIDs, exists := user.Decks[name]
+	if !exists {
+		return Deck{}, fmt.Errorf("baralho não encontrado: %s", name)
+	}
+	deck := Deck{Name: name, Cards: make([]OwnedCard, 0, len(cardIDs))}
+	for _, cardID := range cardIDs {
+		card, owned := user.FindCard(cardID)
+		if !owned {
+			return Deck{}, fmt.Errorf("o baralho %s usa a carta %s, que não está mais na coleção", name, cardID)
+		}
+		deck.Cards = append(deck.Cards, card)
+	}
+	return deck, nil
+}
+
+// ValidateDeck confere se um baralho pode ser salvo: nome válido, DeckSize cartas da coleção
+// e nenhuma carta repetida.
+//
+// Parâmetros:
+//   - name: nome do baralho.
+//   - cardIDs: IDs das cartas da coleção que formam o baralho.
+//
+// Retorno:
+//   - erro descrevendo o primeiro problema encontrado, ou nil.
+func (user User) ValidateDeck(name string, cardIDs []string) error {
+	if strings.TrimSpace(name) == "" || len([]rune(name)) > MaxDeckNameLength || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("nome de baralho inválido, use até %d caracteres sem espaços", MaxDeckNameLength)
+	}
+	if len(cardIDs) != DeckSize {
+		return fmt.Errorf("um baralho precisa de exatamente %d cartas", DeckSize)
+	}
+	seen := make(map[string]bool, len(cardIDs))
+	for _, cardID := range cardIDs {
+		if seen[cardID] {
+			return fmt.Errorf("a carta %s aparece mais de uma vez no baralho", cardID)
+		}
+		seen[cardID] = true
+		if _, owned := user.FindCard(cardID); !owned {
+			return fmt.Errorf("a carta %s não está na sua coleção", cardID)
+		}
+	}
+	if _, exists := user.Decks[name]; !exists && len(user.Decks) >= MaxDecks {
+		return errors.New("limite de baralhos salvos atingido")
+	}
+	return nil
+}
+
+// BasicDeck monta o baralho usado por quem não escolheu um: os tipos das regras, com uma
+// estrela e sem habilidades, repetidos até completar DeckSize cartas.
+//
+// Parâmetros:
+//   - ruleset: regras da sala.
+//
+// Retorno:
+//   - []OwnedCard: cartas do baralho básico.
+func BasicDeck(ruleset Ruleset) []OwnedCard {
+	cards := make([]OwnedCard, 0, DeckSize)
+	for index := 0; index < DeckSize && len(ruleset.Cards) > 0; index++ {
+		cards = append(cards, OwnedCard{
+			ID:   "basic-" + strconv.Itoa(index+1),
+			Card: Card{Type: ruleset.Cards[index%len(ruleset.Cards)], Stars: 1},
+		})
+	}
+	return cards
+}
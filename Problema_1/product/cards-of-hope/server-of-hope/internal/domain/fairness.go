@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MaxClientSeedLength define o tamanho máximo da semente escolhida pelo usuário.
+const MaxClientSeedLength = 64
+
+// PackSeed guarda o par de sementes que sorteia os pacotes de um usuário. Só o hash da semente
+// do servidor é publicado enquanto ela está em uso; a semente é revelada quando o usuário a
+// troca, e a partir daí qualquer pacote aberto com ela pode ser recalculado.
+//
+// Campos:
+//   - ID: identificador da semente.
+//   - UserID: usuário dono da semente.
+//   - ServerSeed: semente secreta do servidor, em hexadecimal.
+//   - Hash: hash SHA-256, em hexadecimal, da semente do servidor, publicado antes do uso.
+//   - ClientSeed: semente escolhida pelo usuário, combinada com a do servidor.
+//   - Nonce: quantidade de pacotes abertos com a semente; é o nonce do próximo pacote.
+//   - CreatedAt: data de criação da semente.
+//   - RevealedAt: data em que a semente foi revelada (nil enquanto está em uso).
+type PackSeed struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	ServerSeed string     `json:"server_seed"`
+	Hash       string     `json:"hash"`
+	ClientSeed string     `json:"client_seed"`
+	Nonce      int        `json:"nonce"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevealedAt *time.Time `json:"revealed_at,omitempty"`
+}
+
+// Revealed informa se a semente do servidor já foi revelada.
+func (seed PackSeed) Revealed() bool {
+	return seed.RevealedAt != nil
+}
+
+// PackOpening registra um pacote aberto, com tudo o que é preciso para recalculá-lo.
+//
+// Campos:
+//   - ID: identificador do registro.
+//   - UserID: usuário que abriu o pacote.
+//   - PackID: tipo de pacote.
+//   - SeedID: semente do servidor usada no sorteio.
+//   - ClientSeed: semente do usuário no momento do sorteio.
+//   - Nonce: nonce do pacote na semente.
+//   - Guarantee: indica se o pacote cumpriu a garantia de raridade.
+//   - PityOpened: pacotes abertos sem a raridade garantida antes deste, de onde vem Guarantee.
+//   - TableHash: hash das tabelas de sorteio do tipo de pacote na abertura (veja
+//     PackType.TableHash); vazio nos pacotes abertos antes de ele ser registrado.
+//   - Cards: cartas sorteadas.
+//   - CreatedAt: data da abertura.
+type PackOpening struct {
+	ID         string      `json:"id"`
+	UserID     string      `json:"user_id"`
+	PackID     string      `json:"pack_id"`
+	SeedID     string      `json:"seed_id"`
+	ClientSeed string      `json:"client_seed"`
+	Nonce      int         `json:"nonce"`
+	Guarantee  bool        `json:"guarantee"`
+	PityOpened int         `json:"pity_opened"`
+	TableHash  string      `json:"table_hash"`
+	Cards      CardPackage `json:"cards"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// SeedHash calcula o hash publicado de uma semente do servidor: o SHA-256, em hexadecimal, dos
+// caracteres da semente.
+func SeedHash(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidClientSeed informa se a semente do usuário não é vazia e cabe no tamanho máximo.
+func ValidClientSeed(clientSeed string) bool {
+	return clientSeed != "" && len(clientSeed) <= MaxClientSeedLength
+}
+
+// FairDraw cria a fonte de sorteio determinística de um pacote. Os números saem, 8 bytes por vez
+// em big-endian, dos blocos HMAC-SHA256 com a semente do servidor como chave e a mensagem
+// "semente_do_cliente:nonce:bloco", com o bloco contando a partir de 0. Cada sorteio em [0, n)
+// descarta os valores a partir do maior múltiplo de n, para que todos os resultados tenham a
+// mesma chance.
+//
+// Parâmetros:
+//   - serverSeed: semente do servidor.
+//   - clientSeed: semente do usuário.
+//   - nonce: nonce do pacote.
+//
+// Retorno:
+//   - func(n int) int: fonte de sorteio, que retorna um inteiro em [0, n).
+func FairDraw(serverSeed, clientSeed string, nonce int) func(n int) int {
+	var block []byte
+	round := 0
+	next := func() uint64 {
+		if len(block) < 8 {
+			mac := hmac.New(sha256.New, []byte(serverSeed))
+			fmt.Fprintf(mac, "%s:%d:%d", clientSeed, nonce, round)
+			block = mac.Sum(nil)
+			round++
+		}
+		value := binary.BigEndian.Uint64(block[:8])
+		block = block[8:]
+		return value
+	}
+	return func(n int) int {
+		if n <= 0 {
+			return 0
+		}
+		limit := math.MaxUint64 - math.MaxUint64%uint64(n)
+		for {
+			if value := next(); value < limit {
+				return int(value % uint64(n))
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package domain
+
+import "testing"
+
+// Os vetores abaixo foram calculados fora do servidor, seguindo a descrição de FairDraw:
+// HMAC-SHA256(semente do servidor, "semente_do_cliente:nonce:bloco"), 8 bytes big-endian por vez.
+func TestFairDrawKnownVectors(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverSeed string
+		clientSeed string
+		nonce      int
+		ns         []int
+		want       []int
+	}{
+		{
+			name:       "dois blocos",
+			serverSeed: "server-seed",
+			clientSeed: "client-seed",
+			ns:         []int{100, 100, 100, 100, 100, 100},
+			want:       []int{94, 60, 15, 63, 2, 26},
+		},
+		{
+			name:       "outro nonce",
+			serverSeed: "server-seed",
+			clientSeed: "client-seed",
+			nonce:      1,
+			ns:         []int{100, 100, 100, 100, 100, 100},
+			want:       []int{4, 90, 43, 92, 5, 71},
+		},
+		{
+			name:       "outra semente do cliente",
+			serverSeed: "server-seed",
+			clientSeed: "other",
+			ns:         []int{100, 100, 100, 100, 100, 100},
+			want:       []int{41, 18, 20, 79, 52, 20},
+		},
+		{
+			name:       "faixas variadas",
+			serverSeed: "server-seed",
+			clientSeed: "client-seed",
+			ns:         []int{2, 3, 1000000, 7, 0, 10},
+			want:       []int{0, 0, 478915, 6, 0, 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Duas fontes com as mesmas sementes devem sortear os mesmos números.
+			for attempt := 0; attempt < 2; attempt++ {
+				draw := FairDraw(test.serverSeed, test.clientSeed, test.nonce)
+				for index, n := range test.ns {
+					if got := draw(n); got != test.want[index] {
+						t.Fatalf("sorteio %d em [0, %d) = %d, esperado %d", index, n, got, test.want[index])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSeedHash(t *testing.T) {
+	const want = "91024ec49c5bec0b689e42892526320fce08337205c91de94c7a588c20d08eeb"
+	if got := SeedHash("server-seed"); got != want {
+		t.Errorf("SeedHash = %s, esperado %s", got, want)
+	}
+}
+
+func TestPackGuarantees(t *testing.T) {
+	tests := []struct {
+		name   string
+		pity   Pity
+		opened int
+		want   bool
+	}{
+		{name: "sem garantia", pity: Pity{}, opened: 100, want: false},
+		{name: "antes da garantia", pity: Pity{Rarity: "epic", Packs: 10}, opened: 8, want: false},
+		{name: "pacote da garantia", pity: Pity{Rarity: "epic", Packs: 10}, opened: 9, want: true},
+		{name: "depois da garantia", pity: Pity{Rarity: "epic", Packs: 10}, opened: 12, want: true},
+		{name: "garantia em todo pacote", pity: Pity{Rarity: "rare", Packs: 1}, opened: 0, want: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pack := PackType{Pity: test.pity}
+			if got := pack.Guarantees(test.opened); got != test.want {
+				t.Errorf("Guarantees(%d) = %v, esperado %v", test.opened, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPackTableHash(t *testing.T) {
+	pack := PackType{
+		Types:    []TypeWeight{{Type: "rock", Weight: 1}},
+		Rarities: []RarityTier{{Rarity: "common", Weight: 1}},
+		Pity:     Pity{Rarity: "common", Packs: 5},
+	}
+	base := pack.TableHash(nil)
+
+	renamed := pack
+	renamed.Name = "outro nome"
+	if renamed.TableHash(nil) != base {
+		t.Error("o nome do pacote mudou o hash das tabelas")
+	}
+	changed := pack
+	changed.Pity.Packs = 6
+	if changed.TableHash(nil) == base {
+		t.Error("a garantia mudou e o hash das tabelas não")
+	}
+	if pack.TableHash([]Ability{{ID: "stubborn"}}) == base {
+		t.Error("as habilidades mudaram e o hash das tabelas não")
+	}
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "server-of-hope/internal/utils"
+import (
+	"math/rand"
+	"server-of-hope/internal/utils"
+	"time"
+)
 
 // Game representa uma partida do jogo.
 //
@@ -8,9 +12,210 @@ import "server-of-hope/internal/utils"
 //   - ID: identificador único da partida.
 //   - Plays: jogadas dos jogadores.
 //   - ResultsSeenBy: jogadores que visualizaram o resultado.
+//   - Round: rodada em andamento, começando em 1.
+//   - Scores: rodadas vencidas por jogador.
+//   - WinnerID: vencedor da partida (vazio enquanto ela não termina).
+//   - Seats: jogadores na ordem dos assentos; a revanche inverte os assentos.
+//   - Number: número da partida dentro da série de revanches, começando em 1.
+//   - Series: partidas vencidas por jogador na série de revanches.
+//   - Reveals: jogadores que verão a carta do oponente assim que ele jogar nesta rodada.
+//   - Hands: cartas na mão de cada jogador.
+//   - DrawPiles: cartas que cada jogador ainda vai comprar, na ordem de compra.
+//   - Commitments: compromissos da rodada em salas com compromisso e revelação.
+//   - TurnDeadline: prazo da rodada em andamento nas partidas por correspondência (zero nas demais).
+//   - LastRound: resultado da última rodada resolvida da partida.
+//   - Settled: indica se o fim da partida já foi acertado (prêmios, aposta e torneio).
 type Game struct {
-	ID             string                   `json:"id"`
-	Plays          *utils.Map[string, Card] `json:"plays"`
-	ResultsSeenBy  *utils.Set[string]       `json:"results_seen_by"`
-	FailedAttempts *utils.Map[string, int]  `json:"failed_attempts"` // tentativas frustradas por jogador
+	ID             string                          `json:"id"`
+	Plays          *utils.Map[string, Card]        `json:"plays"`
+	ResultsSeenBy  *utils.Set[string]              `json:"results_seen_by"`
+	FailedAttempts *utils.Map[string, int]         `json:"failed_attempts"` // tentativas frustradas por jogador
+	Round          int                             `json:"round"`
+	Scores         *utils.Map[string, int]         `json:"scores"`
+	WinnerID       string                          `json:"winner_id"`
+	Seats          []string                        `json:"seats"`
+	Number         int                             `json:"number"`
+	Series         *utils.Map[string, int]         `json:"series"`
+	Reveals        *utils.Set[string]              `json:"reveals"`
+	Hands          *utils.Map[string, []OwnedCard] `json:"hands"`
+	DrawPiles      *utils.Map[string, []OwnedCard] `json:"draw_piles"`
+	Commitments    *utils.Map[string, string]      `json:"commitments"`
+	TurnDeadline   time.Time                       `json:"turn_deadline"`
+	LastRound      *RoundResult                    `json:"last_round,omitempty"`
+	Settled        bool                            `json:"settled"`
+}
+
+// NewGame cria a primeira partida de uma série, na primeira rodada e sem pontos.
+//
+// Parâmetros:
+//   - id: identificador da partida (o mesmo da sala).
+//   - seats: jogadores na ordem dos assentos.
+//
+// Retorno:
+//   - Game: partida criada.
+func NewGame(id string, seats []string) Game {
+	series := utils.NewMap[string, int]()
+	for _, playerID := range seats {
+		series.Set(playerID, 0)
+	}
+	return newMatch(id, seats, 1, series)
+}
+
+// Rematch cria a próxima partida da série, com os assentos invertidos e o placar da série mantido.
+//
+// Retorno:
+//   - Game: nova partida da série.
+func (game Game) Rematch() Game {
+	seats := make([]string, len(game.Seats))
+	for index, playerID := range game.Seats {
+		seats[len(seats)-1-index] = playerID
+	}
+	series := game.Series
+	if series == nil {
+		series = utils.NewMap[string, int]()
+	}
+	return newMatch(game.ID, seats, game.Number+1, series)
+}
+
+// newMatch cria uma partida na primeira rodada, sem pontos, dentro de uma série.
+func newMatch(id string, seats []string, number int, series *utils.Map[string, int]) Game {
+	scores := utils.NewMap[string, int]()
+	for _, playerID := range seats {
+		scores.Set(playerID, 0)
+	}
+	return Game{
+		ID:             id,
+		Plays:          utils.NewMap[string, Card](),
+		ResultsSeenBy:  utils.NewSet[string](),
+		FailedAttempts: utils.NewMap[string, int](),
+		Round:          1,
+		Scores:         scores,
+		Seats:          seats,
+		Number:         number,
+		Series:         series,
+		Reveals:        utils.NewSet[string](),
+		Hands:          utils.NewMap[string, []OwnedCard](),
+		DrawPiles:      utils.NewMap[string, []OwnedCard](),
+		Commitments:    utils.NewMap[string, string](),
+	}
+}
+
+// Deal embaralha o baralho de um jogador e dá a ele uma mão de HandSize cartas; o restante
+// forma a pilha de compra.
+//
+// Parâmetros:
+//   - playerID: jogador que recebe as cartas.
+//   - deck: cartas do baralho do jogador.
+func (game *Game) Deal(playerID string, deck []OwnedCard) {
+	cards := append([]OwnedCard(nil), deck...)
+	rand.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+	handSize := min(HandSize, len(cards))
+	game.Hands.Set(playerID, cards[:handSize])
+	game.DrawPiles.Set(playerID, cards[handSize:])
+}
+
+// TakeFromHand retira uma carta da mão do jogador, buscando pelo ID ou, se nenhum ID
+// corresponder, pela carta do tipo informado com mais estrelas.
+//
+// Parâmetros:
+//   - playerID: jogador dono da mão.
+//   - cardRef: ID ou tipo da carta.
+//
+// Retorno:
+//   - OwnedCard: carta retirada.
+//   - bool: false se a carta não está na mão.
+func (game *Game) TakeFromHand(playerID, cardRef string) (OwnedCard, bool) {
+	hand, _ := game.Hands.Get(playerID)
+	chosen := -1
+	for index, card := range hand {
+		if card.ID == cardRef {
+			chosen = index
+			break
+		}
+		if card.Type == cardRef && (chosen == -1 || card.Stars > hand[chosen].Stars) {
+			chosen = index
+		}
+	}
+	if chosen == -1 {
+		return OwnedCard{}, false
+	}
+	card := hand[chosen]
+	remaining := append(append([]OwnedCard(nil), hand[:chosen]...), hand[chosen+1:]...)
+	game.Hands.Set(playerID, remaining)
+	return card, true
+}
+
+// Draw completa a mão do jogador até HandSize cartas com a pilha de compra.
+func (game *Game) Draw(playerID string) {
+	hand, _ := game.Hands.Get(playerID)
+	pile, _ := game.DrawPiles.Get(playerID)
+	for len(hand) < HandSize && len(pile) > 0 {
+		hand = append(hand, pile[0])
+		pile = pile[1:]
+	}
+	game.Hands.Set(playerID, hand)
+	game.DrawPiles.Set(playerID, pile)
+}
+
+// OutOfCards informa se nenhum dos jogadores tem cartas na mão ou na pilha de compra.
+func (game Game) OutOfCards() bool {
+	empty := true
+	game.Hands.ForEach(func(_ string, hand []OwnedCard) {
+		empty = empty && len(hand) == 0
+	})
+	game.DrawPiles.ForEach(func(_ string, pile []OwnedCard) {
+		empty = empty && len(pile) == 0
+	})
+	return empty
+}
+
+// WaitingOn informa se a rodada em andamento aguarda uma ação do jogador: a jogada ou, com
+// compromisso e revelação, o compromisso ou a revelação depois que os dois se comprometeram.
+//
+// Parâmetros:
+//   - playerID: jogador consultado.
+//   - commitReveal: a partida usa jogadas com compromisso e revelação.
+//
+// Retorno:
+//   - bool: true se a rodada aguarda o jogador.
+func (game Game) WaitingOn(playerID string, commitReveal bool) bool {
+	if _, played := game.Plays.Get(playerID); played || game.WinnerID != "" {
+		return false
+	}
+	if !commitReveal || game.Commitments == nil {
+		return true
+	}
+	if _, committed := game.Commitments.Get(playerID); !committed {
+		return true
+	}
+	return game.Commitments.Size() >= len(game.Seats)
+}
+
+// RoundResult descreve o desfecho de uma rodada.
+//
+// Campos:
+//   - Round: número da rodada encerrada.
+//   - Plays: cartas jogadas por cada jogador.
+//   - Effective: cartas de cada jogador após as habilidades que alteram estrelas.
+//   - Effects: habilidades aplicadas na rodada, na ordem de aplicação.
+//   - WinnerID: vencedor da rodada (vazio em caso de empate).
+//   - Scores: rodadas vencidas por jogador após esta rodada.
+//   - MatchFinished: indica se a partida terminou nesta rodada.
+//   - MatchWinnerID: vencedor da partida, se ela terminou nesta rodada (vazio em caso de empate).
+//   - Series: partidas vencidas por jogador na série, preenchido quando a partida termina.
+//   - ForfeitedBy: jogador que perdeu a partida por revelações inválidas ou por perder o prazo da
+//     rodada (vazio se ela terminou normalmente).
+//   - TimedOut: indica que a partida por correspondência terminou porque o prazo da rodada acabou.
+type RoundResult struct {
+	Round         int             `json:"round"`
+	Plays         map[string]Card `json:"plays"`
+	Effective     map[string]Card `json:"effective"`
+	Effects       []AppliedEffect `json:"effects"`
+	WinnerID      string          `json:"winner_id"`
+	Scores        map[string]int  `json:"scores"`
+	MatchFinished bool            `json:"match_finished"`
+	MatchWinnerID string          `json:"match_winner_id"`
+	Series        map[string]int  `json:"series"`
+	ForfeitedBy   string          `json:"forfeited_by"`
+	TimedOut      bool            `json:"timed_out"`
 }
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// Motivos dos lançamentos do livro-caixa de moedas.
+const (
+	LedgerStarter    = "starter"         // saldo inicial, creditado no primeiro uso da carteira
+	LedgerRoundWin   = "round_win"       // vitória em uma rodada
+	LedgerMatchWin   = "match_win"       // vitória em uma partida
+	LedgerPurchase   = "purchase"        // compra de um pacote de cartas
+	LedgerRefund     = "refund"          // devolução de uma compra que não foi entregue
+	LedgerGrant      = "grant"           // ajuste feito por um administrador
+	LedgerMarketBuy  = "market_purchase" // compra de um anúncio de preço fixo do mercado
+	LedgerBid        = "bid"             // lance retido em um leilão do mercado
+	LedgerBidRefund  = "bid_refund"      // devolução de um lance superado
+	LedgerSale       = "sale"            // venda de cartas no mercado
+	LedgerCraft      = "craft"           // pó gasto na criação de uma carta
+	LedgerDisenchant = "disenchant"      // pó recebido ao desfazer cartas
+	LedgerWager      = "wager"           // moedas apostadas em uma partida, retidas até o fim dela
+	LedgerWagerWin   = "wager_win"       // apostas recebidas pelo vencedor da partida
+	LedgerWagerBack  = "wager_refund"    // aposta devolvida no empate ou na partida interrompida
+)
+
+// CurrencyDust identifica os lançamentos de pó de criação. Os lançamentos sem moeda são de moedas.
+const CurrencyDust = "dust"
+
+// LedgerEntry representa um lançamento do livro-caixa de moedas e de pó de criação. Os lançamentos
+// nunca são alterados nem removidos: cada saldo de um usuário é a soma dos seus lançamentos na
+// mesma moeda.
+//
+// Campos:
+//   - Seq: posição do lançamento no livro-caixa, começando em 1.
+//   - UserID: dono da carteira.
+//   - Currency: moeda do lançamento (vazio para moedas, dust para pó de criação).
+//   - Amount: valor lançado (positivo nos créditos, negativo nos débitos).
+//   - Balance: saldo do usuário na moeda do lançamento, depois dele.
+//   - Reason: motivo do lançamento (starter, round_win, match_win, purchase, refund, grant,
+//     market_purchase, bid, bid_refund, sale, craft, disenchant, wager, wager_win, wager_refund).
+//   - Reference: sala da partida premiada, anúncio do mercado, cartas criadas ou desfeitas, aposta
+//     da partida ou observação do lançamento.
+//   - CreatedAt: momento do lançamento.
+type LedgerEntry struct {
+	Seq       int       `json:"seq"`
+	UserID    string    `json:"user_id"`
+	Currency  string    `json:"currency,omitempty"`
+	Amount    int       `json:"amount"`
+	Balance   int       `json:"balance"`
+	Reason    string    `json:"reason"`
+	Reference string    `json:"reference,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WalletKey identifica o saldo de um usuário em uma moeda. Usuário e moeda ficam em campos
+// separados para que nenhum nome de usuário se confunda com a carteira de outro.
+//
+// Campos:
+//   - UserID: dono da carteira.
+//   - Currency: moeda da carteira (vazio para moedas, dust para pó de criação).
+type WalletKey struct {
+	UserID   string
+	Currency string
+}
+
+// String descreve a carteira nas mensagens de erro.
+func (key WalletKey) String() string {
+	if key.Currency == "" {
+		return key.UserID
+	}
+	return key.UserID + " (" + key.Currency + ")"
+}
+
+// WalletKey identifica o saldo do usuário do lançamento na sua moeda.
+func (entry LedgerEntry) WalletKey() WalletKey {
+	return WalletKey{UserID: entry.UserID, Currency: entry.Currency}
+}
@@ -0,0 +1,135 @@
+package domain
+
+import "time"
+
+// MaxListingCards define quantas cartas um anúncio pode ter: as de um pacote da loja.
+const MaxListingCards = len(CardPackage{})
+
+// Tipos de anúncio do mercado. No preço fixo (fixed), o primeiro comprador leva as cartas pelo
+// preço pedido; no leilão (auction), elas vão para o maior lance quando o prazo termina.
+const (
+	ListingFixed   = "fixed"
+	ListingAuction = "auction"
+)
+
+// Estados de um anúncio. O anúncio fica aberto (active) até ser vendido (sold), retirado pelo
+// vendedor (cancelled) ou vencer sem comprador nem lances (expired).
+const (
+	ListingActive    = "active"
+	ListingSold      = "sold"
+	ListingCancelled = "cancelled"
+	ListingExpired   = "expired"
+)
+
+// ValidListingKind informa se o tipo de anúncio é conhecido.
+func ValidListingKind(kind string) bool {
+	return kind == ListingFixed || kind == ListingAuction
+}
+
+// ValidListingStatus informa se o estado de anúncio é conhecido.
+func ValidListingStatus(status string) bool {
+	switch status {
+	case ListingActive, ListingSold, ListingCancelled, ListingExpired:
+		return true
+	}
+	return false
+}
+
+// Bid representa um lance em um leilão. As moedas do lance ficam retidas até ele ser superado
+// ou o leilão terminar.
+//
+// Campos:
+//   - UserID: usuário que deu o lance.
+//   - Amount: valor do lance, em moedas.
+//   - CreatedAt: momento do lance.
+type Bid struct {
+	UserID    string    `json:"user_id"`
+	Amount    int       `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Listing representa um anúncio do mercado de cartas. As cartas anunciadas saem da coleção do
+// vendedor e ficam retidas no anúncio até a venda, a retirada ou o vencimento.
+//
+// Campos:
+//   - ID: identificador do anúncio.
+//   - SellerID: usuário que anunciou as cartas.
+//   - Kind: tipo do anúncio (fixed, auction).
+//   - Cards: cartas retidas no anúncio.
+//   - Price: preço pedido, no preço fixo, ou lance mínimo, no leilão.
+//   - Bids: lances do leilão, do primeiro ao maior.
+//   - Status: estado do anúncio (active, sold, cancelled, expired).
+//   - BuyerID: usuário que levou as cartas, quando vendido.
+//   - CreatedAt: momento do anúncio.
+//   - EndsAt: momento em que o anúncio termina.
+//   - SettledAt: momento em que o anúncio saiu de active.
+type Listing struct {
+	ID        string    `json:"id"`
+	SellerID  string    `json:"seller_id"`
+	Kind      string    `json:"kind"`
+	Cards     []Card    `json:"cards"`
+	Price     int       `json:"price"`
+	Bids      []Bid     `json:"bids,omitempty"`
+	Status    string    `json:"status"`
+	BuyerID   string    `json:"buyer_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	SettledAt time.Time `json:"settled_at"`
+}
+
+// Ended informa se o anúncio aberto já passou do prazo.
+func (listing Listing) Ended() bool {
+	return listing.Status == ListingActive && time.Now().After(listing.EndsAt)
+}
+
+// HighestBid retorna o maior lance do leilão, se houver.
+func (listing Listing) HighestBid() (Bid, bool) {
+	if len(listing.Bids) == 0 {
+		return Bid{}, false
+	}
+	return listing.Bids[len(listing.Bids)-1], true
+}
+
+// MinimumBid retorna o menor lance aceito pelo leilão: o lance mínimo, sem lances, ou uma moeda
+// a mais que o maior lance.
+func (listing Listing) MinimumBid() int {
+	if bid, ok := listing.HighestBid(); ok {
+		return bid.Amount + 1
+	}
+	return listing.Price
+}
+
+// ListingFilter descreve a busca de anúncios no mercado. Campos vazios ou zerados não filtram.
+//
+// Campos:
+//   - Type: tipo de carta que o anúncio deve ter.
+//   - MinStars: menor quantidade de estrelas da carta procurada.
+//   - MaxStars: maior quantidade de estrelas da carta procurada.
+//   - SellerID: vendedor dos anúncios.
+type ListingFilter struct {
+	Type     string
+	MinStars int
+	MaxStars int
+	SellerID string
+}
+
+// Matches informa se o anúncio atende à busca: ele deve ser do vendedor procurado e ter ao
+// menos uma carta do tipo e da faixa de estrelas pedidos.
+func (filter ListingFilter) Matches(listing Listing) bool {
+	if filter.SellerID != "" && listing.SellerID != filter.SellerID {
+		return false
+	}
+	for _, card := range listing.Cards {
+		if filter.Type != "" && card.Type != filter.Type {
+			continue
+		}
+		if filter.MinStars > 0 && card.Stars < filter.MinStars {
+			continue
+		}
+		if filter.MaxStars > 0 && card.Stars > filter.MaxStars {
+			continue
+		}
+		return true
+	}
+	return false
+}
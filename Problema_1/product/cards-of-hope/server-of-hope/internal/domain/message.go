@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// MaxDirectMessageLength define o tamanho máximo, em caracteres, de uma mensagem direta: o
+// mesmo limite da caixa de texto do cliente.
+const MaxDirectMessageLength = 280
+
+// DirectMessage representa uma mensagem privada de um usuário para outro, fora das salas.
+//
+// Campos:
+//   - ID: identificador da mensagem.
+//   - FromID: usuário que enviou a mensagem.
+//   - ToID: usuário destinatário.
+//   - Text: texto da mensagem.
+//   - SentAt: momento do envio.
+//   - Delivered: a mensagem já chegou ao destinatário; enquanto não chega, fica na caixa de entrada dele.
+type DirectMessage struct {
+	ID        string    `json:"id"`
+	FromID    string    `json:"from_id"`
+	ToID      string    `json:"to_id"`
+	Text      string    `json:"text"`
+	SentAt    time.Time `json:"sent_at"`
+	Delivered bool      `json:"delivered"`
+}
+
+// Between informa se a mensagem faz parte da conversa entre os dois usuários.
+func (message DirectMessage) Between(userID, peerID string) bool {
+	return (message.FromID == userID && message.ToID == peerID) || (message.FromID == peerID && message.ToID == userID)
+}
@@ -0,0 +1,306 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DefaultPackID identifica o tipo de pacote vendido quando a compra não escolhe outro.
+const DefaultPackID = "basic"
+
+// Raridades das cartas, da mais comum à mais rara. Cartas sem raridade, como as da coleção
+// inicial e as compradas antes das raridades, contam como comuns.
+const (
+	RarityCommon    = "common"
+	RarityRare      = "rare"
+	RarityEpic      = "epic"
+	RarityLegendary = "legendary"
+)
+
+// rarityRanks ordena as raridades, da mais comum à mais rara.
+var rarityRanks = map[string]int{
+	"":              0,
+	RarityCommon:    0,
+	RarityRare:      1,
+	RarityEpic:      2,
+	RarityLegendary: 3,
+}
+
+// ValidRarity informa se a raridade é conhecida; a raridade vazia é aceita como comum.
+func ValidRarity(rarity string) bool {
+	_, ok := rarityRanks[rarity]
+	return ok
+}
+
+// RarityRank retorna a posição da raridade, de 0 (comum) a 3 (lendária).
+func RarityRank(rarity string) int {
+	return rarityRanks[rarity]
+}
+
+// TypeWeight define o peso de um tipo de carta no sorteio de um pacote.
+//
+// Campos:
+//   - Type: tipo de carta, das regras padrão.
+//   - Weight: peso do tipo; a chance é o peso dividido pela soma dos pesos.
+type TypeWeight struct {
+	Type   string `json:"type"`
+	Weight int    `json:"weight"`
+}
+
+// StarWeight define o peso de uma quantidade de estrelas no sorteio de uma raridade.
+//
+// Campos:
+//   - Stars: quantidade de estrelas, de 1 a 5.
+//   - Weight: peso da quantidade de estrelas.
+type StarWeight struct {
+	Stars  int `json:"stars"`
+	Weight int `json:"weight"`
+}
+
+// RarityTier define uma raridade da tabela de sorteio de um pacote.
+//
+// Campos:
+//   - Rarity: raridade das cartas da faixa.
+//   - Weight: peso da raridade no sorteio de cada carta.
+//   - Stars: pesos das quantidades de estrelas das cartas desta raridade.
+//   - AbilityChance: chance, em porcentagem, de a carta vir com uma habilidade das regras padrão.
+type RarityTier struct {
+	Rarity        string       `json:"rarity"`
+	Weight        int          `json:"weight"`
+	Stars         []StarWeight `json:"stars"`
+	AbilityChance int          `json:"ability_chance"`
+}
+
+// Pity define a garantia de um pacote: quem abre Packs pacotes seguidos sem nenhuma carta da
+// raridade Rarity (ou mais rara) recebe uma no último deles.
+//
+// Campos:
+//   - Rarity: raridade garantida.
+//   - Packs: quantidade de pacotes até a garantia (0 desativa).
+type Pity struct {
+	Rarity string `json:"rarity"`
+	Packs  int    `json:"packs"`
+}
+
+// PackType descreve um tipo de pacote da loja e a sua tabela de sorteio, definidos como dados
+// no servidor. Cada pacote tem as três cartas de um CardPackage, sorteadas uma a uma: primeiro
+// a raridade, depois o tipo, as estrelas e a habilidade.
+//
+// Campos:
+//   - ID: identificador do tipo de pacote.
+//   - Name: nome de exibição.
+//   - Description: resumo do pacote para os jogadores.
+//   - Price: preço do pacote, em moedas.
+//   - Types: pesos dos tipos de carta.
+//   - Rarities: faixas de raridade, com os pesos das estrelas e a chance de habilidade.
+//   - Pity: garantia de raridade do pacote.
+type PackType struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       int          `json:"price"`
+	Types       []TypeWeight `json:"types"`
+	Rarities    []RarityTier `json:"rarities"`
+	Pity        Pity         `json:"pity"`
+}
+
+// Validate confere se o tipo de pacote está completo e usa tipos de carta e raridades válidos.
+//
+// Parâmetros:
+//   - ruleset: regras padrão, de onde vêm os tipos de carta e as habilidades.
+//
+// Retorno:
+//   - erro agregando todos os problemas encontrados, ou nil.
+func (pack PackType) Validate(ruleset Ruleset) error {
+	var problems []error
+	if pack.ID == "" {
+		problems = append(problems, errors.New("tipo de pacote sem ID"))
+	}
+	if pack.Price < 1 {
+		problems = append(problems, fmt.Errorf("preço de pacote inválido: %d", pack.Price))
+	}
+	typeTotal := 0
+	for _, weight := range pack.Types {
+		if !ruleset.HasCard(weight.Type) {
+			problems = append(problems, fmt.Errorf("tipo de carta fora das regras %s: %q", ruleset.ID, weight.Type))
+		}
+		if weight.Weight < 0 {
+			problems = append(problems, fmt.Errorf("peso negativo do tipo %q", weight.Type))
+		}
+		typeTotal += weight.Weight
+	}
+	if typeTotal == 0 {
+		problems = append(problems, errors.New("o pacote precisa de ao menos um tipo de carta com peso"))
+	}
+
+	rarityTotal := 0
+	seen := make(map[string]bool, len(pack.Rarities))
+	for _, tier := range pack.Rarities {
+		if tier.Rarity == "" || !ValidRarity(tier.Rarity) || seen[tier.Rarity] {
+			problems = append(problems, fmt.Errorf("raridade inválida ou repetida: %q", tier.Rarity))
+		}
+		seen[tier.Rarity] = true
+		if tier.Weight < 0 {
+			problems = append(problems, fmt.Errorf("peso negativo da raridade %q", tier.Rarity))
+		}
+		rarityTotal += tier.Weight
+		starTotal := 0
+		for _, weight := range tier.Stars {
+			if weight.Stars < 1 || weight.Stars > MaxStars || weight.Weight < 0 {
+				problems = append(problems, fmt.Errorf("estrelas inválidas na raridade %q: %d (peso %d)", tier.Rarity, weight.Stars, weight.Weight))
+			}
+			starTotal += weight.Weight
+		}
+		if starTotal == 0 {
+			problems = append(problems, fmt.Errorf("a raridade %q precisa de ao menos uma quantidade de estrelas com peso", tier.Rarity))
+		}
+		if tier.AbilityChance < 0 || tier.AbilityChance > 100 {
+			problems = append(problems, fmt.Errorf("chance de habilidade inválida na raridade %q: %d", tier.Rarity, tier.AbilityChance))
+		}
+		if tier.AbilityChance > 0 && len(ruleset.Abilities) == 0 {
+			problems = append(problems, fmt.Errorf("a raridade %q dá habilidades, mas as regras %s não têm nenhuma", tier.Rarity, ruleset.ID))
+		}
+	}
+	if rarityTotal == 0 {
+		problems = append(problems, errors.New("o pacote precisa de ao menos uma raridade com peso"))
+	}
+
+	if pack.Pity.Packs < 0 {
+		problems = append(problems, fmt.Errorf("garantia com quantidade de pacotes inválida: %d", pack.Pity.Packs))
+	}
+	if pack.Pity.Packs > 0 && len(pack.guaranteed()) == 0 {
+		problems = append(problems, fmt.Errorf("a garantia de %q não tem nenhuma raridade com peso que a cumpra", pack.Pity.Rarity))
+	}
+	return errors.Join(problems...)
+}
+
+// Roll sorteia as cartas de um pacote.
+//
+// Parâmetros:
+//   - draw: fonte de sorteio, que retorna um inteiro em [0, n).
+//   - abilities: habilidades que as cartas podem receber.
+//   - guarantee: indica se o pacote deve cumprir a garantia de raridade.
+//
+// Retorno:
+//   - CardPackage: cartas sorteadas.
+func (pack PackType) Roll(draw func(n int) int, abilities []Ability, guarantee bool) CardPackage {
+	var cards CardPackage
+	for index := range cards {
+		cards[index] = pack.rollCard(draw, abilities, pack.Rarities)
+	}
+	if guarantee && pack.Pity.Packs > 0 && !pack.MeetsPity(cards) {
+		// A última carta é sorteada de novo, só entre as raridades que cumprem a garantia
+		cards[len(cards)-1] = pack.rollCard(draw, abilities, pack.guaranteed())
+	}
+	return cards
+}
+
+// TableHash calcula o hash, em hexadecimal, de tudo o que decide o sorteio do pacote: os pesos
+// dos tipos, as faixas de raridade, a garantia e a ordem das habilidades que as cartas podem
+// receber. Nome, descrição e preço ficam de fora, porque não mudam as cartas sorteadas.
+func (pack PackType) TableHash(abilities []Ability) string {
+	abilityIDs := make([]string, len(abilities))
+	for index, ability := range abilities {
+		abilityIDs[index] = ability.ID
+	}
+	// A serialização de structs e slices tem ordem fixa, então o hash só muda com as tabelas.
+	raw, _ := json.Marshal(struct {
+		Types     []TypeWeight `json:"types"`
+		Rarities  []RarityTier `json:"rarities"`
+		Pity      Pity         `json:"pity"`
+		Abilities []string     `json:"abilities"`
+	}{pack.Types, pack.Rarities, pack.Pity, abilityIDs})
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// Guarantees informa se o próximo pacote deve cumprir a garantia de raridade, dado o número de
+// pacotes abertos desde a última vez que ela foi cumprida.
+func (pack PackType) Guarantees(opened int) bool {
+	return pack.Pity.Packs > 0 && opened+1 >= pack.Pity.Packs
+}
+
+// MeetsPity informa se alguma das cartas tem a raridade garantida pelo pacote, ou mais rara.
+func (pack PackType) MeetsPity(cards CardPackage) bool {
+	for _, card := range cards {
+		if RarityRank(card.Rarity) >= RarityRank(pack.Pity.Rarity) {
+			return true
+		}
+	}
+	return false
+}
+
+// guaranteed retorna as faixas com peso que cumprem a garantia de raridade.
+func (pack PackType) guaranteed() []RarityTier {
+	var tiers []RarityTier
+	for _, tier := range pack.Rarities {
+		if tier.Weight > 0 && RarityRank(tier.Rarity) >= RarityRank(pack.Pity.Rarity) {
+			tiers = append(tiers, tier)
+		}
+	}
+	return tiers
+}
+
+// rollCard sorteia uma carta entre as faixas de raridade informadas.
+func (pack PackType) rollCard(draw func(n int) int, abilities []Ability, tiers []RarityTier) Card {
+	tierWeights := make([]int, len(tiers))
+	for index, tier := range tiers {
+		tierWeights[index] = tier.Weight
+	}
+	tier := tiers[weightedIndex(draw, tierWeights)]
+
+	typeWeights := make([]int, len(pack.Types))
+	for index, weight := range pack.Types {
+		typeWeights[index] = weight.Weight
+	}
+	starWeights := make([]int, len(tier.Stars))
+	for index, weight := range tier.Stars {
+		starWeights[index] = weight.Weight
+	}
+	card := Card{
+		Type:   pack.Types[weightedIndex(draw, typeWeights)].Type,
+		Stars:  tier.Stars[weightedIndex(draw, starWeights)].Stars,
+		Rarity: tier.Rarity,
+	}
+	if len(abilities) > 0 && draw(100) < tier.AbilityChance {
+		card.Ability = abilities[draw(len(abilities))].ID
+	}
+	return card
+}
+
+// weightedIndex sorteia uma posição com chance proporcional ao seu peso.
+func weightedIndex(draw func(n int) int, weights []int) int {
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+	target := draw(total)
+	for index, weight := range weights {
+		if target < weight {
+			return index
+		}
+		target -= weight
+	}
+	return len(weights) - 1
+}
+
+// PackPity guarda quantos pacotes de um tipo um usuário abriu desde a última carta da raridade
+// garantida.
+//
+// Campos:
+//   - UserID: usuário que abre os pacotes.
+//   - PackID: tipo de pacote.
+//   - Opened: pacotes abertos sem a raridade garantida.
+type PackPity struct {
+	UserID string `json:"user_id"`
+	PackID string `json:"pack_id"`
+	Opened int    `json:"opened"`
+}
+
+// PackPityKey retorna a chave do contador de garantia de um usuário em um tipo de pacote.
+func PackPityKey(userID, packID string) string {
+	return userID + "/" + packID
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+// scriptedDraw devolve os valores informados em ordem, sem passar de n-1, e repete o último.
+func scriptedDraw(values ...int) func(n int) int {
+	return func(n int) int {
+		value := values[0]
+		if len(values) > 1 {
+			values = values[1:]
+		}
+		return min(value, n-1)
+	}
+}
+
+// testPack cria um pacote de pedras e papéis, com cartas comuns de 1 estrela e épicas de 5.
+func testPack(pity Pity) PackType {
+	return PackType{
+		ID:    "test",
+		Price: 10,
+		Types: []TypeWeight{{Type: "rock", Weight: 3}, {Type: "paper", Weight: 1}},
+		Rarities: []RarityTier{
+			{Rarity: RarityCommon, Weight: 9, Stars: []StarWeight{{Stars: 1, Weight: 1}}},
+			{Rarity: RarityEpic, Weight: 1, Stars: []StarWeight{{Stars: 5, Weight: 1}}, AbilityChance: 100},
+		},
+		Pity: pity,
+	}
+}
+
+func TestWeightedIndex(t *testing.T) {
+	weights := []int{3, 0, 1}
+	for target, want := range []int{0, 0, 0, 2} {
+		if got := weightedIndex(scriptedDraw(target), weights); got != want {
+			t.Errorf("sorteio %d caiu na posição %d, esperado %d", target, got, want)
+		}
+	}
+}
+
+func TestPackRoll(t *testing.T) {
+	abilities := []Ability{{ID: "mirror"}, {ID: "rally"}}
+	tests := []struct {
+		name      string
+		pity      Pity
+		guarantee bool
+		draws     []int
+		index     int
+		want      Card
+	}{
+		// Cada carta sorteia raridade, tipo e estrelas; as épicas sorteiam também a habilidade.
+		{name: "comum", draws: []int{0, 0, 0}, want: Card{Type: "rock", Stars: 1, Rarity: RarityCommon}},
+		{name: "épica com habilidade", draws: []int{9, 3, 0, 0, 1}, want: Card{Type: "paper", Stars: 5, Rarity: RarityEpic, Ability: "rally"}},
+		{name: "garantia refaz a última carta", pity: Pity{Rarity: RarityEpic, Packs: 3}, guarantee: true, draws: []int{0}, index: 2, want: Card{Type: "rock", Stars: 5, Rarity: RarityEpic, Ability: "mirror"}},
+		{name: "sem garantia a última carta fica", pity: Pity{Rarity: RarityEpic, Packs: 3}, draws: []int{0}, index: 2, want: Card{Type: "rock", Stars: 1, Rarity: RarityCommon}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cards := testPack(test.pity).Roll(scriptedDraw(test.draws...), abilities, test.guarantee)
+			if got := cards[test.index]; got != test.want {
+				t.Errorf("carta %d = %+v, esperado %+v", test.index, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPackValidate(t *testing.T) {
+	ruleset := classicRuleset("")
+	tests := []struct {
+		name        string
+		change      func(pack *PackType)
+		noAbilities bool
+		wantErr     string
+	}{
+		{name: "pacote válido", change: func(pack *PackType) {}},
+		{name: "sem preço", change: func(pack *PackType) { pack.Price = 0 }, wantErr: "preço de pacote inválido"},
+		{name: "tipo fora das regras", change: func(pack *PackType) { pack.Types[1].Type = "spock" }, wantErr: "tipo de carta fora das regras"},
+		{name: "raridade repetida", change: func(pack *PackType) { pack.Rarities[1].Rarity = RarityCommon }, wantErr: "raridade inválida ou repetida"},
+		{name: "estrelas demais", change: func(pack *PackType) { pack.Rarities[0].Stars[0].Stars = MaxStars + 1 }, wantErr: "estrelas inválidas"},
+		{name: "habilidade sem habilidades nas regras", change: func(pack *PackType) {}, noAbilities: true, wantErr: "não têm nenhuma"},
+		{name: "garantia impossível", change: func(pack *PackType) { pack.Pity = Pity{Rarity: RarityLegendary, Packs: 5} }, wantErr: "não tem nenhuma raridade com peso"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pack := testPack(Pity{})
+			test.change(&pack)
+			rules := ruleset
+			if !test.noAbilities {
+				rules.Abilities = []Ability{{ID: "mirror"}}
+			}
+			err := pack.Validate(rules)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("erro = %v, esperado %q", err, test.wantErr)
+			}
+		})
+	}
+}
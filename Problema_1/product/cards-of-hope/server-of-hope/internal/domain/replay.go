@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// Tipos de evento do registro de uma partida.
+const (
+	ReplayMatchStarted  = "match_started"
+	ReplayJoined        = "joined"
+	ReplayLeft          = "left"
+	ReplayKicked        = "kicked"
+	ReplayChat          = "chat"
+	ReplayPlay          = "play"
+	ReplayRoundResult   = "round_result"
+	ReplayMatchFinished = "match_finished"
+)
+
+// Replay representa o registro de uma partida encerrada, com seus eventos em ordem.
+//
+// Campos:
+//   - ID: identificador do registro.
+//   - RoomID: sala em que a partida foi disputada.
+//   - RoomName: nome da sala no início da partida.
+//   - Number: número da partida dentro da série de revanches.
+//   - RulesetID: regras da partida.
+//   - Players: jogadores na ordem dos assentos.
+//   - Spectators: espectadores que acompanharam a partida.
+//   - StartedAt: início da partida.
+//   - FinishedAt: fim da partida.
+//   - WinnerID: vencedor da partida (vazio em empate ou abandono).
+//   - ForfeitedBy: jogador que perdeu a partida por revelações inválidas.
+//   - Abandoned: a partida terminou porque um jogador saiu da sala.
+//   - Events: eventos da partida, do mais antigo para o mais novo.
+type Replay struct {
+	ID          string        `json:"id"`
+	RoomID      string        `json:"room_id"`
+	RoomName    string        `json:"room_name"`
+	Number      int           `json:"match_number"`
+	RulesetID   string        `json:"ruleset_id"`
+	Players     []string      `json:"players"`
+	Spectators  []string      `json:"spectators"`
+	StartedAt   time.Time     `json:"started_at"`
+	FinishedAt  time.Time     `json:"finished_at"`
+	WinnerID    string        `json:"winner_id"`
+	ForfeitedBy string        `json:"forfeited_by,omitempty"`
+	Abandoned   bool          `json:"abandoned"`
+	Events      []ReplayEvent `json:"events"`
+}
+
+// ReplayEvent representa um evento do registro de uma partida.
+//
+// Campos:
+//   - At: momento do evento.
+//   - Type: tipo do evento.
+//   - UserID: usuário que causou o evento (vazio nos eventos da partida).
+//   - Round: rodada em andamento.
+//   - Card: carta jogada, nos eventos play.
+//   - Text: mensagem enviada, nos eventos chat.
+//   - Result: resultado da rodada, nos eventos round_result.
+type ReplayEvent struct {
+	At     time.Time    `json:"at"`
+	Type   string       `json:"type"`
+	UserID string       `json:"user_id,omitempty"`
+	Round  int          `json:"round,omitempty"`
+	Card   *Card        `json:"card,omitempty"`
+	Text   string       `json:"text,omitempty"`
+	Result *ReplayRound `json:"result,omitempty"`
+}
+
+// ReplayRound descreve o resultado de uma rodada no registro.
+//
+// Campos:
+//   - WinnerID: vencedor da rodada (vazio em empate).
+//   - Plays: cartas jogadas por jogador.
+//   - Effective: estrelas de cada carta após as habilidades.
+//   - Effects: habilidades aplicadas, na ordem.
+//   - Scores: rodadas vencidas por jogador após a rodada.
+type ReplayRound struct {
+	WinnerID  string          `json:"winner_id"`
+	Plays     map[string]Card `json:"plays"`
+	Effective map[string]int  `json:"effective_stars"`
+	Effects   []AppliedEffect `json:"effects"`
+	Scores    map[string]int  `json:"scores"`
+}
+
+// Involves informa se o usuário jogou ou assistiu à partida.
+func (replay Replay) Involves(userID string) bool {
+	for _, playerID := range append(append([]string(nil), replay.Players...), replay.Spectators...) {
+		if playerID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Rounds retorna quantas rodadas foram resolvidas na partida.
+func (replay Replay) Rounds() int {
+	rounds := 0
+	for _, event := range replay.Events {
+		if event.Type == ReplayRoundResult {
+			rounds++
+		}
+	}
+	return rounds
+}
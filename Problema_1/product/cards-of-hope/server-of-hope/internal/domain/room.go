@@ -1,30 +1,219 @@
 package domain
 
-import "server-of-hope/internal/utils"
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"server-of-hope/internal/utils"
+	"time"
+)
+
+// RoomCapacity define a quantidade de jogadores de uma sala.
+const RoomCapacity = 2
+
+// SpectatorMessageBuffer define quantas mensagens ficam pendentes para cada espectador.
+// Mensagens além desse limite são descartadas para que um espectador inativo não trave a sala.
+const SpectatorMessageBuffer = 16
+
+// Estados possíveis de uma sala. A sala aguarda jogadores (waiting), confere se os dois
+// estão prontos (ready_check), disputa uma partida (playing) e, ao fim dela, fica encerrada
+// (finished) até que os jogadores fiquem prontos novamente.
+const (
+	RoomStatusWaiting    = "waiting"
+	RoomStatusReadyCheck = "ready_check"
+	RoomStatusPlaying    = "playing"
+	RoomStatusFinished   = "finished"
+)
+
+// ValidRoomStatus informa se o estado de sala é conhecido.
+func ValidRoomStatus(status string) bool {
+	switch status {
+	case RoomStatusWaiting, RoomStatusReadyCheck, RoomStatusPlaying, RoomStatusFinished:
+		return true
+	}
+	return false
+}
 
 // Room representa uma sala de jogo.
 //
 // Campos:
 //   - ID: identificador único da sala.
-//   - UserIDs: IDs dos usuários presentes na sala.
-//   - Messages: canais de mensagens para cada usuário.
+//   - Name: nome de exibição da sala.
+//   - OwnerID: ID do usuário que criou a sala.
+//   - HostID: ID do anfitrião atual, que modera a sala (inicialmente o criador).
+//   - Capacity: quantidade máxima de jogadores.
+//   - Status: estado atual da sala (waiting, ready_check, playing, finished).
+//   - CreatedAt: momento de criação da sala.
+//   - Private: indica se a entrada exige senha ou convite.
+//   - PasswordHash: hash da senha da sala privada (vazio se a sala só aceita convites).
+//   - Locked: impede a entrada de novos jogadores e espectadores.
+//   - Bans: usuários expulsos e o momento até o qual não podem voltar.
+//   - UserIDs: IDs dos jogadores presentes na sala.
+//   - Spectators: IDs dos espectadores, que não ocupam vagas de jogador.
+//   - Ready: IDs dos jogadores que confirmaram estar prontos para a próxima partida.
+//   - RematchRequests: IDs dos jogadores que pediram revanche após a partida encerrada.
+//   - RematchDeadline: prazo para que o outro jogador aceite a revanche.
+//   - RulesetID: conjunto de regras das partidas da sala, escolhido na criação.
+//   - DeckChoices: baralho escolhido por cada jogador para as próximas partidas da sala.
+//   - CommitReveal: as jogadas são feitas com compromisso e revelação (veja Commitment).
+//   - TournamentID: torneio cuja partida é disputada na sala (vazio nas salas comuns).
+//   - Async: partidas por correspondência, em que cada jogador tem TurnHours horas para jogar
+//     cada rodada e pode jogar mesmo com o oponente desconectado.
+//   - TurnHours: prazo de cada rodada das partidas por correspondência, em horas.
+//   - WagerCoins: moedas que cada jogador aposta em cada partida da sala.
+//   - WagerCards: quantidade de cartas que cada jogador aposta em cada partida da sala.
+//   - Stakes: cartas escolhidas por cada jogador para a aposta da próxima partida.
+//   - WagerID: aposta retida da partida atual ou da última partida da sala.
+//   - Messages: canais de mensagens para cada jogador e espectador.
 type Room struct {
-	ID       string                          `json:"id"`
-	UserIDs  *utils.Set[string]              `json:"user_ids"`
-	Messages *utils.Map[string, chan string] `json:"-"`
+	ID              string                          `json:"id"`
+	Name            string                          `json:"name"`
+	OwnerID         string                          `json:"owner_id"`
+	HostID          string                          `json:"host_id"`
+	Capacity        int                             `json:"capacity"`
+	Status          string                          `json:"status"`
+	CreatedAt       time.Time                       `json:"created_at"`
+	Private         bool                            `json:"private"`
+	PasswordHash    string                          `json:"password_hash,omitempty"`
+	Locked          bool                            `json:"locked"`
+	Bans            *utils.Map[string, time.Time]   `json:"bans"`
+	UserIDs         *utils.Set[string]              `json:"user_ids"`
+	Spectators      *utils.Set[string]              `json:"spectators"`
+	Ready           *utils.Set[string]              `json:"ready"`
+	RematchRequests *utils.Set[string]              `json:"rematch_requests"`
+	RematchDeadline time.Time                       `json:"rematch_deadline"`
+	RulesetID       string                          `json:"ruleset_id"`
+	DeckChoices     *utils.Map[string, string]      `json:"deck_choices"`
+	CommitReveal    bool                            `json:"commit_reveal"`
+	TournamentID    string                          `json:"tournament_id,omitempty"`
+	Async           bool                            `json:"async,omitempty"`
+	TurnHours       int                             `json:"turn_hours,omitempty"`
+	WagerCoins      int                             `json:"wager_coins,omitempty"`
+	WagerCards      int                             `json:"wager_cards,omitempty"`
+	Stakes          *utils.Map[string, []string]    `json:"stakes"`
+	WagerID         string                          `json:"wager_id,omitempty"`
+	Messages        *utils.Map[string, chan string] `json:"-"`
 }
 
-// NewRoom cria uma nova sala com o ID informado.
+// NewRoom cria uma nova sala com o ID, nome e dono informados.
 //
 // Parâmetros:
 //   - id: identificador da sala.
+//   - name: nome de exibição da sala.
+//   - ownerID: ID do usuário que criou a sala.
 //
 // Retorno:
 //   - ponteiro para Room.
-func NewRoom(id string) *Room {
+func NewRoom(id, name, ownerID string) *Room {
 	return &Room{
-		ID:       id,
-		UserIDs:  utils.NewSet[string](),
-		Messages: utils.NewMap[string, chan string](),
+		ID:              id,
+		Name:            name,
+		OwnerID:         ownerID,
+		HostID:          ownerID,
+		Capacity:        RoomCapacity,
+		Status:          RoomStatusWaiting,
+		RulesetID:       DefaultRulesetID,
+		CreatedAt:       time.Now(),
+		UserIDs:         utils.NewSet[string](),
+		Spectators:      utils.NewSet[string](),
+		Ready:           utils.NewSet[string](),
+		RematchRequests: utils.NewSet[string](),
+		Bans:            utils.NewMap[string, time.Time](),
+		DeckChoices:     utils.NewMap[string, string](),
+		Stakes:          utils.NewMap[string, []string](),
+		Messages:        utils.NewMap[string, chan string](),
+	}
+}
+
+// IsFull informa se a sala atingiu a quantidade máxima de jogadores.
+func (room Room) IsFull() bool {
+	return room.UserIDs.Size() >= room.Capacity
+}
+
+// TurnTimeout retorna o prazo de cada rodada das partidas por correspondência, ou zero nas
+// salas em que os dois jogadores jogam ao mesmo tempo.
+func (room Room) TurnTimeout() time.Duration {
+	if !room.Async {
+		return 0
+	}
+	return time.Duration(room.TurnHours) * time.Hour
+}
+
+// HasWager informa se as partidas da sala valem uma aposta em moedas ou cartas.
+func (room Room) HasWager() bool {
+	return room.WagerCoins > 0 || room.WagerCards > 0
+}
+
+// IsSpectator informa se o usuário assiste à sala como espectador.
+func (room Room) IsSpectator(userID string) bool {
+	return room.Spectators != nil && room.Spectators.Contains(userID)
+}
+
+// IsMember informa se o usuário está na sala, como jogador ou espectador.
+func (room Room) IsMember(userID string) bool {
+	return room.UserIDs.Contains(userID) || room.IsSpectator(userID)
+}
+
+// IsBanned informa se o usuário foi expulso da sala e ainda não pode voltar.
+func (room Room) IsBanned(userID string) bool {
+	if room.Bans == nil {
+		return false
 	}
+	until, exists := room.Bans.Get(userID)
+	return exists && time.Now().Before(until)
+}
+
+// SetPassword define a senha da sala, armazenando apenas seu hash.
+// Uma senha vazia remove a proteção por senha.
+//
+// Parâmetros:
+//   - password: senha em texto puro.
+func (room *Room) SetPassword(password string) {
+	if password == "" {
+		room.PasswordHash = ""
+		return
+	}
+	room.PasswordHash = hashRoomPassword(room.ID, password)
+}
+
+// CheckPassword informa se a senha confere com a senha da sala.
+//
+// Parâmetros:
+//   - password: senha em texto puro.
+//
+// Retorno:
+//   - bool: true se a sala tem senha e ela confere.
+func (room Room) CheckPassword(password string) bool {
+	if room.PasswordHash == "" || password == "" {
+		return false
+	}
+	expected := hashRoomPassword(room.ID, password)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(room.PasswordHash)) == 1
+}
+
+// hashRoomPassword calcula o hash da senha usando o ID da sala como sal.
+func hashRoomPassword(roomID, password string) string {
+	sum := sha256.Sum256([]byte(roomID + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// Invite representa um código de convite para uma sala privada.
+//
+// Campos:
+//   - Code: código compartilhável do convite.
+//   - RoomID: sala à qual o convite dá acesso.
+//   - CreatedBy: usuário que gerou o convite.
+//   - ExpiresAt: momento em que o convite deixa de valer.
+//   - SingleUse: indica se o convite é consumido no primeiro uso.
+type Invite struct {
+	Code      string    `json:"code"`
+	RoomID    string    `json:"room_id"`
+	CreatedBy string    `json:"created_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+	SingleUse bool      `json:"single_use"`
+}
+
+// Expired informa se o convite já expirou.
+func (invite Invite) Expired() bool {
+	return time.Now().After(invite.ExpiresAt)
 }
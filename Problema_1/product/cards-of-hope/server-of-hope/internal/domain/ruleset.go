@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultRulesetID identifica o conjunto de regras usado quando a sala não escolhe outro.
+const DefaultRulesetID = "classic"
+
+// Políticas de desempate por estrelas, aplicadas quando nenhum tipo vence o outro: vence a
+// carta com mais estrelas (higher), com menos estrelas (lower) ou a rodada empata (none).
+const (
+	StarTieBreakHigher = "higher"
+	StarTieBreakLower  = "lower"
+	StarTieBreakNone   = "none"
+)
+
+// Ruleset descreve as regras de uma partida, definidas como dados no servidor.
+//
+// Campos:
+//   - ID: identificador do conjunto de regras.
+//   - Name: nome de exibição.
+//   - Description: resumo das regras para os jogadores.
+//   - Cards: tipos de carta aceitos.
+//   - Beats: tipos que cada tipo vence; pares sem relação são decididos pelas estrelas.
+//   - StarTieBreak: política de desempate por estrelas (higher, lower ou none).
+//   - WinningRounds: rodadas que um jogador precisa vencer para ganhar a partida.
+//   - MaxRounds: limite de rodadas da partida (0 sem limite); ao atingi-lo, vence quem tiver
+//     mais rodadas e, com placar igual, a partida termina empatada.
+//   - Abilities: habilidades especiais que as cartas podem carregar nestas regras.
+type Ruleset struct {
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Description   string              `json:"description"`
+	Cards         []string            `json:"cards"`
+	Beats         map[string][]string `json:"beats"`
+	StarTieBreak  string              `json:"star_tie_break"`
+	WinningRounds int                 `json:"winning_rounds"`
+	MaxRounds     int                 `json:"max_rounds"`
+	Abilities     []Ability           `json:"abilities"`
+}
+
+// HasCard informa se o tipo de carta faz parte das regras.
+func (ruleset Ruleset) HasCard(cardType string) bool {
+	for _, card := range ruleset.Cards {
+		if card == cardType {
+			return true
+		}
+	}
+	return false
+}
+
+// Ability retorna a habilidade declarada nas regras com o ID informado.
+func (ruleset Ruleset) Ability(abilityID string) (Ability, bool) {
+	for _, ability := range ruleset.Abilities {
+		if ability.ID == abilityID {
+			return ability, true
+		}
+	}
+	return Ability{}, false
+}
+
+// Defeats informa se o tipo de carta attacker vence o tipo defender.
+func (ruleset Ruleset) Defeats(attacker, defender string) bool {
+	for _, beaten := range ruleset.Beats[attacker] {
+		if beaten == defender {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare compara duas cartas: o tipo decide a disputa e, quando nenhum tipo vence o outro,
+// a política de desempate por estrelas é aplicada.
+//
+// Parâmetros:
+//   - first: carta do primeiro jogador.
+//   - second: carta do segundo jogador.
+//
+// Retorno:
+//   - int: 1 se a primeira vence, -1 se a segunda vence e 0 em caso de empate.
+func (ruleset Ruleset) Compare(first, second Card) int {
+	switch {
+	case ruleset.Defeats(first.Type, second.Type):
+		return 1
+	case ruleset.Defeats(second.Type, first.Type):
+		return -1
+	case first.Stars == second.Stars:
+		return 0
+	}
+	switch ruleset.StarTieBreak {
+	case StarTieBreakHigher:
+		if first.Stars > second.Stars {
+			return 1
+		}
+		return -1
+	case StarTieBreakLower:
+		if first.Stars < second.Stars {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// Validate confere se as regras são consistentes.
+//
+// Retorno:
+//   - erro agregando todas as inconsistências encontradas, ou nil.
+func (ruleset Ruleset) Validate() error {
+	var problems []error
+	if ruleset.ID == "" {
+		problems = append(problems, errors.New("conjunto de regras sem ID"))
+	}
+	if len(ruleset.Cards) < 2 {
+		problems = append(problems, errors.New("são necessários ao menos dois tipos de carta"))
+	}
+	seen := make(map[string]bool, len(ruleset.Cards))
+	for _, card := range ruleset.Cards {
+		if card == "" || seen[card] {
+			problems = append(problems, fmt.Errorf("tipo de carta vazio ou repetido: %q", card))
+		}
+		seen[card] = true
+	}
+	for attacker, beaten := range ruleset.Beats {
+		if !seen[attacker] {
+			problems = append(problems, fmt.Errorf("tipo de carta desconhecido em beats: %q", attacker))
+		}
+		for _, defender := range beaten {
+			if !seen[defender] {
+				problems = append(problems, fmt.Errorf("tipo de carta desconhecido em beats: %q", defender))
+			}
+			if defender == attacker || ruleset.Defeats(defender, attacker) {
+				problems = append(problems, fmt.Errorf("relação de vitória ambígua entre %q e %q", attacker, defender))
+			}
+		}
+	}
+	switch ruleset.StarTieBreak {
+	case StarTieBreakHigher, StarTieBreakLower, StarTieBreakNone:
+	default:
+		problems = append(problems, fmt.Errorf("política de desempate inválida: %q", ruleset.StarTieBreak))
+	}
+	if ruleset.WinningRounds < 1 {
+		problems = append(problems, errors.New("a partida precisa de ao menos uma rodada vencida"))
+	}
+	if ruleset.MaxRounds != 0 && ruleset.MaxRounds < ruleset.WinningRounds {
+		problems = append(problems, errors.New("o limite de rodadas é menor que as rodadas para vencer"))
+	}
+	abilities := make(map[string]bool, len(ruleset.Abilities))
+	for _, ability := range ruleset.Abilities {
+		if err := ability.Validate(); err != nil {
+			problems = append(problems, err)
+		}
+		if abilities[ability.ID] {
+			problems = append(problems, fmt.Errorf("habilidade repetida: %q", ability.ID))
+		}
+		abilities[ability.ID] = true
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("regras %q inválidas: %w", ruleset.ID, errors.Join(problems...))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+// classicRuleset reproduz as regras clássicas com a política de desempate informada.
+func classicRuleset(starTieBreak string) Ruleset {
+	return Ruleset{
+		ID:            "classic",
+		Cards:         []string{"rock", "paper", "scissors"},
+		Beats:         map[string][]string{"rock": {"scissors"}, "paper": {"rock"}, "scissors": {"paper"}},
+		StarTieBreak:  starTieBreak,
+		WinningRounds: 2,
+	}
+}
+
+func TestRulesetCompare(t *testing.T) {
+	rpsls := Ruleset{
+		ID:    "rpsls",
+		Cards: []string{"rock", "paper", "scissors", "lizard", "spock"},
+		Beats: map[string][]string{
+			"rock":     {"scissors", "lizard"},
+			"paper":    {"rock", "spock"},
+			"scissors": {"paper", "lizard"},
+			"lizard":   {"spock", "paper"},
+			"spock":    {"scissors", "rock"},
+		},
+		StarTieBreak: StarTieBreakHigher,
+	}
+	tests := []struct {
+		name    string
+		ruleset Ruleset
+		first   Card
+		second  Card
+		want    int
+	}{
+		{name: "o tipo decide antes das estrelas", ruleset: classicRuleset(StarTieBreakHigher), first: Card{Type: "rock", Stars: 1}, second: Card{Type: "scissors", Stars: 5}, want: 1},
+		{name: "o tipo perdedor", ruleset: classicRuleset(StarTieBreakHigher), first: Card{Type: "rock", Stars: 5}, second: Card{Type: "paper", Stars: 1}, want: -1},
+		{name: "mais estrelas vencem", ruleset: classicRuleset(StarTieBreakHigher), first: Card{Type: "rock", Stars: 3}, second: Card{Type: "rock", Stars: 2}, want: 1},
+		{name: "menos estrelas vencem", ruleset: classicRuleset(StarTieBreakLower), first: Card{Type: "rock", Stars: 3}, second: Card{Type: "rock", Stars: 2}, want: -1},
+		{name: "sem desempate por estrelas", ruleset: classicRuleset(StarTieBreakNone), first: Card{Type: "rock", Stars: 3}, second: Card{Type: "rock", Stars: 2}, want: 0},
+		{name: "estrelas iguais empatam", ruleset: classicRuleset(StarTieBreakHigher), first: Card{Type: "paper", Stars: 2}, second: Card{Type: "paper", Stars: 2}, want: 0},
+		{name: "lagarto envenena Spock", ruleset: rpsls, first: Card{Type: "lizard", Stars: 1}, second: Card{Type: "spock", Stars: 3}, want: 1},
+		{name: "Spock vaporiza pedra", ruleset: rpsls, first: Card{Type: "rock", Stars: 3}, second: Card{Type: "spock", Stars: 1}, want: -1},
+		{name: "tipos sem relação usam as estrelas", ruleset: rpsls, first: Card{Type: "lizard", Stars: 2}, second: Card{Type: "rock", Stars: 1}, want: -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.ruleset.Compare(test.first, test.second); got != test.want {
+				t.Errorf("Compare(%v, %v) = %d, esperado %d", test.first, test.second, got, test.want)
+			}
+			// Inverter as cartas inverte o resultado.
+			if got := test.ruleset.Compare(test.second, test.first); got != -test.want {
+				t.Errorf("Compare(%v, %v) = %d, esperado %d", test.second, test.first, got, -test.want)
+			}
+		})
+	}
+}
+
+func TestRulesetValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(ruleset *Ruleset)
+		wantErr string
+	}{
+		{name: "regras válidas", change: func(*Ruleset) {}},
+		{name: "sem ID", change: func(ruleset *Ruleset) { ruleset.ID = "" }, wantErr: "sem ID"},
+		{name: "um só tipo", change: func(ruleset *Ruleset) { ruleset.Cards = []string{"rock"}; ruleset.Beats = nil }, wantErr: "ao menos dois tipos"},
+		{name: "tipo repetido", change: func(ruleset *Ruleset) { ruleset.Cards = append(ruleset.Cards, "rock") }, wantErr: "vazio ou repetido"},
+		{name: "tipo desconhecido em beats", change: func(ruleset *Ruleset) { ruleset.Beats["rock"] = []string{"lizard"} }, wantErr: "desconhecido em beats"},
+		{name: "vitória nos dois sentidos", change: func(ruleset *Ruleset) { ruleset.Beats["scissors"] = []string{"paper", "rock"} }, wantErr: "ambígua"},
+		{name: "desempate desconhecido", change: func(ruleset *Ruleset) { ruleset.StarTieBreak = "random" }, wantErr: "desempate inválida"},
+		{name: "sem rodadas para vencer", change: func(ruleset *Ruleset) { ruleset.WinningRounds = 0 }, wantErr: "ao menos uma rodada"},
+		{name: "limite menor que a vitória", change: func(ruleset *Ruleset) { ruleset.MaxRounds = 1 }, wantErr: "limite de rodadas"},
+		{
+			name: "habilidade repetida",
+			change: func(ruleset *Ruleset) {
+				ability := Ability{ID: "rally", Effect: EffectBoostStars, Amount: 1}
+				ruleset.Abilities = []Ability{ability, ability}
+			},
+			wantErr: "habilidade repetida",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ruleset := classicRuleset(StarTieBreakHigher)
+			test.change(&ruleset)
+			err := ruleset.Validate()
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("erro = %v, esperado %q", err, test.wantErr)
+			}
+		})
+	}
+}
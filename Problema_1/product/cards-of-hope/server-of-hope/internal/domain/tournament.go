@@ -0,0 +1,295 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Formatos de torneio. Na eliminação simples, o vencedor de cada partida avança na chave até
+// a final; no todos contra todos, cada jogador enfrenta todos os outros uma vez e vence quem
+// somar mais pontos.
+const (
+	TournamentSingleElimination = "single_elimination"
+	TournamentRoundRobin        = "round_robin"
+)
+
+// Estados de um torneio: aceita inscrições (registering), disputa as partidas (running) e,
+// quando a última partida termina, fica encerrado (finished).
+const (
+	TournamentStatusRegistering = "registering"
+	TournamentStatusRunning     = "running"
+	TournamentStatusFinished    = "finished"
+)
+
+// Estados de uma partida do torneio. A partida aguarda os jogadores das partidas anteriores ou
+// a rodada dela (pending), é disputada em uma sala (playing) e termina com um vencedor ou, no
+// todos contra todos, empatada (finished). Na eliminação simples, quem fica sem oponente na
+// primeira rodada avança direto (bye).
+const (
+	TournamentMatchPending  = "pending"
+	TournamentMatchPlaying  = "playing"
+	TournamentMatchFinished = "finished"
+	TournamentMatchBye      = "bye"
+)
+
+// Pontos de cada resultado na classificação do todos contra todos.
+const (
+	TournamentWinPoints  = 3
+	TournamentDrawPoints = 1
+)
+
+// ValidTournamentFormat informa se o formato de torneio é conhecido.
+func ValidTournamentFormat(format string) bool {
+	return format == TournamentSingleElimination || format == TournamentRoundRobin
+}
+
+// Tournament representa um torneio disputado em várias salas.
+//
+// Campos:
+//   - ID: identificador do torneio.
+//   - Name: nome de exibição do torneio.
+//   - OwnerID: usuário que criou o torneio e pode iniciá-lo.
+//   - Format: formato do torneio (single_elimination ou round_robin).
+//   - Size: quantidade máxima de jogadores; o torneio começa sozinho ao lotar.
+//   - RulesetID: regras das partidas do torneio.
+//   - Status: estado do torneio (registering, running, finished).
+//   - Players: jogadores inscritos, na ordem de inscrição.
+//   - Matches: partidas do torneio, ordenadas por rodada.
+//   - WinnerID: campeão (vazio enquanto o torneio não termina ou em empate no topo da classificação).
+//   - CreatedAt: momento de criação do torneio.
+//   - StartedAt: momento em que a chave foi montada.
+//   - FinishedAt: momento em que a última partida terminou.
+type Tournament struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	OwnerID    string            `json:"owner_id"`
+	Format     string            `json:"format"`
+	Size       int               `json:"size"`
+	RulesetID  string            `json:"ruleset_id"`
+	Status     string            `json:"status"`
+	Players    []string          `json:"players"`
+	Matches    []TournamentMatch `json:"matches"`
+	WinnerID   string            `json:"winner_id"`
+	CreatedAt  time.Time         `json:"created_at"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt time.Time         `json:"finished_at"`
+}
+
+// TournamentMatch representa uma partida da chave de um torneio.
+//
+// Campos:
+//   - ID: identificador da partida no torneio, no formato "<rodada>-<posição>".
+//   - Round: rodada da partida, começando em 1.
+//   - Slot: posição da partida na rodada, começando em 0.
+//   - Players: os dois jogadores da partida (vazio enquanto a vaga não é definida).
+//   - RoomID: sala em que a partida é disputada.
+//   - Status: estado da partida (pending, playing, finished, bye).
+//   - WinnerID: vencedor da partida (vazio em empate).
+//   - Walkover: a partida foi decidida porque um jogador saiu da sala.
+type TournamentMatch struct {
+	ID       string    `json:"id"`
+	Round    int       `json:"round"`
+	Slot     int       `json:"slot"`
+	Players  [2]string `json:"players"`
+	RoomID   string    `json:"room_id,omitempty"`
+	Status   string    `json:"status"`
+	WinnerID string    `json:"winner_id"`
+	Walkover bool      `json:"walkover,omitempty"`
+}
+
+// Decided informa se a partida já tem resultado, inclusive por bye.
+func (match TournamentMatch) Decided() bool {
+	return match.Status == TournamentMatchFinished || match.Status == TournamentMatchBye
+}
+
+// Has informa se o usuário joga a partida.
+func (match TournamentMatch) Has(userID string) bool {
+	return userID != "" && (match.Players[0] == userID || match.Players[1] == userID)
+}
+
+// Opponent retorna o oponente do usuário na partida.
+func (match TournamentMatch) Opponent(userID string) string {
+	if match.Players[0] == userID {
+		return match.Players[1]
+	}
+	return match.Players[0]
+}
+
+// TournamentStanding representa a campanha de um jogador no torneio.
+//
+// Campos:
+//   - UserID: jogador.
+//   - Played: partidas decididas que o jogador disputou.
+//   - Wins: vitórias, inclusive por W.O.
+//   - Draws: empates.
+//   - Losses: derrotas.
+//   - Points: pontos no todos contra todos.
+type TournamentStanding struct {
+	UserID string `json:"user_id"`
+	Played int    `json:"played"`
+	Wins   int    `json:"wins"`
+	Draws  int    `json:"draws"`
+	Losses int    `json:"losses"`
+	Points int    `json:"points"`
+}
+
+// IsRegistered informa se o usuário está inscrito no torneio.
+func (tournament Tournament) IsRegistered(userID string) bool {
+	for _, playerID := range tournament.Players {
+		if playerID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchIndex retorna a posição em Matches da partida disputada na sala.
+func (tournament Tournament) MatchIndex(roomID string) (int, bool) {
+	for index, match := range tournament.Matches {
+		if roomID != "" && match.RoomID == roomID {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+// Rounds retorna quantas rodadas a chave do torneio tem.
+func (tournament Tournament) Rounds() int {
+	rounds := 0
+	for _, match := range tournament.Matches {
+		rounds = max(rounds, match.Round)
+	}
+	return rounds
+}
+
+// Standings retorna a campanha de cada inscrito, do primeiro ao último colocado: mais pontos,
+// depois mais vitórias e, por fim, ordem alfabética. Byes não contam como partidas.
+func (tournament Tournament) Standings() []TournamentStanding {
+	standings := make(map[string]*TournamentStanding, len(tournament.Players))
+	for _, playerID := range tournament.Players {
+		standings[playerID] = &TournamentStanding{UserID: playerID}
+	}
+	for _, match := range tournament.Matches {
+		if match.Status != TournamentMatchFinished {
+			continue
+		}
+		for _, playerID := range match.Players {
+			standing, ok := standings[playerID]
+			if !ok {
+				continue
+			}
+			standing.Played++
+			switch match.WinnerID {
+			case "":
+				standing.Draws++
+				standing.Points += TournamentDrawPoints
+			case playerID:
+				standing.Wins++
+				standing.Points += TournamentWinPoints
+			default:
+				standing.Losses++
+			}
+		}
+	}
+
+	result := make([]TournamentStanding, 0, len(standings))
+	for _, standing := range standings {
+		result = append(result, *standing)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Points != result[j].Points {
+			return result[i].Points > result[j].Points
+		}
+		if result[i].Wins != result[j].Wins {
+			return result[i].Wins > result[j].Wins
+		}
+		return result[i].UserID < result[j].UserID
+	})
+	return result
+}
+
+// NewSingleEliminationBracket monta a chave de eliminação simples para os jogadores, na ordem
+// informada. A chave tem o tamanho da menor potência de 2 que comporta todos; as vagas que
+// sobram viram byes na primeira rodada, dados aos primeiros jogadores. As rodadas seguintes
+// começam vazias: a partida (r, i) recebe os vencedores de (r-1, 2i) e (r-1, 2i+1).
+//
+// Parâmetros:
+//   - players: jogadores inscritos (ao menos 2).
+//
+// Retorno:
+//   - partidas de todas as rodadas, ordenadas por rodada e posição.
+func NewSingleEliminationBracket(players []string) []TournamentMatch {
+	bracket := 2
+	for bracket < len(players) {
+		bracket *= 2
+	}
+	byes := bracket - len(players)
+
+	var matches []TournamentMatch
+	next := 0
+	for slot := 0; slot < bracket/2; slot++ {
+		match := newTournamentMatch(1, slot)
+		match.Players[0] = players[next]
+		next++
+		if slot < byes {
+			match.Status = TournamentMatchBye
+			match.WinnerID = match.Players[0]
+		} else {
+			match.Players[1] = players[next]
+			next++
+		}
+		matches = append(matches, match)
+	}
+	for round, size := 2, bracket/4; size >= 1; round, size = round+1, size/2 {
+		for slot := 0; slot < size; slot++ {
+			matches = append(matches, newTournamentMatch(round, slot))
+		}
+	}
+	return matches
+}
+
+// NewRoundRobinSchedule monta as rodadas do todos contra todos pelo método do círculo: o
+// primeiro jogador fica fixo e os demais giram a cada rodada. Com número ímpar de jogadores,
+// quem enfrentaria a vaga vazia folga na rodada.
+//
+// Parâmetros:
+//   - players: jogadores inscritos (ao menos 2).
+//
+// Retorno:
+//   - partidas de todas as rodadas, ordenadas por rodada e posição.
+func NewRoundRobinSchedule(players []string) []TournamentMatch {
+	circle := append([]string(nil), players...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, "")
+	}
+	size := len(circle)
+
+	var matches []TournamentMatch
+	for round := 1; round < size; round++ {
+		slot := 0
+		for index := 0; index < size/2; index++ {
+			first, second := circle[index], circle[size-1-index]
+			if first == "" || second == "" {
+				continue
+			}
+			match := newTournamentMatch(round, slot)
+			match.Players = [2]string{first, second}
+			matches = append(matches, match)
+			slot++
+		}
+		// Gira todos menos o primeiro: o último passa para a segunda posição
+		circle = append([]string{circle[0], circle[size-1]}, circle[1:size-1]...)
+	}
+	return matches
+}
+
+// newTournamentMatch cria uma partida pendente na rodada e posição informadas.
+func newTournamentMatch(round, slot int) TournamentMatch {
+	return TournamentMatch{
+		ID:     strconv.Itoa(round) + "-" + strconv.Itoa(slot+1),
+		Round:  round,
+		Slot:   slot,
+		Status: TournamentMatchPending,
+	}
+}
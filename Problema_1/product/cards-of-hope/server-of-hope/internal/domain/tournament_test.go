@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+)
+
+// matchLabels descreve as partidas como "id:jogador-jogador:estado", para comparar chaves inteiras.
+func matchLabels(matches []TournamentMatch) []string {
+	labels := make([]string, len(matches))
+	for index, match := range matches {
+		labels[index] = fmt.Sprintf("%s:%s-%s:%s", match.ID, match.Players[0], match.Players[1], match.Status)
+	}
+	return labels
+}
+
+func TestNewSingleEliminationBracket(t *testing.T) {
+	tests := []struct {
+		name    string
+		players []string
+		want    []string
+	}{
+		{name: "dois jogadores", players: []string{"a", "b"}, want: []string{"1-1:a-b:pending"}},
+		{
+			name:    "três jogadores, o primeiro avança direto",
+			players: []string{"a", "b", "c"},
+			want:    []string{"1-1:a-:bye", "1-2:b-c:pending", "2-1:-:pending"},
+		},
+		{
+			name:    "cinco jogadores",
+			players: []string{"a", "b", "c", "d", "e"},
+			want: []string{
+				"1-1:a-:bye", "1-2:b-:bye", "1-3:c-:bye", "1-4:d-e:pending",
+				"2-1:-:pending", "2-2:-:pending", "3-1:-:pending",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := matchLabels(NewSingleEliminationBracket(test.players))
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Errorf("chave = %v, esperado %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewRoundRobinSchedule(t *testing.T) {
+	for _, players := range [][]string{{"a", "b"}, {"a", "b", "c"}, {"a", "b", "c", "d"}, {"a", "b", "c", "d", "e"}} {
+		t.Run(fmt.Sprint(len(players), " jogadores"), func(t *testing.T) {
+			matches := NewRoundRobinSchedule(players)
+			pairs := make(map[[2]string]bool)
+			for _, match := range matches {
+				pair := match.Players
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if pairs[pair] {
+					t.Errorf("confronto %v repetido", pair)
+				}
+				pairs[pair] = true
+			}
+			// Todos enfrentam todos uma vez, em no máximo uma partida por jogador a cada rodada.
+			if want := len(players) * (len(players) - 1) / 2; len(pairs) != want {
+				t.Errorf("%d confrontos, esperado %d", len(pairs), want)
+			}
+			busy := make(map[string]bool)
+			for _, match := range matches {
+				for _, playerID := range match.Players {
+					key := fmt.Sprint(match.Round, playerID)
+					if busy[key] {
+						t.Errorf("%s joga duas vezes na rodada %d", playerID, match.Round)
+					}
+					busy[key] = true
+				}
+			}
+		})
+	}
+}
+
+func TestStandings(t *testing.T) {
+	tournament := Tournament{
+		Players: []string{"a", "b", "c", "d"},
+		Matches: []TournamentMatch{
+			{Players: [2]string{"a", "b"}, Status: TournamentMatchFinished, WinnerID: "a"},
+			{Players: [2]string{"c", "d"}, Status: TournamentMatchFinished},
+			{Players: [2]string{"a", "c"}, Status: TournamentMatchFinished, WinnerID: "c"},
+			{Players: [2]string{"b", "d"}, Status: TournamentMatchPlaying},
+			{Players: [2]string{"d", ""}, Status: TournamentMatchBye, WinnerID: "d"},
+		},
+	}
+	want := []TournamentStanding{
+		{UserID: "c", Played: 2, Wins: 1, Draws: 1, Points: TournamentWinPoints + TournamentDrawPoints},
+		{UserID: "a", Played: 2, Wins: 1, Losses: 1, Points: TournamentWinPoints},
+		{UserID: "d", Played: 1, Draws: 1, Points: TournamentDrawPoints},
+		{UserID: "b", Played: 1, Losses: 1},
+	}
+	if got := tournament.Standings(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("classificação = %+v, esperado %+v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// MaxTradeCards define quantas cartas cada lado de uma troca pode ter.
+const MaxTradeCards = DeckSize
+
+// Estados de uma proposta de troca. A proposta aguarda a resposta do destinatário (pending) e
+// termina aceita (accepted), recusada (declined), cancelada por quem a fez (cancelled), vencida
+// (expired) ou inválida porque alguma carta saiu da coleção do dono antes do aceite (failed).
+const (
+	TradePending   = "pending"
+	TradeAccepted  = "accepted"
+	TradeDeclined  = "declined"
+	TradeCancelled = "cancelled"
+	TradeExpired   = "expired"
+	TradeFailed    = "failed"
+)
+
+// ValidTradeStatus informa se o estado de proposta de troca é conhecido.
+func ValidTradeStatus(status string) bool {
+	switch status {
+	case TradePending, TradeAccepted, TradeDeclined, TradeCancelled, TradeExpired, TradeFailed:
+		return true
+	}
+	return false
+}
+
+// Trade representa uma proposta de troca de cartas entre dois usuários.
+//
+// Campos:
+//   - ID: identificador da proposta.
+//   - FromID: usuário que fez a proposta.
+//   - ToID: usuário que recebeu a proposta.
+//   - Offered: cartas da coleção de FromID oferecidas, como estavam na proposta.
+//   - Requested: cartas da coleção de ToID pedidas em troca, como estavam na proposta.
+//   - Status: estado da proposta (pending, accepted, declined, cancelled, expired, failed).
+//   - CreatedAt: momento da proposta.
+//   - ExpiresAt: momento em que a proposta deixa de valer.
+//   - ResolvedAt: momento em que a proposta saiu de pending.
+type Trade struct {
+	ID         string      `json:"id"`
+	FromID     string      `json:"from_id"`
+	ToID       string      `json:"to_id"`
+	Offered    []OwnedCard `json:"offered"`
+	Requested  []OwnedCard `json:"requested"`
+	Status     string      `json:"status"`
+	CreatedAt  time.Time   `json:"created_at"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	ResolvedAt time.Time   `json:"resolved_at"`
+}
+
+// Expired informa se a proposta pendente já passou do prazo.
+func (trade Trade) Expired() bool {
+	return trade.Status == TradePending && time.Now().After(trade.ExpiresAt)
+}
+
+// Involves informa se o usuário fez ou recebeu a proposta.
+func (trade Trade) Involves(userID string) bool {
+	return trade.FromID == userID || trade.ToID == userID
+}
+
+// CardIDs retorna os IDs das cartas informadas.
+func CardIDs(cards []OwnedCard) []string {
+	cardIDs := make([]string, 0, len(cards))
+	for _, card := range cards {
+		cardIDs = append(cardIDs, card.ID)
+	}
+	return cardIDs
+}
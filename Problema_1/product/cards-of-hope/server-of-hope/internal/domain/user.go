@@ -6,8 +6,16 @@ package domain
 //   - ID: identificador único do usuário.
 //   - Username: nome de usuário.
 //   - Password: senha do usuário (omitida na serialização, se vazia).
+//   - Collection: cartas que o usuário possui.
+//   - CardSeq: último ID atribuído a uma carta da coleção.
+//   - Decks: baralhos salvos, com os IDs das cartas da coleção que os formam.
+//   - Bot: usuário controlado pelo servidor; não faz login e fica fora das classificações.
 type User struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	Password string `json:"password,omitempty"`
+	ID         string              `json:"id"`
+	Username   string              `json:"username"`
+	Password   string              `json:"password,omitempty"`
+	Collection []OwnedCard         `json:"collection"`
+	CardSeq    int                 `json:"card_seq"`
+	Decks      map[string][]string `json:"decks"`
+	Bot        bool                `json:"bot,omitempty"`
 }
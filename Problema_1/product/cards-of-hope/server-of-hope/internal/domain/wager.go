@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// MaxWagerCards define quantas cartas cada jogador pode apostar em uma partida: as de um pacote da loja.
+const MaxWagerCards = len(CardPackage{})
+
+// Estados de uma aposta. As apostas ficam retidas (held) durante a partida e terminam pagas ao
+// vencedor (paid) ou devolvidas aos jogadores (refunded) no empate ou na partida interrompida.
+const (
+	WagerHeld     = "held"
+	WagerPaid     = "paid"
+	WagerRefunded = "refunded"
+)
+
+// ValidWagerStatus informa se o estado de aposta é conhecido.
+func ValidWagerStatus(status string) bool {
+	switch status {
+	case WagerHeld, WagerPaid, WagerRefunded:
+		return true
+	}
+	return false
+}
+
+// Stake representa o que um jogador apostou em uma partida.
+//
+// Campos:
+//   - Coins: moedas apostadas.
+//   - Cards: cartas apostadas, retiradas da coleção do jogador.
+type Stake struct {
+	Coins int    `json:"coins"`
+	Cards []Card `json:"cards,omitempty"`
+}
+
+// Wager representa as apostas retidas de uma partida de uma sala com aposta. As moedas e as
+// cartas saem das carteiras e das coleções no início da partida e ficam retidas até o fim dela.
+//
+// Campos:
+//   - ID: identificador da aposta.
+//   - RoomID: sala da partida.
+//   - Match: número da partida na série da sala.
+//   - Stakes: apostas de cada jogador.
+//   - Status: estado da aposta (held, paid, refunded).
+//   - WinnerID: jogador que recebeu as apostas, quando pagas.
+//   - CreatedAt: momento em que as apostas foram retidas.
+//   - SettledAt: momento em que a aposta saiu de held.
+type Wager struct {
+	ID        string           `json:"id"`
+	RoomID    string           `json:"room_id"`
+	Match     int              `json:"match"`
+	Stakes    map[string]Stake `json:"stakes"`
+	Status    string           `json:"status"`
+	WinnerID  string           `json:"winner_id,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	SettledAt time.Time        `json:"settled_at"`
+}
+
+// Pot soma as moedas e junta as cartas apostadas por todos os jogadores.
+func (wager Wager) Pot() (int, []Card) {
+	coins := 0
+	var cards []Card
+	for _, stake := range wager.Stakes {
+		coins += stake.Coins
+		cards = append(cards, stake.Cards...)
+	}
+	return coins, cards
+}
+
+// Involves informa se o usuário tem uma aposta retida.
+func (wager Wager) Involves(userID string) bool {
+	_, exists := wager.Stakes[userID]
+	return exists
+}
@@ -0,0 +1,253 @@
+package state
+
+import (
+	"fmt"
+	"server-of-hope/internal/data"
+	"server-of-hope/internal/domain"
+	"server-of-hope/internal/utils"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Snapshot captura o estado atual dos repositórios e da loja em um ArchiveData.
+//
+// Retorno:
+//   - data.ArchiveData: cópia do estado do servidor.
+//   - erro caso algum repositório não possa ser listado.
+func Snapshot() (data.ArchiveData, error) {
+	users, err := UserRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	rooms, err := RoomRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	games, err := GameRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	replays, err := ReplayRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	tournaments, err := TournamentRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	ledger, err := LedgerRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	sort.Slice(ledger, func(i, j int) bool {
+		return ledger[i].Seq < ledger[j].Seq
+	})
+	trades, err := TradeRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	listings, err := ListingRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	packPity, err := PackPityRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	packSeeds, err := PackSeedRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	packOpenings, err := PackOpeningRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	wagers, err := WagerRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	directMessages, err := DirectMessageRepository.List()
+	if err != nil {
+		return data.ArchiveData{}, err
+	}
+	sort.Slice(directMessages, func(i, j int) bool {
+		return directMessages[i].SentAt.Before(directMessages[j].SentAt)
+	})
+	return data.ArchiveData{
+		Users:          users,
+		Rooms:          rooms,
+		Games:          games,
+		Stock:          StoreService.Stock(),
+		Replays:        replays,
+		Tournaments:    tournaments,
+		Ledger:         ledger,
+		Trades:         trades,
+		Listings:       listings,
+		PackPity:       packPity,
+		PackSeeds:      packSeeds,
+		PackOpenings:   packOpenings,
+		Wagers:         wagers,
+		DirectMessages: directMessages,
+	}, nil
+}
+
+// Restore carrega nos repositórios e na loja o estado contido em um ArchiveData.
+// Deve ser chamado logo após Initialize, antes de o servidor aceitar conexões.
+//
+// Parâmetros:
+//   - archiveData: estado a ser restaurado.
+//
+// Retorno:
+//   - erro caso os dados sejam inconsistentes ou não possam ser gravados.
+func Restore(archiveData data.ArchiveData) error {
+	if err := archiveData.Verify(); err != nil {
+		return err
+	}
+	for _, user := range archiveData.Users {
+		if err := UserRepository.Create(user.ID, user); err != nil {
+			return fmt.Errorf("usuário %s: %w", user.ID, err)
+		}
+	}
+	for _, room := range archiveData.Rooms {
+		if room.UserIDs == nil {
+			room.UserIDs = utils.NewSet[string]()
+		}
+		if room.Spectators == nil {
+			room.Spectators = utils.NewSet[string]()
+		}
+		if room.Ready == nil {
+			room.Ready = utils.NewSet[string]()
+		}
+		if room.RematchRequests == nil {
+			room.RematchRequests = utils.NewSet[string]()
+		}
+		if room.Bans == nil {
+			room.Bans = utils.NewMap[string, time.Time]()
+		}
+		if room.DeckChoices == nil {
+			room.DeckChoices = utils.NewMap[string, string]()
+		}
+		if room.Stakes == nil {
+			room.Stakes = utils.NewMap[string, []string]()
+		}
+		if room.RulesetID == "" {
+			room.RulesetID = domain.DefaultRulesetID // Salas anteriores às regras configuráveis usam as clássicas
+		}
+		// Os canais de mensagens não são persistidos; cada membro recebe um novo canal vazio.
+		room.Messages = utils.NewMap[string, chan string]()
+		room.UserIDs.ForEach(func(userID string) {
+			room.Messages.Set(userID, make(chan string, 1))
+		})
+		room.Spectators.ForEach(func(userID string) {
+			room.Messages.Set(userID, make(chan string, domain.SpectatorMessageBuffer))
+		})
+		if err := RoomRepository.Create(room.ID, room); err != nil {
+			return fmt.Errorf("sala %s: %w", room.ID, err)
+		}
+		utils.AdvanceCount(room.ID)
+	}
+	for _, game := range archiveData.Games {
+		if game.Plays == nil {
+			game.Plays = utils.NewMap[string, domain.Card]()
+		}
+		if game.ResultsSeenBy == nil {
+			game.ResultsSeenBy = utils.NewSet[string]()
+		}
+		if game.FailedAttempts == nil {
+			game.FailedAttempts = utils.NewMap[string, int]()
+		}
+		if game.Scores == nil {
+			game.Scores = utils.NewMap[string, int]()
+		}
+		if game.Round == 0 {
+			game.Round = 1
+		}
+		if game.Number == 0 {
+			game.Number = 1
+		}
+		if game.Series == nil {
+			game.Series = utils.NewMap[string, int]()
+		}
+		if game.Seats == nil {
+			game.Seats = game.Scores.Keys()
+		}
+		if game.Reveals == nil {
+			game.Reveals = utils.NewSet[string]()
+		}
+		if game.Hands == nil {
+			game.Hands = utils.NewMap[string, []domain.OwnedCard]()
+		}
+		if game.DrawPiles == nil {
+			game.DrawPiles = utils.NewMap[string, []domain.OwnedCard]()
+		}
+		if game.Commitments == nil {
+			game.Commitments = utils.NewMap[string, string]()
+		}
+		if err := GameRepository.Create(game.ID, game); err != nil {
+			return fmt.Errorf("partida %s: %w", game.ID, err)
+		}
+	}
+	for _, replay := range archiveData.Replays {
+		if err := ReplayRepository.Create(replay.ID, replay); err != nil {
+			return fmt.Errorf("registro de partida %s: %w", replay.ID, err)
+		}
+		utils.AdvanceCount(replay.ID)
+	}
+	for _, tournament := range archiveData.Tournaments {
+		if err := TournamentRepository.Create(tournament.ID, tournament); err != nil {
+			return fmt.Errorf("torneio %s: %w", tournament.ID, err)
+		}
+		utils.AdvanceCount(tournament.ID)
+	}
+	for _, entry := range archiveData.Ledger {
+		if err := LedgerRepository.Create(strconv.Itoa(entry.Seq), entry); err != nil {
+			return fmt.Errorf("lançamento %d: %w", entry.Seq, err)
+		}
+	}
+	for _, trade := range archiveData.Trades {
+		if err := TradeRepository.Create(trade.ID, trade); err != nil {
+			return fmt.Errorf("proposta de troca %s: %w", trade.ID, err)
+		}
+		utils.AdvanceCount(trade.ID)
+	}
+	for _, listing := range archiveData.Listings {
+		if err := ListingRepository.Create(listing.ID, listing); err != nil {
+			return fmt.Errorf("anúncio %s: %w", listing.ID, err)
+		}
+		utils.AdvanceCount(listing.ID)
+	}
+	for _, pity := range archiveData.PackPity {
+		if err := PackPityRepository.Create(domain.PackPityKey(pity.UserID, pity.PackID), pity); err != nil {
+			return fmt.Errorf("garantia de %s no pacote %s: %w", pity.UserID, pity.PackID, err)
+		}
+	}
+	for _, seed := range archiveData.PackSeeds {
+		if err := PackSeedRepository.Create(seed.ID, seed); err != nil {
+			return fmt.Errorf("semente de pacotes %s: %w", seed.ID, err)
+		}
+		utils.AdvanceCount(seed.ID)
+	}
+	for _, opening := range archiveData.PackOpenings {
+		if err := PackOpeningRepository.Create(opening.ID, opening); err != nil {
+			return fmt.Errorf("pacote aberto %s: %w", opening.ID, err)
+		}
+		utils.AdvanceCount(opening.ID)
+	}
+	for _, wager := range archiveData.Wagers {
+		if err := WagerRepository.Create(wager.ID, wager); err != nil {
+			return fmt.Errorf("aposta %s: %w", wager.ID, err)
+		}
+		utils.AdvanceCount(wager.ID)
+	}
+	for _, message := range archiveData.DirectMessages {
+		if err := DirectMessageRepository.Create(message.ID, message); err != nil {
+			return fmt.Errorf("mensagem direta %s: %w", message.ID, err)
+		}
+		utils.AdvanceCount(message.ID)
+	}
+	for _, cardPackage := range archiveData.Stock {
+		StoreService.AddPackage(cardPackage)
+	}
+	return nil
+}
@@ -13,18 +13,51 @@ var UserConnections *utils.Map[string, string]
 // AuthService fornece autenticação de usuários.
 var AuthService application.AuthServiceInterface
 
+// RulesetService consulta os conjuntos de regras que as salas podem escolher.
+var RulesetService application.RulesetServiceInterface
+
 // RoomService gerencia as salas do sistema.
 var RoomService application.RoomServiceInterface
 
-// ChatService gerencia o envio e recebimento de mensagens entre usuários.
+// ChatService gerencia o envio e recebimento de mensagens entre usuários, nas salas e diretas.
 var ChatService application.ChatServiceInterface
 
 // StoreService gerencia os pacotes de cartas disponíveis na loja.
 var StoreService application.StoreServiceInterface
 
+// DeckService gerencia as coleções de cartas e os baralhos dos usuários.
+var DeckService application.DeckServiceInterface
+
 // GameService gerencia a lógica das partidas do jogo.
 var GameService application.GameServiceInterface
 
+// BotService controla os bots que ocupam o segundo assento das salas.
+var BotService application.BotServiceInterface
+
+// ReplayService guarda os registros das partidas.
+var ReplayService application.ReplayServiceInterface
+
+// TournamentService organiza os torneios e abre as salas das suas partidas.
+var TournamentService application.TournamentServiceInterface
+
+// WalletService guarda as carteiras de moedas e o livro-caixa dos lançamentos.
+var WalletService application.WalletServiceInterface
+
+// TradeService guarda as propostas de troca de cartas e faz as trocas aceitas.
+var TradeService application.TradeServiceInterface
+
+// MarketService guarda os anúncios do mercado de cartas e fecha os que terminam.
+var MarketService application.MarketServiceInterface
+
+// CraftingService cria cartas a partir de cartas repetidas e as desfaz em pó de criação.
+var CraftingService application.CraftingServiceInterface
+
+// ChallengeService guarda os desafios entre usuários conectados e abre as salas dos duelos.
+var ChallengeService application.ChallengeServiceInterface
+
+// WagerService retém as apostas das partidas com aposta e as paga ao vencedor ou devolve.
+var WagerService application.WagerServiceInterface
+
 // UserRepository armazena os dados dos usuários.
 var UserRepository data.RepositoryInterface[domain.User]
 
@@ -33,3 +66,39 @@ var RoomRepository data.RepositoryInterface[domain.Room]
 
 // GameRepository armazena os dados das partidas.
 var GameRepository data.RepositoryInterface[domain.Game]
+
+// RulesetRepository armazena os conjuntos de regras carregados na inicialização.
+var RulesetRepository data.RepositoryInterface[domain.Ruleset]
+
+// ReplayRepository armazena os registros das partidas encerradas.
+var ReplayRepository data.RepositoryInterface[domain.Replay]
+
+// TournamentRepository armazena os torneios e suas chaves.
+var TournamentRepository data.RepositoryInterface[domain.Tournament]
+
+// LedgerRepository armazena os lançamentos do livro-caixa de moedas.
+var LedgerRepository data.RepositoryInterface[domain.LedgerEntry]
+
+// TradeRepository armazena as propostas de troca de cartas.
+var TradeRepository data.RepositoryInterface[domain.Trade]
+
+// PackTypeRepository armazena os tipos de pacote da loja carregados na inicialização.
+var PackTypeRepository data.RepositoryInterface[domain.PackType]
+
+// PackPityRepository armazena os contadores das garantias de raridade dos pacotes.
+var PackPityRepository data.RepositoryInterface[domain.PackPity]
+
+// PackSeedRepository armazena as sementes dos sorteios verificáveis dos pacotes.
+var PackSeedRepository data.RepositoryInterface[domain.PackSeed]
+
+// PackOpeningRepository armazena os registros dos pacotes abertos.
+var PackOpeningRepository data.RepositoryInterface[domain.PackOpening]
+
+// ListingRepository armazena os anúncios do mercado de cartas.
+var ListingRepository data.RepositoryInterface[domain.Listing]
+
+// WagerRepository armazena as apostas das partidas com aposta.
+var WagerRepository data.RepositoryInterface[domain.Wager]
+
+// DirectMessageRepository armazena as mensagens diretas entre usuários.
+var DirectMessageRepository data.RepositoryInterface[domain.DirectMessage]
@@ -5,6 +5,7 @@ import (
 	"server-of-hope/internal/data"
 	"server-of-hope/internal/domain"
 	"server-of-hope/internal/utils"
+	"sync"
 )
 
 // Initialize inicializa os repositórios e serviços globais do servidor.
@@ -13,14 +14,61 @@ func Initialize() {
 
 	UserRepository = data.NewInMemoryRepository[domain.User]()
 	RoomRepository = data.NewInMemoryRepository[domain.Room]()
-	StoreService = application.NewStoreService()
 	GameRepository = data.NewInMemoryRepository[domain.Game]()
+	RulesetRepository = data.NewInMemoryRepository[domain.Ruleset]()
+	ReplayRepository = data.NewInMemoryRepository[domain.Replay]()
+	TournamentRepository = data.NewInMemoryRepository[domain.Tournament]()
+	LedgerRepository = data.NewInMemoryRepository[domain.LedgerEntry]()
+	TradeRepository = data.NewInMemoryRepository[domain.Trade]()
+	ListingRepository = data.NewInMemoryRepository[domain.Listing]()
+	PackTypeRepository = data.NewInMemoryRepository[domain.PackType]()
+	PackPityRepository = data.NewInMemoryRepository[domain.PackPity]()
+	PackSeedRepository = data.NewInMemoryRepository[domain.PackSeed]()
+	PackOpeningRepository = data.NewInMemoryRepository[domain.PackOpening]()
+	WagerRepository = data.NewInMemoryRepository[domain.Wager]()
+	DirectMessageRepository = data.NewInMemoryRepository[domain.DirectMessage]()
 	UserConnections = utils.NewMap[string, string]()
 
+	rulesets, err := data.LoadRulesets()
+	if err != nil {
+		panic(err) // As regras são embutidas no binário, então só falham se ele foi gerado com arquivos inválidos
+	}
+	for _, ruleset := range rulesets {
+		RulesetRepository.Create(ruleset.ID, ruleset)
+	}
+	defaultRuleset, _ := RulesetRepository.Read(domain.DefaultRulesetID)
+	packs, err := data.LoadPackTypes(defaultRuleset)
+	if err != nil {
+		panic(err) // Os pacotes também são embutidos no binário
+	}
+	for _, pack := range packs {
+		PackTypeRepository.Create(pack.ID, pack)
+	}
+	craftingRules, err := data.LoadCraftingRules()
+	if err != nil {
+		panic(err) // As receitas também são embutidas no binário
+	}
+
+	// Todos os serviços que alteram as salas compartilham o mesmo mutex, para que uma mudança não
+	// apague a outra.
+	roomMutex := &sync.Mutex{}
+
 	AuthService = application.NewAuthService(UserRepository)
-	RoomService = application.NewRoomService(RoomRepository)
-	ChatService = application.NewChatService(RoomRepository, UserRepository)
-	GameService = application.NewGameService(GameRepository, UserRepository, RoomRepository)
+	RulesetService = application.NewRulesetService(RulesetRepository)
+	StoreService = application.NewStoreService(PackTypeRepository, PackPityRepository, RulesetRepository, PackSeedRepository, PackOpeningRepository)
+	RoomService = application.NewRoomService(RoomRepository, RulesetRepository, roomMutex)
+	ChatService = application.NewChatService(RoomRepository, UserRepository, DirectMessageRepository)
+	DeckService = application.NewDeckService(UserRepository, RoomRepository, RulesetRepository, roomMutex)
+	WalletService = application.NewWalletService(LedgerRepository, UserRepository)
+	WagerService = application.NewWagerService(WagerRepository, UserRepository, RoomRepository, GameRepository, DeckService, WalletService, roomMutex)
+	GameService = application.NewGameService(GameRepository, UserRepository, RoomRepository, RulesetRepository, WagerService, roomMutex)
+	ReplayService = application.NewReplayService(ReplayRepository)
+	BotService = application.NewBotService(UserRepository, RoomRepository, RulesetRepository, RoomService, GameService)
+	TournamentService = application.NewTournamentService(TournamentRepository, UserRepository, RulesetRepository, RoomService)
+	TradeService = application.NewTradeService(TradeRepository, UserRepository, DeckService)
+	MarketService = application.NewMarketService(ListingRepository, UserRepository, DeckService, WalletService)
+	CraftingService = application.NewCraftingService(craftingRules, UserRepository, DeckService, WalletService)
+	ChallengeService = application.NewChallengeService(UserRepository, RulesetRepository, RoomService)
 }
 
 // Finalize libera os recursos e limpa os repositórios e serviços globais.
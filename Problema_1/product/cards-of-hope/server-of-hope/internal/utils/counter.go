@@ -1,10 +1,28 @@
 package utils
 
-import "strconv"
+import (
+	"strconv"
+	"sync/atomic"
+)
 
-var count uint64 = 0
+// count é compartilhado por todos os handlers, que rodam em goroutines próprias.
+var count atomic.Uint64
 
 func Count() string {
-	count++
-	return strconv.FormatUint(count, 10)
+	return strconv.FormatUint(count.Add(1), 10)
+}
+
+// AdvanceCount garante que o contador nunca gere novamente o ID numérico informado.
+// IDs não numéricos são ignorados.
+func AdvanceCount(id string) {
+	value, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return
+	}
+	for {
+		current := count.Load()
+		if value <= current || count.CompareAndSwap(current, value) {
+			return
+		}
+	}
 }
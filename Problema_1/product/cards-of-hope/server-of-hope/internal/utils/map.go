@@ -1,6 +1,9 @@
 package utils
 
-import "sync"
+import (
+	"encoding/json"
+	"sync"
+)
 
 // Map representa um mapa seguro para uso concorrente.
 type Map[K comparable, V any] struct {
@@ -79,3 +82,22 @@ func (m *Map[K, V]) ForEach(f func(K, V)) {
 		f(key, value)
 	}
 }
+
+// MarshalJSON serializa o mapa como um objeto JSON.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return json.Marshal(m.data)
+}
+
+// UnmarshalJSON substitui o conteúdo do mapa pelo objeto JSON informado.
+func (m *Map[K, V]) UnmarshalJSON(raw []byte) error {
+	data := make(map[K]V)
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.data = data
+	return nil
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+)
+
+// codeAlphabet contém os caracteres usados em códigos aleatórios, sem símbolos ambíguos (0/O, 1/I).
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// RandomCode gera um código aleatório criptograficamente seguro com o tamanho informado.
+func RandomCode(length int) string {
+	code := make([]byte, length)
+	limit := big.NewInt(int64(len(codeAlphabet)))
+	for i := range code {
+		index, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			panic(err) // crypto/rand só falha se o sistema não tiver fonte de entropia
+		}
+		code[i] = codeAlphabet[index.Int64()]
+	}
+	return string(code)
+}
+
+// RandomHex gera um valor aleatório criptograficamente seguro com a quantidade de bytes
+// informada, em hexadecimal.
+func RandomHex(size int) string {
+	value := make([]byte, size)
+	if _, err := rand.Read(value); err != nil {
+		panic(err) // crypto/rand só falha se o sistema não tiver fonte de entropia
+	}
+	return hex.EncodeToString(value)
+}
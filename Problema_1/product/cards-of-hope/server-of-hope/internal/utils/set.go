@@ -1,6 +1,9 @@
 package utils
 
-import "sync"
+import (
+	"encoding/json"
+	"sync"
+)
 
 // Set representa um conjunto de elementos únicos, seguro para uso concorrente.
 type Set[T comparable] struct {
@@ -68,3 +71,24 @@ func (s *Set[T]) ForEach(f func(T)) {
 		f(item)
 	}
 }
+
+// MarshalJSON serializa o conjunto como um array JSON.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Items())
+}
+
+// UnmarshalJSON substitui o conteúdo do conjunto pelos elementos do array JSON informado.
+func (s *Set[T]) UnmarshalJSON(raw []byte) error {
+	var items []T
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return err
+	}
+	data := make(map[T]struct{}, len(items))
+	for _, item := range items {
+		data[item] = struct{}{}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data = data
+	return nil
+}
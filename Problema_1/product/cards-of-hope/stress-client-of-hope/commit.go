@@ -0,0 +1,277 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Testa partidas completas no modo de compromisso e revelação: cada par de clientes cria uma
+// sala com commit_reveal, joga até o fim da partida e, com -badreveal, envia antes de cada
+// revelação correta uma revelação adulterada que o servidor precisa recusar.
+type commitStats struct {
+	matches  int64
+	rounds   int64
+	commits  int64
+	reveals  int64
+	rejected int64 // revelações adulteradas recusadas pelo servidor
+	accepted int64 // revelações adulteradas aceitas (falha de verificação)
+	errors   int64
+}
+
+// player é um jogador simulado com sua própria conexão.
+type player struct {
+	name string
+	conn net.Conn
+	enc  *json.Encoder
+	dec  *json.Decoder
+	hand []string // IDs das cartas na mão
+}
+
+const playerTimeout = 15 * time.Second
+
+func newPlayer(addr, name string) (*player, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &player{name: name, conn: conn, enc: json.NewEncoder(conn), dec: json.NewDecoder(conn)}, nil
+}
+
+func (p *player) send(method string, data Dict) error {
+	p.conn.SetDeadline(time.Now().Add(playerTimeout))
+	return p.enc.Encode(Request{Method: method, Data: data})
+}
+
+// next lê a próxima mensagem do servidor, guardando a mão quando ela chega.
+func (p *player) next() (Response, error) {
+	var resp Response
+	p.conn.SetDeadline(time.Now().Add(playerTimeout))
+	if err := p.dec.Decode(&resp); err != nil {
+		return resp, err
+	}
+	if resp.Method == "hand" {
+		p.hand = p.hand[:0]
+		cards, _ := resp.Data["hand"].([]any)
+		for _, item := range cards {
+			card, _ := item.(map[string]any)
+			if id, ok := card["id"].(string); ok {
+				p.hand = append(p.hand, id)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// expect lê mensagens até encontrar uma das informadas, descartando as demais.
+func (p *player) expect(methods ...string) (Response, error) {
+	for {
+		resp, err := p.next()
+		if err != nil {
+			return resp, err
+		}
+		for _, method := range methods {
+			if resp.Method == method {
+				return resp, nil
+			}
+		}
+	}
+}
+
+// request envia um comando e aguarda a resposta dele, exigindo status ok.
+func (p *player) request(method string, data Dict) (Response, error) {
+	if err := p.send(method, data); err != nil {
+		return Response{}, err
+	}
+	resp, err := p.expect(method)
+	if err == nil && resp.Status != "ok" {
+		message, _ := resp.Data["message"].(string)
+		err = fmt.Errorf("%s: %s", method, message)
+	}
+	return resp, err
+}
+
+func randomHex(size int) string {
+	buffer := make([]byte, size)
+	rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
+
+func commitmentOf(cardID, nonce string) string {
+	sum := sha256.Sum256([]byte(cardID + ":" + nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// playCommitRound joga uma rodada com compromisso e revelação. Retorna true quando a partida termina.
+func playCommitRound(p *player, roomID string, badReveal bool, s *commitStats) (bool, error) {
+	if len(p.hand) == 0 {
+		return false, fmt.Errorf("%s sem cartas na mão", p.name)
+	}
+	index, _ := rand.Int(rand.Reader, big.NewInt(int64(len(p.hand))))
+	cardID := p.hand[index.Int64()]
+	nonce := randomHex(16)
+
+	resp, err := p.request("commit", Dict{"user_id": p.name, "room_id": roomID, "commitment": commitmentOf(cardID, nonce)})
+	if err != nil {
+		return false, err
+	}
+	atomic.AddInt64(&s.commits, 1)
+	if ready, _ := resp.Data["reveal"].(bool); !ready {
+		// Aguarda o compromisso do oponente
+		for {
+			push, err := p.expect("committed")
+			if err != nil {
+				return false, err
+			}
+			if ready, _ := push.Data["reveal"].(bool); ready {
+				break
+			}
+		}
+	}
+
+	if badReveal {
+		if err := p.send("reveal", Dict{"user_id": p.name, "room_id": roomID, "card_id": cardID, "nonce": randomHex(16)}); err != nil {
+			return false, err
+		}
+		resp, err := p.expect("reveal")
+		if err != nil {
+			return false, err
+		}
+		if resp.Status == "ok" {
+			atomic.AddInt64(&s.accepted, 1)
+		} else {
+			atomic.AddInt64(&s.rejected, 1)
+		}
+	}
+
+	if _, err := p.request("reveal", Dict{"user_id": p.name, "room_id": roomID, "card_id": cardID, "nonce": nonce}); err != nil {
+		return false, err
+	}
+	atomic.AddInt64(&s.reveals, 1)
+
+	resp, err = p.expect("hand", "match_finished")
+	if err != nil {
+		return false, err
+	}
+	return resp.Method == "match_finished", nil
+}
+
+// runCommitPair registra dois jogadores, cria uma sala com compromisso e revelação e disputa uma partida.
+func runCommitPair(addr string, id int, badReveal bool, s *commitStats) error {
+	prefix := fmt.Sprintf("stress%d-%d", time.Now().UnixNano()%1000000, id)
+	players := make([]*player, 2)
+	for i, suffix := range []string{"a", "b"} {
+		p, err := newPlayer(addr, prefix+suffix)
+		if err != nil {
+			return err
+		}
+		defer p.conn.Close()
+		if _, err := p.request("register", Dict{"username": p.name, "password": "stress"}); err != nil {
+			return err
+		}
+		resp, err := p.request("login", Dict{"username": p.name, "password": "stress"})
+		if err != nil {
+			return err
+		}
+		p.name, _ = resp.Data["user_id"].(string)
+		players[i] = p
+	}
+
+	resp, err := players[0].request("create", Dict{"user_id": players[0].name, "name": prefix, "commit_reveal": true})
+	if err != nil {
+		return err
+	}
+	roomID, _ := resp.Data["room_id"].(string)
+	if _, err := players[1].request("join", Dict{"user_id": players[1].name, "room_id": roomID}); err != nil {
+		return err
+	}
+	for _, p := range players {
+		if _, err := p.request("ready", Dict{"user_id": p.name, "room_id": roomID}); err != nil {
+			return err
+		}
+	}
+	for _, p := range players {
+		if len(p.hand) == 0 {
+			if _, err := p.expect("hand"); err != nil {
+				return err
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(players))
+	for i, p := range players {
+		wg.Add(1)
+		go func(i int, p *player) {
+			defer wg.Done()
+			for {
+				finished, err := playCommitRound(p, roomID, badReveal, s)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				if i == 0 {
+					atomic.AddInt64(&s.rounds, 1)
+				}
+				if finished {
+					return
+				}
+			}
+		}(i, p)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	atomic.AddInt64(&s.matches, 1)
+	return nil
+}
+
+func testCommitReveal(addr string, clients int, badReveal bool) {
+	pairs := clients / 2
+	if pairs == 0 {
+		pairs = 1
+	}
+	fmt.Printf("Testando partidas com compromisso e revelação: %d pares de jogadores, revelações adulteradas: %v\n", pairs, badReveal)
+
+	var wg sync.WaitGroup
+	var s commitStats
+	start := time.Now()
+	for i := 0; i < pairs; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			if err := runCommitPair(addr, id, badReveal, &s); err != nil {
+				atomic.AddInt64(&s.errors, 1)
+				fmt.Printf("Par %d: %v\n", id, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	fmt.Println("\n--- Resultados do Teste de Compromisso e Revelação ---")
+	fmt.Printf("Partidas concluídas: %d/%d\n", s.matches, pairs)
+	fmt.Printf("Rodadas jogadas: %d\n", s.rounds)
+	fmt.Printf("Compromissos enviados: %d\n", s.commits)
+	fmt.Printf("Revelações aceitas: %d\n", s.reveals)
+	if badReveal {
+		fmt.Printf("Revelações adulteradas recusadas: %d\n", s.rejected)
+		fmt.Printf("Revelações adulteradas aceitas: %d\n", s.accepted)
+	}
+	fmt.Printf("Erros: %d\n", s.errors)
+	fmt.Printf("Duração: %.2f s\n", elapsed.Seconds())
+	if s.errors > 0 || s.accepted > 0 {
+		os.Exit(1)
+	}
+}
@@ -147,17 +147,21 @@ func testConnections(addr string, clients int) {
 
 func main() {
 	var (
-		addr     string
-		clients  int
-		interval int
-		duration int
-		onlyConn bool
+		addr      string
+		clients   int
+		interval  int
+		duration  int
+		onlyConn  bool
+		commit    bool
+		badReveal bool
 	)
 	flag.StringVar(&addr, "addr", "localhost:8080", "Endereço do servidor (host:porta)")
 	flag.IntVar(&clients, "clients", 100, "Número de conexões simultâneas")
 	flag.IntVar(&interval, "interval", 100, "Intervalo entre pings (ms)")
 	flag.IntVar(&duration, "duration", 10, "Duração do teste (segundos)")
 	flag.BoolVar(&onlyConn, "onlyconn", false, "Testar apenas conexões simultâneas (sem enviar comandos)")
+	flag.BoolVar(&commit, "commit", false, "Testar partidas com compromisso e revelação (um par de jogadores a cada dois clientes)")
+	flag.BoolVar(&badReveal, "badreveal", false, "No modo -commit, enviar uma revelação adulterada antes de cada revelação correta")
 	flag.Parse()
 
 	if onlyConn {
@@ -166,6 +170,11 @@ func main() {
 		return
 	}
 
+	if commit {
+		testCommitReveal(addr, clients, badReveal)
+		return
+	}
+
 	fmt.Printf("Stress test: %d clientes, %d ms entre pings, duração %d s, servidor %s\n", clients, interval, duration, addr)
 
 	var wg sync.WaitGroup